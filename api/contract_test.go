@@ -0,0 +1,645 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/api/middleware"
+	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
+	"github.com/pack-calculator/internal/forecasts"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/shipping_rates"
+	"github.com/pack-calculator/internal/stats"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/auth"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/postgres"
+)
+
+var contractTime = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+// contractPacks serves configuration 1 and knows no other
+type contractPacks struct{}
+
+func (contractPacks) Create(ctx context.Context, config *pack_configurations.PackConfiguration) (*pack_configurations.PackConfiguration, error) {
+	config.ID = 2
+	config.Active = true
+	return config, nil
+}
+
+func (contractPacks) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	return contractPacks{}.Get(ctx, 1)
+}
+
+func (contractPacks) List(ctx context.Context) ([]pack_configurations.PackConfiguration, error) {
+	config, _ := contractPacks{}.Get(ctx, 1)
+	return []pack_configurations.PackConfiguration{*config}, nil
+}
+
+func (contractPacks) Get(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	if id != 1 {
+		return nil, pack_configurations.ErrConfigurationNotFound
+	}
+	return &pack_configurations.PackConfiguration{ID: 1, Active: true, PackSizes: pq.Int64Array{250, 500, 1000}, Rules: []string{"at most 2 x 250"}}, nil
+}
+
+func (contractPacks) Activate(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	return contractPacks{}.Get(ctx, id)
+}
+
+// contractCalculations answers with canned calculations. An overfill cap rejects the
+// order, order quantity 13 fails and order quantity 14 finds the database unavailable.
+type contractCalculations struct{}
+
+func (contractCalculations) OrderProcessing(ctx context.Context, order order_calculations.OrderRequest) (*order_calculations.OrderCalculation, error) {
+	if order.OrderQuantity == 13 {
+		return nil, errors.New("database is restarting")
+	}
+	if order.OrderQuantity == 14 {
+		return nil, postgres.ClassifyError(&pq.Error{Code: "57P03", Message: "the database system is starting up"})
+	}
+	if order.Overfill != nil {
+		return nil, &order_calculations.NoAcceptablePackingError{
+			OrderQuantity: order.OrderQuantity,
+			MaxOverfill:   order.Overfill.MaxItems,
+			Options:       []order_calculations.PackingOption{{TotalItems: 1000, TotalPacks: 1, Packs: []order_calculations.PackResult{{Size: 1000, Quantity: 1}}}},
+		}
+	}
+	calc := contractCalculation()
+	calc.Reservation = &reservations.Reservation{
+		ID:            1,
+		CalculationID: 1,
+		Status:        reservations.StatusActive,
+		Items:         []reservations.ReservationItem{{WarehouseID: 1, Size: 500, Quantity: 1}},
+		ExpiresAt:     contractTime.Add(15 * time.Minute),
+	}
+	calc.Sourcing = []order_calculations.WarehouseAllocation{{WarehouseID: 1, Warehouse: "north", Packs: calc.Result, TotalItems: 750, TotalPacks: 2}}
+	return calc, nil
+}
+
+func (contractCalculations) CalculateOptimalPacks(ctx context.Context, orderQuantity int, packSizes []int) (map[int]int, error) {
+	return map[int]int{250: 1, 500: 1}, nil
+}
+
+func (contractCalculations) AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*order_calculations.Amendment, error) {
+	if calculationID != 1 {
+		return nil, order_calculations.ErrCalculationNotFound
+	}
+	calc := contractCalculation()
+	calc.ID, calc.OrderQuantity = 2, orderQuantity
+	return &order_calculations.Amendment{
+		Calculation: calc,
+		FixedPacks:  calc.Result,
+		AddedPacks:  []order_calculations.PackResult{},
+		FromScratch: order_calculations.PackingOption{TotalItems: 750, TotalPacks: 2, Packs: calc.Result},
+	}, nil
+}
+
+func (contractCalculations) ListCalculations(ctx context.Context, offset, limit int) ([]order_calculations.OrderCalculation, error) {
+	return []order_calculations.OrderCalculation{*contractCalculation()}, nil
+}
+
+func (contractCalculations) GetCalculation(ctx context.Context, id uint) (*order_calculations.OrderCalculation, error) {
+	if id != 1 {
+		return nil, order_calculations.ErrCalculationNotFound
+	}
+	return contractCalculation(), nil
+}
+
+func contractCalculation() *order_calculations.OrderCalculation {
+	return &order_calculations.OrderCalculation{
+		ID:              1,
+		OrderQuantity:   501,
+		MinQuantity:     501,
+		Mode:            order_calculations.CalculationModeStandard,
+		Result:          []order_calculations.PackResult{{Size: 500, Quantity: 1}, {Size: 250, Quantity: 1}},
+		TotalItems:      750,
+		TotalPacks:      2,
+		ConfigurationID: 1,
+		Timestamp:       contractTime,
+	}
+}
+
+type contractRates struct{}
+
+func (contractRates) Upload(ctx context.Context, rates []shipping_rates.Rate) error { return nil }
+
+func (contractRates) List(ctx context.Context) ([]shipping_rates.Rate, error) {
+	return []shipping_rates.Rate{{ID: 1, Carrier: "dhl", Zone: "EU", MaxWeight: 10, Cost: 4.5}}, nil
+}
+
+func (contractRates) Quote(ctx context.Context, request shipping_rates.QuoteRequest) (*shipping_rates.Quote, error) {
+	if request.Zone != "EU" {
+		return nil, apperrors.NewValidationError("No rates for zone " + request.Zone)
+	}
+	option := shipping_rates.QuoteOption{Carrier: "dhl", Packs: []order_calculations.PackResult{{Size: 500, Quantity: 1}}, TotalItems: 500, TotalPacks: 1, ShippingCost: 4.5}
+	return &shipping_rates.Quote{OrderQuantity: request.OrderQuantity, Zone: request.Zone, ItemOptimal: option, CostOptimal: option}, nil
+}
+
+type contractWarehouses struct{}
+
+func (contractWarehouses) Save(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	warehouse.ID = 1
+	return nil
+}
+
+func (contractWarehouses) List(ctx context.Context) ([]warehouses.Warehouse, error) {
+	return []warehouses.Warehouse{{ID: 1, Name: "north", Inventory: map[int]int{500: 10}}}, nil
+}
+
+type contractReservations struct{}
+
+func (contractReservations) Commit(ctx context.Context, id uint) (*reservations.Reservation, error) {
+	switch id {
+	case 1:
+		committedAt := contractTime
+		return &reservations.Reservation{ID: 1, CalculationID: 1, Status: reservations.StatusCommitted, Items: []reservations.ReservationItem{}, ExpiresAt: contractTime, CommittedAt: &committedAt}, nil
+	case 2:
+		return nil, reservations.ErrReservationNotActive
+	default:
+		return nil, reservations.ErrReservationNotFound
+	}
+}
+
+func (contractReservations) ReleaseExpired(ctx context.Context) (int64, error) { return 0, nil }
+
+type contractStats struct{}
+
+func (contractStats) Stats(ctx context.Context, request stats.Request) (*stats.Stats, error) {
+	return &stats.Stats{Period: request.Period, BucketSize: request.BucketSize, Groups: []stats.Group{{
+		Period:          contractTime,
+		ConfigurationID: 1,
+		Calculations:    2,
+		Histogram:       []stats.HistogramBucket{{From: 500, To: 999, Calculations: 2}},
+		PackUsage:       []stats.PackUsage{{Size: 500, Packs: 2}},
+	}}}, nil
+}
+
+func (contractStats) Refresh(ctx context.Context) error { return nil }
+
+type contractForecasts struct{}
+
+func (contractForecasts) Generate(ctx context.Context) (*forecasts.Forecast, error) { return nil, nil }
+
+func (contractForecasts) Latest(ctx context.Context) (*forecasts.Forecast, error) {
+	return &forecasts.Forecast{ID: 1, GeneratedAt: contractTime, Method: "exponential", HistoryDays: 28, HorizonDays: 30, Items: []forecasts.ForecastItem{{Size: 500, DailyUsage: 1.5, ProjectedUsage: 45, OnHand: 10, LeadTimeDays: 7, ReorderPoint: 11, ReorderNow: true, ReorderQuantity: 35}}}, nil
+}
+
+func (contractForecasts) Tenants(ctx context.Context) ([]string, error) { return nil, nil }
+
+type contractExperiments struct{}
+
+func (contractExperiments) Start(ctx context.Context, experiment *experiments.Experiment) error {
+	experiment.ID, experiment.Status, experiment.StartedAt = 1, experiments.StatusRunning, contractTime
+	return nil
+}
+
+func (contractExperiments) Stop(ctx context.Context, id uint) (*experiments.Experiment, error) {
+	if id != 1 {
+		return nil, experiments.ErrExperimentNotFound
+	}
+	stoppedAt := contractTime
+	return &experiments.Experiment{ID: 1, Name: "larger packs", ConfigurationID: 1, TrafficShare: 0.5, Status: experiments.StatusStopped, StartedAt: contractTime, StoppedAt: &stoppedAt}, nil
+}
+
+func (contractExperiments) Results(ctx context.Context, id uint) (*experiments.Results, error) {
+	experiment, err := contractExperiments{}.Stop(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &experiments.Results{Experiment: *experiment, Arms: []experiments.ArmComparison{{Arm: experiments.ArmControl, Orders: 3, TotalPacks: 6, AveragePacks: 2}}}, nil
+}
+
+type contractCustomers struct{}
+
+func (contractCustomers) Save(ctx context.Context, profile *customers.Profile) error { return nil }
+
+func (contractCustomers) List(ctx context.Context) ([]customers.Profile, error) {
+	return []customers.Profile{{CustomerID: "acme", Name: "Acme", ExtraSizes: []int{750}, ExcludedSizes: []int{}}}, nil
+}
+
+// contractKeys knows key 1, the reader key with packs:read and the writer key with packs:write
+type contractKeys struct{}
+
+func (contractKeys) Issue(ctx context.Context, request *api_keys.IssueAPIRequest) (*api_keys.IssueAPIResponse, error) {
+	return &api_keys.IssueAPIResponse{APIKey: api_keys.APIKey{ID: 2, Name: request.Name, Prefix: "pk_0a1b2c3d", Scopes: request.Scopes, CreatedBy: auth.Subject(ctx), CreatedAt: contractTime}, Key: "pk_0a1b2c3d4e5f"}, nil
+}
+
+func (contractKeys) List(ctx context.Context) ([]api_keys.APIKey, error) {
+	return []api_keys.APIKey{{ID: 1, Name: "checkout", Prefix: "pk_9f8e7d6c", Scopes: []string{auth.ScopeCalculate}, CreatedBy: "bootstrap", CreatedAt: contractTime}}, nil
+}
+
+func (contractKeys) Revoke(ctx context.Context, id uint) (*api_keys.APIKey, error) {
+	if id != 1 {
+		return nil, api_keys.ErrKeyNotFound
+	}
+	revokedAt := contractTime
+	return &api_keys.APIKey{ID: 1, Name: "checkout", Prefix: "pk_9f8e7d6c", Scopes: []string{auth.ScopeCalculate}, CreatedBy: "bootstrap", CreatedAt: contractTime, RevokedAt: &revokedAt}, nil
+}
+
+func (contractKeys) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	switch key {
+	case "pk_reader":
+		return &auth.Principal{Subject: "api_key:3", Scopes: []string{auth.ScopePacksRead}}, nil
+	case "pk_writer":
+		return &auth.Principal{Subject: "api_key:4", Scopes: []string{auth.ScopePacksWrite}}, nil
+	case "pk_retail":
+		return &auth.Principal{Subject: "api_key:5", Scopes: []string{auth.ScopePacksWrite}, Tenant: "retail"}, nil
+	}
+	return nil, api_keys.ErrInvalidKey
+}
+
+// contractAudit holds the activation of configuration 1 in an intact chain
+type contractAudit struct{}
+
+func (contractAudit) List(ctx context.Context, filter audit_events.Filter) ([]audit_events.Event, error) {
+	return []audit_events.Event{{
+		ID: 1, Action: audit_events.ActionPackConfigurationActivate, ResourceType: audit_events.ResourcePackConfiguration, ResourceID: "1",
+		Actor: "user:alice", RequestID: "3f2a9c1e", ClientIP: "192.0.2.10",
+		Before: json.RawMessage(`{"activeConfigurationId":null}`), After: json.RawMessage(`{"activeConfigurationId":1}`),
+		CreatedAt: contractTime, Hash: "5d41402abc4b2a76b9719d911017c592",
+	}}, nil
+}
+
+func (contractAudit) Verify(ctx context.Context) (*audit_events.Verification, error) {
+	return &audit_events.Verification{Valid: true, Events: 1}, nil
+}
+
+// newContractRouter serves the API router with canned services
+func newContractRouter(cfg *config.AppConfig) *gin.Engine {
+	return newContractRouterWithTokens(cfg, nil)
+}
+
+// newContractRouterWithTokens serves the API router with canned services, accepting the
+// bearer tokens of the verifier
+func newContractRouterWithTokens(cfg *config.AppConfig, tokens *auth.TokenVerifier) *gin.Engine {
+	return newContractRouterWithCalculations(cfg, tokens, order_calculations.NewHandler(zap.NewNop(), contractCalculations{}))
+}
+
+// newContractRouterWithCalculations serves the API router with canned services and the
+// given calculations handler
+func newContractRouterWithCalculations(cfg *config.AppConfig, tokens *auth.TokenVerifier, calculationsHandler *order_calculations.Handler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+	return SetupRouter(logger, cfg, middleware.NewAuthenticator(cfg.Auth, contractKeys{}, tokens), middleware.NewRateLimiter(cfg.RateLimiter),
+		pack_configurations.NewHandler(logger, contractPacks{}),
+		calculationsHandler,
+		shipping_rates.NewHandler(logger, contractRates{}),
+		warehouses.NewHandler(logger, contractWarehouses{}),
+		reservations.NewHandler(logger, contractReservations{}),
+		stats.NewHandler(logger, contractStats{}),
+		forecasts.NewHandler(logger, contractForecasts{}),
+		experiments.NewHandler(logger, contractExperiments{}),
+		customers.NewHandler(logger, contractCustomers{}),
+		api_keys.NewHandler(logger, contractKeys{}),
+		audit_events.NewHandler(logger, contractAudit{}),
+	)
+}
+
+// loadContract loads api/swagger.yaml and returns a router over its operations
+func loadContract(t *testing.T) routers.Router {
+	ctx := context.Background()
+	doc, err := openapi3.NewLoader().LoadFromFile("swagger.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(ctx))
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	return router
+}
+
+func TestContract_V1(t *testing.T) {
+	contract := loadContract(t)
+	router := newContractRouter(&config.AppConfig{})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "get active packs", method: http.MethodGet, path: "/packs", wantStatus: http.StatusOK},
+		{name: "create packs", method: http.MethodPost, path: "/packs", body: `{"packSizes": [250, 500, 1000]}`, wantStatus: http.StatusOK},
+		{name: "create invalid packs", method: http.MethodPost, path: "/packs", body: `{"packSizes": [250, 250]}`, wantStatus: http.StatusBadRequest, wantCode: apperrors.CodeInvalidRequest},
+		{name: "list configurations", method: http.MethodGet, path: "/packs/configurations", wantStatus: http.StatusOK},
+		{name: "get configuration", method: http.MethodGet, path: "/packs/configurations/1", wantStatus: http.StatusOK},
+		{name: "get unknown configuration", method: http.MethodGet, path: "/packs/configurations/9", wantStatus: http.StatusNotFound, wantCode: apperrors.CodeNotFound},
+		{name: "activate configuration", method: http.MethodPost, path: "/packs/configurations/1/activate", wantStatus: http.StatusOK},
+		{name: "calculate", method: http.MethodPost, path: "/calculate", body: `{"orderQuantity": 501, "sourcing": true, "reserve": true}`, wantStatus: http.StatusOK},
+		{name: "calculate invalid order", method: http.MethodPost, path: "/calculate", body: `{"orderQuantity": -1}`, wantStatus: http.StatusBadRequest, wantCode: apperrors.CodeInvalidRequest},
+		{name: "calculate beyond the overfill cap", method: http.MethodPost, path: "/calculate", body: `{"orderQuantity": 501, "overfill": {"maxItems": 100}}`, wantStatus: http.StatusUnprocessableEntity, wantCode: apperrors.CodeUnprocessable},
+		{name: "calculate with a failing service", method: http.MethodPost, path: "/calculate", body: `{"orderQuantity": 13}`, wantStatus: http.StatusInternalServerError, wantCode: apperrors.CodeInternal},
+		{name: "calculate with the database unavailable", method: http.MethodPost, path: "/calculate", body: `{"orderQuantity": 14}`, wantStatus: http.StatusServiceUnavailable, wantCode: apperrors.CodeUnavailable},
+		{name: "list calculations", method: http.MethodGet, path: "/calculations?limit=10", wantStatus: http.StatusOK},
+		{name: "get calculation", method: http.MethodGet, path: "/calculations/1", wantStatus: http.StatusOK},
+		{name: "get unknown calculation", method: http.MethodGet, path: "/calculations/9", wantStatus: http.StatusNotFound, wantCode: apperrors.CodeNotFound},
+		{name: "amend calculation", method: http.MethodPost, path: "/calculations/1/amend", body: `{"orderQuantity": 600}`, wantStatus: http.StatusOK},
+		{name: "list rates", method: http.MethodGet, path: "/rates", wantStatus: http.StatusOK},
+		{name: "upload rates", method: http.MethodPost, path: "/rates", contentType: "text/csv", body: "carrier,zone,max_weight,cost\ndhl,EU,10,4.5\n", wantStatus: http.StatusOK},
+		{name: "upload invalid rates", method: http.MethodPost, path: "/rates", contentType: "text/csv", body: "carrier,zone\n", wantStatus: http.StatusBadRequest, wantCode: apperrors.CodeInvalidRequest},
+		{name: "quote", method: http.MethodPost, path: "/quote", body: `{"orderQuantity": 500, "zone": "EU"}`, wantStatus: http.StatusOK},
+		{name: "quote unknown zone", method: http.MethodPost, path: "/quote", body: `{"orderQuantity": 500, "zone": "US"}`, wantStatus: http.StatusBadRequest, wantCode: apperrors.CodeInvalidRequest},
+		{name: "list warehouses", method: http.MethodGet, path: "/warehouses", wantStatus: http.StatusOK},
+		{name: "save warehouse", method: http.MethodPost, path: "/warehouses", body: `{"name": "north", "inventory": {"500": 10}}`, wantStatus: http.StatusOK},
+		{name: "commit reservation", method: http.MethodPost, path: "/reservations/1/commit", wantStatus: http.StatusOK},
+		{name: "commit inactive reservation", method: http.MethodPost, path: "/reservations/2/commit", wantStatus: http.StatusConflict, wantCode: apperrors.CodeConflict},
+		{name: "stats", method: http.MethodGet, path: "/stats?period=day&bucketSize=500", wantStatus: http.StatusOK},
+		{name: "forecast", method: http.MethodGet, path: "/forecast", wantStatus: http.StatusOK},
+		{name: "start experiment", method: http.MethodPost, path: "/experiments", body: `{"name": "larger packs", "configurationId": 1, "trafficShare": 0.5}`, wantStatus: http.StatusOK},
+		{name: "stop experiment", method: http.MethodPost, path: "/experiments/1/stop", wantStatus: http.StatusOK},
+		{name: "experiment results", method: http.MethodGet, path: "/experiments/1/results", wantStatus: http.StatusOK},
+		{name: "unknown experiment results", method: http.MethodGet, path: "/experiments/9/results", wantStatus: http.StatusNotFound, wantCode: apperrors.CodeNotFound},
+		{name: "list customers", method: http.MethodGet, path: "/customers", wantStatus: http.StatusOK},
+		{name: "list API keys", method: http.MethodGet, path: "/keys", wantStatus: http.StatusOK},
+		{name: "issue API key", method: http.MethodPost, path: "/keys", body: `{"name": "checkout", "scopes": ["calculate", "packs:read"]}`, wantStatus: http.StatusOK},
+		{name: "issue API key with an unknown scope", method: http.MethodPost, path: "/keys", body: `{"name": "checkout", "scopes": ["everything"]}`, wantStatus: http.StatusBadRequest, wantCode: apperrors.CodeInvalidRequest},
+		{name: "revoke API key", method: http.MethodPost, path: "/keys/1/revoke", wantStatus: http.StatusOK},
+		{name: "revoke unknown API key", method: http.MethodPost, path: "/keys/9/revoke", wantStatus: http.StatusNotFound, wantCode: apperrors.CodeNotFound},
+		{name: "save customer", method: http.MethodPost, path: "/customers", body: `{"customerId": "acme", "name": "Acme", "extraSizes": [750]}`, wantStatus: http.StatusOK},
+		{name: "list audit events", method: http.MethodGet, path: "/audit?actor=user:alice&from=2026-10-18T00:00:00Z&limit=10", wantStatus: http.StatusOK},
+		{name: "list audit events ending before they start", method: http.MethodGet, path: "/audit?from=2026-10-18T00:00:00Z&to=2026-10-17T00:00:00Z", wantStatus: http.StatusBadRequest, wantCode: apperrors.CodeInvalidRequest},
+		{name: "verify audit trail", method: http.MethodGet, path: "/audit/verify", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			req := httptest.NewRequest(tt.method, "/api/v1"+tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				req.Header.Set("Content-Type", contentType)
+			}
+
+			route, pathParams, err := contract.FindRoute(req)
+			require.NoError(t, err)
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    &openapi3filter.Options{ExcludeRequestBody: tt.wantStatus != http.StatusOK, AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+			}
+			require.NoError(t, openapi3filter.ValidateRequest(ctx, requestInput))
+
+			// The validation above consumed the body
+			req.Body = io.NopCloser(strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			})
+			assert.NoError(t, err)
+
+			if tt.wantCode != "" {
+				var problem apperrors.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, apperrors.ProblemContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.wantCode, problem.Code)
+				assert.Equal(t, tt.wantStatus, problem.Status)
+				assert.Equal(t, "/api/v1"+strings.SplitN(tt.path, "?", 2)[0], problem.Instance)
+				assert.NotEmpty(t, problem.Detail)
+			}
+		})
+	}
+}
+
+func TestContract_V1ProblemDetails(t *testing.T) {
+	router := newContractRouter(&config.AppConfig{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/calculate", strings.NewReader(`{"orderQuantity": 501, "overfill": {"maxItems": 100}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"type": "urn:pack-calculator:problem:unprocessable",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "No acceptable packing within the overfill cap",
+		"instance": "/api/v1/calculate",
+		"code": "unprocessable",
+		"details": {
+			"orderQuantity": 501,
+			"maxOverfill": 100,
+			"options": [{"totalItems": 1000, "totalPacks": 1, "packs": [{"size": 1000, "quantity": 1}]}]
+		}
+	}`, w.Body.String())
+}
+
+func TestContract_RequestID(t *testing.T) {
+	router := newContractRouter(&config.AppConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/packs", nil)
+	req.Header.Set(middleware.RequestIDHeader, "checkout-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "checkout-42", w.Header().Get(middleware.RequestIDHeader))
+
+	// An ID that cannot be logged as is gets replaced
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/packs", nil)
+	req.Header.Set(middleware.RequestIDHeader, "checkout 42")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, w.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestContract_V1RateLimited(t *testing.T) {
+	router := newContractRouter(&config.AppConfig{RateLimiter: config.RateLimiterConfig{Enabled: true, MaxRequests: 1}})
+
+	var w *httptest.ResponseRecorder
+	for range 2 {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/packs", nil))
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	var problem apperrors.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, apperrors.CodeRateLimited, problem.Code)
+}
+
+func TestContract_V1Authentication(t *testing.T) {
+	contract := loadContract(t)
+	signingKey, tokens := newContractTokenVerifier(t)
+	router := newContractRouterWithTokens(&config.AppConfig{Auth: config.AuthConfig{Enabled: true, BootstrapKey: "bootstrap-key-of-at-least-32-characters"}}, tokens)
+	token := func(roles []string, expiry time.Duration) string {
+		return signContractToken(t, signingKey, map[string]any{"iss": contractIssuer, "aud": contractAudience, "sub": "alice", "roles": roles, "exp": time.Now().Add(expiry).Unix()})
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		key        string
+		token      string
+		tenant     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "no key", method: http.MethodGet, path: "/packs", wantStatus: http.StatusUnauthorized, wantCode: apperrors.CodeUnauthorized},
+		{name: "unknown key", method: http.MethodGet, path: "/packs", key: "pk_unknown", wantStatus: http.StatusUnauthorized, wantCode: apperrors.CodeUnauthorized},
+		{name: "key with the scope", method: http.MethodGet, path: "/packs", key: "pk_reader", wantStatus: http.StatusOK},
+		{name: "key without the scope", method: http.MethodPost, path: "/packs/configurations/1/activate", key: "pk_reader", wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "write scope", method: http.MethodPost, path: "/packs/configurations/1/activate", key: "pk_writer", wantStatus: http.StatusOK},
+		{name: "admin route", method: http.MethodGet, path: "/keys", key: "pk_writer", wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "bootstrap key", method: http.MethodGet, path: "/keys", key: "bootstrap-key-of-at-least-32-characters", wantStatus: http.StatusOK},
+		{name: "viewer token", method: http.MethodGet, path: "/packs", token: token([]string{auth.RoleViewer}, time.Hour), wantStatus: http.StatusOK},
+		{name: "viewer token on a write route", method: http.MethodPost, path: "/packs/configurations/1/activate", token: token([]string{auth.RoleViewer}, time.Hour), wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "operator token", method: http.MethodPost, path: "/packs/configurations/1/activate", token: token([]string{auth.RoleOperator}, time.Hour), wantStatus: http.StatusOK},
+		{name: "operator token on an admin route", method: http.MethodGet, path: "/keys", token: token([]string{auth.RoleOperator}, time.Hour), wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "admin token", method: http.MethodGet, path: "/keys", token: token([]string{auth.RoleAdmin}, time.Hour), wantStatus: http.StatusOK},
+		{name: "expired token", method: http.MethodGet, path: "/packs", token: token([]string{auth.RoleAdmin}, -time.Hour), wantStatus: http.StatusUnauthorized, wantCode: apperrors.CodeUnauthorized},
+		{name: "token for another audience", method: http.MethodGet, path: "/packs", wantStatus: http.StatusUnauthorized, wantCode: apperrors.CodeUnauthorized,
+			token: signContractToken(t, signingKey, map[string]any{"iss": contractIssuer, "aud": "billing", "sub": "alice", "roles": []string{auth.RoleAdmin}, "exp": time.Now().Add(time.Hour).Unix()})},
+		{name: "token takes precedence over the key", method: http.MethodPost, path: "/packs/configurations/1/activate", key: "pk_writer", token: token([]string{auth.RoleViewer}, time.Hour), wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "key bound to a tenant", method: http.MethodPost, path: "/packs/configurations/1/activate", key: "pk_retail", tenant: "retail", wantStatus: http.StatusOK},
+		{name: "key bound to another tenant", method: http.MethodPost, path: "/packs/configurations/1/activate", key: "pk_retail", tenant: "wholesale", wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "unbound key selecting a tenant", method: http.MethodPost, path: "/packs/configurations/1/activate", key: "pk_writer", tenant: "retail", wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden},
+		{name: "token bound to another tenant", method: http.MethodPost, path: "/packs/configurations/1/activate", tenant: "wholesale", wantStatus: http.StatusForbidden, wantCode: apperrors.CodeForbidden,
+			token: signContractToken(t, signingKey, map[string]any{"iss": contractIssuer, "aud": contractAudience, "sub": "alice", "roles": []string{auth.RoleOperator}, "tenant": "retail", "exp": time.Now().Add(time.Hour).Unix()})},
+		{name: "admin token selecting a tenant", method: http.MethodGet, path: "/keys", token: token([]string{auth.RoleAdmin}, time.Hour), tenant: "retail", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			req := httptest.NewRequest(tt.method, "/api/v1"+tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.key)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.tenant != "" {
+				req.Header.Set(middleware.TenantHeader, tt.tenant)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			route, pathParams, err := contract.FindRoute(httptest.NewRequest(tt.method, "/api/v1"+tt.path, nil))
+			require.NoError(t, err)
+			err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route},
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			})
+			assert.NoError(t, err)
+
+			if tt.wantCode != "" {
+				var problem apperrors.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.wantCode, problem.Code)
+			}
+		})
+	}
+}
+
+// The issuer and audience of the bearer tokens accepted by the contract tests
+const (
+	contractIssuer   = "https://id.example.com"
+	contractAudience = "pack-calculator"
+)
+
+// newContractTokenVerifier returns a generated signing key and a verifier of the tokens
+// it signs, reading the public key from a JWKS file
+func newContractTokenVerifier(t *testing.T) (*rsa.PrivateKey, *auth.TokenVerifier) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "contract",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwks, 0o600))
+	return key, auth.NewTokenVerifier(auth.TokenConfig{
+		Issuer:   contractIssuer,
+		Audience: contractAudience,
+		RoleMap:  map[string]string{auth.RoleViewer: auth.RoleViewer, auth.RoleOperator: auth.RoleOperator, auth.RoleAdmin: auth.RoleAdmin},
+	}, auth.NewKeySet(path, nil))
+}
+
+// signContractToken returns an RS256 JWT of the claims
+func signContractToken(t *testing.T, key *rsa.PrivateKey, claims map[string]any) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	input := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"contract"}`)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestContract_LegacyRoutesKeepTheirShape(t *testing.T) {
+	router := newContractRouter(&config.AppConfig{})
+
+	t.Run("errors", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/calculations/9", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"Type": "NOT_FOUND", "Message": "Calculation not found", "Err": {}}`, w.Body.String())
+	})
+
+	t.Run("calculation response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/calculate", strings.NewReader(`{"orderQuantity": 501}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Contains(t, body, "pack_configurations")
+		assert.Equal(t, true, body["success"])
+	})
+}
@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pack-calculator/api/middleware"
+	packcalculatorv1 "github.com/pack-calculator/api/proto/packcalculator/v1"
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Metadata carrying the credentials and tenant of a call, like the headers of the REST API
+const (
+	apiKeyMetadata        = "x-api-key"
+	authorizationMetadata = "authorization"
+	tenantMetadata        = "x-tenant-id"
+)
+
+// methodScopes maps each method to the scope it requires, matching the REST routes
+var methodScopes = map[string]string{
+	packcalculatorv1.PackCalculator_Calculate_FullMethodName:                  auth.ScopeCalculate,
+	packcalculatorv1.PackCalculator_CalculateBatch_FullMethodName:             auth.ScopeCalculate,
+	packcalculatorv1.PackCalculator_GetActivePackConfiguration_FullMethodName: auth.ScopePacksRead,
+	packcalculatorv1.PackCalculator_ListPackConfigurations_FullMethodName:     auth.ScopePacksRead,
+	packcalculatorv1.PackCalculator_GetPackConfiguration_FullMethodName:       auth.ScopePacksRead,
+	packcalculatorv1.PackCalculator_CreatePackConfiguration_FullMethodName:    auth.ScopePacksWrite,
+	packcalculatorv1.PackCalculator_ActivatePackConfiguration_FullMethodName:  auth.ScopePacksWrite,
+}
+
+// Auth returns an interceptor that authenticates the bearer token or API key in the call
+// metadata, checks the scope of the method and resolves the tenant the call acts for.
+// Methods without a scope require admin.
+func Auth(authenticator *middleware.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		principal, err := authenticator.Authenticate(ctx, firstValue(ctx, apiKeyMetadata), middleware.BearerToken(firstValue(ctx, authorizationMetadata)))
+		if err != nil {
+			return nil, authError(err)
+		}
+		scope, ok := methodScopes[info.FullMethod]
+		if !ok {
+			scope = auth.ScopeAdmin
+		}
+		if err := authenticator.Authorize(principal, scope); err != nil {
+			return nil, authError(err)
+		}
+		tenantID, err := authenticator.ResolveTenant(principal, firstValue(ctx, tenantMetadata))
+		if err != nil {
+			return nil, authError(err)
+		}
+
+		if principal != nil {
+			ctx = auth.NewContext(ctx, principal)
+		}
+		return handler(tenant.NewContext(ctx, tenantID), req)
+	}
+}
+
+// firstValue returns the first value of a metadata key of the call, or an empty string
+func firstValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// authError converts an authentication error into a gRPC status, hiding internal errors
+func authError(err error) error {
+	appErr := errors.Wrap("Failed to authenticate the call", err)
+	code, ok := errorCodes[appErr.Type]
+	if !ok {
+		code = codes.Internal
+	}
+	return status.Error(code, appErr.Message)
+}
@@ -0,0 +1,209 @@
+package grpcserver
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	packcalculatorv1 "github.com/pack-calculator/api/proto/packcalculator/v1"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/pkg/postgres"
+)
+
+// fromCalculateRequest converts a request into the JSON request type the validation works on
+func fromCalculateRequest(req *packcalculatorv1.CalculateRequest) order_calculations.CalculateAPIRequest {
+	request := order_calculations.CalculateAPIRequest{
+		OrderQuantity:   int(req.GetOrderQuantity()),
+		MinQuantity:     int(req.GetMinQuantity()),
+		MaxQuantity:     int(req.GetMaxQuantity()),
+		CartonObjective: req.GetCartonObjective(),
+		Sourcing:        req.GetSourcing(),
+		Reserve:         req.GetReserve(),
+		ReservationTTL:  int(req.GetReservationTtlSeconds()),
+		OrderReference:  req.GetOrderReference(),
+		CustomerID:      req.GetCustomerId(),
+	}
+	if overfill := req.GetOverfill(); overfill != nil {
+		request.Overfill = &order_calculations.OverfillPolicy{
+			MaxItems:   int(overfill.GetMaxItems()),
+			MaxPercent: overfill.GetMaxPercent(),
+			Backorder:  overfill.GetBackorder(),
+		}
+	}
+	if len(req.GetInventory()) > 0 {
+		request.Inventory = toIntMap(req.GetInventory())
+	}
+	if tieBreak := req.GetTieBreak(); tieBreak != nil {
+		request.TieBreak = &order_calculations.TieBreakPolicy{Policy: tieBreak.GetPolicy()}
+		if len(tieBreak.GetWeights()) > 0 {
+			request.TieBreak.Weights = toIntMap(tieBreak.GetWeights())
+		}
+	}
+	return request
+}
+
+func toCalculateResponse(request *order_calculations.CalculateAPIRequest, calc *order_calculations.OrderCalculation) *packcalculatorv1.CalculateResponse {
+	response := &packcalculatorv1.CalculateResponse{
+		CalculationId: uint64(calc.ID),
+		OrderQuantity: int64(request.OrderQuantity),
+		MinQuantity:   int64(request.MinQuantity),
+		MaxQuantity:   int64(request.MaxQuantity),
+		TotalItems:    int64(calc.TotalItems),
+		TotalPacks:    int64(calc.TotalPacks),
+		Packs:         toPacks(calc.Result),
+		Reason:        calc.Reason,
+		Backorder:     int64(calc.Backorder),
+		Hierarchy:     toPacks(calc.Hierarchy),
+	}
+	for _, unit := range calc.Hierarchy {
+		response.TopLevelUnits += int64(unit.Quantity)
+	}
+	for _, shipment := range calc.Shipments {
+		response.Shipments = append(response.Shipments, &packcalculatorv1.Shipment{
+			Kind:       shipment.Kind,
+			Quantity:   int64(shipment.Quantity),
+			Packs:      toPacks(shipment.Result),
+			TotalItems: int64(shipment.TotalItems),
+			TotalPacks: int64(shipment.TotalPacks),
+		})
+	}
+	for _, allocation := range calc.Sourcing {
+		response.Sourcing = append(response.Sourcing, &packcalculatorv1.WarehouseAllocation{
+			WarehouseId: uint64(allocation.WarehouseID),
+			Warehouse:   allocation.Warehouse,
+			Packs:       toPacks(allocation.Packs),
+			TotalItems:  int64(allocation.TotalItems),
+			TotalPacks:  int64(allocation.TotalPacks),
+		})
+	}
+	if plan := calc.Cartons; plan != nil {
+		response.Cartons = &packcalculatorv1.CartonPlan{
+			TotalCartons: int64(plan.TotalCartons),
+			TotalCost:    plan.TotalCost,
+			Unassigned:   toPacks(plan.Unassigned),
+		}
+		for _, carton := range plan.Cartons {
+			response.Cartons.Cartons = append(response.Cartons.Cartons, &packcalculatorv1.CartonAssignment{
+				Carton: carton.Carton,
+				Packs:  toPacks(carton.Packs),
+				Volume: carton.Volume,
+				Weight: carton.Weight,
+				Cost:   carton.Cost,
+			})
+		}
+	}
+	if reservation := calc.Reservation; reservation != nil {
+		response.Reservation = &packcalculatorv1.Reservation{
+			Id:        uint64(reservation.ID),
+			Status:    reservation.Status,
+			ExpiresAt: timestamppb.New(reservation.ExpiresAt),
+		}
+		for _, item := range reservation.Items {
+			response.Reservation.Items = append(response.Reservation.Items, &packcalculatorv1.ReservationItem{
+				WarehouseId: uint64(item.WarehouseID),
+				Size:        int64(item.Size),
+				Quantity:    int64(item.Quantity),
+			})
+		}
+	}
+	if experiment := calc.Experiment; experiment != nil {
+		response.Experiment = &packcalculatorv1.ExperimentAssignment{
+			ExperimentId: uint64(experiment.ExperimentID),
+			Arm:          experiment.Arm,
+		}
+	}
+	return response
+}
+
+func toPacks(results []order_calculations.PackResult) []*packcalculatorv1.Pack {
+	if len(results) == 0 {
+		return nil
+	}
+	packs := make([]*packcalculatorv1.Pack, 0, len(results))
+	for _, result := range results {
+		packs = append(packs, &packcalculatorv1.Pack{
+			Unit:     result.Unit,
+			Size:     int64(result.Size),
+			Quantity: int64(result.Quantity),
+			Contents: toPacks(result.Contents),
+		})
+	}
+	return packs
+}
+
+// fromCreatePackConfigurationRequest converts a request into the JSON request type the validation works on
+func fromCreatePackConfigurationRequest(req *packcalculatorv1.CreatePackConfigurationRequest) pack_configurations.PackCfgAPIRequest {
+	request := pack_configurations.PackCfgAPIRequest{Rules: req.GetRules()}
+	for _, size := range req.GetPackSizes() {
+		request.PackSizes = append(request.PackSizes, int(size))
+	}
+	for _, rule := range req.GetNesting() {
+		request.Nesting = append(request.Nesting, pack_configurations.NestingRule{
+			Unit:     rule.GetUnit(),
+			Contains: rule.GetContains(),
+			Size:     int(rule.GetSize()),
+			Capacity: int(rule.GetCapacity()),
+		})
+	}
+	for _, spec := range req.GetPackSpecs() {
+		request.PackSpecs = append(request.PackSpecs, pack_configurations.PackSpec{
+			Size:   int(spec.GetSize()),
+			Volume: spec.GetVolume(),
+			Weight: spec.GetWeight(),
+		})
+	}
+	for _, carton := range req.GetCartons() {
+		request.Cartons = append(request.Cartons, pack_configurations.CartonType{
+			Name:      carton.GetName(),
+			MaxVolume: carton.GetMaxVolume(),
+			MaxWeight: carton.GetMaxWeight(),
+			Cost:      carton.GetCost(),
+		})
+	}
+	return request
+}
+
+func toPackConfiguration(packCfg *pack_configurations.PackConfiguration) *packcalculatorv1.PackConfiguration {
+	if packCfg == nil {
+		return &packcalculatorv1.PackConfiguration{}
+	}
+	response := &packcalculatorv1.PackConfiguration{
+		Id:     uint64(packCfg.ID),
+		Active: packCfg.Active,
+		Rules:  packCfg.Rules,
+	}
+	for _, size := range postgres.Int64ArrayToIntSlice(packCfg.PackSizes) {
+		response.PackSizes = append(response.PackSizes, int64(size))
+	}
+	for _, rule := range packCfg.Nesting {
+		response.Nesting = append(response.Nesting, &packcalculatorv1.NestingRule{
+			Unit:     rule.Unit,
+			Contains: rule.Contains,
+			Size:     int64(rule.Size),
+			Capacity: int64(rule.Capacity),
+		})
+	}
+	for _, spec := range packCfg.PackSpecs {
+		response.PackSpecs = append(response.PackSpecs, &packcalculatorv1.PackSpec{
+			Size:   int64(spec.Size),
+			Volume: spec.Volume,
+			Weight: spec.Weight,
+		})
+	}
+	for _, carton := range packCfg.Cartons {
+		response.Cartons = append(response.Cartons, &packcalculatorv1.CartonType{
+			Name:      carton.Name,
+			MaxVolume: carton.MaxVolume,
+			MaxWeight: carton.MaxWeight,
+			Cost:      carton.Cost,
+		})
+	}
+	return response
+}
+
+func toIntMap(values map[int64]int64) map[int]int {
+	converted := make(map[int]int, len(values))
+	for key, value := range values {
+		converted[int(key)] = int(value)
+	}
+	return converted
+}
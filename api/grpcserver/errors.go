@@ -0,0 +1,50 @@
+package grpcserver
+
+import (
+	stderrors "errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/pkg/errors"
+)
+
+// errorCodes maps the error types of pkg/errors to gRPC status codes
+var errorCodes = map[errors.ErrorType]codes.Code{
+	errors.ErrorTypeInvalidRequest: codes.InvalidArgument,
+	errors.ErrorTypeNotFound:       codes.NotFound,
+	errors.ErrorTypeConflict:       codes.Aborted,
+	errors.ErrorTypeUnprocessable:  codes.FailedPrecondition,
+	errors.ErrorTypeUnauthorized:   codes.Unauthenticated,
+	errors.ErrorTypeForbidden:      codes.PermissionDenied,
+	errors.ErrorTypeRateLimited:    codes.ResourceExhausted,
+	errors.ErrorTypeUnavailable:    codes.Unavailable,
+	errors.ErrorTypeTimeout:        codes.DeadlineExceeded,
+	errors.ErrorTypeInternal:       codes.Internal,
+}
+
+// statusError converts a service error into a gRPC status. Errors of a known type other
+// than internal keep their message. Other errors are logged and reported with errMsg, like
+// the REST handlers do.
+func (s *Server) statusError(err error, errMsg string) error {
+	var noAcceptable *order_calculations.NoAcceptablePackingError
+	if stderrors.As(err, &noAcceptable) {
+		return status.Error(codes.FailedPrecondition, noAcceptable.Error())
+	}
+	if stderrors.Is(err, order_calculations.ErrCalculationNotFound) || stderrors.Is(err, pack_configurations.ErrConfigurationNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	var appErr *errors.Error
+	if stderrors.As(err, &appErr) {
+		if code := errorCodes[appErr.Type]; code != codes.Internal && code != codes.OK {
+			return status.Error(code, appErr.Message)
+		}
+	}
+
+	s.logger.Error(errMsg, zap.Error(err))
+	return status.Error(codes.Internal, errMsg)
+}
@@ -0,0 +1,236 @@
+// Package grpcserver serves the PackCalculator gRPC API. It validates requests like the
+// REST middleware and calls the same services as the REST handlers.
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/pack-calculator/api/middleware"
+	packcalculatorv1 "github.com/pack-calculator/api/proto/packcalculator/v1"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/pkg/postgres"
+)
+
+// MaxBatchSize is the largest number of orders in a batch calculation
+const MaxBatchSize = 100
+
+// requestIDMetadata carries the ID of a call, like the X-Request-ID header of the REST API
+const requestIDMetadata = "x-request-id"
+
+// Server implements the PackCalculator gRPC service
+type Server struct {
+	packcalculatorv1.UnimplementedPackCalculatorServer
+	logger       *zap.Logger
+	calculations order_calculations.Service
+	packs        pack_configurations.Service
+}
+
+func NewServer(logger *zap.Logger, calculations order_calculations.Service, packs pack_configurations.Service) *Server {
+	return &Server{
+		logger:       logger,
+		calculations: calculations,
+		packs:        packs,
+	}
+}
+
+// New creates a gRPC server with request IDs, request logging, panic recovery, rate limiting,
+// authentication and the PackCalculator service registered. The rate limiter is shared with
+// the REST API.
+func New(logger *zap.Logger, server *Server, authenticator *middleware.Authenticator, rateLimiter *middleware.RateLimiter) *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(RequestSource(), Logger(logger), Recovery(logger), RateLimit(rateLimiter), Auth(authenticator)))
+	packcalculatorv1.RegisterPackCalculatorServer(grpcServer, server)
+	return grpcServer
+}
+
+// RequestSource returns an interceptor that keeps the request ID in the call metadata, or
+// generates one, returns it in the response header and puts it with the peer address in
+// the call context
+func RequestSource() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		requestID := firstValue(ctx, requestIDMetadata)
+		if !middleware.ValidRequestID(requestID) {
+			requestID = middleware.NewRequestID()
+		}
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+
+		source := audit_events.Source{RequestID: requestID}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			source.ClientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(source.ClientIP); err == nil {
+				source.ClientIP = host
+			}
+		}
+		return handler(audit_events.NewContext(ctx, source), req)
+	}
+}
+
+// Logger returns an interceptor that logs the method, status code and duration of each call
+func Logger(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log.Info("gRPC request",
+			zap.String("method", info.FullMethod),
+			zap.String("request_id", audit_events.SourceFrom(ctx).RequestID),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return resp, err
+	}
+}
+
+// Recovery returns an interceptor that turns a panic in a call into an Internal status, so
+// one failing call does not take down the server
+func Recovery(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("gRPC call panicked",
+					zap.String("method", info.FullMethod),
+					zap.String("request_id", audit_events.SourceFrom(ctx).RequestID),
+					zap.Any("panic", r),
+					zap.Stack("stack"),
+				)
+				resp, err = nil, status.Error(codes.Internal, "Internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RateLimit returns an interceptor that limits the calls of each client IP with the limiter
+func RateLimit(limiter *middleware.RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !limiter.Allow(audit_events.SourceFrom(ctx).ClientIP) {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Calculate calculates the optimal packs for an order
+func (s *Server) Calculate(ctx context.Context, req *packcalculatorv1.CalculateRequest) (*packcalculatorv1.CalculateResponse, error) {
+	return s.calculate(ctx, req)
+}
+
+// CalculateBatch calculates each order of the batch, reporting failures per order
+func (s *Server) CalculateBatch(ctx context.Context, req *packcalculatorv1.CalculateBatchRequest) (*packcalculatorv1.CalculateBatchResponse, error) {
+	if len(req.GetOrders()) == 0 || len(req.GetOrders()) > MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "A batch must hold between 1 and %d orders", MaxBatchSize)
+	}
+
+	response := &packcalculatorv1.CalculateBatchResponse{Results: make([]*packcalculatorv1.CalculateResult, 0, len(req.GetOrders()))}
+	for _, order := range req.GetOrders() {
+		calculated, err := s.calculate(ctx, order)
+		if err != nil {
+			st := status.Convert(err)
+			response.Results = append(response.Results, &packcalculatorv1.CalculateResult{
+				Result: &packcalculatorv1.CalculateResult_Error{Error: &packcalculatorv1.Error{Code: int32(st.Code()), Message: st.Message()}},
+			})
+			continue
+		}
+		response.Results = append(response.Results, &packcalculatorv1.CalculateResult{
+			Result: &packcalculatorv1.CalculateResult_Response{Response: calculated},
+		})
+	}
+	return response, nil
+}
+
+func (s *Server) calculate(ctx context.Context, req *packcalculatorv1.CalculateRequest) (*packcalculatorv1.CalculateResponse, error) {
+	request := fromCalculateRequest(req)
+	if err := middleware.ValidateCalculateRequest(&request); err != nil {
+		return nil, s.statusError(err, "")
+	}
+
+	calc, err := s.calculations.OrderProcessing(ctx, order_calculations.OrderRequest{
+		OrderQuantity:   request.OrderQuantity,
+		MinQuantity:     request.MinQuantity,
+		MaxQuantity:     request.MaxQuantity,
+		Overfill:        request.Overfill,
+		Inventory:       request.Inventory,
+		CartonObjective: request.CartonObjective,
+		Sourcing:        request.Sourcing,
+		Reserve:         request.Reserve,
+		ReservationTTL:  time.Duration(request.ReservationTTL) * time.Second,
+		OrderReference:  request.OrderReference,
+		CustomerID:      request.CustomerID,
+		TieBreak:        request.TieBreak,
+	})
+	if err != nil {
+		return nil, s.statusError(err, "Failed to process order request")
+	}
+	return toCalculateResponse(&request, calc), nil
+}
+
+// GetActivePackConfiguration returns the active pack configuration
+func (s *Server) GetActivePackConfiguration(ctx context.Context, req *packcalculatorv1.GetActivePackConfigurationRequest) (*packcalculatorv1.PackConfiguration, error) {
+	packCfg, err := s.packs.GetActive(ctx)
+	if err != nil {
+		return nil, s.statusError(err, "Failed to retrieve pack configuration")
+	}
+	return toPackConfiguration(packCfg), nil
+}
+
+// CreatePackConfiguration creates a pack configuration and makes it the active one. An
+// identical configuration that already exists is activated and returned instead.
+func (s *Server) CreatePackConfiguration(ctx context.Context, req *packcalculatorv1.CreatePackConfigurationRequest) (*packcalculatorv1.PackConfiguration, error) {
+	request := fromCreatePackConfigurationRequest(req)
+	if err := middleware.ValidatePackConfiguration(&request); err != nil {
+		return nil, s.statusError(err, "")
+	}
+
+	packCfg, err := s.packs.Create(ctx, &pack_configurations.PackConfiguration{
+		PackSizes: postgres.IntSliceToPqArray(request.PackSizes),
+		Nesting:   request.Nesting,
+		PackSpecs: request.PackSpecs,
+		Cartons:   request.Cartons,
+		Rules:     request.Rules,
+	})
+	if err != nil {
+		return nil, s.statusError(err, "Failed to create pack configuration")
+	}
+	return toPackConfiguration(packCfg), nil
+}
+
+// ListPackConfigurations returns every pack configuration in the order they were created
+func (s *Server) ListPackConfigurations(ctx context.Context, req *packcalculatorv1.ListPackConfigurationsRequest) (*packcalculatorv1.ListPackConfigurationsResponse, error) {
+	configs, err := s.packs.List(ctx)
+	if err != nil {
+		return nil, s.statusError(err, "Failed to list pack configurations")
+	}
+
+	response := &packcalculatorv1.ListPackConfigurationsResponse{Configurations: make([]*packcalculatorv1.PackConfiguration, 0, len(configs))}
+	for i := range configs {
+		response.Configurations = append(response.Configurations, toPackConfiguration(&configs[i]))
+	}
+	return response, nil
+}
+
+// GetPackConfiguration returns a pack configuration by ID
+func (s *Server) GetPackConfiguration(ctx context.Context, req *packcalculatorv1.GetPackConfigurationRequest) (*packcalculatorv1.PackConfiguration, error) {
+	packCfg, err := s.packs.Get(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, s.statusError(err, "Failed to retrieve pack configuration")
+	}
+	return toPackConfiguration(packCfg), nil
+}
+
+// ActivatePackConfiguration makes an existing pack configuration the active one
+func (s *Server) ActivatePackConfiguration(ctx context.Context, req *packcalculatorv1.ActivatePackConfigurationRequest) (*packcalculatorv1.PackConfiguration, error) {
+	packCfg, err := s.packs.Activate(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, s.statusError(err, "Failed to activate pack configuration")
+	}
+	return toPackConfiguration(packCfg), nil
+}
@@ -0,0 +1,445 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/pack-calculator/api/middleware"
+	packcalculatorv1 "github.com/pack-calculator/api/proto/packcalculator/v1"
+	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+// MockCalculationService is a mock implementation of order_calculations.Service
+type MockCalculationService struct {
+	mock.Mock
+}
+
+func (m *MockCalculationService) OrderProcessing(ctx context.Context, order order_calculations.OrderRequest) (*order_calculations.OrderCalculation, error) {
+	args := m.Called(ctx, order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*order_calculations.OrderCalculation), args.Error(1)
+}
+
+func (m *MockCalculationService) CalculateOptimalPacks(ctx context.Context, orderQuantity int, packSizes []int) (map[int]int, error) {
+	args := m.Called(ctx, orderQuantity, packSizes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *MockCalculationService) AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*order_calculations.Amendment, error) {
+	args := m.Called(ctx, calculationID, orderQuantity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*order_calculations.Amendment), args.Error(1)
+}
+
+func (m *MockCalculationService) ListCalculations(ctx context.Context, offset, limit int) ([]order_calculations.OrderCalculation, error) {
+	args := m.Called(ctx, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]order_calculations.OrderCalculation), args.Error(1)
+}
+
+func (m *MockCalculationService) GetCalculation(ctx context.Context, id uint) (*order_calculations.OrderCalculation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*order_calculations.OrderCalculation), args.Error(1)
+}
+
+// MockPackService is a mock implementation of pack_configurations.Service
+type MockPackService struct {
+	mock.Mock
+}
+
+func (m *MockPackService) Create(ctx context.Context, config *pack_configurations.PackConfiguration) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, config)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackService) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackService) List(ctx context.Context) ([]pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackService) Get(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackService) Activate(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+// newTestClient serves the services over an in-memory connection without authentication
+func newTestClient(t *testing.T, calculations order_calculations.Service, packs pack_configurations.Service) packcalculatorv1.PackCalculatorClient {
+	return newAuthTestClient(t, calculations, packs, middleware.NewAuthenticator(config.AuthConfig{}, nil, nil))
+}
+
+// newRateLimitedTestClient serves the services over an in-memory connection with the rate limiter
+func newRateLimitedTestClient(t *testing.T, packs pack_configurations.Service, rateLimiter *middleware.RateLimiter) packcalculatorv1.PackCalculatorClient {
+	return newClient(t, New(zap.NewNop(), NewServer(zap.NewNop(), new(MockCalculationService), packs), middleware.NewAuthenticator(config.AuthConfig{}, nil, nil), rateLimiter))
+}
+
+// newAuthTestClient serves the services over an in-memory connection with the authenticator
+func newAuthTestClient(t *testing.T, calculations order_calculations.Service, packs pack_configurations.Service, authenticator *middleware.Authenticator) packcalculatorv1.PackCalculatorClient {
+	return newClient(t, New(zap.NewNop(), NewServer(zap.NewNop(), calculations, packs), authenticator, middleware.NewRateLimiter(config.RateLimiterConfig{})))
+}
+
+// newClient serves the gRPC server over an in-memory connection
+func newClient(t *testing.T, grpcServer *grpc.Server) packcalculatorv1.PackCalculatorClient {
+	listener := bufconn.Listen(1024 * 1024)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return packcalculatorv1.NewPackCalculatorClient(conn)
+}
+
+func TestServer_Calculate(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 18, 9, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		request   *packcalculatorv1.CalculateRequest
+		mockSetup func(*MockCalculationService)
+		want      *packcalculatorv1.CalculateResponse
+		wantCode  codes.Code
+		wantMsg   string
+	}{
+		{
+			name: "success case",
+			request: &packcalculatorv1.CalculateRequest{
+				OrderQuantity:         501,
+				Sourcing:              true,
+				Reserve:               true,
+				ReservationTtlSeconds: 60,
+				TieBreak:              &packcalculatorv1.TieBreakPolicy{Policy: order_calculations.TieBreakFewerSizes},
+			},
+			mockSetup: func(m *MockCalculationService) {
+				m.On("OrderProcessing", mock.Anything, order_calculations.OrderRequest{
+					OrderQuantity:  501,
+					MinQuantity:    501,
+					Sourcing:       true,
+					Reserve:        true,
+					ReservationTTL: time.Minute,
+					TieBreak:       &order_calculations.TieBreakPolicy{Policy: order_calculations.TieBreakFewerSizes},
+				}).Return(&order_calculations.OrderCalculation{
+					ID:         7,
+					Result:     []order_calculations.PackResult{{Size: 500, Quantity: 1}, {Size: 250, Quantity: 1}},
+					TotalItems: 750,
+					TotalPacks: 2,
+					Reservation: &reservations.Reservation{
+						ID:        3,
+						Status:    reservations.StatusActive,
+						Items:     []reservations.ReservationItem{{WarehouseID: 1, Size: 500, Quantity: 1}},
+						ExpiresAt: expiresAt,
+					},
+				}, nil)
+			},
+			want: &packcalculatorv1.CalculateResponse{
+				CalculationId: 7,
+				OrderQuantity: 501,
+				MinQuantity:   501,
+				TotalItems:    750,
+				TotalPacks:    2,
+				Packs:         []*packcalculatorv1.Pack{{Size: 500, Quantity: 1}, {Size: 250, Quantity: 1}},
+				Reservation: &packcalculatorv1.Reservation{
+					Id:     3,
+					Status: reservations.StatusActive,
+					Items:  []*packcalculatorv1.ReservationItem{{WarehouseId: 1, Size: 500, Quantity: 1}},
+				},
+			},
+		},
+		{
+			name:      "invalid request",
+			request:   &packcalculatorv1.CalculateRequest{OrderQuantity: 10, MaxQuantity: 5},
+			mockSetup: func(m *MockCalculationService) {},
+			wantCode:  codes.InvalidArgument,
+			wantMsg:   "Maximum quantity must not be below minimum quantity",
+		},
+		{
+			name:    "validation error from the service",
+			request: &packcalculatorv1.CalculateRequest{OrderQuantity: 10, CustomerId: "nobody"},
+			mockSetup: func(m *MockCalculationService) {
+				m.On("OrderProcessing", mock.Anything, mock.Anything).Return(nil, apperrors.NewValidationError(`Unknown customer "nobody"`))
+			},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  `Unknown customer "nobody"`,
+		},
+		{
+			name:    "no acceptable packing",
+			request: &packcalculatorv1.CalculateRequest{OrderQuantity: 2600, Overfill: &packcalculatorv1.OverfillPolicy{MaxItems: 260}},
+			mockSetup: func(m *MockCalculationService) {
+				m.On("OrderProcessing", mock.Anything, mock.Anything).Return(nil, &order_calculations.NoAcceptablePackingError{OrderQuantity: 2600, MaxOverfill: 260})
+			},
+			wantCode: codes.FailedPrecondition,
+			wantMsg:  "no acceptable packing for 2600 items within an overfill of 260",
+		},
+		{
+			name:    "service error",
+			request: &packcalculatorv1.CalculateRequest{OrderQuantity: 10},
+			mockSetup: func(m *MockCalculationService) {
+				m.On("OrderProcessing", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			wantCode: codes.Internal,
+			wantMsg:  "Failed to process order request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCalculationService)
+			tt.mockSetup(mockService)
+			client := newTestClient(t, mockService, new(MockPackService))
+
+			got, err := client.Calculate(context.Background(), tt.request)
+
+			mockService.AssertExpectations(t)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.Equal(t, tt.wantMsg, status.Convert(err).Message())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, expiresAt, got.GetReservation().GetExpiresAt().AsTime())
+			got.Reservation.ExpiresAt = nil
+			assert.Equal(t, tt.want.String(), got.String())
+		})
+	}
+}
+
+func TestServer_CalculateBatch(t *testing.T) {
+	t.Run("reports results per order", func(t *testing.T) {
+		mockService := new(MockCalculationService)
+		mockService.On("OrderProcessing", mock.Anything, order_calculations.OrderRequest{OrderQuantity: 1, MinQuantity: 1}).
+			Return(&order_calculations.OrderCalculation{ID: 1, Result: []order_calculations.PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1}, nil)
+		mockService.On("OrderProcessing", mock.Anything, order_calculations.OrderRequest{OrderQuantity: 2, MinQuantity: 2}).
+			Return(nil, errors.New("connection refused"))
+		client := newTestClient(t, mockService, new(MockPackService))
+
+		got, err := client.CalculateBatch(context.Background(), &packcalculatorv1.CalculateBatchRequest{Orders: []*packcalculatorv1.CalculateRequest{
+			{OrderQuantity: 1},
+			{OrderQuantity: -1},
+			{OrderQuantity: 2},
+		}})
+
+		assert.NoError(t, err)
+		assert.Len(t, got.GetResults(), 3)
+		assert.Equal(t, int64(250), got.GetResults()[0].GetResponse().GetTotalItems())
+		assert.Equal(t, int32(codes.InvalidArgument), got.GetResults()[1].GetError().GetCode())
+		assert.Equal(t, "Order quantity must be a positive integer", got.GetResults()[1].GetError().GetMessage())
+		assert.Equal(t, int32(codes.Internal), got.GetResults()[2].GetError().GetCode())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		client := newTestClient(t, new(MockCalculationService), new(MockPackService))
+
+		_, err := client.CalculateBatch(context.Background(), &packcalculatorv1.CalculateBatchRequest{})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("batch too large", func(t *testing.T) {
+		client := newTestClient(t, new(MockCalculationService), new(MockPackService))
+		orders := make([]*packcalculatorv1.CalculateRequest, MaxBatchSize+1)
+		for i := range orders {
+			orders[i] = &packcalculatorv1.CalculateRequest{OrderQuantity: 1}
+		}
+
+		_, err := client.CalculateBatch(context.Background(), &packcalculatorv1.CalculateBatchRequest{Orders: orders})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_CreatePackConfiguration(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   *packcalculatorv1.CreatePackConfigurationRequest
+		mockSetup func(*MockPackService)
+		want      *packcalculatorv1.PackConfiguration
+		wantCode  codes.Code
+	}{
+		{
+			name:    "success case",
+			request: &packcalculatorv1.CreatePackConfigurationRequest{PackSizes: []int64{250, 500}, Rules: []string{"at most 2 x 250"}},
+			mockSetup: func(m *MockPackService) {
+				m.On("Create", mock.Anything, &pack_configurations.PackConfiguration{PackSizes: pq.Int64Array{250, 500}, Rules: []string{"at most 2 x 250"}}).
+					Return(&pack_configurations.PackConfiguration{ID: 4, Active: true, PackSizes: pq.Int64Array{250, 500}, Rules: []string{"at most 2 x 250"}}, nil)
+			},
+			want: &packcalculatorv1.PackConfiguration{Id: 4, Active: true, PackSizes: []int64{250, 500}, Rules: []string{"at most 2 x 250"}},
+		},
+		{
+			name:      "duplicate pack sizes",
+			request:   &packcalculatorv1.CreatePackConfigurationRequest{PackSizes: []int64{250, 250}},
+			mockSetup: func(m *MockPackService) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:    "service error",
+			request: &packcalculatorv1.CreatePackConfigurationRequest{PackSizes: []int64{250}},
+			mockSetup: func(m *MockPackService) {
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPackService)
+			tt.mockSetup(mockService)
+			client := newTestClient(t, new(MockCalculationService), mockService)
+
+			got, err := client.CreatePackConfiguration(context.Background(), tt.request)
+
+			mockService.AssertExpectations(t)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.String(), got.String())
+		})
+	}
+}
+
+func TestServer_PackConfigurations(t *testing.T) {
+	ctx := context.Background()
+	mockService := new(MockPackService)
+	mockService.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{ID: 2, Active: true, PackSizes: pq.Int64Array{23, 31, 53}}, nil)
+	mockService.On("List", mock.Anything).Return([]pack_configurations.PackConfiguration{
+		{ID: 1, PackSizes: pq.Int64Array{250, 500}},
+		{ID: 2, Active: true, PackSizes: pq.Int64Array{23, 31, 53}},
+	}, nil)
+	mockService.On("Get", mock.Anything, uint(1)).Return(&pack_configurations.PackConfiguration{ID: 1, PackSizes: pq.Int64Array{250, 500}}, nil)
+	mockService.On("Get", mock.Anything, uint(9)).Return(nil, pack_configurations.ErrConfigurationNotFound)
+	mockService.On("Activate", mock.Anything, uint(1)).Return(&pack_configurations.PackConfiguration{ID: 1, Active: true, PackSizes: pq.Int64Array{250, 500}}, nil)
+	client := newTestClient(t, new(MockCalculationService), mockService)
+
+	active, err := client.GetActivePackConfiguration(ctx, &packcalculatorv1.GetActivePackConfigurationRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{23, 31, 53}, active.GetPackSizes())
+
+	list, err := client.ListPackConfigurations(ctx, &packcalculatorv1.ListPackConfigurationsRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, list.GetConfigurations(), 2)
+	assert.True(t, list.GetConfigurations()[1].GetActive())
+
+	got, err := client.GetPackConfiguration(ctx, &packcalculatorv1.GetPackConfigurationRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{250, 500}, got.GetPackSizes())
+
+	_, err = client.GetPackConfiguration(ctx, &packcalculatorv1.GetPackConfigurationRequest{Id: 9})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	activated, err := client.ActivatePackConfiguration(ctx, &packcalculatorv1.ActivatePackConfigurationRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.True(t, activated.GetActive())
+	mockService.AssertExpectations(t)
+}
+
+func TestRequestSource(t *testing.T) {
+	mockService := new(MockPackService)
+	mockService.On("Activate", mock.MatchedBy(func(ctx context.Context) bool {
+		return audit_events.SourceFrom(ctx).RequestID == "checkout-42"
+	}), uint(1)).Return(&pack_configurations.PackConfiguration{ID: 1, Active: true, PackSizes: pq.Int64Array{250, 500}}, nil)
+	mockService.On("Activate", mock.Anything, uint(2)).Return(&pack_configurations.PackConfiguration{ID: 2, Active: true, PackSizes: pq.Int64Array{250, 500}}, nil)
+	client := newTestClient(t, new(MockCalculationService), mockService)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDMetadata, "checkout-42")
+	_, err := client.ActivatePackConfiguration(ctx, &packcalculatorv1.ActivatePackConfigurationRequest{Id: 1}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"checkout-42"}, header.Get(requestIDMetadata))
+
+	// Calls without an ID get a generated one
+	_, err = client.ActivatePackConfiguration(context.Background(), &packcalculatorv1.ActivatePackConfigurationRequest{Id: 2}, grpc.Header(&header))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, header.Get(requestIDMetadata)[0])
+	mockService.AssertExpectations(t)
+}
+
+func TestRateLimit(t *testing.T) {
+	mockService := new(MockPackService)
+	mockService.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{ID: 2, Active: true, PackSizes: pq.Int64Array{23, 31, 53}}, nil).Once()
+	client := newRateLimitedTestClient(t, mockService, middleware.NewRateLimiter(config.RateLimiterConfig{Enabled: true, MaxRequests: 1}))
+
+	_, err := client.GetActivePackConfiguration(context.Background(), &packcalculatorv1.GetActivePackConfigurationRequest{})
+	assert.NoError(t, err)
+
+	_, err = client.GetActivePackConfiguration(context.Background(), &packcalculatorv1.GetActivePackConfigurationRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	mockService.AssertExpectations(t)
+}
+
+func TestRecovery(t *testing.T) {
+	calculations := new(MockCalculationService)
+	calculations.On("OrderProcessing", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		panic("nil configuration")
+	})
+	packs := new(MockPackService)
+	packs.On("GetActive", mock.Anything).Return(nil, pack_configurations.ErrConfigurationNotFound)
+	client := newTestClient(t, calculations, packs)
+
+	_, err := client.Calculate(context.Background(), &packcalculatorv1.CalculateRequest{OrderQuantity: 501})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// The server keeps serving after a call panicked
+	_, err = client.GetActivePackConfiguration(context.Background(), &packcalculatorv1.GetActivePackConfigurationRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/errors"
+)
+
+// APIKeyHeader carries the API key of a request
+const APIKeyHeader = "X-API-Key"
+
+// BootstrapSubject identifies requests made with the bootstrap key
+const BootstrapSubject = "bootstrap"
+
+// Authenticator identifies the caller of a request by its bearer token or API key and
+// checks the scope each route requires. With authentication disabled every request is
+// allowed. Bearer tokens are rejected when tokens is nil.
+type Authenticator struct {
+	enabled       bool
+	bootstrapHash string
+	keys          api_keys.Service
+	tokens        *auth.TokenVerifier
+}
+
+func NewAuthenticator(cfg config.AuthConfig, keys api_keys.Service, tokens *auth.TokenVerifier) *Authenticator {
+	a := &Authenticator{
+		enabled: cfg.Enabled,
+		keys:    keys,
+		tokens:  tokens,
+	}
+	if cfg.BootstrapKey != "" {
+		a.bootstrapHash = api_keys.HashKey(cfg.BootstrapKey)
+	}
+	return a
+}
+
+// BearerToken returns the token of an Authorization header using the Bearer scheme, or
+// an empty string
+func BearerToken(authorization string) string {
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Authenticate returns the principal of a bearer token, or else of an API key. It returns
+// nil without credentials or when authentication is disabled. Invalid tokens and unknown
+// or revoked keys are unauthorized errors.
+func (a *Authenticator) Authenticate(ctx context.Context, key, token string) (*auth.Principal, error) {
+	if !a.enabled {
+		return nil, nil
+	}
+	if token != "" {
+		return a.authenticateToken(ctx, token)
+	}
+	if key == "" {
+		return nil, nil
+	}
+	if a.bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(api_keys.HashKey(key)), []byte(a.bootstrapHash)) == 1 {
+		return &auth.Principal{Subject: BootstrapSubject, Name: "bootstrap key", Scopes: []string{auth.ScopeAdmin}}, nil
+	}
+
+	principal, err := a.keys.Authenticate(ctx, key)
+	if stderrors.Is(err, api_keys.ErrInvalidKey) {
+		return nil, errors.NewUnauthorizedError("Invalid API key")
+	}
+	if err != nil {
+		return nil, errors.Wrap("Failed to authenticate the API key", err)
+	}
+	return principal, nil
+}
+
+func (a *Authenticator) authenticateToken(ctx context.Context, token string) (*auth.Principal, error) {
+	if a.tokens == nil {
+		return nil, errors.NewUnauthorizedError("Bearer tokens are not accepted, use an API key")
+	}
+	principal, err := a.tokens.Verify(ctx, token)
+	if stderrors.Is(err, auth.ErrInvalidToken) {
+		return nil, errors.NewError(errors.ErrorTypeUnauthorized, "Invalid bearer token", err)
+	}
+	if err != nil {
+		return nil, errors.NewUnavailableErrorWrap("Failed to load the token signing keys", err)
+	}
+	return principal, nil
+}
+
+// Authorize checks that the principal was granted the scope
+func (a *Authenticator) Authorize(principal *auth.Principal, scope string) error {
+	if !a.enabled {
+		return nil
+	}
+	if principal == nil {
+		return errors.NewUnauthorizedError("Authentication required")
+	}
+	if !principal.HasScope(scope) {
+		return errors.NewForbiddenError(fmt.Sprintf("The %s scope is required", scope))
+	}
+	return nil
+}
+
+// Middleware authenticates the bearer token or API key of the request and puts its
+// principal in the request context. Requests without credentials continue anonymously.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.Authenticate(c.Request.Context(), c.GetHeader(APIKeyHeader), BearerToken(c.GetHeader("Authorization")))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if principal != nil {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+		}
+		c.Next()
+	}
+}
+
+// Require rejects requests whose principal was not granted the scope
+func (a *Authenticator) Require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := a.Authorize(auth.FromContext(c.Request.Context()), scope); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
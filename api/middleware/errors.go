@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/pkg/errors"
+)
+
+// ErrorFormat selects how RenderErrors writes error responses
+type ErrorFormat int
+
+const (
+	// LegacyErrors writes the errors.Error as JSON with its Type, Message and Err fields
+	LegacyErrors ErrorFormat = iota
+	// ProblemDetails writes RFC 7807 problem details
+	ProblemDetails
+)
+
+// RenderErrors returns a middleware that writes the response for the last error added with
+// c.Error, unless a response was already written. The status follows from the error type
+// and errors without a type are internal errors. Server errors are logged.
+func RenderErrors(log *zap.Logger, format ErrorFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		appErr := errors.Wrap("Internal server error", err)
+		status := errors.HTTPStatus(appErr.Type)
+		if status >= http.StatusInternalServerError {
+			log.Error(appErr.Message, zap.Error(err), zap.String("path", c.Request.URL.Path))
+		}
+
+		if format == ProblemDetails {
+			problem := errors.NewProblem(appErr.Type, appErr.Message)
+			problem.Instance = c.Request.URL.Path
+			if status < http.StatusInternalServerError {
+				problem.Details = problemDetails(appErr)
+			}
+			c.Header("Content-Type", errors.ProblemContentType)
+			c.JSON(status, problem)
+			return
+		}
+		// Server errors only keep their message, so the cause is never serialised
+		if status >= http.StatusInternalServerError {
+			appErr = errors.NewError(appErr.Type, appErr.Message, stderrors.New(appErr.Message))
+		}
+		c.JSON(status, appErr)
+	}
+}
+
+// problemDetails returns the JSON form of a structured error wrapped by appErr, or nil when
+// there is none. Only the closest packings of a NoAcceptablePackingError are serialised, so
+// the fields of other errors, such as those of database drivers, never reach clients.
+func problemDetails(appErr *errors.Error) json.RawMessage {
+	var noAcceptable *order_calculations.NoAcceptablePackingError
+	if !stderrors.As(appErr.Err, &noAcceptable) {
+		return nil
+	}
+	details, err := json.Marshal(noAcceptable)
+	if err != nil {
+		return nil
+	}
+	return details
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/auth"
 )
 
 // responseWriter captures the status code and body of the response
@@ -68,6 +71,8 @@ func Logger(log *zap.Logger) gin.HandlerFunc {
 			zap.String("path", c.Request.URL.Path),
 			zap.String("query", c.Request.URL.RawQuery),
 			zap.String("client_ip", c.ClientIP()),
+			zap.String("request_id", audit_events.SourceFrom(c.Request.Context()).RequestID),
+			zap.String("actor", auth.Subject(c.Request.Context())),
 			zap.Int("status_code", w.statusCode),
 			zap.Duration("duration", duration),
 		}
@@ -77,9 +82,9 @@ func Logger(log *zap.Logger) gin.HandlerFunc {
 			fields = append(fields, zap.String("request_body", string(requestBody)))
 		}
 
-		// Add response body if present and not too large
+		// Add response body if present, not too large and not a secret that must not be stored
 		responseBody := w.body.String()
-		if len(responseBody) > 0 && len(responseBody) < 1024*10 { // Limit to 10KB
+		if len(responseBody) > 0 && len(responseBody) < 1024*10 && w.Header().Get("Cache-Control") != "no-store" { // Limit to 10KB
 			fields = append(fields, zap.String("response_body", responseBody))
 		}
 
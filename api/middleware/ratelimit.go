@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"net/http"
 	"sync"
 	"time"
 
@@ -55,38 +54,43 @@ func (r *RateLimiter) cleanup() {
 	}
 }
 
-func (r *RateLimiter) Middleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if !r.enabled {
-			c.Next()
-			return
-		}
-
-		ip := c.ClientIP()
+// Allow reports whether a request from the client key fits in the limit of the last
+// second, counting it when it does. It always allows requests when the limiter is disabled.
+func (r *RateLimiter) Allow(key string) bool {
+	if !r.enabled {
+		return true
+	}
 
-		r.mutex.Lock()
-		now := time.Now()
-		times := r.requests[ip]
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	times := r.requests[key]
 
-		// Remove requests older than 1 second
-		var valid []time.Time
-		for _, t := range times {
-			if now.Sub(t) < time.Second {
-				valid = append(valid, t)
-			}
+	// Remove requests older than 1 second
+	var valid []time.Time
+	for _, t := range times {
+		if now.Sub(t) < time.Second {
+			valid = append(valid, t)
 		}
+	}
 
-		if len(valid) >= r.maxRequests {
-			r.mutex.Unlock()
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, errors.NewValidationError("rate limit exceeded"))
-			return
-		}
+	if len(valid) >= r.maxRequests {
+		return false
+	}
 
-		// Add current request
-		valid = append(valid, now)
-		r.requests[ip] = valid
-		r.mutex.Unlock()
+	// Add current request
+	r.requests[key] = append(valid, now)
+	return true
+}
 
+// Middleware limits the requests of each client IP
+func (r *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.Allow(c.ClientIP()) {
+			c.Error(errors.NewRateLimitedError("rate limit exceeded"))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
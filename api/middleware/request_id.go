@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/pack-calculator/internal/audit_events"
+)
+
+// RequestIDHeader carries the ID of a request, given by the client or generated
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID returns a middleware that keeps the request ID sent by the client, or generates
+// one, echoes it in the response and puts it with the client IP in the request context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !ValidRequestID(requestID) {
+			requestID = NewRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := audit_events.NewContext(c.Request.Context(), audit_events.Source{
+			RequestID: requestID,
+			ClientIP:  c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// ValidRequestID reports whether a client supplied request ID is printable ASCII of a
+// bounded length, so it can be logged and stored as is
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRequestID generates a random request ID
+func NewRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// TenantHeader selects the tenant a request acts for
+const TenantHeader = "X-Tenant-ID"
+
+// ResolveTenant returns the tenant a request acts for. A principal bound to a tenant
+// always acts for it. Admins, and every request while authentication is disabled, act
+// for the requested tenant; other principals only for the default tenant. Without a
+// request the default tenant is used.
+func (a *Authenticator) ResolveTenant(principal *auth.Principal, requested string) (string, error) {
+	if requested != "" && !tenant.Valid(requested) {
+		return "", errors.NewValidationError("Invalid tenant ID, expected 1 to 64 lowercase letters, digits, dashes or underscores")
+	}
+	if a.enabled && principal != nil && principal.Tenant != "" {
+		if requested != "" && requested != principal.Tenant {
+			return "", errors.NewForbiddenError(fmt.Sprintf("The credentials are bound to the tenant %q", principal.Tenant))
+		}
+		return principal.Tenant, nil
+	}
+	if requested == "" {
+		return tenant.Default, nil
+	}
+	if a.enabled && requested != tenant.Default && (principal == nil || !principal.HasScope(auth.ScopeAdmin)) {
+		return "", errors.NewForbiddenError(fmt.Sprintf("The credentials are not bound to the tenant %q", requested))
+	}
+	return requested, nil
+}
+
+// Tenant puts the tenant the request acts for in the request context. It runs after
+// Middleware, so the principal of the request is known.
+func (a *Authenticator) Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, err := a.ResolveTenant(auth.FromContext(c.Request.Context()), c.GetHeader(TenantHeader))
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
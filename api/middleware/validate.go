@@ -1,13 +1,24 @@
 package middleware
 
 import (
-	"net/http"
+	"fmt"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
 	"github.com/pack-calculator/internal/order_calculations"
 	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/shipping_rates"
+	"github.com/pack-calculator/internal/stats"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/auth"
 	"github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/packsolver"
+	"github.com/pack-calculator/pkg/tenant"
 )
 
 // ValidateOrder validates the order calculation input
@@ -17,19 +28,112 @@ func ValidateOrder() gin.HandlerFunc {
 
 		// Decode JSON body
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Abort()
+			return
+		}
+
+		if err := ValidateCalculateRequest(&request); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		// Set orderCalc in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateCalculateRequest fills in the defaults of an order calculation request and
+// validates it, returning a validation error. The gRPC API shares it with ValidateOrder.
+func ValidateCalculateRequest(request *order_calculations.CalculateAPIRequest) error {
+	// orderQuantity is shorthand for a window starting at the order quantity,
+	// and minQuantity stands in for a missing orderQuantity
+	if request.MinQuantity == 0 {
+		request.MinQuantity = request.OrderQuantity
+	}
+	if request.OrderQuantity == 0 {
+		request.OrderQuantity = request.MinQuantity
+	}
+
+	// Validate orderQuantity is positive
+	if request.OrderQuantity <= 0 || request.MinQuantity <= 0 {
+		return errors.NewValidationError("Order quantity must be a positive integer")
+	}
+
+	// Validate the accepted range is well formed and contains the order quantity
+	if request.MaxQuantity < 0 || (request.MaxQuantity > 0 && request.MaxQuantity < request.MinQuantity) {
+		return errors.NewValidationError("Maximum quantity must not be below minimum quantity")
+	}
+	if request.OrderQuantity < request.MinQuantity || (request.MaxQuantity > 0 && request.OrderQuantity > request.MaxQuantity) {
+		return errors.NewValidationError("Order quantity must lie within the accepted range")
+	}
+
+	// Validate the overfill policy override
+	if request.Overfill != nil && (request.Overfill.MaxItems < 0 || request.Overfill.MaxPercent < 0) {
+		return errors.NewValidationError("Overfill caps must not be negative")
+	}
+
+	// Validate the carton planning objective
+	switch request.CartonObjective {
+	case "", order_calculations.CartonObjectiveCount, order_calculations.CartonObjectiveCost:
+	default:
+		return errors.NewValidationError("Carton objective must be count or cost")
+	}
+
+	// Validate the available inventory for partial fulfilment
+	for size, count := range request.Inventory {
+		if size <= 0 || count < 0 {
+			return errors.NewValidationError("Inventory must map positive pack sizes to non-negative counts")
+		}
+	}
+
+	// Validate the tie-break policy override
+	if request.TieBreak != nil {
+		if err := validateTieBreak(request.TieBreak); err != nil {
+			return err
+		}
+	}
+
+	// Warehouse sourcing takes the inventory from the warehouses
+	if request.Sourcing && request.Inventory != nil {
+		return errors.NewValidationError("Inventory cannot be combined with warehouse sourcing")
+	}
+
+	// Reservations hold packs in warehouse stock
+	if request.Reserve && !request.Sourcing {
+		return errors.NewValidationError("Reservations require warehouse sourcing")
+	}
+	if request.ReservationTTL < 0 {
+		return errors.NewValidationError("Reservation TTL must not be negative")
+	}
+
+	return nil
+}
+
+// ValidateAmendment validates the order amendment input
+func ValidateAmendment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request order_calculations.AmendAPIRequest
+
+		// Decode JSON body
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
 			c.Abort()
 			return
 		}
 
 		// Validate orderQuantity is positive
 		if request.OrderQuantity <= 0 {
-			c.JSON(http.StatusBadRequest, errors.NewValidationError("Order quantity must be a positive integer"))
+			c.Error(errors.NewValidationError("Order quantity must be a positive integer"))
 			c.Abort()
 			return
 		}
 
-		// Set orderCalc in context
+		// Set amendment in context
 		c.Set("payload", &request)
 
 		// Continue to next handler if validation passes
@@ -37,43 +141,381 @@ func ValidateOrder() gin.HandlerFunc {
 	}
 }
 
-// ValidatePacks validates the pack configuration input
-func ValidatePacks() gin.HandlerFunc {
+// ValidateQuote validates the shipping quote input
+func ValidateQuote() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var request pack_configurations.PackCfgAPIRequest
+		var request shipping_rates.QuoteAPIRequest
+
+		// Decode JSON body
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Abort()
+			return
+		}
+
+		// Validate orderQuantity is positive
+		if request.OrderQuantity <= 0 {
+			c.Error(errors.NewValidationError("Order quantity must be a positive integer"))
+			c.Abort()
+			return
+		}
+
+		// Validate the destination zone is given
+		if request.Zone == "" {
+			c.Error(errors.NewValidationError("Zone is required"))
+			c.Abort()
+			return
+		}
+
+		// Set quote in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateRates parses and validates an uploaded CSV rate table
+func ValidateRates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rates, err := shipping_rates.ParseRatesCSV(c.Request.Body)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		// Set rates in context
+		c.Set("payload", &shipping_rates.RatesAPIRequest{Rates: rates})
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateWarehouse validates the warehouse input
+func ValidateWarehouse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request warehouses.WarehouseAPIRequest
+
+		// Decode JSON body
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Abort()
+			return
+		}
+
+		// Validate the warehouse is named
+		if request.Name == "" {
+			c.Error(errors.NewValidationError("Warehouse name is required"))
+			c.Abort()
+			return
+		}
+
+		// Validate the inventory per pack size
+		if request.Inventory == nil {
+			request.Inventory = map[int]int{}
+		}
+		for size, count := range request.Inventory {
+			if size <= 0 || count < 0 {
+				c.Error(errors.NewValidationError("Inventory must map positive pack sizes to non-negative counts"))
+				c.Abort()
+				return
+			}
+		}
+
+		// Set warehouse in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateCalculationList validates the paging of a calculation listing
+func ValidateCalculationList() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request order_calculations.ListCalculationsAPIRequest
+
+		// Decode query parameters
+		if err := c.ShouldBindQuery(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid query parameters", err))
+			c.Abort()
+			return
+		}
+
+		// Validate the page, defaulting to the first page of the default size
+		if request.Offset < 0 || request.Limit < 0 || request.Limit > order_calculations.MaxCalculationsLimit {
+			c.Error(errors.NewValidationError(fmt.Sprintf("Offset must not be negative and limit must be between 1 and %d", order_calculations.MaxCalculationsLimit)))
+			c.Abort()
+			return
+		}
+		if request.Limit == 0 {
+			request.Limit = order_calculations.DefaultCalculationsLimit
+		}
+
+		// Set listing request in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateAuditList validates the filter and paging of an audit trail listing
+func ValidateAuditList() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request audit_events.ListEventsAPIRequest
+
+		// Decode query parameters
+		if err := c.ShouldBindQuery(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid query parameters", err))
+			c.Abort()
+			return
+		}
+
+		// Validate the page, defaulting to the first page of the default size
+		if request.Offset < 0 || request.Limit < 0 || request.Limit > audit_events.MaxEventsLimit {
+			c.Error(errors.NewValidationError(fmt.Sprintf("Offset must not be negative and limit must be between 1 and %d", audit_events.MaxEventsLimit)))
+			c.Abort()
+			return
+		}
+		if request.Limit == 0 {
+			request.Limit = audit_events.DefaultEventsLimit
+		}
+
+		// Validate the time range
+		if !request.From.IsZero() && !request.To.IsZero() && request.To.Before(request.From) {
+			c.Error(errors.NewValidationError("To time must not be before from time"))
+			c.Abort()
+			return
+		}
+
+		// Set listing request in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateStats validates the statistics query parameters
+func ValidateStats() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request stats.StatsAPIRequest
+
+		// Decode query parameters
+		if err := c.ShouldBindQuery(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid query parameters", err))
+			c.Abort()
+			return
+		}
+
+		// Validate the grouping period, defaulting to days
+		switch request.Period {
+		case "":
+			request.Period = stats.PeriodDay
+		case stats.PeriodDay, stats.PeriodWeek, stats.PeriodMonth:
+		default:
+			c.Error(errors.NewValidationError(fmt.Sprintf("Period must be %s, %s or %s", stats.PeriodDay, stats.PeriodWeek, stats.PeriodMonth)))
+			c.Abort()
+			return
+		}
+
+		// Validate the histogram bucket size, defaulting to single order quantities
+		if request.BucketSize < 0 {
+			c.Error(errors.NewValidationError("Bucket size must be greater than zero"))
+			c.Abort()
+			return
+		}
+		if request.BucketSize == 0 {
+			request.BucketSize = 1
+		}
+
+		// Validate the date range
+		if !request.From.IsZero() && !request.To.IsZero() && request.To.Before(request.From) {
+			c.Error(errors.NewValidationError("To date must not be before from date"))
+			c.Abort()
+			return
+		}
+
+		// Set statistics request in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateExperiment validates the experiment input
+func ValidateExperiment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request experiments.ExperimentAPIRequest
+
+		// Decode JSON body
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Abort()
+			return
+		}
+
+		// Validate the experiment is named and has a candidate configuration
+		if request.Name == "" {
+			c.Error(errors.NewValidationError("Experiment name is required"))
+			c.Abort()
+			return
+		}
+		if request.ConfigurationID == 0 {
+			c.Error(errors.NewValidationError("Candidate configuration ID is required"))
+			c.Abort()
+			return
+		}
+
+		// Validate the traffic share is a percentage
+		if request.TrafficShare <= 0 || request.TrafficShare > 100 {
+			c.Error(errors.NewValidationError("Traffic share must be greater than 0 and at most 100 percent"))
+			c.Abort()
+			return
+		}
+
+		// Set experiment in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateCustomerProfile validates the customer profile input
+func ValidateCustomerProfile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request customers.ProfileAPIRequest
 
 		// Decode JSON body
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
 			c.Abort()
 			return
 		}
 
-		// Validate packSizes is not empty
-		if len(request.PackSizes) == 0 {
-			c.JSON(http.StatusBadRequest, errors.NewValidationError("Pack sizes cannot be empty"))
+		if request.CustomerID == "" {
+			c.Error(errors.NewValidationError("Customer ID is required"))
 			c.Abort()
 			return
 		}
 
-		// Validate all numbers are positive
-		for _, size := range request.PackSizes {
+		// Validate the listed sizes are positive and not both extra and excluded
+		extra := make(map[int]bool, len(request.ExtraSizes))
+		for _, size := range request.ExtraSizes {
 			if size <= 0 {
-				c.JSON(http.StatusBadRequest, errors.NewValidationError("Pack sizes must be positive integers"))
+				c.Error(errors.NewValidationError(fmt.Sprintf("Extra pack size must be positive, got %d", size)))
+				c.Abort()
+				return
+			}
+			extra[size] = true
+		}
+		for _, size := range request.ExcludedSizes {
+			if size <= 0 {
+				c.Error(errors.NewValidationError(fmt.Sprintf("Excluded pack size must be positive, got %d", size)))
+				c.Abort()
+				return
+			}
+			if extra[size] {
+				c.Error(errors.NewValidationError(fmt.Sprintf("Pack size %d cannot be both extra and excluded", size)))
 				c.Abort()
 				return
 			}
 		}
 
-		// Check for duplicates
-		seen := make(map[int]bool)
-		for _, size := range request.PackSizes {
-			if seen[size] {
-				c.JSON(http.StatusBadRequest, errors.NewValidationError("Pack sizes must not contain duplicates"))
+		// Validate the size bounds
+		if request.MinSize < 0 || request.MaxSize < 0 {
+			c.Error(errors.NewValidationError("Minimum and maximum pack sizes cannot be negative"))
+			c.Abort()
+			return
+		}
+		if request.MaxSize > 0 && request.MinSize > request.MaxSize {
+			c.Error(errors.NewValidationError("Minimum pack size cannot exceed the maximum pack size"))
+			c.Abort()
+			return
+		}
+
+		// Set customer profile in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidateAPIKey validates the input for issuing an API key
+func ValidateAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request api_keys.IssueAPIRequest
+
+		// Decode JSON body
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Abort()
+			return
+		}
+
+		if request.Name == "" {
+			c.Error(errors.NewValidationError("API key name is required"))
+			c.Abort()
+			return
+		}
+
+		// Validate at least one known scope is granted, each once
+		if len(request.Scopes) == 0 {
+			c.Error(errors.NewValidationError("At least one scope is required"))
+			c.Abort()
+			return
+		}
+		seen := make(map[string]bool, len(request.Scopes))
+		for _, scope := range request.Scopes {
+			if !auth.ValidScope(scope) {
+				c.Error(errors.NewValidationError(fmt.Sprintf("Unknown scope %q, expected one of %s", scope, strings.Join(auth.Scopes, ", "))))
 				c.Abort()
 				return
 			}
-			seen[size] = true
+			if seen[scope] {
+				c.Error(errors.NewValidationError(fmt.Sprintf("Scope %q is listed more than once", scope)))
+				c.Abort()
+				return
+			}
+			seen[scope] = true
+		}
+
+		if request.Tenant != "" && !tenant.Valid(request.Tenant) {
+			c.Error(errors.NewValidationError("Tenant ID must be 1 to 64 lowercase letters, digits, dashes or underscores"))
+			c.Abort()
+			return
+		}
+
+		// Set API key request in context
+		c.Set("payload", &request)
+
+		// Continue to next handler if validation passes
+		c.Next()
+	}
+}
+
+// ValidatePacks validates the pack configuration input
+func ValidatePacks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request pack_configurations.PackCfgAPIRequest
+
+		// Decode JSON body
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.Error(errors.NewValidationErrorWrap("Invalid JSON format", err))
+			c.Abort()
+			return
+		}
+
+		if err := ValidatePackConfiguration(&request); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
 		}
 
 		// Set packCfg in context
@@ -83,3 +525,174 @@ func ValidatePacks() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ValidatePackConfiguration validates a pack configuration request, returning a
+// validation error. The gRPC API shares it with ValidatePacks.
+func ValidatePackConfiguration(request *pack_configurations.PackCfgAPIRequest) error {
+	// Validate packSizes is not empty
+	if len(request.PackSizes) == 0 {
+		return errors.NewValidationError("Pack sizes cannot be empty")
+	}
+
+	// Validate all numbers are positive
+	for _, size := range request.PackSizes {
+		if size <= 0 {
+			return errors.NewValidationError("Pack sizes must be positive integers")
+		}
+	}
+
+	// Check for duplicates
+	seen := make(map[int]bool)
+	for _, size := range request.PackSizes {
+		if seen[size] {
+			return errors.NewValidationError("Pack sizes must not contain duplicates")
+		}
+		seen[size] = true
+	}
+
+	// Validate nesting rules
+	if msg := validateNesting(request.Nesting); msg != "" {
+		return errors.NewValidationError(msg)
+	}
+
+	// Validate pack specs and the carton catalogue
+	if msg := validateCartons(request.PackSizes, request.PackSpecs, request.Cartons); msg != "" {
+		return errors.NewValidationError(msg)
+	}
+
+	// Validate pack rules
+	if msg := validateRules(request.PackSizes, request.Rules); msg != "" {
+		return errors.NewValidationError(msg)
+	}
+
+	return nil
+}
+
+// validateNesting checks that nesting rules form a well defined hierarchy,
+// returning a validation message or an empty string when the rules are valid
+func validateNesting(rules []pack_configurations.NestingRule) string {
+	contains := make(map[string]string)
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Unit == "" || rule.Unit == pack_configurations.NestingUnitPack {
+			return "Nesting units must be named and must not be called pack"
+		}
+		if rule.Capacity <= 0 || rule.Size < 0 {
+			return "Nesting capacities must be positive integers"
+		}
+		key := fmt.Sprintf("%s/%d", rule.Contains, rule.Size)
+		if seen[key] {
+			return "Nesting rules must not hold the same kind of unit twice"
+		}
+		seen[key] = true
+		contains[rule.Unit] = rule.Contains
+	}
+
+	for _, rule := range rules {
+		if rule.Contains != pack_configurations.NestingUnitPack {
+			if _, ok := contains[rule.Contains]; !ok {
+				return "Nesting rules must contain packs or another nesting unit"
+			}
+		}
+
+		// Follow the chain of contained units, which must end at packs
+		unit := rule.Unit
+		for steps := 0; unit != pack_configurations.NestingUnitPack; steps++ {
+			if steps > len(rules) {
+				return "Nesting rules must not be circular"
+			}
+			unit = contains[unit]
+		}
+	}
+
+	return ""
+}
+
+// validateCartons checks pack specs refer to configured sizes and carton types are well formed,
+// returning a validation message or an empty string when they are valid
+func validateCartons(packSizes []int, specs []pack_configurations.PackSpec, cartons []pack_configurations.CartonType) string {
+	sizes := make(map[int]bool, len(packSizes))
+	for _, size := range packSizes {
+		sizes[size] = true
+	}
+
+	specified := make(map[int]bool, len(specs))
+	for _, spec := range specs {
+		if !sizes[spec.Size] || specified[spec.Size] {
+			return "Pack specs must refer to each configured pack size at most once"
+		}
+		if spec.Volume < 0 || spec.Weight < 0 {
+			return "Pack volume and weight must not be negative"
+		}
+		specified[spec.Size] = true
+	}
+
+	names := make(map[string]bool, len(cartons))
+	for _, carton := range cartons {
+		if carton.Name == "" || names[carton.Name] {
+			return "Carton types must have unique names"
+		}
+		if carton.MaxVolume < 0 || carton.MaxWeight < 0 || carton.Cost < 0 {
+			return "Carton limits and costs must not be negative"
+		}
+		names[carton.Name] = true
+	}
+
+	return ""
+}
+
+// validateRules checks that pack rules parse, refer to configured pack sizes, have at most
+// packsolver.MaxNeverMix never mix rules and do not contradict each other unconditionally,
+// returning a validation message or an empty string
+func validateRules(packSizes []int, texts []string) string {
+	rules, err := pack_configurations.ParseRules(texts)
+	if err != nil {
+		return err.Error()
+	}
+
+	sizes := make(map[int]bool, len(packSizes))
+	for _, size := range packSizes {
+		sizes[size] = true
+	}
+
+	var unconditional []pack_configurations.Rule
+	neverMix := 0
+	for _, rule := range rules {
+		if rule.Kind == pack_configurations.RuleNeverMix {
+			neverMix++
+		}
+		for _, size := range rule.Sizes() {
+			if !sizes[size] {
+				return fmt.Sprintf("Rule %q refers to pack size %d, which is not configured", rule.Text, size)
+			}
+		}
+		if rule.Condition == nil {
+			unconditional = append(unconditional, rule)
+		}
+	}
+
+	if neverMix > packsolver.MaxNeverMix {
+		return fmt.Sprintf("At most %d never mix rules are allowed", packsolver.MaxNeverMix)
+	}
+
+	constraints := pack_configurations.Constraints(unconditional)
+	for size, count := range constraints.AtLeast {
+		if limit, ok := constraints.AtMost[size]; ok && count > limit {
+			return fmt.Sprintf("Rules require at least %d and at most %d packs of %d", count, limit, size)
+		}
+	}
+	return ""
+}
+
+// validateTieBreak checks a tie-break policy override, which must name its policy, with the
+// rules of the solver
+func validateTieBreak(tieBreak *order_calculations.TieBreakPolicy) error {
+	if tieBreak.Policy == "" {
+		return errors.NewValidationError("Tie-break policy is required")
+	}
+	if err := tieBreak.Validate(); err != nil {
+		invalid := err.(*packsolver.TieBreakError)
+		return errors.NewValidationErrorWrap("Invalid tie-break policy: "+invalid.Reason, err)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: packcalculator/v1/packcalculator.proto
+
+package packcalculatorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PackCalculator_Calculate_FullMethodName                  = "/packcalculator.v1.PackCalculator/Calculate"
+	PackCalculator_CalculateBatch_FullMethodName             = "/packcalculator.v1.PackCalculator/CalculateBatch"
+	PackCalculator_GetActivePackConfiguration_FullMethodName = "/packcalculator.v1.PackCalculator/GetActivePackConfiguration"
+	PackCalculator_CreatePackConfiguration_FullMethodName    = "/packcalculator.v1.PackCalculator/CreatePackConfiguration"
+	PackCalculator_ListPackConfigurations_FullMethodName     = "/packcalculator.v1.PackCalculator/ListPackConfigurations"
+	PackCalculator_GetPackConfiguration_FullMethodName       = "/packcalculator.v1.PackCalculator/GetPackConfiguration"
+	PackCalculator_ActivatePackConfiguration_FullMethodName  = "/packcalculator.v1.PackCalculator/ActivatePackConfiguration"
+)
+
+// PackCalculatorClient is the client API for PackCalculator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PackCalculator calculates pack combinations for orders and manages pack configurations.
+// It is served next to the REST API and shares its services.
+type PackCalculatorClient interface {
+	// Calculate calculates the optimal packs for an order
+	Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error)
+	// CalculateBatch calculates up to 100 orders one after another. A failing order
+	// reports its error in its result and does not fail the batch.
+	CalculateBatch(ctx context.Context, in *CalculateBatchRequest, opts ...grpc.CallOption) (*CalculateBatchResponse, error)
+	// GetActivePackConfiguration returns the active pack configuration
+	GetActivePackConfiguration(ctx context.Context, in *GetActivePackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error)
+	// CreatePackConfiguration creates a pack configuration and makes it the active one
+	CreatePackConfiguration(ctx context.Context, in *CreatePackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error)
+	// ListPackConfigurations returns every pack configuration in the order they were created
+	ListPackConfigurations(ctx context.Context, in *ListPackConfigurationsRequest, opts ...grpc.CallOption) (*ListPackConfigurationsResponse, error)
+	// GetPackConfiguration returns a pack configuration by ID
+	GetPackConfiguration(ctx context.Context, in *GetPackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error)
+	// ActivatePackConfiguration makes an existing pack configuration the active one
+	ActivatePackConfiguration(ctx context.Context, in *ActivatePackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error)
+}
+
+type packCalculatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPackCalculatorClient(cc grpc.ClientConnInterface) PackCalculatorClient {
+	return &packCalculatorClient{cc}
+}
+
+func (c *packCalculatorClient) Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateResponse)
+	err := c.cc.Invoke(ctx, PackCalculator_Calculate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packCalculatorClient) CalculateBatch(ctx context.Context, in *CalculateBatchRequest, opts ...grpc.CallOption) (*CalculateBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateBatchResponse)
+	err := c.cc.Invoke(ctx, PackCalculator_CalculateBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packCalculatorClient) GetActivePackConfiguration(ctx context.Context, in *GetActivePackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PackConfiguration)
+	err := c.cc.Invoke(ctx, PackCalculator_GetActivePackConfiguration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packCalculatorClient) CreatePackConfiguration(ctx context.Context, in *CreatePackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PackConfiguration)
+	err := c.cc.Invoke(ctx, PackCalculator_CreatePackConfiguration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packCalculatorClient) ListPackConfigurations(ctx context.Context, in *ListPackConfigurationsRequest, opts ...grpc.CallOption) (*ListPackConfigurationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPackConfigurationsResponse)
+	err := c.cc.Invoke(ctx, PackCalculator_ListPackConfigurations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packCalculatorClient) GetPackConfiguration(ctx context.Context, in *GetPackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PackConfiguration)
+	err := c.cc.Invoke(ctx, PackCalculator_GetPackConfiguration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packCalculatorClient) ActivatePackConfiguration(ctx context.Context, in *ActivatePackConfigurationRequest, opts ...grpc.CallOption) (*PackConfiguration, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PackConfiguration)
+	err := c.cc.Invoke(ctx, PackCalculator_ActivatePackConfiguration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PackCalculatorServer is the server API for PackCalculator service.
+// All implementations must embed UnimplementedPackCalculatorServer
+// for forward compatibility.
+//
+// PackCalculator calculates pack combinations for orders and manages pack configurations.
+// It is served next to the REST API and shares its services.
+type PackCalculatorServer interface {
+	// Calculate calculates the optimal packs for an order
+	Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error)
+	// CalculateBatch calculates up to 100 orders one after another. A failing order
+	// reports its error in its result and does not fail the batch.
+	CalculateBatch(context.Context, *CalculateBatchRequest) (*CalculateBatchResponse, error)
+	// GetActivePackConfiguration returns the active pack configuration
+	GetActivePackConfiguration(context.Context, *GetActivePackConfigurationRequest) (*PackConfiguration, error)
+	// CreatePackConfiguration creates a pack configuration and makes it the active one
+	CreatePackConfiguration(context.Context, *CreatePackConfigurationRequest) (*PackConfiguration, error)
+	// ListPackConfigurations returns every pack configuration in the order they were created
+	ListPackConfigurations(context.Context, *ListPackConfigurationsRequest) (*ListPackConfigurationsResponse, error)
+	// GetPackConfiguration returns a pack configuration by ID
+	GetPackConfiguration(context.Context, *GetPackConfigurationRequest) (*PackConfiguration, error)
+	// ActivatePackConfiguration makes an existing pack configuration the active one
+	ActivatePackConfiguration(context.Context, *ActivatePackConfigurationRequest) (*PackConfiguration, error)
+	mustEmbedUnimplementedPackCalculatorServer()
+}
+
+// UnimplementedPackCalculatorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPackCalculatorServer struct{}
+
+func (UnimplementedPackCalculatorServer) Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Calculate not implemented")
+}
+func (UnimplementedPackCalculatorServer) CalculateBatch(context.Context, *CalculateBatchRequest) (*CalculateBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateBatch not implemented")
+}
+func (UnimplementedPackCalculatorServer) GetActivePackConfiguration(context.Context, *GetActivePackConfigurationRequest) (*PackConfiguration, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActivePackConfiguration not implemented")
+}
+func (UnimplementedPackCalculatorServer) CreatePackConfiguration(context.Context, *CreatePackConfigurationRequest) (*PackConfiguration, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePackConfiguration not implemented")
+}
+func (UnimplementedPackCalculatorServer) ListPackConfigurations(context.Context, *ListPackConfigurationsRequest) (*ListPackConfigurationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPackConfigurations not implemented")
+}
+func (UnimplementedPackCalculatorServer) GetPackConfiguration(context.Context, *GetPackConfigurationRequest) (*PackConfiguration, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPackConfiguration not implemented")
+}
+func (UnimplementedPackCalculatorServer) ActivatePackConfiguration(context.Context, *ActivatePackConfigurationRequest) (*PackConfiguration, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivatePackConfiguration not implemented")
+}
+func (UnimplementedPackCalculatorServer) mustEmbedUnimplementedPackCalculatorServer() {}
+func (UnimplementedPackCalculatorServer) testEmbeddedByValue()                        {}
+
+// UnsafePackCalculatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PackCalculatorServer will
+// result in compilation errors.
+type UnsafePackCalculatorServer interface {
+	mustEmbedUnimplementedPackCalculatorServer()
+}
+
+func RegisterPackCalculatorServer(s grpc.ServiceRegistrar, srv PackCalculatorServer) {
+	// If the following call pancis, it indicates UnimplementedPackCalculatorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PackCalculator_ServiceDesc, srv)
+}
+
+func _PackCalculator_Calculate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).Calculate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_Calculate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).Calculate(ctx, req.(*CalculateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackCalculator_CalculateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).CalculateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_CalculateBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).CalculateBatch(ctx, req.(*CalculateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackCalculator_GetActivePackConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActivePackConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).GetActivePackConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_GetActivePackConfiguration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).GetActivePackConfiguration(ctx, req.(*GetActivePackConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackCalculator_CreatePackConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePackConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).CreatePackConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_CreatePackConfiguration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).CreatePackConfiguration(ctx, req.(*CreatePackConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackCalculator_ListPackConfigurations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPackConfigurationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).ListPackConfigurations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_ListPackConfigurations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).ListPackConfigurations(ctx, req.(*ListPackConfigurationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackCalculator_GetPackConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPackConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).GetPackConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_GetPackConfiguration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).GetPackConfiguration(ctx, req.(*GetPackConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackCalculator_ActivatePackConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivatePackConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackCalculatorServer).ActivatePackConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackCalculator_ActivatePackConfiguration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackCalculatorServer).ActivatePackConfiguration(ctx, req.(*ActivatePackConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PackCalculator_ServiceDesc is the grpc.ServiceDesc for PackCalculator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PackCalculator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "packcalculator.v1.PackCalculator",
+	HandlerType: (*PackCalculatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Calculate",
+			Handler:    _PackCalculator_Calculate_Handler,
+		},
+		{
+			MethodName: "CalculateBatch",
+			Handler:    _PackCalculator_CalculateBatch_Handler,
+		},
+		{
+			MethodName: "GetActivePackConfiguration",
+			Handler:    _PackCalculator_GetActivePackConfiguration_Handler,
+		},
+		{
+			MethodName: "CreatePackConfiguration",
+			Handler:    _PackCalculator_CreatePackConfiguration_Handler,
+		},
+		{
+			MethodName: "ListPackConfigurations",
+			Handler:    _PackCalculator_ListPackConfigurations_Handler,
+		},
+		{
+			MethodName: "GetPackConfiguration",
+			Handler:    _PackCalculator_GetPackConfiguration_Handler,
+		},
+		{
+			MethodName: "ActivatePackConfiguration",
+			Handler:    _PackCalculator_ActivatePackConfiguration_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "packcalculator/v1/packcalculator.proto",
+}
@@ -9,29 +9,74 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/pack-calculator/api/middleware"
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
+	"github.com/pack-calculator/internal/forecasts"
 	"github.com/pack-calculator/internal/order_calculations"
 	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/shipping_rates"
+	"github.com/pack-calculator/internal/stats"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/auth"
 )
 
-func SetupRouter(logger *zap.Logger, cfg *config.AppConfig, packCfgHandler *pack_configurations.Handler, calculationsHandler *order_calculations.Handler) *gin.Engine {
+func SetupRouter(logger *zap.Logger, cfg *config.AppConfig, authenticator *middleware.Authenticator, rateLimiter *middleware.RateLimiter, packCfgHandler *pack_configurations.Handler, calculationsHandler *order_calculations.Handler, ratesHandler *shipping_rates.Handler, warehouseHandler *warehouses.Handler, reservationHandler *reservations.Handler, statsHandler *stats.Handler, forecastHandler *forecasts.Handler, experimentHandler *experiments.Handler, customerHandler *customers.Handler, apiKeyHandler *api_keys.Handler, auditHandler *audit_events.Handler) *gin.Engine {
 	// Create Gin router without default logging
 	router := gin.New()
 
-	// Use our custom logger and recovery middleware
+	// Only take the client IP from X-Forwarded-For when a trusted proxy set it, since the
+	// audit trail records it
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Error("Invalid trusted proxies, trusting none", zap.Error(err))
+		_ = router.SetTrustedProxies(nil)
+	}
+
+	// Tag each request with an ID, then use our custom logger and recovery middleware
+	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger(logger))
 	router.Use(gin.Recovery())
 
-	// Initialize rate limiter
-	rateLimiter := middleware.NewRateLimiter(cfg.RateLimiter)
-
-	// API routes with rate limiter
-	apiGroup := router.Group("/api", rateLimiter.Middleware())
-	{
-		apiGroup.GET("/packs", packCfgHandler.GetActivePackConfiguration)
-		apiGroup.POST("/packs", middleware.ValidatePacks(), packCfgHandler.CreatePackConfiguration)
-		apiGroup.POST("/calculate", middleware.ValidateOrder(), calculationsHandler.CalculatePacksForOrder)
+	// Each route requires a scope, checked before its input is validated
+	require := authenticator.Require
+	routes := func(group *gin.RouterGroup, calculate gin.HandlerFunc) {
+		group.GET("/packs", require(auth.ScopePacksRead), packCfgHandler.GetActivePackConfiguration)
+		group.POST("/packs", require(auth.ScopePacksWrite), middleware.ValidatePacks(), packCfgHandler.CreatePackConfiguration)
+		group.GET("/packs/configurations", require(auth.ScopePacksRead), packCfgHandler.ListPackConfigurations)
+		group.GET("/packs/configurations/:id", require(auth.ScopePacksRead), packCfgHandler.GetPackConfiguration)
+		group.POST("/packs/configurations/:id/activate", require(auth.ScopePacksWrite), packCfgHandler.ActivatePackConfiguration)
+		group.POST("/calculate", require(auth.ScopeCalculate), middleware.ValidateOrder(), calculate)
+		group.GET("/calculations", require(auth.ScopeCalculate), middleware.ValidateCalculationList(), calculationsHandler.ListCalculations)
+		group.GET("/calculations/:id", require(auth.ScopeCalculate), calculationsHandler.GetCalculation)
+		group.POST("/calculations/:id/amend", require(auth.ScopeCalculate), middleware.ValidateAmendment(), calculationsHandler.AmendCalculation)
+		group.GET("/rates", require(auth.ScopePacksRead), ratesHandler.ListRates)
+		group.POST("/rates", require(auth.ScopeAdmin), middleware.ValidateRates(), ratesHandler.UploadRates)
+		group.POST("/quote", require(auth.ScopeCalculate), middleware.ValidateQuote(), ratesHandler.QuoteOrder)
+		group.GET("/warehouses", require(auth.ScopePacksRead), warehouseHandler.ListWarehouses)
+		group.POST("/warehouses", require(auth.ScopeAdmin), middleware.ValidateWarehouse(), warehouseHandler.SaveWarehouse)
+		group.POST("/reservations/:id/commit", require(auth.ScopeCalculate), reservationHandler.CommitReservation)
+		group.GET("/stats", require(auth.ScopePacksRead), middleware.ValidateStats(), statsHandler.GetStats)
+		group.GET("/forecast", require(auth.ScopePacksRead), forecastHandler.GetLatestForecast)
+		group.POST("/experiments", require(auth.ScopePacksWrite), middleware.ValidateExperiment(), experimentHandler.StartExperiment)
+		group.POST("/experiments/:id/stop", require(auth.ScopePacksWrite), experimentHandler.StopExperiment)
+		group.GET("/experiments/:id/results", require(auth.ScopePacksRead), experimentHandler.GetResults)
+		group.GET("/customers", require(auth.ScopePacksRead), customerHandler.ListProfiles)
+		group.POST("/customers", require(auth.ScopeAdmin), middleware.ValidateCustomerProfile(), customerHandler.SaveProfile)
+		group.GET("/keys", require(auth.ScopeAdmin), apiKeyHandler.ListKeys)
+		group.POST("/keys", require(auth.ScopeAdmin), middleware.ValidateAPIKey(), apiKeyHandler.IssueKey)
+		group.POST("/keys/:id/revoke", require(auth.ScopeAdmin), apiKeyHandler.RevokeKey)
+		group.GET("/audit", require(auth.ScopeAdmin), middleware.ValidateAuditList(), auditHandler.ListEvents)
+		group.GET("/audit/verify", require(auth.ScopeAdmin), auditHandler.VerifyChain)
 	}
 
+	// Legacy API routes with rate limiter and authentication, keeping their original response shapes
+	routes(router.Group("/api", middleware.RenderErrors(logger, middleware.LegacyErrors), rateLimiter.Middleware(), authenticator.Middleware(), authenticator.Tenant()), calculationsHandler.CalculatePacksForOrder)
+
+	// Version 1 API routes render errors as problem details and share the rate limit
+	routes(router.Group("/api/v1", middleware.RenderErrors(logger, middleware.ProblemDetails), rateLimiter.Middleware(), authenticator.Middleware(), authenticator.Tenant()), calculationsHandler.CalculatePacksForOrderV1)
+
 	// Serve static files from /static URL path
 	router.Static("/static", "./static")
 	// Serve index.html for root path
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// tenantPacks holds an active configuration per tenant. Only GetActive is called when
+// calculating an order without a reference.
+type tenantPacks struct {
+	pack_configurations.Repository
+	active map[string]*pack_configurations.PackConfiguration
+}
+
+func (r tenantPacks) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	return r.active[tenant.FromContext(ctx)], nil
+}
+
+// tenantCalculations keeps the calculations saved per tenant and has no cached ones
+type tenantCalculations struct {
+	order_calculations.Repository
+	saved map[string][]order_calculations.OrderCalculation
+}
+
+func (r tenantCalculations) Save(ctx context.Context, calc *order_calculations.OrderCalculation) error {
+	calc.ID = uint(len(r.saved) + 1)
+	r.saved[tenant.FromContext(ctx)] = append(r.saved[tenant.FromContext(ctx)], *calc)
+	return nil
+}
+
+func (r tenantCalculations) GetByConfigurationIDAndOrderQuantity(ctx context.Context, orderQuantity int, configID uint, signature string) (*order_calculations.OrderCalculation, error) {
+	return nil, nil
+}
+
+func (r tenantCalculations) RecordCacheLookup(ctx context.Context, lookup *order_calculations.CacheLookup) error {
+	return nil
+}
+
+// TestRouter_CalculateActsForTheTenant tests that a calculation is solved with the active
+// configuration of the tenant of the request and stored under that tenant
+func TestRouter_CalculateActsForTheTenant(t *testing.T) {
+	packs := tenantPacks{active: map[string]*pack_configurations.PackConfiguration{
+		tenant.Default: {ID: 1, TenantID: tenant.Default, PackSizes: pq.Int64Array{250, 500}, Active: true},
+		"retail":       {ID: 4, TenantID: "retail", PackSizes: pq.Int64Array{6, 12}, Active: true},
+	}}
+	calculations := tenantCalculations{saved: map[string][]order_calculations.OrderCalculation{}}
+	service := order_calculations.NewService(zap.NewNop(), calculations, packs, order_calculations.Deps{})
+	router := newContractRouterWithCalculations(&config.AppConfig{}, nil, order_calculations.NewHandler(zap.NewNop(), service))
+
+	for _, path := range []string{"/api/calculate", "/api/v1/calculate"} {
+		t.Run(path, func(t *testing.T) {
+			clear(calculations.saved)
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"orderQuantity": 18}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Tenant-ID", "retail")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var response map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.EqualValues(t, 18, response["totalItems"])
+
+			require.Len(t, calculations.saved["retail"], 1)
+			assert.Equal(t, uint(4), calculations.saved["retail"][0].ConfigurationID)
+			assert.Empty(t, calculations.saved[tenant.Default])
+		})
+	}
+}
+
+// TestRouter_ClientIPTrustsOnlyConfiguredProxies tests that X-Forwarded-For only sets the
+// client IP of requests that come through a trusted proxy
+func TestRouter_ClientIPTrustsOnlyConfiguredProxies(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		want           string
+	}{
+		{name: "no trusted proxies", want: "10.1.2.3"},
+		{name: "trusted proxy", trustedProxies: []string{"10.0.0.0/8"}, want: "203.0.113.9"},
+		{name: "untrusted proxy", trustedProxies: []string{"192.168.0.0/16"}, want: "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newContractRouter(&config.AppConfig{Server: config.ServerConfig{TrustedProxies: tt.trustedProxies}})
+			router.GET("/client-ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/client-ip", nil)
+			req.RemoteAddr = "10.1.2.3:41000"
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Body.String())
+		})
+	}
+}
+
+// failingPacks fails to read the active configuration with a driver error naming a table
+type failingPacks struct {
+	pack_configurations.Repository
+}
+
+func (failingPacks) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	return nil, postgres.ClassifyError(&pq.Error{Code: "42P01", Message: `relation "secret_table" does not exist`, Table: "secret_table"})
+}
+
+// TestRouter_DatabaseErrorsAreNotExposed tests that the fields of an unclassified driver
+// error are not serialised into the response
+func TestRouter_DatabaseErrorsAreNotExposed(t *testing.T) {
+	service := order_calculations.NewService(zap.NewNop(), tenantCalculations{}, failingPacks{}, order_calculations.Deps{})
+	router := newContractRouterWithCalculations(&config.AppConfig{}, nil, order_calculations.NewHandler(zap.NewNop(), service))
+
+	for _, path := range []string{"/api/calculate", "/api/v1/calculate"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"orderQuantity": 18}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.NotContains(t, w.Body.String(), "secret_table")
+			assert.NotContains(t, w.Body.String(), "42P01")
+			assert.NotContains(t, w.Body.String(), `"details"`)
+		})
+	}
+}
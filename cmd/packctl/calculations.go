@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pack-calculator/pkg/apitypes"
+	"github.com/pack-calculator/pkg/client"
+)
+
+var calculationHeader = []string{"ID", "TIMESTAMP", "QUANTITY", "MODE", "PACKS", "TOTAL ITEMS", "TOTAL PACKS", "CONFIGURATION"}
+
+// runCalculations reads the order calculations stored by the server
+func runCalculations(args []string, api *client.Client, out *printer, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: packctl calculations list [-offset N] [-limit N]|get <id>|export [-file path] [-format csv|json]")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("calculations list", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		offset := fs.Int("offset", 0, "number of calculations to skip")
+		limit := fs.Int("limit", apitypes.DefaultCalculationsLimit, "maximum number of calculations to list")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		calcs, err := api.ListCalculations(context.Background(), *offset, *limit)
+		if err != nil {
+			return err
+		}
+		return printCalculations(out, calcs)
+	case "get":
+		id, err := parseID(args[1:])
+		if err != nil {
+			return err
+		}
+		calc, err := api.GetCalculation(context.Background(), id)
+		if err != nil {
+			return err
+		}
+		return out.print(calc, calculationHeader, [][]string{calculationRowOf(*calc)})
+	case "export":
+		fs := flag.NewFlagSet("calculations export", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		file := fs.String("file", "", "file to write to instead of standard output")
+		format := fs.String("format", formatCSV, "export format: csv or json")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *format != formatCSV && *format != formatJSON {
+			return fmt.Errorf("export format must be csv or json, got %q", *format)
+		}
+		return exportCalculations(api, out.w, *file, *format)
+	default:
+		return fmt.Errorf("unknown calculations command %q", args[0])
+	}
+}
+
+// exportCalculations pages through every calculation until a short page and writes them all
+func exportCalculations(api *client.Client, stdout io.Writer, file, format string) error {
+	calcs := []apitypes.Calculation{}
+	for offset := 0; ; offset += apitypes.MaxCalculationsLimit {
+		page, err := api.ListCalculations(context.Background(), offset, apitypes.MaxCalculationsLimit)
+		if err != nil {
+			return err
+		}
+		calcs = append(calcs, page...)
+		if len(page) < apitypes.MaxCalculationsLimit {
+			break
+		}
+	}
+
+	w := stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return printCalculations(&printer{w: w, format: format}, calcs)
+}
+
+func printCalculations(out *printer, calcs []apitypes.Calculation) error {
+	rows := make([][]string, 0, len(calcs))
+	for _, calc := range calcs {
+		rows = append(rows, calculationRowOf(calc))
+	}
+	return out.print(apitypes.CalculationsResponse{Calculations: calcs}, calculationHeader, rows)
+}
+
+func calculationRowOf(calc apitypes.Calculation) []string {
+	packs := make([]string, 0, len(calc.Result))
+	for _, pack := range calc.Result {
+		packs = append(packs, fmt.Sprintf("%dx%d", pack.Quantity, pack.Size))
+	}
+	return []string{
+		strconv.FormatUint(uint64(calc.ID), 10),
+		calc.Timestamp.UTC().Format(time.RFC3339),
+		strconv.Itoa(calc.OrderQuantity),
+		calc.Mode,
+		strings.Join(packs, " "),
+		strconv.Itoa(calc.TotalItems),
+		strconv.Itoa(calc.TotalPacks),
+		strconv.FormatUint(uint64(calc.ConfigurationID), 10),
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/pkg/apitypes"
+)
+
+func TestRun_Calculations(t *testing.T) {
+	timestamp := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	total := apitypes.MaxCalculationsLimit + 2
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/calculations/7" {
+			json.NewEncoder(w).Encode(apitypes.Calculation{
+				ID: 7, OrderQuantity: 501, Mode: "standard", ConfigurationID: 1, Timestamp: timestamp,
+				Result:     []apitypes.PackResult{{Size: 500, Quantity: 1}, {Size: 250, Quantity: 1}},
+				TotalItems: 750, TotalPacks: 2,
+			})
+			return
+		}
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		calcs := []apitypes.Calculation{}
+		for id := total - offset; id > 0 && len(calcs) < limit; id-- {
+			calcs = append(calcs, apitypes.Calculation{
+				ID: uint(id), OrderQuantity: 1, Mode: "standard", ConfigurationID: 1, Timestamp: timestamp,
+				Result:     []apitypes.PackResult{{Size: 250, Quantity: 1}},
+				TotalItems: 250, TotalPacks: 1,
+			})
+		}
+		json.NewEncoder(w).Encode(apitypes.CalculationsResponse{Calculations: calcs})
+	}))
+	defer server.Close()
+
+	t.Run("list", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		err := run([]string{"-server", server.URL, "-output", "csv", "calculations", "list", "-offset", "500", "-limit", "5"}, &stdout, &stderr)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ID,TIMESTAMP,QUANTITY,MODE,PACKS,TOTAL ITEMS,TOTAL PACKS,CONFIGURATION\n"+
+			"2,2026-10-18T09:00:00Z,1,standard,1x250,250,1,1\n"+
+			"1,2026-10-18T09:00:00Z,1,standard,1x250,250,1,1\n", stdout.String())
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		err := run([]string{"-server", server.URL, "calculations", "get", "7"}, &stdout, &stderr)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ID  TIMESTAMP             QUANTITY  MODE      PACKS        TOTAL ITEMS  TOTAL PACKS  CONFIGURATION\n"+
+			"7   2026-10-18T09:00:00Z  501       standard  1x500 1x250  750          2            1\n", stdout.String())
+	})
+
+	t.Run("export pages through every calculation", func(t *testing.T) {
+		offsets = nil
+		file := filepath.Join(t.TempDir(), "calculations.csv")
+		var stdout, stderr bytes.Buffer
+
+		err := run([]string{"-server", server.URL, "calculations", "export", "-file", file}, &stdout, &stderr)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0", "500"}, offsets)
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		assert.Len(t, lines, total+1)
+		assert.True(t, strings.HasPrefix(lines[1], "502,"))
+		assert.True(t, strings.HasPrefix(lines[total], "1,"))
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("invalid export format", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		err := run([]string{"-server", server.URL, "calculations", "export", "-format", "table"}, &stdout, &stderr)
+
+		assert.ErrorContains(t, err, "export format must be csv or json")
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pack-calculator/pkg/apitypes"
+	"github.com/pack-calculator/pkg/client"
+)
+
+var (
+	keyHeader       = []string{"ID", "NAME", "PREFIX", "SCOPES", "TENANT", "CREATED BY", "LAST USED", "REVOKED"}
+	issuedKeyHeader = []string{"ID", "NAME", "SCOPES", "KEY"}
+)
+
+// runKeys manages the API keys of the server
+func runKeys(args []string, api *client.Client, out *printer, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: packctl keys list|issue -name NAME -scope SCOPE [-scope ...] [-tenant ID]|revoke <id>")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "list":
+		keys, err := api.ListAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(keys))
+		for _, key := range keys {
+			rows = append(rows, keyRowOf(key))
+		}
+		return out.print(apitypes.APIKeysResponse{Keys: keys}, keyHeader, rows)
+	case "issue":
+		fs := flag.NewFlagSet("keys issue", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		name := fs.String("name", "", "name of the API key, e.g. the client using it")
+		var scopes stringList
+		fs.Var(&scopes, "scope", "scope granted to the key: calculate, packs:read, packs:write or admin (repeatable)")
+		tenant := fs.String("tenant", "", "tenant the key is bound to, any tenant its scopes allow when empty")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		issued, err := api.IssueAPIKey(ctx, apitypes.IssueAPIKeyRequest{Name: *name, Scopes: scopes, Tenant: *tenant})
+		if err != nil {
+			return err
+		}
+		row := []string{strconv.FormatUint(uint64(issued.ID), 10), issued.Name, strings.Join(issued.Scopes, " "), issued.Key}
+		return out.print(issued, issuedKeyHeader, [][]string{row})
+	case "revoke":
+		id, err := parseID(args[1:])
+		if err != nil {
+			return err
+		}
+		key, err := api.RevokeAPIKey(ctx, id)
+		if err != nil {
+			return err
+		}
+		return out.print(key, keyHeader, [][]string{keyRowOf(*key)})
+	default:
+		return fmt.Errorf("unknown keys command %q", args[0])
+	}
+}
+
+func keyRowOf(key apitypes.APIKey) []string {
+	return []string{
+		strconv.FormatUint(uint64(key.ID), 10),
+		key.Name,
+		key.Prefix,
+		strings.Join(key.Scopes, " "),
+		key.Tenant,
+		key.CreatedBy,
+		formatTime(key.LastUsedAt),
+		formatTime(key.RevokedAt),
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
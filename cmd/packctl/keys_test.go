@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/pkg/apitypes"
+	"github.com/pack-calculator/pkg/errors"
+)
+
+func TestRun_Keys(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	var issued apitypes.IssueAPIKeyRequest
+	var gotKey, gotTenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-API-Key")
+		gotTenant = r.Header.Get("X-Tenant-ID")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/keys":
+			json.NewEncoder(w).Encode(apitypes.APIKeysResponse{Keys: []apitypes.APIKey{
+				{ID: 1, Name: "checkout", Prefix: "pk_0123abcd", Scopes: []string{"calculate"}, Tenant: "retail", CreatedBy: "bootstrap", CreatedAt: createdAt, LastUsedAt: &createdAt},
+			}})
+		case "POST /api/keys":
+			json.NewDecoder(r.Body).Decode(&issued)
+			json.NewEncoder(w).Encode(apitypes.IssueAPIKeyResponse{APIKey: apitypes.APIKey{ID: 2, Name: issued.Name, Scopes: issued.Scopes}, Key: "pk_secret"})
+		case "POST /api/keys/1/revoke":
+			json.NewEncoder(w).Encode(apitypes.APIKey{ID: 1, Name: "checkout", Prefix: "pk_0123abcd", Scopes: []string{"calculate"}, CreatedBy: "bootstrap", RevokedAt: &createdAt})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(errors.NewNotFoundErrorWrap("API key not found", nil))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "list",
+			args: []string{"-output", "csv", "keys", "list"},
+			want: "ID,NAME,PREFIX,SCOPES,TENANT,CREATED BY,LAST USED,REVOKED\n1,checkout,pk_0123abcd,calculate,retail,bootstrap,2026-10-18T09:00:00Z,\n",
+		},
+		{
+			name: "issue",
+			args: []string{"-output", "csv", "keys", "issue", "-name", "ops", "-scope", "packs:read", "-scope", "packs:write", "-tenant", "retail"},
+			want: "ID,NAME,SCOPES,KEY\n2,ops,packs:read packs:write,pk_secret\n",
+		},
+		{
+			name: "revoke",
+			args: []string{"-output", "csv", "keys", "revoke", "1"},
+			want: "ID,NAME,PREFIX,SCOPES,TENANT,CREATED BY,LAST USED,REVOKED\n1,checkout,pk_0123abcd,calculate,,bootstrap,,2026-10-18T09:00:00Z\n",
+		},
+		{
+			name:    "not found",
+			args:    []string{"keys", "revoke", "9"},
+			wantErr: "404 Not Found: API key not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := run(append([]string{"-server", server.URL, "-api-key", "pk_admin", "-tenant", "retail"}, tt.args...), &stdout, &stderr)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, stdout.String())
+			assert.Equal(t, "pk_admin", gotKey)
+			assert.Equal(t, "retail", gotTenant)
+		})
+	}
+
+	assert.Equal(t, apitypes.IssueAPIKeyRequest{Name: "ops", Scopes: []string{"packs:read", "packs:write"}, Tenant: "retail"}, issued)
+}
@@ -0,0 +1,95 @@
+// Command packctl solves pack calculations offline and manages a running pack calculator server.
+//
+// Usage:
+//
+//	packctl [-server URL] [-api-key KEY] [-tenant ID] [-output table|json|csv] <command> [arguments]
+//
+// The commands are:
+//
+//	solve -sizes 250,500,1000 -quantity 501   compute packs locally without a database
+//	packs list                                list pack configurations
+//	packs get <id>                            show a pack configuration
+//	packs activate <id>                       make a pack configuration the active one
+//	packs create -sizes 250,500 [-rule ...]   create and activate a pack configuration
+//	calculations list [-offset N] [-limit N]  list order calculations, newest first
+//	calculations get <id>                     show an order calculation
+//	calculations export [-file path]          export every order calculation
+//	keys list                                 list API keys
+//	keys issue -name NAME -scope SCOPE        issue an API key, printing the key once
+//	           [-tenant ID]                   bind the key to a tenant
+//	keys revoke <id>                          revoke an API key
+//
+// When the server requires authentication, the API key is read from -api-key or the
+// PACKCTL_API_KEY environment variable. Pack configurations and calculations are those of
+// the tenant of -tenant or PACKCTL_TENANT, the default tenant when unset.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pack-calculator/pkg/client"
+)
+
+const defaultServer = "http://localhost:8080"
+
+const usage = `Usage: packctl [-server URL] [-api-key KEY] [-tenant ID] [-output table|json|csv] <command> [arguments]
+
+Commands:
+  solve -sizes 250,500,1000 -quantity 501   compute packs locally without a database
+  packs list|get <id>|activate <id>|create  manage pack configurations
+  calculations list|get <id>|export         read order calculations
+  keys list|issue|revoke <id>               manage API keys
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "packctl:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags and dispatches to the command
+func run(args []string, stdout, stderr io.Writer) error {
+	server := os.Getenv("PACKCTL_SERVER")
+	if server == "" {
+		server = defaultServer
+	}
+
+	fs := flag.NewFlagSet("packctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+	fs.StringVar(&server, "server", server, "base URL of the pack calculator server (env PACKCTL_SERVER)")
+	apiKey := fs.String("api-key", os.Getenv("PACKCTL_API_KEY"), "API key sent to the server (env PACKCTL_API_KEY)")
+	tenant := fs.String("tenant", os.Getenv("PACKCTL_TENANT"), "tenant to act for (env PACKCTL_TENANT)")
+	output := fs.String("output", formatTable, "output format: table, json or csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := validateFormat(*output); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing command")
+	}
+
+	out := &printer{w: stdout, format: *output}
+	api := client.New(server, client.Options{APIKey: *apiKey, Tenant: *tenant})
+	command, rest := fs.Arg(0), fs.Args()[1:]
+	switch command {
+	case "solve":
+		return runSolve(rest, out, stderr)
+	case "packs":
+		return runPacks(rest, api, out, stderr)
+	case "calculations":
+		return runCalculations(rest, api, out, stderr)
+	case "keys":
+		return runKeys(rest, api, out, stderr)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
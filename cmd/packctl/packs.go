@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pack-calculator/pkg/apitypes"
+	"github.com/pack-calculator/pkg/client"
+)
+
+var packHeader = []string{"ID", "ACTIVE", "PACK SIZES", "RULES"}
+
+// runPacks manages the pack configurations of the server
+func runPacks(args []string, api *client.Client, out *printer, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: packctl packs list|get <id>|activate <id>|create -sizes 250,500 [-rule ...]")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "list":
+		configs, err := api.ListPackConfigurations(ctx)
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(configs))
+		for _, packCfg := range configs {
+			rows = append(rows, packRowOf(packCfg))
+		}
+		return out.print(apitypes.PackConfigurationsResponse{Configurations: configs}, packHeader, rows)
+	case "get", "activate":
+		id, err := parseID(args[1:])
+		if err != nil {
+			return err
+		}
+		get := api.GetPackConfiguration
+		if args[0] == "activate" {
+			get = api.ActivatePackConfiguration
+		}
+		packCfg, err := get(ctx, id)
+		if err != nil {
+			return err
+		}
+		return out.print(packCfg, packHeader, [][]string{packRowOf(*packCfg)})
+	case "create":
+		fs := flag.NewFlagSet("packs create", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		sizes := fs.String("sizes", "", "comma separated pack sizes, e.g. 250,500,1000")
+		var rules stringList
+		fs.Var(&rules, "rule", "pack selection rule such as \"at most 2 x 250\" (repeatable)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		packSizes, err := parseSizes(*sizes)
+		if err != nil {
+			return err
+		}
+		packCfg, err := api.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: packSizes, Rules: rules})
+		if err != nil {
+			return err
+		}
+		return out.print(packCfg, packHeader, [][]string{packRowOf(*packCfg)})
+	default:
+		return fmt.Errorf("unknown packs command %q", args[0])
+	}
+}
+
+func packRowOf(packCfg apitypes.PackConfiguration) []string {
+	id := ""
+	if packCfg.ID != 0 {
+		id = strconv.FormatUint(uint64(packCfg.ID), 10)
+	}
+	return []string{id, strconv.FormatBool(packCfg.Active), joinInts(packCfg.PackSizes), strings.Join(packCfg.Rules, "; ")}
+}
+
+// parseID returns the single positive ID argument of a command
+func parseID(args []string) (uint, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected exactly one ID argument")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid ID %q", args[0])
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/pkg/apitypes"
+	"github.com/pack-calculator/pkg/errors"
+)
+
+func TestRun_Packs(t *testing.T) {
+	var created apitypes.PackConfigurationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/packs/configurations":
+			json.NewEncoder(w).Encode(apitypes.PackConfigurationsResponse{Configurations: []apitypes.PackConfiguration{
+				{ID: 1, PackSizes: []int{250, 500}},
+				{ID: 2, Active: true, PackSizes: []int{250, 500, 1000}, Rules: []string{"at most 2 x 250"}},
+			}})
+		case "GET /api/packs/configurations/2":
+			json.NewEncoder(w).Encode(apitypes.PackConfiguration{ID: 2, Active: true, PackSizes: []int{250, 500, 1000}})
+		case "POST /api/packs/configurations/1/activate":
+			json.NewEncoder(w).Encode(apitypes.PackConfiguration{ID: 1, Active: true, PackSizes: []int{250, 500}})
+		case "POST /api/packs":
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(apitypes.PackConfiguration{PackSizes: created.PackSizes, Rules: created.Rules})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(errors.NewValidationError("Pack configuration not found"))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "list",
+			args: []string{"packs", "list"},
+			want: "ID  ACTIVE  PACK SIZES    RULES\n1   false   250 500       \n2   true    250 500 1000  at most 2 x 250\n",
+		},
+		{
+			name: "get",
+			args: []string{"-output", "csv", "packs", "get", "2"},
+			want: "ID,ACTIVE,PACK SIZES,RULES\n2,true,250 500 1000,\n",
+		},
+		{
+			name: "activate",
+			args: []string{"-output", "json", "packs", "activate", "1"},
+			want: "{\n  \"id\": 1,\n  \"active\": true,\n  \"packSizes\": [\n    250,\n    500\n  ]\n}\n",
+		},
+		{
+			name: "create",
+			args: []string{"-output", "csv", "packs", "create", "-sizes", "23,31,53", "-rule", "at least 1 x 53"},
+			want: "ID,ACTIVE,PACK SIZES,RULES\n,false,23 31 53,at least 1 x 53\n",
+		},
+		{
+			name:    "not found",
+			args:    []string{"packs", "get", "9"},
+			wantErr: "404 Not Found: Pack configuration not found",
+		},
+		{
+			name:    "invalid id",
+			args:    []string{"packs", "activate", "abc"},
+			wantErr: "invalid ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := run(append([]string{"-server", server.URL}, tt.args...), &stdout, &stderr)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+
+	assert.Equal(t, apitypes.PackConfigurationRequest{PackSizes: []int{23, 31, 53}, Rules: []string{"at least 1 x 53"}}, created)
+}
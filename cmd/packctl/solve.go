@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/pkg/packsolver"
+)
+
+// solveResult is the packing printed by the solve command
+type solveResult struct {
+	Quantity   int       `json:"quantity"`
+	Result     []packRow `json:"result"`
+	TotalItems int       `json:"totalItems"`
+	TotalPacks int       `json:"totalPacks"`
+}
+
+type packRow struct {
+	Size     int `json:"size"`
+	Quantity int `json:"quantity"`
+}
+
+// stringList collects the values of a flag given more than once
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, "; ") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runSolve computes the packs for a quantity with the solver library, without a server or database
+func runSolve(args []string, out *printer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sizes := fs.String("sizes", "", "comma separated pack sizes, e.g. 250,500,1000")
+	quantity := fs.Int("quantity", 0, "order quantity")
+	tieBreak := fs.String("tie-break", "", "tie-break policy: larger_packs, fewer_sizes, lexicographic or weighted")
+	weights := fs.String("weights", "", "size:weight pairs for the weighted tie-break, e.g. 250:1,500:2")
+	inventory := fs.String("inventory", "", "size:count pairs limiting the packs available, e.g. 250:10,500:4")
+	var rules stringList
+	fs.Var(&rules, "rule", "pack selection rule such as \"at most 2 x 250\" (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	packSizes, err := parseSizes(*sizes)
+	if err != nil {
+		return err
+	}
+	if *quantity <= 0 {
+		return fmt.Errorf("-quantity must be a positive integer")
+	}
+	opts := packsolver.Options{TieBreak: packsolver.TieBreak{Policy: *tieBreak}}
+	if *weights != "" {
+		if opts.TieBreak.Weights, err = config.ParseSizeValues(*weights, "weights", "size:weight", true); err != nil {
+			return err
+		}
+	}
+	if *inventory != "" {
+		if opts.Inventory, err = config.ParseSizeValues(*inventory, "inventory", "size:count", false); err != nil {
+			return err
+		}
+	}
+	parsedRules, err := pack_configurations.ParseRules(rules)
+	if err != nil {
+		return err
+	}
+	opts.Constraints = pack_configurations.Constraints(pack_configurations.ActiveRules(parsedRules, *quantity))
+
+	solution, err := packsolver.Solve(packSizes, *quantity, opts)
+	if err != nil {
+		return err
+	}
+
+	result := solveResult{Quantity: *quantity, Result: []packRow{}, TotalItems: solution.TotalItems, TotalPacks: solution.TotalPacks}
+	for size, count := range solution.Packs {
+		result.Result = append(result.Result, packRow{Size: size, Quantity: count})
+	}
+	sort.Slice(result.Result, func(i, j int) bool { return result.Result[i].Size < result.Result[j].Size })
+
+	rows := make([][]string, 0, len(result.Result)+1)
+	for _, pack := range result.Result {
+		rows = append(rows, []string{strconv.Itoa(pack.Size), strconv.Itoa(pack.Quantity), strconv.Itoa(pack.Size * pack.Quantity)})
+	}
+	if out.format == formatTable {
+		rows = append(rows, []string{"TOTAL", strconv.Itoa(result.TotalPacks), strconv.Itoa(result.TotalItems)})
+	}
+	return out.print(result, []string{"SIZE", "PACKS", "ITEMS"}, rows)
+}
+
+// parseSizes parses a comma separated list of pack sizes
+func parseSizes(value string) ([]int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("-sizes is required")
+	}
+	var sizes []int
+	for _, part := range strings.Split(value, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("pack sizes must be positive integers, got %q", part)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
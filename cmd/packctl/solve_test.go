@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_Solve(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "table",
+			args: []string{"solve", "-sizes", "250,500,1000,2000,5000", "-quantity", "12001"},
+			want: "SIZE   PACKS  ITEMS\n250    1      250\n2000   1      2000\n5000   2      10000\nTOTAL  4      12250\n",
+		},
+		{
+			name: "csv",
+			args: []string{"-output", "csv", "solve", "-sizes", "250,500", "-quantity", "501"},
+			want: "SIZE,PACKS,ITEMS\n250,1,250\n500,1,500\n",
+		},
+		{
+			name: "json",
+			args: []string{"-output", "json", "solve", "-sizes", "250,500", "-quantity", "251"},
+			want: "{\n  \"quantity\": 251,\n  \"result\": [\n    {\n      \"size\": 500,\n      \"quantity\": 1\n    }\n  ],\n  \"totalItems\": 500,\n  \"totalPacks\": 1\n}\n",
+		},
+		{
+			name: "rules",
+			args: []string{"-output", "csv", "solve", "-sizes", "250,500,1000", "-quantity", "1000", "-rule", "at most 0 x 1000 when quantity >= 1000"},
+			want: "SIZE,PACKS,ITEMS\n500,2,1000\n",
+		},
+		{
+			name: "tie-break",
+			args: []string{"-output", "csv", "solve", "-sizes", "250,500,750,1000,1250", "-quantity", "2000", "-tie-break", "fewer_sizes"},
+			want: "SIZE,PACKS,ITEMS\n1000,2,2000\n",
+		},
+		{
+			name:    "inventory cannot cover",
+			args:    []string{"solve", "-sizes", "250,500", "-quantity", "1001", "-inventory", "250:2,500:1"},
+			wantErr: "no packing satisfies the constraints",
+		},
+		{
+			name:    "missing sizes",
+			args:    []string{"solve", "-quantity", "1"},
+			wantErr: "-sizes is required",
+		},
+		{
+			name:    "missing quantity",
+			args:    []string{"solve", "-sizes", "250"},
+			wantErr: "-quantity must be a positive integer",
+		},
+		{
+			name:    "negative quantity",
+			args:    []string{"solve", "-sizes", "250", "-quantity", "-5"},
+			wantErr: "-quantity must be a positive integer",
+		},
+		{
+			name:    "invalid weights",
+			args:    []string{"solve", "-sizes", "250", "-quantity", "1", "-weights", "250"},
+			wantErr: "weights must be comma separated size:weight pairs",
+		},
+		{
+			name:    "invalid output",
+			args:    []string{"-output", "xml", "solve", "-sizes", "250", "-quantity", "1"},
+			wantErr: "output must be table, json or csv",
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"ship"},
+			wantErr: "unknown command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := run(tt.args, &stdout, &stderr)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
@@ -1,15 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 
 	"go.uber.org/zap"
 
 	"github.com/pack-calculator/api"
+	"github.com/pack-calculator/api/grpcserver"
+	"github.com/pack-calculator/api/middleware"
 	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
+	"github.com/pack-calculator/internal/forecasts"
 	"github.com/pack-calculator/internal/order_calculations"
 	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/shipping_rates"
+	"github.com/pack-calculator/internal/stats"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/auth"
 	"github.com/pack-calculator/pkg/logger"
 	"github.com/pack-calculator/pkg/postgres"
 )
@@ -45,20 +59,123 @@ func main() {
 	// Initialize repositories
 	packsCfgRepo := pack_configurations.NewRepository(db)
 	calculationsCfgRepo := order_calculations.NewRepository(db)
+	ratesRepo := shipping_rates.NewRepository(db)
+	warehouseRepo := warehouses.NewRepository(db)
+	reservationRepo := reservations.NewRepository(db)
+	statsRepo := stats.NewRepository(db)
+	forecastRepo := forecasts.NewRepository(db)
+	experimentRepo := experiments.NewRepository(db)
+	customerRepo := customers.NewRepository(db)
+	apiKeyRepo := api_keys.NewRepository(db)
+	auditRepo := audit_events.NewRepository(db)
 	l.Info("database repositories initialized")
 
 	// Initialize services
 	packsService := pack_configurations.NewService(l, packsCfgRepo)
-	calculationsService := order_calculations.NewService(l, calculationsCfgRepo, packsCfgRepo)
+	warehouseService := warehouses.NewService(l, warehouseRepo)
+	reservationService := reservations.NewService(l, reservationRepo)
+	statsService := stats.NewService(l, statsRepo)
+	overfillPolicy := order_calculations.OverfillPolicy{
+		MaxItems:   cfg.Overfill.MaxItems,
+		MaxPercent: cfg.Overfill.MaxPercent,
+		Backorder:  cfg.Overfill.Backorder,
+	}
+	tieBreak := order_calculations.TieBreakPolicy{
+		Policy:  cfg.TieBreak.Policy,
+		Weights: cfg.TieBreak.Weights,
+	}
+	calculationsService := order_calculations.NewService(l, calculationsCfgRepo, packsCfgRepo, order_calculations.Deps{
+		Warehouses:     warehouseRepo,
+		Reservations:   reservationRepo,
+		Experiments:    experimentRepo,
+		Customers:      customerRepo,
+		OverfillPolicy: overfillPolicy,
+		TieBreak:       tieBreak,
+		ReservationTTL: cfg.Reservation.TTL,
+	})
+	experimentService := experiments.NewService(l, experimentRepo, packsCfgRepo)
+	customerService := customers.NewService(l, customerRepo)
+	apiKeyService := api_keys.NewService(l, apiKeyRepo)
+	auditService := audit_events.NewService(l, auditRepo)
+	ratesService := shipping_rates.NewService(l, ratesRepo, packsCfgRepo, calculationsService)
+	forecastSettings := forecasts.Settings{
+		Method:          cfg.Forecast.Method,
+		Alpha:           cfg.Forecast.Alpha,
+		HistoryDays:     cfg.Forecast.HistoryDays,
+		HorizonDays:     cfg.Forecast.HorizonDays,
+		DefaultLeadTime: cfg.Forecast.DefaultLeadTime,
+		LeadTimes:       cfg.Forecast.LeadTimes,
+	}
+	forecastService := forecasts.NewService(l, forecastRepo, packsCfgRepo, warehouseRepo, reservationRepo, forecastSettings)
 	l.Info("services initialized")
 
 	// Initialize handlers
 	packsHandler := pack_configurations.NewHandler(l, packsService)
 	calculationsHandler := order_calculations.NewHandler(l, calculationsService)
+	ratesHandler := shipping_rates.NewHandler(l, ratesService)
+	warehouseHandler := warehouses.NewHandler(l, warehouseService)
+	reservationHandler := reservations.NewHandler(l, reservationService)
+	statsHandler := stats.NewHandler(l, statsService)
+	forecastHandler := forecasts.NewHandler(l, forecastService)
+	experimentHandler := experiments.NewHandler(l, experimentService)
+	customerHandler := customers.NewHandler(l, customerService)
+	apiKeyHandler := api_keys.NewHandler(l, apiKeyService)
+	auditHandler := audit_events.NewHandler(l, auditService)
 	l.Info("handlers initialized")
 
+	// Authenticate bearer tokens and API keys on both APIs
+	var tokenVerifier *auth.TokenVerifier
+	if cfg.Auth.JWT.JWKS != "" {
+		keySet := auth.NewKeySet(cfg.Auth.JWT.JWKS, nil)
+		if err := keySet.Load(context.Background()); err != nil {
+			l.Fatal("Failed to load the JWKS", zap.Error(err))
+		}
+		tokenVerifier = auth.NewTokenVerifier(auth.TokenConfig{
+			Issuer:      cfg.Auth.JWT.Issuer,
+			Audience:    cfg.Auth.JWT.Audience,
+			RolesClaim:  cfg.Auth.JWT.RolesClaim,
+			RoleMap:     cfg.Auth.JWT.RoleMap,
+			TenantClaim: cfg.Auth.JWT.TenantClaim,
+		}, keySet)
+		l.Info("bearer tokens accepted", zap.String("issuer", cfg.Auth.JWT.Issuer))
+	}
+	authenticator := middleware.NewAuthenticator(cfg.Auth, apiKeyService, tokenVerifier)
+	// REST and gRPC calls share one rate limit per client IP
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimiter)
+	if cfg.Auth.Enabled {
+		l.Info("authentication enabled")
+	}
+
+	// Release expired reservations in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reservations.NewSweeper(l, reservationService, cfg.Reservation.SweepInterval).Run(ctx)
+	l.Info("reservation sweeper started")
+
+	// Refresh the statistics rollups in the background
+	go stats.NewRefresher(l, statsService, cfg.Stats.RefreshInterval).Run(ctx)
+	l.Info("statistics refresher started")
+
+	// Generate the replenishment forecast on a schedule
+	go forecasts.NewForecaster(l, forecastService, cfg.Forecast.Interval).Run(ctx)
+	l.Info("forecaster started")
+
+	// Serve the gRPC API next to the REST API, sharing its services
+	listener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		l.Fatal("Failed to listen on gRPC port", zap.Error(err))
+	}
+	grpcServer := grpcserver.New(l, grpcserver.NewServer(l, calculationsService, packsService), authenticator, rateLimiter)
+	defer grpcServer.GracefulStop()
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			l.Fatal("Failed to start gRPC server", zap.Error(err))
+		}
+	}()
+	l.Info(fmt.Sprintf("gRPC server listening on port %s", cfg.Server.GRPCPort))
+
 	// Setup router
-	router := api.SetupRouter(l, cfg, packsHandler, calculationsHandler)
+	router := api.SetupRouter(l, cfg, authenticator, rateLimiter, packsHandler, calculationsHandler, ratesHandler, warehouseHandler, reservationHandler, statsHandler, forecastHandler, experimentHandler, customerHandler, apiKeyHandler, auditHandler)
 	l.Info("router initialized")
 
 	// Start server
@@ -2,8 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/packsolver"
 )
 
 // AppConfig holds all application configurations
@@ -11,11 +17,20 @@ type AppConfig struct {
 	Server      ServerConfig
 	Database    DatabaseConfig
 	RateLimiter RateLimiterConfig
+	Overfill    OverfillConfig
+	Reservation ReservationConfig
+	Stats       StatsConfig
+	Forecast    ForecastConfig
+	TieBreak    TieBreakConfig
+	Auth        AuthConfig
 }
 
-// ServerConfig holds HTTP server related configurations
+// ServerConfig holds HTTP server related configurations. The client IP of a request is only
+// taken from X-Forwarded-For when it comes from one of the trusted proxies.
 type ServerConfig struct {
-	Port string
+	Port           string
+	GRPCPort       string
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds database related configurations
@@ -29,11 +44,71 @@ type RateLimiterConfig struct {
 	MaxRequests int
 }
 
+// OverfillConfig holds the default cap on items shipped above the order quantity
+type OverfillConfig struct {
+	MaxItems   int
+	MaxPercent float64
+	Backorder  bool
+}
+
+// ReservationConfig holds how long reservations hold stock and how often expired ones are released
+type ReservationConfig struct {
+	TTL           time.Duration
+	SweepInterval time.Duration
+}
+
+// StatsConfig holds how often the statistics rollups are refreshed
+type StatsConfig struct {
+	RefreshInterval time.Duration
+}
+
+// ForecastConfig holds how the replenishment forecast is generated and how often.
+// LeadTimes overrides DefaultLeadTime, in days, per pack size.
+type ForecastConfig struct {
+	Interval        time.Duration
+	Method          string
+	Alpha           float64
+	HistoryDays     int
+	HorizonDays     int
+	DefaultLeadTime int
+	LeadTimes       map[int]int
+}
+
+// TieBreakConfig holds the default policy for choosing between equally optimal packings.
+// Weights, per pack size, are only used by the weighted policy.
+type TieBreakConfig struct {
+	Policy  string
+	Weights map[int]int
+}
+
+// AuthConfig holds whether requests must be authenticated. BootstrapKey is an API key
+// with the admin scope that is accepted without being stored, to issue the first keys.
+// Bearer tokens are accepted when JWT.JWKS is set.
+type AuthConfig struct {
+	Enabled      bool
+	BootstrapKey string
+	JWT          JWTConfig
+}
+
+// JWTConfig holds the OpenID Connect issuer whose bearer tokens are accepted and the
+// Audience they must be issued for. JWKS is the file path or http(s) URL of its signing
+// keys. RoleMap maps values of the RolesClaim claim to the viewer, operator and admin
+// roles; unmapped values grant nothing. TenantClaim binds a token to a tenant.
+type JWTConfig struct {
+	Issuer      string
+	Audience    string
+	JWKS        string
+	RolesClaim  string
+	RoleMap     map[string]string
+	TenantClaim string
+}
+
 // LoadConfig loads application configurations from environment variables
 func LoadConfig() (*AppConfig, error) {
 	config := &AppConfig{
 		Server: ServerConfig{
-			Port: getEnvWithDefault("SERVER_PORT", "8080"),
+			Port:     getEnvWithDefault("SERVER_PORT", "8080"),
+			GRPCPort: getEnvWithDefault("GRPC_PORT", "9090"),
 		},
 		Database: DatabaseConfig{
 			URL: os.Getenv("DATABASE_URL"),
@@ -41,6 +116,12 @@ func LoadConfig() (*AppConfig, error) {
 		RateLimiter: RateLimiterConfig{},
 	}
 
+	trustedProxies, err := parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, err
+	}
+	config.Server.TrustedProxies = trustedProxies
+
 	rateLimitStatus := getEnvWithDefault("RATE_LIMITER", "enabled")
 	if rateLimitStatus == "enabled" {
 		config.RateLimiter.Enabled = true
@@ -53,6 +134,88 @@ func LoadConfig() (*AppConfig, error) {
 		config.RateLimiter.MaxRequests = parsed
 	}
 
+	maxOverfillItems := getEnvWithDefault("OVERFILL_MAX_ITEMS", "0")
+	if parsed, err := strconv.Atoi(maxOverfillItems); err == nil && parsed > 0 {
+		config.Overfill.MaxItems = parsed
+	}
+
+	maxOverfillPercent := getEnvWithDefault("OVERFILL_MAX_PERCENT", "0")
+	if parsed, err := strconv.ParseFloat(maxOverfillPercent, 64); err == nil && parsed > 0 {
+		config.Overfill.MaxPercent = parsed
+	}
+
+	config.Overfill.Backorder = getEnvWithDefault("OVERFILL_BACKORDER", "disabled") == "enabled"
+
+	reservationTTL := getEnvWithDefault("RESERVATION_TTL", "15m")
+	if parsed, err := time.ParseDuration(reservationTTL); err == nil && parsed > 0 {
+		config.Reservation.TTL = parsed
+	}
+
+	sweepInterval := getEnvWithDefault("RESERVATION_SWEEP_INTERVAL", "1m")
+	if parsed, err := time.ParseDuration(sweepInterval); err == nil && parsed > 0 {
+		config.Reservation.SweepInterval = parsed
+	}
+
+	statsRefreshInterval := getEnvWithDefault("STATS_REFRESH_INTERVAL", "5m")
+	if parsed, err := time.ParseDuration(statsRefreshInterval); err == nil && parsed > 0 {
+		config.Stats.RefreshInterval = parsed
+	}
+
+	forecastInterval := getEnvWithDefault("FORECAST_INTERVAL", "1h")
+	if parsed, err := time.ParseDuration(forecastInterval); err == nil && parsed > 0 {
+		config.Forecast.Interval = parsed
+	}
+
+	config.Forecast.Method = getEnvWithDefault("FORECAST_METHOD", "exponential")
+
+	forecastAlpha := getEnvWithDefault("FORECAST_ALPHA", "0.3")
+	if parsed, err := strconv.ParseFloat(forecastAlpha, 64); err == nil && parsed > 0 && parsed <= 1 {
+		config.Forecast.Alpha = parsed
+	}
+
+	historyDays := getEnvWithDefault("FORECAST_HISTORY_DAYS", "28")
+	if parsed, err := strconv.Atoi(historyDays); err == nil && parsed > 0 {
+		config.Forecast.HistoryDays = parsed
+	}
+
+	horizonDays := getEnvWithDefault("FORECAST_HORIZON_DAYS", "30")
+	if parsed, err := strconv.Atoi(horizonDays); err == nil && parsed > 0 {
+		config.Forecast.HorizonDays = parsed
+	}
+
+	leadTime := getEnvWithDefault("FORECAST_LEAD_TIME_DAYS", "7")
+	if parsed, err := strconv.Atoi(leadTime); err == nil && parsed >= 0 {
+		config.Forecast.DefaultLeadTime = parsed
+	}
+
+	leadTimes, err := ParseSizeValues(os.Getenv("FORECAST_LEAD_TIMES"), "forecast lead times", "size:days", false)
+	if err != nil {
+		return nil, err
+	}
+	config.Forecast.LeadTimes = leadTimes
+
+	config.TieBreak.Policy = getEnvWithDefault("TIE_BREAK_POLICY", "larger_packs")
+
+	tieBreakWeights, err := ParseSizeValues(os.Getenv("TIE_BREAK_WEIGHTS"), "tie-break weights", "size:weight", true)
+	if err != nil {
+		return nil, err
+	}
+	config.TieBreak.Weights = tieBreakWeights
+
+	config.Auth.Enabled = getEnvWithDefault("AUTH", "disabled") == "enabled"
+	config.Auth.BootstrapKey = os.Getenv("AUTH_BOOTSTRAP_KEY")
+	config.Auth.JWT.Issuer = os.Getenv("AUTH_JWT_ISSUER")
+	config.Auth.JWT.Audience = os.Getenv("AUTH_JWT_AUDIENCE")
+	config.Auth.JWT.JWKS = os.Getenv("AUTH_JWT_JWKS")
+	config.Auth.JWT.RolesClaim = getEnvWithDefault("AUTH_JWT_ROLES_CLAIM", "roles")
+	config.Auth.JWT.TenantClaim = getEnvWithDefault("AUTH_JWT_TENANT_CLAIM", "tenant")
+
+	roleMap, err := parseRoleMap(os.Getenv("AUTH_JWT_ROLE_MAP"))
+	if err != nil {
+		return nil, err
+	}
+	config.Auth.JWT.RoleMap = roleMap
+
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
@@ -68,6 +231,57 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseSizeValues parses comma separated size:value pairs, for example "250:3,500:10"
+func ParseSizeValues(value, name, format string, allowNegative bool) (map[int]int, error) {
+	values := make(map[int]int)
+	if value == "" {
+		return values, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		size, raw, found := strings.Cut(strings.TrimSpace(pair), ":")
+		parsedSize, sizeErr := strconv.Atoi(size)
+		parsedValue, valueErr := strconv.Atoi(raw)
+		if !found || sizeErr != nil || valueErr != nil || parsedSize <= 0 || (parsedValue < 0 && !allowNegative) {
+			return nil, fmt.Errorf("%s must be comma separated %s pairs, got %q", name, format, pair)
+		}
+		values[parsedSize] = parsedValue
+	}
+	return values, nil
+}
+
+// parseRoleMap parses comma separated claim=role pairs, for example "pack-admins=admin"
+func parseRoleMap(value string) (map[string]string, error) {
+	roles := make(map[string]string)
+	if value == "" {
+		return roles, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		claim, role, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || claim == "" || !auth.ValidRole(role) {
+			return nil, fmt.Errorf("auth JWT role map must be comma separated claim=role pairs with the viewer, operator or admin role, got %q", pair)
+		}
+		roles[claim] = role
+	}
+	return roles, nil
+}
+
+// parseTrustedProxies parses comma separated IP addresses and CIDR ranges, for example
+// "10.0.0.0/8,192.168.1.10"
+func parseTrustedProxies(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("trusted proxies must be comma separated IP addresses or CIDR ranges, got %q", proxy)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
 // validateConfig checks if all required configurations are set
 func validateConfig(config *AppConfig) error {
 	if config.Database.URL == "" {
@@ -78,5 +292,42 @@ func validateConfig(config *AppConfig) error {
 		return fmt.Errorf("rate limiter max requests must be greater than zero")
 	}
 
+	if config.Reservation.TTL == 0 || config.Reservation.SweepInterval == 0 {
+		return fmt.Errorf("reservation TTL and sweep interval must be positive durations")
+	}
+
+	if config.Stats.RefreshInterval == 0 {
+		return fmt.Errorf("stats refresh interval must be a positive duration")
+	}
+
+	if config.Forecast.Method != "exponential" && config.Forecast.Method != "moving_average" {
+		return fmt.Errorf("forecast method must be exponential or moving_average")
+	}
+
+	if config.Forecast.Interval == 0 || config.Forecast.Alpha == 0 || config.Forecast.HistoryDays == 0 || config.Forecast.HorizonDays == 0 {
+		return fmt.Errorf("forecast interval, alpha, history and horizon must be positive, with alpha at most 1")
+	}
+
+	if config.Auth.BootstrapKey != "" && len(config.Auth.BootstrapKey) < 32 {
+		return fmt.Errorf("auth bootstrap key must be at least 32 characters")
+	}
+
+	if config.Auth.JWT.JWKS != "" && config.Auth.JWT.Issuer == "" {
+		return fmt.Errorf("bearer tokens require the issuer (set AUTH_JWT_ISSUER environment variable)")
+	}
+
+	if config.Auth.JWT.JWKS != "" && config.Auth.JWT.Audience == "" {
+		return fmt.Errorf("bearer tokens require the audience (set AUTH_JWT_AUDIENCE environment variable)")
+	}
+
+	if config.Auth.JWT.JWKS != "" && len(config.Auth.JWT.RoleMap) == 0 {
+		return fmt.Errorf("bearer tokens require a role map (set AUTH_JWT_ROLE_MAP environment variable)")
+	}
+
+	tieBreak := packsolver.TieBreak{Policy: config.TieBreak.Policy, Weights: config.TieBreak.Weights}
+	if err := tieBreak.Validate(); err != nil {
+		return fmt.Errorf("%w (set TIE_BREAK_POLICY and TIE_BREAK_WEIGHTS environment variables)", err)
+	}
+
 	return nil
 }
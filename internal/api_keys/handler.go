@@ -0,0 +1,75 @@
+package api_keys
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// IssueKey issues an API key and returns it together with the key itself
+func (h *Handler) IssueKey(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+
+	issued, err := h.service.Issue(c.Request.Context(), payload.(*IssueAPIRequest))
+	if err != nil {
+		c.Error(errors.Wrap("Failed to issue API key", err))
+		return
+	}
+
+	// The response holds the key, which must not be cached or logged
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, issued)
+}
+
+// ListKeys returns every API key, without the keys themselves
+func (h *Handler) ListKeys(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.Error(errors.Wrap("Failed to retrieve API keys", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, KeysAPIResponse{Keys: keys})
+}
+
+// RevokeKey revokes an API key
+func (h *Handler) RevokeKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid API key ID", err))
+		return
+	}
+
+	apiKey, err := h.service.Revoke(c.Request.Context(), uint(id))
+	if err != nil {
+		if stderrors.Is(err, ErrKeyNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("API key not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to revoke API key", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKey)
+}
@@ -0,0 +1,208 @@
+package api_keys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Issue(ctx context.Context, request *IssueAPIRequest) (*IssueAPIResponse, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*IssueAPIResponse), args.Error(1)
+}
+
+func (m *MockService) List(ctx context.Context) ([]APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]APIKey), args.Error(1)
+}
+
+func (m *MockService) Revoke(ctx context.Context, id uint) (*APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*APIKey), args.Error(1)
+}
+
+func (m *MockService) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.Principal), args.Error(1)
+}
+
+func TestHandler_IssueKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}}
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantKey        string
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", request)
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Issue", mock.Anything, request).Return(&IssueAPIResponse{APIKey: APIKey{ID: 3, Name: "checkout"}, Key: "pk_secret"}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantKey:        "pk_secret",
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", request)
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Issue", mock.Anything, request).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/keys", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).IssueKey(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantKey != "" {
+				var got IssueAPIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.wantKey, got.Key)
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+				assert.Equal(t, "checkout", got.Name)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ListKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/keys", nil)
+	mockService := new(MockService)
+	mockService.On("List", mock.Anything).Return([]APIKey{{ID: 3, Name: "checkout", KeyHash: "hash"}}, nil)
+
+	NewHandler(zap.NewNop(), mockService).ListKeys(c)
+	renderErrors(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+	var got KeysAPIResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, []APIKey{{ID: 3, Name: "checkout"}}, got.Keys)
+}
+
+func TestHandler_RevokeKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+	}{
+		{
+			name: "success case",
+			id:   "3",
+			mockSetup: func(m *MockService) {
+				m.On("Revoke", mock.Anything, uint(3)).Return(&APIKey{ID: 3}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "3",
+			mockSetup: func(m *MockService) {
+				m.On("Revoke", mock.Anything, uint(3)).Return(nil, ErrKeyNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "3",
+			mockSetup: func(m *MockService) {
+				m.On("Revoke", mock.Anything, uint(3)).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/keys/"+tt.id+"/revoke", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).RevokeKey(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
@@ -0,0 +1,131 @@
+package api_keys
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for API key persistence operations. GetByID, List and
+// Revoke act on the keys of the tenant of the context, while authentication looks keys up
+// across tenants.
+type Repository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id uint) (*APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, id uint, now time.Time) error
+	TouchLastUsed(ctx context.Context, id uint, now time.Time) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// tenantScope restricts a query to the keys bound to the tenant of ctx. The keys of the
+// default tenant include the unbound keys.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	id := tenant.FromContext(ctx)
+	if id != tenant.Default {
+		return tenant.Scope(ctx)
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant_id IN ?", []string{"", tenant.Default})
+	}
+}
+
+// Create stores the key and records its issuance in the audit trail, without the key hash
+func (r *gormRepository) Create(ctx context.Context, key *APIKey) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionAPIKeyIssue,
+			ResourceType: audit_events.ResourceAPIKey,
+			ResourceID:   strconv.FormatUint(uint64(key.ID), 10),
+			After:        key,
+		})
+	})
+	return postgres.ClassifyError(err)
+}
+
+func (r *gormRepository) GetByID(ctx context.Context, id uint) (*APIKey, error) {
+	var key APIKey
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).First(&key, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &key, nil
+}
+
+// GetByHash returns the key with the hash, revoked or not, or nil when there is none
+func (r *gormRepository) GetByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	var key APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &key, nil
+}
+
+func (r *gormRepository) List(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Order("id").Find(&keys).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return keys, nil
+}
+
+// revocation is the audited state of a revocation
+type revocation struct {
+	RevokedAt *time.Time `json:"revokedAt"`
+}
+
+// Revoke marks the key as revoked and records the revocation in the audit trail, leaving
+// keys that were revoked before unchanged
+func (r *gormRepository) Revoke(ctx context.Context, id uint, now time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&APIKey{}).
+			Scopes(tenantScope(ctx)).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionAPIKeyRevoke,
+			ResourceType: audit_events.ResourceAPIKey,
+			ResourceID:   strconv.FormatUint(uint64(id), 10),
+			Before:       revocation{},
+			After:        revocation{RevokedAt: &now},
+		})
+	})
+	return postgres.ClassifyError(err)
+}
+
+func (r *gormRepository) TouchLastUsed(ctx context.Context, id uint, now time.Time) error {
+	err := r.db.WithContext(ctx).Model(&APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", now).Error
+	return postgres.ClassifyError(err)
+}
@@ -0,0 +1,230 @@
+package api_keys
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+// expectAuditEvent expects an event to be appended to the audit trail with the states
+func expectAuditEvent(mock sqlmock.Sqlmock, action, resourceID string, before, after driver.Value) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+		WithArgs(sqlmock.AnyArg(), action, audit_events.ResourceAPIKey, resourceID, "anonymous", "", "", before, after, sqlmock.AnyArg(), "previous", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func TestCreate(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	key := &APIKey{Name: "checkout", Prefix: "pk_0123abcd", KeyHash: "hash", Scopes: []string{"calculate"}, Tenant: "retail", CreatedBy: "bootstrap", CreatedAt: createdAt}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "api_keys" ("name","prefix","key_hash","scopes","tenant_id","created_by","created_at","last_used_at","revoked_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
+		WithArgs("checkout", "pk_0123abcd", "hash", `["calculate"]`, "retail", "bootstrap", createdAt, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	expectAuditEvent(mock, audit_events.ActionAPIKeyIssue, "3", nil,
+		`{"id":3,"name":"checkout","prefix":"pk_0123abcd","scopes":["calculate"],"tenantId":"retail","createdBy":"bootstrap","createdAt":"2026-10-18T09:00:00Z"}`)
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), key)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), key.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByHash(t *testing.T) {
+	t.Run("key found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1 ORDER BY "api_keys"."id" LIMIT $2`)).
+			WithArgs("hash", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "scopes", "tenant_id", "created_by", "created_at", "last_used_at", "revoked_at"}).
+				AddRow(3, "checkout", "pk_0123abcd", "hash", `["calculate"]`, "retail", "bootstrap", createdAt, nil, nil))
+
+		key, err := repo.GetByHash(context.Background(), "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &APIKey{ID: 3, Name: "checkout", Prefix: "pk_0123abcd", KeyHash: "hash", Scopes: []string{"calculate"}, Tenant: "retail", CreatedBy: "bootstrap", CreatedAt: createdAt}, key)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1`)).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		key, err := repo.GetByHash(context.Background(), "hash")
+
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1`)).
+			WillReturnError(errors.New("database error"))
+
+		key, err := repo.GetByHash(context.Background(), "hash")
+
+		assert.Error(t, err)
+		assert.Nil(t, key)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Run("default tenant includes unbound keys", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE tenant_id IN ($1,$2) ORDER BY id`)).
+			WithArgs("", tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "scopes", "created_by", "created_at", "last_used_at", "revoked_at"}).
+				AddRow(3, "checkout", "pk_0123abcd", "hash", `["calculate"]`, "bootstrap", createdAt, nil, nil).
+				AddRow(4, "ops", "pk_4567ef01", "other", `["admin"]`, "api_key:3", createdAt, nil, createdAt))
+
+		keys, err := repo.List(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, keys, 2)
+		assert.Equal(t, []string{"admin"}, keys[1].Scopes)
+		assert.Equal(t, &createdAt, keys[1].RevokedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("other tenant only includes its keys", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE tenant_id = $1 ORDER BY id`)).
+			WithArgs("retail").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "scopes", "tenant_id"}).
+				AddRow(5, "shop", "pk_89abcdef", "hash", `["calculate"]`, "retail"))
+
+		keys, err := repo.List(tenant.NewContext(context.Background(), "retail"))
+
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, "retail", keys[0].Tenant)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	t.Run("revoked key", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		now := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "api_keys" SET "revoked_at"=$1 WHERE (id = $2 AND revoked_at IS NULL) AND tenant_id = $3`)).
+			WithArgs(now, 3, "retail").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, audit_events.ActionAPIKeyRevoke, "3", `{"revokedAt":null}`, `{"revokedAt":"2026-10-18T17:00:00Z"}`)
+		mock.ExpectCommit()
+
+		err := repo.Revoke(tenant.NewContext(context.Background(), "retail"), 3, now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("key revoked before", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		now := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "api_keys" SET "revoked_at"=$1 WHERE (id = $2 AND revoked_at IS NULL) AND tenant_id = $3`)).
+			WithArgs(now, 3, "retail").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Revoke(tenant.NewContext(context.Background(), "retail"), 3, now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTouchLastUsed(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	now := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "api_keys" SET "last_used_at"=$1 WHERE id = $2`)).
+		WithArgs(now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.TouchLastUsed(context.Background(), 3, now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,130 @@
+package api_keys
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+var (
+	// ErrKeyNotFound is returned when a referenced API key does not exist
+	ErrKeyNotFound = errors.New("API key not found")
+	// ErrInvalidKey is returned when an API key is unknown or revoked
+	ErrInvalidKey = errors.New("invalid API key")
+)
+
+// lastUsedInterval limits how often the last use of a key is written
+const lastUsedInterval = time.Minute
+
+// prefixLength is the number of characters of a key kept to tell keys apart
+const prefixLength = len(KeyPrefix) + 8
+
+type Service interface {
+	Issue(ctx context.Context, request *IssueAPIRequest) (*IssueAPIResponse, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, id uint) (*APIKey, error)
+	Authenticate(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+type service struct {
+	logger *zap.Logger
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Issue creates a key with the requested scopes and tenant, recording the principal that
+// issued it. Keys issued by a principal bound to a tenant, or for a request acting for a
+// tenant other than the default one, are bound to that tenant.
+// The key is returned once and cannot be retrieved later.
+func (s *service) Issue(ctx context.Context, request *IssueAPIRequest) (*IssueAPIResponse, error) {
+	tenantID := request.Tenant
+	if principal := auth.FromContext(ctx); principal != nil && principal.Tenant != "" {
+		if tenantID != "" && tenantID != principal.Tenant {
+			return nil, apperrors.NewForbiddenError("API keys can only be issued for the tenant of the issuer")
+		}
+		tenantID = principal.Tenant
+	}
+	if acting := tenant.FromContext(ctx); acting != tenant.Default {
+		if tenantID != "" && tenantID != acting {
+			return nil, apperrors.NewForbiddenError("API keys can only be issued for the tenant the request acts for")
+		}
+		tenantID = acting
+	}
+
+	key, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := APIKey{
+		Name:      request.Name,
+		Prefix:    key[:prefixLength],
+		KeyHash:   HashKey(key),
+		Scopes:    request.Scopes,
+		Tenant:    tenantID,
+		CreatedBy: auth.Subject(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, &apiKey); err != nil {
+		return nil, err
+	}
+	s.logger.Info("API key issued", zap.String("key", apiKey.Subject()), zap.Strings("scopes", apiKey.Scopes), zap.String("issued_by", apiKey.CreatedBy))
+	return &IssueAPIResponse{APIKey: apiKey, Key: key}, nil
+}
+
+func (s *service) List(ctx context.Context) ([]APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+// Revoke stops the key from authenticating. Revoking a revoked key returns it unchanged.
+func (s *service) Revoke(ctx context.Context, id uint) (*APIKey, error) {
+	if err := s.repo.Revoke(ctx, id, time.Now()); err != nil {
+		return nil, err
+	}
+	apiKey, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrKeyNotFound
+	}
+	s.logger.Info("API key revoked", zap.String("key", apiKey.Subject()), zap.String("revoked_by", auth.Subject(ctx)))
+	return apiKey, nil
+}
+
+// Authenticate returns the principal of a key that is not revoked. The last use of the
+// key is recorded at most once per lastUsedInterval and failing to record it is only logged.
+func (s *service) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return nil, ErrInvalidKey
+	}
+	apiKey, err := s.repo.GetByHash(ctx, HashKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || apiKey.RevokedAt != nil {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedInterval {
+		if err := s.repo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			s.logger.Warn("Failed to record API key use", zap.String("key", apiKey.Subject()), zap.Error(err))
+		}
+	}
+
+	return &auth.Principal{Subject: apiKey.Subject(), Name: apiKey.Name, Scopes: apiKey.Scopes, Tenant: apiKey.Tenant}, nil
+}
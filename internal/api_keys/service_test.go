@@ -0,0 +1,258 @@
+package api_keys
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, key *APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uint) (*APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*APIKey), args.Error(1)
+}
+
+func (m *MockRepository) GetByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*APIKey), args.Error(1)
+}
+
+func (m *MockRepository) List(ctx context.Context) ([]APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]APIKey), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(ctx context.Context, id uint, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TouchLastUsed(ctx context.Context, id uint, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
+func TestService_Issue(t *testing.T) {
+	t.Run("stores the hash and returns the key once", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		var stored *APIKey
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*api_keys.APIKey")).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).(*APIKey)
+				stored.ID = 3
+			}).
+			Return(nil)
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "bootstrap", Scopes: []string{auth.ScopeAdmin}})
+
+		got, err := NewService(zap.NewNop(), mockRepo).Issue(ctx, &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(got.Key, KeyPrefix))
+		assert.Equal(t, uint(3), got.ID)
+		assert.Equal(t, HashKey(got.Key), stored.KeyHash)
+		assert.Equal(t, got.Key[:prefixLength], stored.Prefix)
+		assert.Equal(t, "bootstrap", stored.CreatedBy)
+		assert.Equal(t, []string{auth.ScopeCalculate}, stored.Scopes)
+	})
+
+	t.Run("key of a tenant bound issuer inherits its tenant", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		var stored *APIKey
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*api_keys.APIKey")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*APIKey) }).
+			Return(nil)
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "api_key:1", Scopes: []string{auth.ScopeAdmin}, Tenant: "retail"})
+
+		_, err := NewService(zap.NewNop(), mockRepo).Issue(ctx, &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "retail", stored.Tenant)
+	})
+
+	t.Run("tenant bound issuer cannot issue keys for another tenant", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "api_key:1", Scopes: []string{auth.ScopeAdmin}, Tenant: "retail"})
+
+		got, err := NewService(zap.NewNop(), mockRepo).Issue(ctx, &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}, Tenant: "wholesale"})
+
+		assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeForbidden))
+		assert.Nil(t, got)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("key issued for a tenant is bound to it", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		var stored *APIKey
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*api_keys.APIKey")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*APIKey) }).
+			Return(nil)
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "bootstrap", Scopes: []string{auth.ScopeAdmin}})
+
+		_, err := NewService(zap.NewNop(), mockRepo).Issue(tenant.NewContext(ctx, "retail"), &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "retail", stored.Tenant)
+	})
+
+	t.Run("key cannot be issued for another tenant than the request acts for", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "bootstrap", Scopes: []string{auth.ScopeAdmin}})
+
+		got, err := NewService(zap.NewNop(), mockRepo).Issue(tenant.NewContext(ctx, "retail"), &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}, Tenant: "wholesale"})
+
+		assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeForbidden))
+		assert.Nil(t, got)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+		got, err := NewService(zap.NewNop(), mockRepo).Issue(context.Background(), &IssueAPIRequest{Name: "checkout", Scopes: []string{auth.ScopeCalculate}})
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestService_Revoke(t *testing.T) {
+	t.Run("revoked key", func(t *testing.T) {
+		revokedAt := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+		mockRepo := new(MockRepository)
+		mockRepo.On("Revoke", mock.Anything, uint(3), mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, uint(3)).Return(&APIKey{ID: 3, RevokedAt: &revokedAt}, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo).Revoke(context.Background(), 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &revokedAt, got.RevokedAt)
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Revoke", mock.Anything, uint(3), mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, uint(3)).Return(nil, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo).Revoke(context.Background(), 3)
+
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		assert.Nil(t, got)
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	key := "pk_0123abcd0123abcd0123abcd0123abcd0123abcd0123abcd"
+	recently := time.Now().Add(-time.Second)
+	revokedAt := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		key           string
+		mockSetup     func(*MockRepository)
+		wantPrincipal *auth.Principal
+		wantErr       error
+	}{
+		{
+			name: "records the first use",
+			key:  key,
+			mockSetup: func(m *MockRepository) {
+				m.On("GetByHash", mock.Anything, HashKey(key)).Return(&APIKey{ID: 3, Name: "checkout", Scopes: []string{auth.ScopeCalculate}}, nil)
+				m.On("TouchLastUsed", mock.Anything, uint(3), mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			wantPrincipal: &auth.Principal{Subject: "api_key:3", Name: "checkout", Scopes: []string{auth.ScopeCalculate}},
+		},
+		{
+			name: "recently used key",
+			key:  key,
+			mockSetup: func(m *MockRepository) {
+				m.On("GetByHash", mock.Anything, HashKey(key)).Return(&APIKey{ID: 3, Name: "checkout", Scopes: []string{auth.ScopeCalculate}, LastUsedAt: &recently}, nil)
+			},
+			wantPrincipal: &auth.Principal{Subject: "api_key:3", Name: "checkout", Scopes: []string{auth.ScopeCalculate}},
+		},
+		{
+			name: "failing to record the use",
+			key:  key,
+			mockSetup: func(m *MockRepository) {
+				m.On("GetByHash", mock.Anything, HashKey(key)).Return(&APIKey{ID: 3, Name: "checkout", Scopes: []string{auth.ScopeCalculate}}, nil)
+				m.On("TouchLastUsed", mock.Anything, uint(3), mock.AnythingOfType("time.Time")).Return(errors.New("database error"))
+			},
+			wantPrincipal: &auth.Principal{Subject: "api_key:3", Name: "checkout", Scopes: []string{auth.ScopeCalculate}},
+		},
+		{
+			name: "key bound to a tenant",
+			key:  key,
+			mockSetup: func(m *MockRepository) {
+				m.On("GetByHash", mock.Anything, HashKey(key)).Return(&APIKey{ID: 3, Name: "checkout", Scopes: []string{auth.ScopeCalculate}, Tenant: "retail", LastUsedAt: &recently}, nil)
+			},
+			wantPrincipal: &auth.Principal{Subject: "api_key:3", Name: "checkout", Scopes: []string{auth.ScopeCalculate}, Tenant: "retail"},
+		},
+		{
+			name:      "not an API key",
+			key:       "Bearer token",
+			mockSetup: func(m *MockRepository) {},
+			wantErr:   ErrInvalidKey,
+		},
+		{
+			name: "unknown key",
+			key:  key,
+			mockSetup: func(m *MockRepository) {
+				m.On("GetByHash", mock.Anything, HashKey(key)).Return(nil, nil)
+			},
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name: "revoked key",
+			key:  key,
+			mockSetup: func(m *MockRepository) {
+				m.On("GetByHash", mock.Anything, HashKey(key)).Return(&APIKey{ID: 3, RevokedAt: &revokedAt}, nil)
+			},
+			wantErr: ErrInvalidKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tt.mockSetup(mockRepo)
+
+			got, err := NewService(zap.NewNop(), mockRepo).Authenticate(context.Background(), tt.key)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantPrincipal, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
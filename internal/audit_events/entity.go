@@ -0,0 +1,137 @@
+package audit_events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Actions recorded in the audit trail
+const (
+	ActionPackConfigurationCreate   = "pack_configuration.create"
+	ActionPackConfigurationActivate = "pack_configuration.activate"
+	ActionAPIKeyIssue               = "api_key.issue"
+	ActionAPIKeyRevoke              = "api_key.revoke"
+	ActionWarehouseCreate           = "warehouse.create"
+	ActionWarehouseUpdate           = "warehouse.update"
+	ActionReservationCreate         = "reservation.create"
+	ActionReservationCommit         = "reservation.commit"
+	ActionReservationRelease        = "reservation.release"
+	ActionCustomerProfileCreate     = "customer_profile.create"
+	ActionCustomerProfileUpdate     = "customer_profile.update"
+	ActionRateTableReplace          = "rate_table.replace"
+	ActionExperimentStart           = "experiment.start"
+	ActionExperimentStop            = "experiment.stop"
+)
+
+// Types of the resources changed by the recorded actions. A rate table is identified
+// by its carrier.
+const (
+	ResourcePackConfiguration = "pack_configuration"
+	ResourceAPIKey            = "api_key"
+	ResourceWarehouse         = "warehouse"
+	ResourceReservation       = "reservation"
+	ResourceCustomerProfile   = "customer_profile"
+	ResourceRateTable         = "rate_table"
+	ResourceExperiment        = "experiment"
+)
+
+// Event is an entry of the append-only audit trail. Hash covers the fields of the event
+// and PrevHash, the hash of the event before it, so changing or removing any event breaks
+// the chain from there on. Before and After are the JSON states of the resource, null
+// when it did not exist before or after the change. The events of every tenant form one
+// chain.
+type Event struct {
+	ID           uint            `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID     string          `gorm:"column:tenant_id;not null" json:"-"`
+	Action       string          `gorm:"column:action;not null" json:"action"`
+	ResourceType string          `gorm:"column:resource_type;not null" json:"resourceType"`
+	ResourceID   string          `gorm:"column:resource_id;not null" json:"resourceId"`
+	Actor        string          `gorm:"column:actor;not null" json:"actor"`
+	RequestID    string          `gorm:"column:request_id;not null" json:"requestId"`
+	ClientIP     string          `gorm:"column:client_ip;not null" json:"clientIp"`
+	Before       json.RawMessage `gorm:"column:before_state;serializer:json" json:"before"`
+	After        json.RawMessage `gorm:"column:after_state;serializer:json" json:"after"`
+	CreatedAt    time.Time       `gorm:"column:created_at;not null" json:"createdAt"`
+	PrevHash     string          `gorm:"column:prev_hash;not null" json:"prevHash"`
+	Hash         string          `gorm:"column:hash;not null" json:"hash"`
+}
+
+// TableName overrides the default table name for events
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+// ComputeHash returns the hex encoded SHA-256 hash of the event and PrevHash
+func (e *Event) ComputeHash() string {
+	// The fields are hashed in a fixed order, with the time at the precision it is stored.
+	// The tenant is only hashed when it is not the default one, so the hashes of events
+	// written before tenants were introduced still verify.
+	fields := []any{
+		e.PrevHash, e.Action, e.ResourceType, e.ResourceID, e.Actor, e.RequestID, e.ClientIP,
+		e.Before, e.After, e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if e.TenantID != "" && e.TenantID != tenant.Default {
+		fields = append(fields, e.TenantID)
+	}
+	content, _ := json.Marshal(fields)
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+// Change describes a mutation to record in the audit trail. Before and After are encoded
+// as JSON; nil records a resource that did not exist.
+type Change struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       any
+	After        any
+}
+
+// Filter selects events by their fields and creation time. Empty fields match every event.
+type Filter struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	Offset       int
+	Limit        int
+}
+
+const (
+	// DefaultEventsLimit is the page size when a listing does not set one
+	DefaultEventsLimit = 50
+	// MaxEventsLimit is the largest page a listing may request
+	MaxEventsLimit = 500
+)
+
+// ListEventsAPIRequest represents the query parameters of an audit trail listing
+type ListEventsAPIRequest struct {
+	Actor        string    `form:"actor"`
+	Action       string    `form:"action"`
+	ResourceType string    `form:"resourceType"`
+	ResourceID   string    `form:"resourceId"`
+	From         time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Offset       int       `form:"offset"`
+	Limit        int       `form:"limit"`
+}
+
+// EventsAPIResponse represents an API response listing audit events, newest first
+type EventsAPIResponse struct {
+	Events []Event `json:"events"`
+}
+
+// Verification is the outcome of checking the hash chain. BrokenAt is the first event
+// whose hash or link to the event before it does not match.
+type Verification struct {
+	Valid    bool  `json:"valid"`
+	Events   int   `json:"events"`
+	BrokenAt *uint `json:"brokenAt,omitempty"`
+}
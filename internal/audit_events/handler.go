@@ -0,0 +1,67 @@
+package audit_events
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// ListEvents returns a page of the audit trail, newest first, filtered by the query
+func (h *Handler) ListEvents(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*ListEventsAPIRequest)
+
+	events, err := h.service.List(c.Request.Context(), Filter{
+		Actor:        request.Actor,
+		Action:       request.Action,
+		ResourceType: request.ResourceType,
+		ResourceID:   request.ResourceID,
+		From:         request.From,
+		To:           request.To,
+		Offset:       request.Offset,
+		Limit:        request.Limit,
+	})
+	if err != nil {
+		c.Error(errors.Wrap("Failed to retrieve audit events", err))
+		return
+	}
+	if events == nil {
+		events = []Event{}
+	}
+	c.JSON(http.StatusOK, EventsAPIResponse{Events: events})
+}
+
+// VerifyChain checks the hash chain of the audit trail. The chain links the events of
+// every tenant, so principals bound to a tenant cannot verify it.
+func (h *Handler) VerifyChain(c *gin.Context) {
+	if principal := auth.FromContext(c.Request.Context()); principal != nil && principal.Tenant != "" {
+		c.Error(errors.NewForbiddenError("The audit trail can only be verified with credentials not bound to a tenant"))
+		return
+	}
+	verification, err := h.service.Verify(c.Request.Context())
+	if err != nil {
+		c.Error(errors.Wrap("Failed to verify the audit trail", err))
+		return
+	}
+	c.JSON(http.StatusOK, verification)
+}
@@ -0,0 +1,182 @@
+package audit_events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) List(ctx context.Context, filter Filter) ([]Event, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+func (m *MockService) Verify(ctx context.Context) (*Verification, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Verification), args.Error(1)
+}
+
+func TestHandler_ListEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	request := &ListEventsAPIRequest{Action: ActionAPIKeyIssue, From: from, Limit: 20}
+	filter := Filter{Action: ActionAPIKeyIssue, From: from, Limit: 20}
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", request)
+			},
+			mockSetup: func(m *MockService) {
+				m.On("List", mock.Anything, filter).Return([]Event{{ID: 2, Action: ActionAPIKeyIssue, After: json.RawMessage(`{"id":3}`)}}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"events":[{"id":2,"action":"api_key.issue","resourceType":"","resourceId":"","actor":"","requestId":"","clientIp":"","before":null,"after":{"id":3},"createdAt":"0001-01-01T00:00:00Z","prevHash":"","hash":""}]}`,
+		},
+		{
+			name: "no events",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", request)
+			},
+			mockSetup: func(m *MockService) {
+				m.On("List", mock.Anything, filter).Return(nil, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"events":[]}`,
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", request)
+			},
+			mockSetup: func(m *MockService) {
+				m.On("List", mock.Anything, filter).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/audit", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).ListEvents(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_VerifyChain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	brokenAt := uint(3)
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:      "broken chain",
+			principal: &auth.Principal{Subject: "api_key:1", Scopes: []string{auth.ScopeAdmin}},
+			mockSetup: func(m *MockService) {
+				m.On("Verify", mock.Anything).Return(&Verification{Events: 3, BrokenAt: &brokenAt}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"valid":false,"events":3,"brokenAt":3}`,
+		},
+		{
+			name: "service error",
+			mockSetup: func(m *MockService) {
+				m.On("Verify", mock.Anything).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:           "admin bound to a tenant",
+			principal:      &auth.Principal{Subject: "api_key:2", Scopes: []string{auth.ScopeAdmin}, Tenant: "retail"},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/verify", nil)
+			if tt.principal != nil {
+				c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), tt.principal))
+			}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).VerifyChain(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
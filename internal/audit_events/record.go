@@ -0,0 +1,71 @@
+package audit_events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// chainLockID is the transaction level advisory lock that serializes appending events, so
+// each event links to the one committed before it
+const chainLockID = 7_261_019
+
+// Source is where the request that made a change came from
+type Source struct {
+	RequestID string
+	ClientIP  string
+}
+
+type sourceKey struct{}
+
+// NewContext returns a copy of ctx that carries the source of the request
+func NewContext(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFrom returns the source of the request of ctx, empty outside of a request
+func SourceFrom(ctx context.Context) Source {
+	source, _ := ctx.Value(sourceKey{}).(Source)
+	return source
+}
+
+// Record appends the change to the audit trail in the transaction tx, so the event is only
+// kept when the change is committed. The tenant, actor and source are taken from ctx.
+func Record(ctx context.Context, tx *gorm.DB, change Change) error {
+	before, err := json.Marshal(change.Before)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(change.After)
+	if err != nil {
+		return err
+	}
+
+	source := SourceFrom(ctx)
+	event := &Event{
+		TenantID:     tenant.FromContext(ctx),
+		Action:       change.Action,
+		ResourceType: change.ResourceType,
+		ResourceID:   change.ResourceID,
+		Actor:        auth.Subject(ctx),
+		RequestID:    source.RequestID,
+		ClientIP:     source.ClientIP,
+		Before:       before,
+		After:        after,
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockID).Error; err != nil {
+		return err
+	}
+	if err := tx.Raw("SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1").Scan(&event.PrevHash).Error; err != nil {
+		return err
+	}
+	event.Hash = event.ComputeHash()
+	return tx.Create(event).Error
+}
@@ -0,0 +1,113 @@
+package audit_events
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/auth"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+// capture matches any argument and keeps it
+type capture struct {
+	value driver.Value
+}
+
+func (c *capture) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+func TestRecord(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "user:alice"})
+	ctx = NewContext(ctx, Source{RequestID: "req-1", ClientIP: "10.0.0.7"})
+	ctx = tenant.NewContext(ctx, "retail")
+	createdAt, hash := &capture{}, &capture{}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WithArgs(chainLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events" ("tenant_id","action","resource_type","resource_id","actor","request_id","client_ip","before_state","after_state","created_at","prev_hash","hash") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING "id"`)).
+		WithArgs("retail", ActionPackConfigurationActivate, "pack_configuration", "2", "user:alice", "req-1", "10.0.0.7", `{"activeConfigurationId":1}`, `{"activeConfigurationId":2}`, createdAt, "previous", hash).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectCommit()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return Record(ctx, tx, Change{
+			Action:       ActionPackConfigurationActivate,
+			ResourceType: "pack_configuration",
+			ResourceID:   "2",
+			Before:       map[string]int{"activeConfigurationId": 1},
+			After:        map[string]int{"activeConfigurationId": 2},
+		})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// The stored hash is the one the event is verified against
+	event := Event{
+		TenantID: "retail", Action: ActionPackConfigurationActivate, ResourceType: "pack_configuration", ResourceID: "2",
+		Actor: "user:alice", RequestID: "req-1", ClientIP: "10.0.0.7",
+		Before: []byte(`{"activeConfigurationId":1}`), After: []byte(`{"activeConfigurationId":2}`),
+		PrevHash: "previous",
+	}
+	event.CreatedAt = createdAt.value.(time.Time)
+	assert.Equal(t, event.ComputeHash(), hash.value)
+}
+
+func TestRecord_FirstEvent(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events`)).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+		WithArgs(tenant.Default, ActionAPIKeyIssue, "api_key", "1", "anonymous", "", "", nil, `{"id":1}`, sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return Record(context.Background(), tx, Change{Action: ActionAPIKeyIssue, ResourceType: "api_key", ResourceID: "1", After: map[string]int{"id": 1}})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package audit_events
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for reading the audit trail. Events are only written
+// by Record, in the transaction of the change they describe. List returns the events of
+// the tenant of the context, while ListAfter walks the chain of every tenant.
+type Repository interface {
+	List(ctx context.Context, filter Filter) ([]Event, error)
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]Event, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// List returns a page of the events of the tenant matching the filter, newest first
+func (r *gormRepository) List(ctx context.Context, filter Filter) ([]Event, error) {
+	query := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx))
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var events []Event
+	err := query.Order("id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&events).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return events, nil
+}
+
+// ListAfter returns up to limit events following the event with the ID, oldest first
+func (r *gormRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]Event, error) {
+	var events []Event
+	err := r.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return events, nil
+}
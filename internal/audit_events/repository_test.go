@@ -0,0 +1,77 @@
+package audit_events
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+var eventColumns = []string{"id", "action", "resource_type", "resource_id", "actor", "request_id", "client_ip", "before_state", "after_state", "created_at", "prev_hash", "hash"}
+
+func TestList(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	t.Run("filtered page", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_events" WHERE actor = $1 AND resource_type = $2 AND created_at >= $3 AND tenant_id = $4 ORDER BY id DESC LIMIT $5 OFFSET $6`)).
+			WithArgs("user:alice", "warehouse", from, "retail", 20, 40).
+			WillReturnRows(sqlmock.NewRows(eventColumns).
+				AddRow(5, ActionWarehouseUpdate, "warehouse", "1", "user:alice", "req-1", "10.0.0.7", `{"id":1,"name":"north","inventory":{"250":4}}`, `{"id":1,"name":"north","inventory":{"250":9}}`, createdAt, "prev", "hash"))
+
+		events, err := repo.List(tenant.NewContext(context.Background(), "retail"), Filter{Actor: "user:alice", ResourceType: "warehouse", From: from, Offset: 40, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+		assert.JSONEq(t, `{"id":1,"name":"north","inventory":{"250":9}}`, string(events[0].After))
+		assert.Equal(t, "req-1", events[0].RequestID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_events"`)).
+			WillReturnError(errors.New("database error"))
+
+		events, err := repo.List(context.Background(), Filter{Limit: 20})
+
+		assert.Error(t, err)
+		assert.Nil(t, events)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListAfter(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	createdAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_events" WHERE id > $1 ORDER BY id LIMIT $2`)).
+		WithArgs(4, 1000).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(5, ActionAPIKeyIssue, "api_key", "3", "bootstrap", "", "", nil, `{"id":3}`, createdAt, "prev", "hash"))
+
+	events, err := repo.ListAfter(context.Background(), 4, 1000)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Nil(t, events[0].Before)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package audit_events
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// verifyBatchSize is the number of events read at a time when verifying the chain
+const verifyBatchSize = 1000
+
+type Service interface {
+	List(ctx context.Context, filter Filter) ([]Event, error)
+	Verify(ctx context.Context) (*Verification, error)
+}
+
+type service struct {
+	logger *zap.Logger
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// List returns a page of the events matching the filter, newest first
+func (s *service) List(ctx context.Context, filter Filter) ([]Event, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Verify walks the audit trail from the first event and checks that each event links to
+// the one before it and still has the hash it was written with
+func (s *service) Verify(ctx context.Context) (*Verification, error) {
+	verification := &Verification{Valid: true}
+	var lastID uint
+	prevHash := ""
+	for {
+		events, err := s.repo.ListAfter(ctx, lastID, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			verification.Events++
+			if event.PrevHash != prevHash || event.ComputeHash() != event.Hash {
+				id := event.ID
+				verification.Valid = false
+				verification.BrokenAt = &id
+				s.logger.Error("Audit trail hash chain is broken", zap.Uint("event", id))
+				return verification, nil
+			}
+			prevHash = event.Hash
+			lastID = event.ID
+		}
+		if len(events) < verifyBatchSize {
+			return verification, nil
+		}
+	}
+}
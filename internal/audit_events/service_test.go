@@ -0,0 +1,149 @@
+package audit_events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) List(ctx context.Context, filter Filter) ([]Event, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+func (m *MockRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]Event, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+// chain returns events linked by their hashes, as Record writes them
+func chain(n int) []Event {
+	events := make([]Event, 0, n)
+	prevHash := ""
+	for i := 1; i <= n; i++ {
+		event := Event{
+			ID:           uint(i),
+			Action:       ActionWarehouseUpdate,
+			ResourceType: "warehouse",
+			ResourceID:   "1",
+			Actor:        "user:alice",
+			Before:       json.RawMessage(`{"inventory":{"250":4}}`),
+			After:        json.RawMessage(`{"inventory":{"250":9}}`),
+			CreatedAt:    time.Date(2026, 10, 18, 9, i, 0, 0, time.UTC),
+			PrevHash:     prevHash,
+		}
+		event.Hash = event.ComputeHash()
+		prevHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestService_Verify(t *testing.T) {
+	uint3 := uint(3)
+
+	tests := []struct {
+		name    string
+		events  func() []Event
+		want    *Verification
+		wantErr bool
+	}{
+		{
+			name:   "intact chain",
+			events: func() []Event { return chain(4) },
+			want:   &Verification{Valid: true, Events: 4},
+		},
+		{
+			name:   "empty trail",
+			events: func() []Event { return []Event{} },
+			want:   &Verification{Valid: true},
+		},
+		{
+			name: "changed event",
+			events: func() []Event {
+				events := chain(4)
+				events[2].After = json.RawMessage(`{"inventory":{"250":900}}`)
+				return events
+			},
+			want: &Verification{Valid: false, Events: 3, BrokenAt: &uint3},
+		},
+		{
+			name: "event moved to another tenant",
+			events: func() []Event {
+				events := chain(4)
+				events[2].TenantID = "retail"
+				return events
+			},
+			want: &Verification{Valid: false, Events: 3, BrokenAt: &uint3},
+		},
+		{
+			name: "removed event",
+			events: func() []Event {
+				events := chain(4)
+				return append(events[:1:1], events[2:]...)
+			},
+			want: &Verification{Valid: false, Events: 2, BrokenAt: &uint3},
+		},
+		{
+			name: "rehashed event",
+			events: func() []Event {
+				events := chain(4)
+				events[2].Actor = "user:mallory"
+				events[2].Hash = events[2].ComputeHash()
+				return events
+			},
+			want: &Verification{Valid: false, Events: 4, BrokenAt: func() *uint { id := uint(4); return &id }()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockRepo.On("ListAfter", mock.Anything, uint(0), verifyBatchSize).Return(tt.events(), nil)
+
+			got, err := NewService(zap.NewNop(), mockRepo).Verify(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("reads the trail in batches", func(t *testing.T) {
+		events := chain(verifyBatchSize + 1)
+		mockRepo := new(MockRepository)
+		mockRepo.On("ListAfter", mock.Anything, uint(0), verifyBatchSize).Return(events[:verifyBatchSize], nil)
+		mockRepo.On("ListAfter", mock.Anything, uint(verifyBatchSize), verifyBatchSize).Return(events[verifyBatchSize:], nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo).Verify(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Verification{Valid: true, Events: verifyBatchSize + 1}, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("ListAfter", mock.Anything, uint(0), verifyBatchSize).Return(nil, errors.New("database error"))
+
+		got, err := NewService(zap.NewNop(), mockRepo).Verify(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,59 @@
+package customers
+
+import "sort"
+
+// Profile restricts or extends the active pack sizes for one customer. ExtraSizes are
+// accepted in addition to the active sizes, ExcludedSizes are never used, and a non-zero
+// MinSize or MaxSize bounds the sizes the customer accepts.
+type Profile struct {
+	TenantID      string `gorm:"column:tenant_id;primarykey" json:"-"`
+	CustomerID    string `gorm:"column:customer_id;primarykey" json:"customerId"`
+	Name          string `gorm:"column:name;not null" json:"name"`
+	ExtraSizes    []int  `gorm:"column:extra_sizes;serializer:json;not null" json:"extraSizes"`
+	ExcludedSizes []int  `gorm:"column:excluded_sizes;serializer:json;not null" json:"excludedSizes"`
+	MinSize       int    `gorm:"column:min_size;not null" json:"minSize,omitempty"`
+	MaxSize       int    `gorm:"column:max_size;not null" json:"maxSize,omitempty"`
+}
+
+// TableName overrides the default table name for profiles
+func (Profile) TableName() string {
+	return "customer_profiles"
+}
+
+// EffectiveSizes applies the profile to the active pack sizes, returning the sizes
+// the customer accepts in ascending order
+func (p Profile) EffectiveSizes(activeSizes []int) []int {
+	excluded := make(map[int]bool, len(p.ExcludedSizes))
+	for _, size := range p.ExcludedSizes {
+		excluded[size] = true
+	}
+
+	seen := make(map[int]bool)
+	sizes := []int{}
+	for _, group := range [][]int{activeSizes, p.ExtraSizes} {
+		for _, size := range group {
+			if seen[size] || excluded[size] || size < p.MinSize || (p.MaxSize > 0 && size > p.MaxSize) {
+				continue
+			}
+			seen[size] = true
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+// ProfileAPIRequest represents an API request to create or update a customer profile
+type ProfileAPIRequest struct {
+	CustomerID    string `json:"customerId"`
+	Name          string `json:"name"`
+	ExtraSizes    []int  `json:"extraSizes,omitempty"`
+	ExcludedSizes []int  `json:"excludedSizes,omitempty"`
+	MinSize       int    `json:"minSize,omitempty"`
+	MaxSize       int    `json:"maxSize,omitempty"`
+}
+
+// ProfilesAPIResponse represents an API response listing customer profiles
+type ProfilesAPIResponse struct {
+	Profiles []Profile `json:"profiles"`
+}
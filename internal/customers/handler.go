@@ -0,0 +1,65 @@
+package customers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// ListProfiles returns every customer profile
+func (h *Handler) ListProfiles(c *gin.Context) {
+	profiles, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.Error(errors.Wrap("Failed to retrieve customer profiles", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ProfilesAPIResponse{Profiles: profiles})
+}
+
+// SaveProfile creates a customer profile or replaces the existing one
+func (h *Handler) SaveProfile(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*ProfileAPIRequest)
+
+	profile := &Profile{
+		CustomerID:    request.CustomerID,
+		Name:          request.Name,
+		ExtraSizes:    request.ExtraSizes,
+		ExcludedSizes: request.ExcludedSizes,
+		MinSize:       request.MinSize,
+		MaxSize:       request.MaxSize,
+	}
+	// Store and return empty lists rather than null for sizes left out of the request
+	if profile.ExtraSizes == nil {
+		profile.ExtraSizes = []int{}
+	}
+	if profile.ExcludedSizes == nil {
+		profile.ExcludedSizes = []int{}
+	}
+	if err := h.service.Save(c.Request.Context(), profile); err != nil {
+		c.Error(errors.Wrap("Failed to save customer profile", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
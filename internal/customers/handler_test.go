@@ -0,0 +1,133 @@
+package customers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Save(ctx context.Context, profile *Profile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockService) List(ctx context.Context) ([]Profile, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Profile), args.Error(1)
+}
+
+func TestHandler_ListProfiles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success case", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/customers", nil)
+		mockService := new(MockService)
+		mockService.On("List", mock.Anything).Return([]Profile{{CustomerID: "acme", Name: "Acme", ExcludedSizes: []int{5000}}}, nil)
+
+		NewHandler(zap.NewNop(), mockService).ListProfiles(c)
+		renderErrors(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response ProfilesAPIResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "acme", response.Profiles[0].CustomerID)
+		assert.Equal(t, []int{5000}, response.Profiles[0].ExcludedSizes)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/customers", nil)
+		mockService := new(MockService)
+		mockService.On("List", mock.Anything).Return(nil, errors.New("service error"))
+
+		NewHandler(zap.NewNop(), mockService).ListProfiles(c)
+		renderErrors(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestHandler_SaveProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ProfileAPIRequest{CustomerID: "bulk", Name: "Bulk buyer", MinSize: 1000})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Save", mock.Anything, &Profile{CustomerID: "bulk", Name: "Bulk buyer", ExtraSizes: []int{}, ExcludedSizes: []int{}, MinSize: 1000}).Return(nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ProfileAPIRequest{CustomerID: "bulk", Name: "Bulk buyer"})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Save", mock.Anything, mock.Anything).Return(errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/customers", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).SaveProfile(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
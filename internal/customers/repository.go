@@ -0,0 +1,84 @@
+package customers
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for customer profile persistence operations. Every
+// operation acts on the profiles of the tenant of the context.
+type Repository interface {
+	Save(ctx context.Context, profile *Profile) error
+	GetByCustomerID(ctx context.Context, customerID string) (*Profile, error)
+	List(ctx context.Context) ([]Profile, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// Save creates the profile, or replaces the profile stored for the same customer of the
+// tenant of ctx, and records the change in the audit trail. The stored profile is locked
+// for the duration of the transaction.
+func (r *gormRepository) Save(ctx context.Context, profile *Profile) error {
+	profile.TenantID = tenant.FromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		action := audit_events.ActionCustomerProfileUpdate
+		var before *Profile
+		var stored Profile
+		err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).Where("customer_id = ?", profile.CustomerID).First(&stored).Error
+		switch {
+		case err == nil:
+			before = &stored
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			action = audit_events.ActionCustomerProfileCreate
+		default:
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(profile).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       action,
+			ResourceType: audit_events.ResourceCustomerProfile,
+			ResourceID:   profile.CustomerID,
+			Before:       before,
+			After:        profile,
+		})
+	})
+	return postgres.ClassifyError(err)
+}
+
+func (r *gormRepository) GetByCustomerID(ctx context.Context, customerID string) (*Profile, error) {
+	var profile Profile
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Where("customer_id = ?", customerID).First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &profile, nil
+}
+
+func (r *gormRepository) List(ctx context.Context) ([]Profile, error) {
+	var profiles []Profile
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Order("customer_id").Find(&profiles).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return profiles, nil
+}
@@ -0,0 +1,180 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+// expectAuditEvent expects an event to be appended to the audit trail with the states
+func expectAuditEvent(mock sqlmock.Sqlmock, action, resourceID string, before, after driver.Value) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+		WithArgs(sqlmock.AnyArg(), action, audit_events.ResourceCustomerProfile, resourceID, "anonymous", "", "", before, after, sqlmock.AnyArg(), "previous", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func TestSave(t *testing.T) {
+	selectStored := regexp.QuoteMeta(`SELECT * FROM "customer_profiles" WHERE customer_id = $1 AND tenant_id = $2 ORDER BY "customer_profiles"."tenant_id" LIMIT $3 FOR UPDATE`)
+	upsert := regexp.QuoteMeta(`INSERT INTO "customer_profiles" ("tenant_id","customer_id","name","extra_sizes","excluded_sizes","min_size","max_size") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("tenant_id","customer_id") DO UPDATE SET "name"="excluded"."name","extra_sizes"="excluded"."extra_sizes","excluded_sizes"="excluded"."excluded_sizes","min_size"="excluded"."min_size","max_size"="excluded"."max_size"`)
+
+	t.Run("creates the profile", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		profile := &Profile{CustomerID: "acme", Name: "Acme", ExtraSizes: []int{750}, ExcludedSizes: []int{5000}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectStored).
+			WithArgs("acme", tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "customer_id"}))
+		mock.ExpectExec(upsert).
+			WithArgs(tenant.Default, "acme", "Acme", "[750]", "[5000]", 0, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, audit_events.ActionCustomerProfileCreate, "acme", nil, `{"customerId":"acme","name":"Acme","extraSizes":[750],"excludedSizes":[5000]}`)
+		mock.ExpectCommit()
+
+		err := repo.Save(context.Background(), profile)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("replaces the stored profile", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		profile := &Profile{CustomerID: "acme", Name: "Acme", ExtraSizes: []int{}, ExcludedSizes: []int{250}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectStored).
+			WithArgs("acme", "retail", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "customer_id", "name", "extra_sizes", "excluded_sizes", "min_size", "max_size"}).
+				AddRow("retail", "acme", "Acme", "[]", "[]", 0, 0))
+		mock.ExpectExec(upsert).
+			WithArgs("retail", "acme", "Acme", "[]", "[250]", 0, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, audit_events.ActionCustomerProfileUpdate, "acme",
+			`{"customerId":"acme","name":"Acme","extraSizes":[],"excludedSizes":[]}`,
+			`{"customerId":"acme","name":"Acme","extraSizes":[],"excludedSizes":[250]}`)
+		mock.ExpectCommit()
+
+		err := repo.Save(tenant.NewContext(context.Background(), "retail"), profile)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectStored).
+			WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "customer_id"}))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "customer_profiles"`)).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := repo.Save(context.Background(), &Profile{CustomerID: "acme", Name: "Acme"})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetByCustomerID(t *testing.T) {
+	t.Run("existing profile", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "customer_profiles" WHERE customer_id = $1 AND tenant_id = $2 ORDER BY "customer_profiles"."tenant_id" LIMIT $3`)).
+			WithArgs("bulk", "retail", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "customer_id", "name", "extra_sizes", "excluded_sizes", "min_size", "max_size"}).
+				AddRow("retail", "bulk", "Bulk buyer", "[]", "[]", 1000, 0))
+
+		profile, err := repo.GetByCustomerID(tenant.NewContext(context.Background(), "retail"), "bulk")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Profile{TenantID: "retail", CustomerID: "bulk", Name: "Bulk buyer", ExtraSizes: []int{}, ExcludedSizes: []int{}, MinSize: 1000}, profile)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown customer", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "customer_profiles" WHERE customer_id = $1 AND tenant_id = $2`)).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		profile, err := repo.GetByCustomerID(context.Background(), "nobody")
+
+		assert.NoError(t, err)
+		assert.Nil(t, profile)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestList(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "customer_profiles" WHERE tenant_id = $1 ORDER BY customer_id`)).
+		WithArgs(tenant.Default).
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "name", "extra_sizes", "excluded_sizes", "min_size", "max_size"}).
+			AddRow("acme", "Acme", "[750]", "[5000]", 0, 0).
+			AddRow("bulk", "Bulk buyer", "[]", "[]", 1000, 0))
+
+	profiles, err := repo.List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	assert.Equal(t, []int{5000}, profiles[0].ExcludedSizes)
+	assert.Equal(t, 1000, profiles[1].MinSize)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,33 @@
+package customers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Save(ctx context.Context, profile *Profile) error
+	List(ctx context.Context) ([]Profile, error)
+}
+
+type service struct {
+	logger *zap.Logger
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Save creates the profile, or replaces the profile of the same customer
+func (s *service) Save(ctx context.Context, profile *Profile) error {
+	return s.repo.Save(ctx, profile)
+}
+
+func (s *service) List(ctx context.Context) ([]Profile, error) {
+	return s.repo.List(ctx)
+}
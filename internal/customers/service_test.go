@@ -0,0 +1,121 @@
+package customers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, profile *Profile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByCustomerID(ctx context.Context, customerID string) (*Profile, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Profile), args.Error(1)
+}
+
+func (m *MockRepository) List(ctx context.Context) ([]Profile, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Profile), args.Error(1)
+}
+
+func TestService_Save(t *testing.T) {
+	profile := &Profile{CustomerID: "acme", Name: "Acme", ExcludedSizes: []int{5000}}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Save", mock.Anything, profile).Return(nil)
+
+		err := NewService(zap.NewNop(), mockRepo).Save(context.Background(), profile)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Save", mock.Anything, profile).Return(errors.New("db error"))
+
+		err := NewService(zap.NewNop(), mockRepo).Save(context.Background(), profile)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("List", mock.Anything).Return([]Profile{{CustomerID: "acme"}}, nil)
+
+	got, err := NewService(zap.NewNop(), mockRepo).List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Profile{{CustomerID: "acme"}}, got)
+}
+
+func TestProfile_EffectiveSizes(t *testing.T) {
+	active := []int{250, 500, 1000, 2000, 5000}
+
+	tests := []struct {
+		name    string
+		profile Profile
+		want    []int
+	}{
+		{
+			name:    "empty profile keeps the active sizes",
+			profile: Profile{},
+			want:    []int{250, 500, 1000, 2000, 5000},
+		},
+		{
+			name:    "excluded size",
+			profile: Profile{ExcludedSizes: []int{5000}},
+			want:    []int{250, 500, 1000, 2000},
+		},
+		{
+			name:    "minimum size",
+			profile: Profile{MinSize: 1000},
+			want:    []int{1000, 2000, 5000},
+		},
+		{
+			name:    "size range",
+			profile: Profile{MinSize: 500, MaxSize: 2000},
+			want:    []int{500, 1000, 2000},
+		},
+		{
+			name:    "extra sizes are added in order without duplicates",
+			profile: Profile{ExtraSizes: []int{750, 500}},
+			want:    []int{250, 500, 750, 1000, 2000, 5000},
+		},
+		{
+			name:    "extra sizes respect the bounds",
+			profile: Profile{ExtraSizes: []int{750, 10000}, MaxSize: 5000},
+			want:    []int{250, 500, 750, 1000, 2000, 5000},
+		},
+		{
+			name:    "nothing left",
+			profile: Profile{MaxSize: 100},
+			want:    []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.profile.EffectiveSizes(active))
+		})
+	}
+}
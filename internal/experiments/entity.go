@@ -0,0 +1,95 @@
+package experiments
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// Experiment statuses
+const (
+	StatusRunning = "running"
+	StatusStopped = "stopped"
+)
+
+// Experiment arms an order can be assigned to
+const (
+	ArmControl   = "control"
+	ArmCandidate = "candidate"
+)
+
+// Experiment trials a candidate pack configuration on a share of the orders. TrafficShare
+// is the percentage of order references that are calculated with the candidate.
+type Experiment struct {
+	ID              uint       `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID        string     `gorm:"column:tenant_id;not null" json:"-"`
+	Name            string     `gorm:"column:name;not null" json:"name"`
+	ConfigurationID uint       `gorm:"column:configuration_id;not null" json:"configurationId"`
+	TrafficShare    float64    `gorm:"column:traffic_share;not null" json:"trafficShare"`
+	Status          string     `gorm:"column:status;not null" json:"status"`
+	StartedAt       time.Time  `gorm:"column:started_at;not null" json:"startedAt"`
+	StoppedAt       *time.Time `gorm:"column:stopped_at" json:"stoppedAt,omitempty"`
+}
+
+// Arm assigns an order reference to an arm of the experiment. The reference is hashed
+// together with the experiment ID, so an order always lands in the same arm of an
+// experiment while different experiments split the traffic independently.
+func (e Experiment) Arm(orderReference string) string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d:%s", e.ID, orderReference)
+	if float64(hash.Sum64()%10000) < e.TrafficShare*100 {
+		return ArmCandidate
+	}
+	return ArmControl
+}
+
+// Assignment records an order calculated in an arm of an experiment. Cached calculations
+// are shared between orders, so the arm is recorded per order rather than on the calculation.
+type Assignment struct {
+	ID             uint      `gorm:"column:id;primarykey;autoIncrement" json:"-"`
+	TenantID       string    `gorm:"column:tenant_id;not null" json:"-"`
+	ExperimentID   uint      `gorm:"column:experiment_id;not null" json:"experimentId"`
+	Arm            string    `gorm:"column:arm;not null" json:"arm"`
+	CalculationID  uint      `gorm:"column:calculation_id;not null" json:"calculationId"`
+	OrderReference string    `gorm:"column:order_reference;not null" json:"orderReference"`
+	OrderQuantity  int       `gorm:"column:order_quantity;not null" json:"-"`
+	TotalItems     int       `gorm:"column:total_items;not null" json:"-"`
+	TotalPacks     int       `gorm:"column:total_packs;not null" json:"-"`
+	Timestamp      time.Time `gorm:"column:timestamp;not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+// TableName overrides the default table name for assignments
+func (Assignment) TableName() string {
+	return "experiment_assignments"
+}
+
+// ArmResult sums the orders calculated in one arm of an experiment
+type ArmResult struct {
+	Arm           string `gorm:"column:arm"`
+	Orders        int    `gorm:"column:orders"`
+	TotalOverfill int    `gorm:"column:total_overfill"`
+	TotalPacks    int    `gorm:"column:total_packs"`
+}
+
+// ArmComparison reports the average overfill and pack count of one arm
+type ArmComparison struct {
+	Arm             string  `json:"arm"`
+	Orders          int     `json:"orders"`
+	TotalOverfill   int     `json:"totalOverfill"`
+	AverageOverfill float64 `json:"averageOverfill"`
+	TotalPacks      int     `json:"totalPacks"`
+	AveragePacks    float64 `json:"averagePacks"`
+}
+
+// Results compares the arms of an experiment
+type Results struct {
+	Experiment Experiment      `json:"experiment"`
+	Arms       []ArmComparison `json:"arms"`
+}
+
+// ExperimentAPIRequest represents an API request to start an experiment
+type ExperimentAPIRequest struct {
+	Name            string  `json:"name"`
+	ConfigurationID uint    `json:"configurationId"`
+	TrafficShare    float64 `json:"trafficShare"`
+}
@@ -0,0 +1,88 @@
+package experiments
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// StartExperiment starts trialling a candidate configuration on a share of the orders
+func (h *Handler) StartExperiment(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*ExperimentAPIRequest)
+
+	experiment := &Experiment{
+		Name:            request.Name,
+		ConfigurationID: request.ConfigurationID,
+		TrafficShare:    request.TrafficShare,
+	}
+	if err := h.service.Start(c.Request.Context(), experiment); err != nil {
+		c.Error(errors.Wrap("Failed to start experiment", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, experiment)
+}
+
+// StopExperiment stops routing orders to the candidate configuration of an experiment
+func (h *Handler) StopExperiment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid experiment ID", err))
+		return
+	}
+
+	experiment, err := h.service.Stop(c.Request.Context(), uint(id))
+	if err != nil {
+		if stderrors.Is(err, ErrExperimentNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("Experiment not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to stop experiment", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, experiment)
+}
+
+// GetResults compares the overfill and pack counts of the arms of an experiment
+func (h *Handler) GetResults(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid experiment ID", err))
+		return
+	}
+
+	results, err := h.service.Results(c.Request.Context(), uint(id))
+	if err != nil {
+		if stderrors.Is(err, ErrExperimentNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("Experiment not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to retrieve experiment results", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,251 @@
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Start(ctx context.Context, experiment *Experiment) error {
+	args := m.Called(ctx, experiment)
+	return args.Error(0)
+}
+
+func (m *MockService) Stop(ctx context.Context, id uint) (*Experiment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Experiment), args.Error(1)
+}
+
+func (m *MockService) Results(ctx context.Context, id uint) (*Results, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Results), args.Error(1)
+}
+
+func TestHandler_StartExperiment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ExperimentAPIRequest{Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Start", mock.Anything, &Experiment{Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10}).Return(nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "invalid candidate",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ExperimentAPIRequest{Name: "smaller packs", ConfigurationID: 1, TrafficShare: 10})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Start", mock.Anything, mock.Anything).Return(apperrors.NewValidationError("Candidate pack configuration must not be the active configuration"))
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ExperimentAPIRequest{Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Start", mock.Anything, mock.Anything).Return(errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/experiments", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).StartExperiment(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_StopExperiment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+	}{
+		{
+			name: "success case",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Stop", mock.Anything, uint(4)).Return(&Experiment{ID: 4, Status: StatusStopped}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Stop", mock.Anything, uint(4)).Return(nil, ErrExperimentNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Stop", mock.Anything, uint(4)).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/experiments/"+tt.id+"/stop", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).StopExperiment(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	results := &Results{
+		Experiment: Experiment{ID: 4, Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10, Status: StatusRunning},
+		Arms: []ArmComparison{
+			{Arm: ArmControl, Orders: 18, TotalOverfill: 90, AverageOverfill: 5, TotalPacks: 36, AveragePacks: 2},
+			{Arm: ArmCandidate, Orders: 2, TotalOverfill: 4, AverageOverfill: 2, TotalPacks: 5, AveragePacks: 2.5},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantResults    *Results
+	}{
+		{
+			name: "success case",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Results", mock.Anything, uint(4)).Return(results, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResults:    results,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Results", mock.Anything, uint(4)).Return(nil, ErrExperimentNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Results", mock.Anything, uint(4)).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/experiments/"+tt.id+"/results", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).GetResults(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantResults != nil {
+				var got Results
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantResults, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
@@ -0,0 +1,152 @@
+package experiments
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for experiment persistence operations. Every
+// operation acts on the experiments of the tenant of the context.
+type Repository interface {
+	Start(ctx context.Context, experiment *Experiment) error
+	GetByID(ctx context.Context, id uint) (*Experiment, error)
+	GetRunning(ctx context.Context) (*Experiment, error)
+	Stop(ctx context.Context, id uint, now time.Time) error
+	RecordAssignment(ctx context.Context, assignment *Assignment) error
+	ArmResults(ctx context.Context, experimentID uint) ([]ArmResult, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// scoped returns a session restricted to the rows of the tenant of ctx
+func (r *gormRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(tenant.Scope(ctx))
+}
+
+// Start stops the running experiment of the tenant, if any, and creates the new one in
+// one transaction, recording both in the audit trail
+func (r *gormRepository) Start(ctx context.Context, experiment *Experiment) error {
+	experiment.TenantID = tenant.FromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var running []Experiment
+		err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ?", StatusRunning).Find(&running).Error
+		if err != nil {
+			return err
+		}
+		for _, stopped := range running {
+			if err := stop(ctx, tx, stopped, experiment.StartedAt); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(experiment).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionExperimentStart,
+			ResourceType: audit_events.ResourceExperiment,
+			ResourceID:   strconv.FormatUint(uint64(experiment.ID), 10),
+			After:        experiment,
+		})
+	})
+	return postgres.ClassifyError(err)
+}
+
+// stop stops a running experiment and records it in the audit trail
+func stop(ctx context.Context, tx *gorm.DB, experiment Experiment, now time.Time) error {
+	before := experiment
+	err := tx.Model(&experiment).Updates(map[string]interface{}{"status": StatusStopped, "stopped_at": now}).Error
+	if err != nil {
+		return err
+	}
+	experiment.Status = StatusStopped
+	experiment.StoppedAt = &now
+	return audit_events.Record(ctx, tx, audit_events.Change{
+		Action:       audit_events.ActionExperimentStop,
+		ResourceType: audit_events.ResourceExperiment,
+		ResourceID:   strconv.FormatUint(uint64(experiment.ID), 10),
+		Before:       before,
+		After:        experiment,
+	})
+}
+
+func (r *gormRepository) GetByID(ctx context.Context, id uint) (*Experiment, error) {
+	var experiment Experiment
+	err := r.scoped(ctx).First(&experiment, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &experiment, nil
+}
+
+// GetRunning returns the running experiment, or nil when none is running
+func (r *gormRepository) GetRunning(ctx context.Context) (*Experiment, error) {
+	var experiment Experiment
+	err := r.scoped(ctx).Where("status = ?", StatusRunning).First(&experiment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &experiment, nil
+}
+
+// Stop stops the experiment when it is running, recording it in the audit trail. Stopping
+// an experiment that is not running changes nothing.
+func (r *gormRepository) Stop(ctx context.Context, id uint, now time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var experiment Experiment
+		err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", id, StatusRunning).First(&experiment).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return stop(ctx, tx, experiment, now)
+	})
+	return postgres.ClassifyError(err)
+}
+
+func (r *gormRepository) RecordAssignment(ctx context.Context, assignment *Assignment) error {
+	assignment.TenantID = tenant.FromContext(ctx)
+	return postgres.ClassifyError(r.db.WithContext(ctx).Create(assignment).Error)
+}
+
+// ArmResults sums the overfill and packs of the orders in each arm of an experiment.
+// Partially fulfilled orders ship fewer items than ordered and count as no overfill.
+func (r *gormRepository) ArmResults(ctx context.Context, experimentID uint) ([]ArmResult, error) {
+	var results []ArmResult
+	err := r.scoped(ctx).Model(&Assignment{}).
+		Select("arm, COUNT(*) AS orders, SUM(GREATEST(total_items - order_quantity, 0)) AS total_overfill, SUM(total_packs) AS total_packs").
+		Where("experiment_id = ?", experimentID).
+		Group("arm").
+		Order("arm").
+		Scan(&results).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,235 @@
+package experiments
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+// expectAuditEvent expects an event to be appended to the audit trail with the states
+func expectAuditEvent(mock sqlmock.Sqlmock, action, resourceID string, before, after driver.Value) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+		WithArgs(sqlmock.AnyArg(), action, audit_events.ResourceExperiment, resourceID, "anonymous", "", "", before, after, sqlmock.AnyArg(), "previous", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+var experimentColumns = []string{"id", "tenant_id", "name", "configuration_id", "traffic_share", "status", "started_at", "stopped_at"}
+
+func TestStart(t *testing.T) {
+	selectRunning := regexp.QuoteMeta(`SELECT * FROM "experiments" WHERE status = $1 AND tenant_id = $2 FOR UPDATE`)
+
+	t.Run("stops the running experiment and creates the new one", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		previousStart := time.Date(2026, 10, 11, 9, 0, 0, 0, time.UTC)
+		startedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+		experiment := &Experiment{Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10, Status: StatusRunning, StartedAt: startedAt}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRunning).
+			WithArgs(StatusRunning, "retail").
+			WillReturnRows(sqlmock.NewRows(experimentColumns).AddRow(3, "retail", "larger packs", 1, 20.0, StatusRunning, previousStart, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "experiments" SET "status"=$1,"stopped_at"=$2 WHERE "id" = $3`)).
+			WithArgs(StatusStopped, startedAt, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, audit_events.ActionExperimentStop, "3",
+			`{"id":3,"name":"larger packs","configurationId":1,"trafficShare":20,"status":"running","startedAt":"2026-10-11T09:00:00Z"}`,
+			`{"id":3,"name":"larger packs","configurationId":1,"trafficShare":20,"status":"stopped","startedAt":"2026-10-11T09:00:00Z","stoppedAt":"2026-10-18T09:00:00Z"}`)
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "experiments" ("tenant_id","name","configuration_id","traffic_share","status","started_at","stopped_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+			WithArgs("retail", "smaller packs", 2, 10.0, StatusRunning, startedAt, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		expectAuditEvent(mock, audit_events.ActionExperimentStart, "4", nil,
+			`{"id":4,"name":"smaller packs","configurationId":2,"trafficShare":10,"status":"running","startedAt":"2026-10-18T09:00:00Z"}`)
+		mock.ExpectCommit()
+
+		err := repo.Start(tenant.NewContext(context.Background(), "retail"), experiment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), experiment.ID)
+		assert.Equal(t, "retail", experiment.TenantID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error rolls back", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRunning).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := repo.Start(context.Background(), &Experiment{Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetRunning(t *testing.T) {
+	t.Run("running experiment", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		startedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "experiments" WHERE status = $1 AND tenant_id = $2 ORDER BY "experiments"."id" LIMIT $3`)).
+			WithArgs(StatusRunning, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "configuration_id", "traffic_share", "status", "started_at", "stopped_at"}).
+				AddRow(4, "smaller packs", 2, 10.0, StatusRunning, startedAt, nil))
+
+		experiment, err := repo.GetRunning(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Experiment{ID: 4, Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10, Status: StatusRunning, StartedAt: startedAt}, experiment)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no running experiment", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "experiments" WHERE status = $1 AND tenant_id = $2`)).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		experiment, err := repo.GetRunning(context.Background())
+
+		assert.NoError(t, err)
+		assert.Nil(t, experiment)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStop(t *testing.T) {
+	selectRunning := regexp.QuoteMeta(`SELECT * FROM "experiments" WHERE (id = $1 AND status = $2) AND tenant_id = $3 ORDER BY "experiments"."id" LIMIT $4 FOR UPDATE`)
+	startedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+
+	t.Run("running experiment", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRunning).
+			WithArgs(4, StatusRunning, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows(experimentColumns).AddRow(4, tenant.Default, "smaller packs", 2, 10.0, StatusRunning, startedAt, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "experiments" SET "status"=$1,"stopped_at"=$2 WHERE "id" = $3`)).
+			WithArgs(StatusStopped, now, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, audit_events.ActionExperimentStop, "4",
+			`{"id":4,"name":"smaller packs","configurationId":2,"trafficShare":10,"status":"running","startedAt":"2026-10-18T09:00:00Z"}`,
+			`{"id":4,"name":"smaller packs","configurationId":2,"trafficShare":10,"status":"stopped","startedAt":"2026-10-18T09:00:00Z","stoppedAt":"2026-10-18T17:00:00Z"}`)
+		mock.ExpectCommit()
+
+		err := repo.Stop(context.Background(), 4, now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("experiment that is not running", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRunning).
+			WithArgs(4, StatusRunning, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows(experimentColumns))
+		mock.ExpectCommit()
+
+		err := repo.Stop(context.Background(), 4, now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRecordAssignment(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	assignment := &Assignment{ExperimentID: 4, Arm: ArmCandidate, CalculationID: 42, OrderReference: "order-1", OrderQuantity: 8, TotalItems: 10, TotalPacks: 2}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "experiment_assignments" ("tenant_id","experiment_id","arm","calculation_id","order_reference","order_quantity","total_items","total_packs") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id","timestamp"`)).
+		WithArgs(tenant.Default, 4, ArmCandidate, 42, "order-1", 8, 10, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(9, time.Now()))
+	mock.ExpectCommit()
+
+	err := repo.RecordAssignment(context.Background(), assignment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), assignment.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArmResults(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT arm, COUNT(*) AS orders, SUM(GREATEST(total_items - order_quantity, 0)) AS total_overfill, SUM(total_packs) AS total_packs FROM "experiment_assignments" WHERE experiment_id = $1 AND tenant_id = $2 GROUP BY "arm" ORDER BY arm`)).
+		WithArgs(4, "retail").
+		WillReturnRows(sqlmock.NewRows([]string{"arm", "orders", "total_overfill", "total_packs"}).
+			AddRow(ArmCandidate, 2, 4, 5).
+			AddRow(ArmControl, 18, 90, 40))
+
+	results, err := repo.ArmResults(tenant.NewContext(context.Background(), "retail"), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ArmResult{
+		{Arm: ArmCandidate, Orders: 2, TotalOverfill: 4, TotalPacks: 5},
+		{Arm: ArmControl, Orders: 18, TotalOverfill: 90, TotalPacks: 40},
+	}, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,104 @@
+package experiments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/pack_configurations"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+// ErrExperimentNotFound is returned when a referenced experiment does not exist
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+type Service interface {
+	Start(ctx context.Context, experiment *Experiment) error
+	Stop(ctx context.Context, id uint) (*Experiment, error)
+	Results(ctx context.Context, id uint) (*Results, error)
+}
+
+type service struct {
+	logger       *zap.Logger
+	repo         Repository
+	packsCfgRepo pack_configurations.Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository, packsCfgRepo pack_configurations.Repository) Service {
+	return &service{
+		logger:       logger,
+		repo:         repo,
+		packsCfgRepo: packsCfgRepo,
+	}
+}
+
+// Start runs an experiment with a candidate configuration that is not the active one,
+// stopping the experiment that was running before
+func (s *service) Start(ctx context.Context, experiment *Experiment) error {
+	candidate, err := s.packsCfgRepo.GetByID(ctx, experiment.ConfigurationID)
+	if err != nil {
+		return err
+	}
+	if candidate == nil {
+		return apperrors.NewValidationError("Candidate pack configuration does not exist")
+	}
+	if candidate.Active {
+		return apperrors.NewValidationError("Candidate pack configuration must not be the active configuration")
+	}
+
+	experiment.Status = StatusRunning
+	experiment.StartedAt = time.Now()
+	experiment.StoppedAt = nil
+	return s.repo.Start(ctx, experiment)
+}
+
+// Stop ends an experiment, keeping its assignments for the results
+func (s *service) Stop(ctx context.Context, id uint) (*Experiment, error) {
+	if err := s.repo.Stop(ctx, id, time.Now()); err != nil {
+		return nil, err
+	}
+	experiment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment == nil {
+		return nil, ErrExperimentNotFound
+	}
+	return experiment, nil
+}
+
+// Results compares the average overfill and pack count of the arms of an experiment
+func (s *service) Results(ctx context.Context, id uint) (*Results, error) {
+	experiment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment == nil {
+		return nil, ErrExperimentNotFound
+	}
+
+	armResults, err := s.repo.ArmResults(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both arms are reported, even before an order lands in one of them
+	results := &Results{Experiment: *experiment, Arms: []ArmComparison{}}
+	for _, arm := range []string{ArmControl, ArmCandidate} {
+		comparison := ArmComparison{Arm: arm}
+		for _, result := range armResults {
+			if result.Arm != arm {
+				continue
+			}
+			comparison.Orders = result.Orders
+			comparison.TotalOverfill = result.TotalOverfill
+			comparison.TotalPacks = result.TotalPacks
+			comparison.AverageOverfill = float64(result.TotalOverfill) / float64(result.Orders)
+			comparison.AveragePacks = float64(result.TotalPacks) / float64(result.Orders)
+		}
+		results.Arms = append(results.Arms, comparison)
+	}
+	return results, nil
+}
@@ -0,0 +1,273 @@
+package experiments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/pack_configurations"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Start(ctx context.Context, experiment *Experiment) error {
+	args := m.Called(ctx, experiment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uint) (*Experiment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Experiment), args.Error(1)
+}
+
+func (m *MockRepository) GetRunning(ctx context.Context) (*Experiment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Experiment), args.Error(1)
+}
+
+func (m *MockRepository) Stop(ctx context.Context, id uint, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RecordAssignment(ctx context.Context, assignment *Assignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ArmResults(ctx context.Context, experimentID uint) ([]ArmResult, error) {
+	args := m.Called(ctx, experimentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ArmResult), args.Error(1)
+}
+
+// MockPackConfigRepository is a mock implementation of pack_configurations.Repository
+type MockPackConfigRepository struct {
+	mock.Mock
+}
+
+func (m *MockPackConfigRepository) Create(ctx context.Context, config *pack_configurations.PackConfiguration) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, config)
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetByID(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetBySignature(ctx context.Context, signature string) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) SetActive(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) Update(ctx context.Context, config *pack_configurations.PackConfiguration) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) List(ctx context.Context) ([]pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func TestService_Start(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate *pack_configurations.PackConfiguration
+		wantErr   bool
+		wantValid bool
+	}{
+		{
+			name:      "starts with an inactive candidate",
+			candidate: &pack_configurations.PackConfiguration{ID: 2},
+		},
+		{
+			name:      "candidate does not exist",
+			wantErr:   true,
+			wantValid: true,
+		},
+		{
+			name:      "candidate is the active configuration",
+			candidate: &pack_configurations.PackConfiguration{ID: 2, Active: true},
+			wantErr:   true,
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			if tt.candidate != nil {
+				mockPackRepo.On("GetByID", mock.Anything, uint(2)).Return(tt.candidate, nil)
+			} else {
+				mockPackRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, nil)
+			}
+			if !tt.wantErr {
+				mockRepo.On("Start", mock.Anything, mock.MatchedBy(func(e *Experiment) bool {
+					return e.Status == StatusRunning && !e.StartedAt.IsZero()
+				})).Return(nil)
+			}
+
+			experiment := &Experiment{Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10}
+			err := NewService(zap.NewNop(), mockRepo, mockPackRepo).Start(context.Background(), experiment)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantValid, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, StatusRunning, experiment.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Stop(t *testing.T) {
+	t.Run("stopped experiment", func(t *testing.T) {
+		stoppedAt := time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC)
+		mockRepo := new(MockRepository)
+		mockRepo.On("Stop", mock.Anything, uint(4), mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, uint(4)).Return(&Experiment{ID: 4, Status: StatusStopped, StoppedAt: &stoppedAt}, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil).Stop(context.Background(), 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusStopped, got.Status)
+	})
+
+	t.Run("experiment not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Stop", mock.Anything, uint(4), mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, uint(4)).Return(nil, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil).Stop(context.Background(), 4)
+
+		assert.ErrorIs(t, err, ErrExperimentNotFound)
+		assert.Nil(t, got)
+	})
+}
+
+func TestService_Results(t *testing.T) {
+	experiment := &Experiment{ID: 4, Name: "smaller packs", ConfigurationID: 2, TrafficShare: 10, Status: StatusRunning}
+
+	t.Run("compares both arms", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(4)).Return(experiment, nil)
+		mockRepo.On("ArmResults", mock.Anything, uint(4)).Return([]ArmResult{
+			{Arm: ArmCandidate, Orders: 2, TotalOverfill: 4, TotalPacks: 5},
+			{Arm: ArmControl, Orders: 18, TotalOverfill: 90, TotalPacks: 36},
+		}, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil).Results(context.Background(), 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Results{
+			Experiment: *experiment,
+			Arms: []ArmComparison{
+				{Arm: ArmControl, Orders: 18, TotalOverfill: 90, AverageOverfill: 5, TotalPacks: 36, AveragePacks: 2},
+				{Arm: ArmCandidate, Orders: 2, TotalOverfill: 4, AverageOverfill: 2, TotalPacks: 5, AveragePacks: 2.5},
+			},
+		}, got)
+	})
+
+	t.Run("arm without orders", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(4)).Return(experiment, nil)
+		mockRepo.On("ArmResults", mock.Anything, uint(4)).Return([]ArmResult{
+			{Arm: ArmControl, Orders: 3, TotalOverfill: 3, TotalPacks: 6},
+		}, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil).Results(context.Background(), 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ArmComparison{Arm: ArmCandidate}, got.Arms[1])
+	})
+
+	t.Run("experiment not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(4)).Return(nil, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil).Results(context.Background(), 4)
+
+		assert.ErrorIs(t, err, ErrExperimentNotFound)
+		assert.Nil(t, got)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(4)).Return(experiment, nil)
+		mockRepo.On("ArmResults", mock.Anything, uint(4)).Return(nil, errors.New("db error"))
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil).Results(context.Background(), 4)
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestExperiment_Arm(t *testing.T) {
+	experiment := Experiment{ID: 4, TrafficShare: 20}
+
+	// The same reference always lands in the same arm
+	for i := 0; i < 100; i++ {
+		reference := fmt.Sprintf("order-%d", i)
+		assert.Equal(t, experiment.Arm(reference), experiment.Arm(reference))
+	}
+
+	// The candidate receives roughly its traffic share
+	candidates := 0
+	for i := 0; i < 10000; i++ {
+		if experiment.Arm(fmt.Sprintf("order-%d", i)) == ArmCandidate {
+			candidates++
+		}
+	}
+	assert.InDelta(t, 2000, candidates, 200)
+
+	// Shares at the bounds route all or none of the traffic
+	assert.Equal(t, ArmControl, Experiment{ID: 4, TrafficShare: 0}.Arm("order-1"))
+	assert.Equal(t, ArmCandidate, Experiment{ID: 4, TrafficShare: 100}.Arm("order-1"))
+}
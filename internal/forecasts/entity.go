@@ -0,0 +1,65 @@
+package forecasts
+
+import "time"
+
+// Forecasting methods used to project daily pack consumption
+const (
+	MethodMovingAverage = "moving_average"
+	MethodExponential   = "exponential"
+)
+
+// Settings control how forecasts are generated. LeadTimes holds the replenishment
+// lead time in days per pack size, falling back to DefaultLeadTime.
+type Settings struct {
+	Method          string
+	Alpha           float64
+	HistoryDays     int
+	HorizonDays     int
+	DefaultLeadTime int
+	LeadTimes       map[int]int
+}
+
+// leadTime returns the replenishment lead time in days of a pack size
+func (s Settings) leadTime(size int) int {
+	if days, ok := s.LeadTimes[size]; ok {
+		return days
+	}
+	return s.DefaultLeadTime
+}
+
+// UsageRow counts the packs of one size used on one day
+type UsageRow struct {
+	Day   time.Time `gorm:"column:day"`
+	Size  int       `gorm:"column:size"`
+	Packs int       `gorm:"column:packs"`
+}
+
+// Forecast is one run of the replenishment forecast
+type Forecast struct {
+	ID          uint           `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID    string         `gorm:"column:tenant_id;not null" json:"-"`
+	GeneratedAt time.Time      `gorm:"column:generated_at;not null" json:"generatedAt"`
+	Method      string         `gorm:"column:method;not null" json:"method"`
+	HistoryDays int            `gorm:"column:history_days;not null" json:"historyDays"`
+	HorizonDays int            `gorm:"column:horizon_days;not null" json:"horizonDays"`
+	Items       []ForecastItem `gorm:"column:items;serializer:json;not null" json:"items"`
+}
+
+// TableName overrides the default table name for forecasts
+func (Forecast) TableName() string {
+	return "pack_forecasts"
+}
+
+// ForecastItem projects the consumption of one pack size and suggests a reorder.
+// A reorder is suggested once the available stock no longer covers the consumption
+// during the lead time, and covers the lead time plus the forecast horizon.
+type ForecastItem struct {
+	Size            int     `json:"size"`
+	DailyUsage      float64 `json:"dailyUsage"`
+	ProjectedUsage  int     `json:"projectedUsage"`
+	OnHand          int     `json:"onHand"`
+	LeadTimeDays    int     `json:"leadTimeDays"`
+	ReorderPoint    int     `json:"reorderPoint"`
+	ReorderNow      bool    `json:"reorderNow"`
+	ReorderQuantity int     `json:"reorderQuantity"`
+}
@@ -0,0 +1,61 @@
+package forecasts
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Forecaster periodically generates the replenishment forecast of every tenant in the
+// background
+type Forecaster struct {
+	logger   *zap.Logger
+	service  Service
+	interval time.Duration
+}
+
+func NewForecaster(logger *zap.Logger, service Service, interval time.Duration) *Forecaster {
+	return &Forecaster{
+		logger:   logger,
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run generates a forecast right away, so one is available after startup, and then
+// every interval until the context is cancelled
+func (f *Forecaster) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	f.generate(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.generate(ctx)
+		}
+	}
+}
+
+// generate generates the forecast of each tenant. A tenant that fails is logged and does
+// not stop the others.
+func (f *Forecaster) generate(ctx context.Context) {
+	tenants, err := f.service.Tenants(ctx)
+	if err != nil {
+		f.logger.Error("Failed to list the tenants to forecast", zap.Error(err))
+		return
+	}
+	for _, tenantID := range tenants {
+		forecast, err := f.service.Generate(tenant.NewContext(ctx, tenantID))
+		if err != nil {
+			f.logger.Error("Failed to generate forecast", zap.String("tenant", tenantID), zap.Error(err))
+			continue
+		}
+		f.logger.Info("Generated forecast", zap.String("tenant", tenantID), zap.Int("sizes", len(forecast.Items)))
+	}
+}
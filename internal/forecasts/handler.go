@@ -0,0 +1,38 @@
+package forecasts
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// GetLatestForecast returns the most recently generated replenishment forecast
+func (h *Handler) GetLatestForecast(c *gin.Context) {
+	forecast, err := h.service.Latest(c.Request.Context())
+	if err != nil {
+		if stderrors.Is(err, ErrForecastNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("No forecast has been generated yet", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to retrieve forecast", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
@@ -0,0 +1,117 @@
+package forecasts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Generate(ctx context.Context) (*Forecast, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Forecast), args.Error(1)
+}
+
+func (m *MockService) Latest(ctx context.Context) (*Forecast, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Forecast), args.Error(1)
+}
+
+func (m *MockService) Tenants(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestHandler_GetLatestForecast(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	forecast := &Forecast{
+		ID:          5,
+		Method:      MethodExponential,
+		HistoryDays: 28,
+		HorizonDays: 30,
+		Items:       []ForecastItem{{Size: 250, DailyUsage: 2, ProjectedUsage: 60, LeadTimeDays: 7, ReorderPoint: 14, ReorderNow: true, ReorderQuantity: 74}},
+	}
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantForecast   *Forecast
+	}{
+		{
+			name: "success case",
+			mockSetup: func(m *MockService) {
+				m.On("Latest", mock.Anything).Return(forecast, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantForecast:   forecast,
+		},
+		{
+			name: "no forecast yet",
+			mockSetup: func(m *MockService) {
+				m.On("Latest", mock.Anything).Return(nil, ErrForecastNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			mockSetup: func(m *MockService) {
+				m.On("Latest", mock.Anything).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/forecast", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).GetLatestForecast(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantForecast != nil {
+				var got Forecast
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantForecast, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
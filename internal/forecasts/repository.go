@@ -0,0 +1,93 @@
+package forecasts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// dailyUsageQuery sums the packs used by a tenant per day and size, one row per served order.
+// Orders served from the cache store no calculation, so each of their lookups counts the
+// packs of the calculation it was served with. An amendment stores every pack of the
+// amended order, so it supersedes the calculation it amends once it is in the range.
+const dailyUsageQuery = `SELECT date_trunc('day', u.timestamp) AS day, (pack->>'size')::INTEGER AS size, SUM((pack->>'quantity')::INTEGER) AS packs
+FROM (
+	SELECT c.timestamp, c.result FROM order_calculations c
+	WHERE c.tenant_id = ? AND c.timestamp >= ? AND c.timestamp < ?
+		AND NOT EXISTS (SELECT 1 FROM order_calculations a WHERE a.amends_id = c.id AND a.timestamp < ?)
+	UNION ALL
+	SELECT l.timestamp, c.result FROM calculation_cache_lookups l
+	JOIN order_calculations c ON c.id = l.calculation_id
+	WHERE l.tenant_id = ? AND l.timestamp >= ? AND l.timestamp < ?
+) u, json_array_elements(u.result) AS pack
+GROUP BY 1, 2
+ORDER BY 1, 2`
+
+// tenantsQuery lists the tenants with a pack configuration or a warehouse, the tenants
+// a forecast is generated for
+const tenantsQuery = `SELECT tenant_id FROM pack_configurations
+UNION
+SELECT tenant_id FROM warehouses
+ORDER BY 1`
+
+// Repository defines the interface for forecast persistence operations. Every operation
+// but Tenants acts on the forecasts and usage of the tenant of the context.
+type Repository interface {
+	DailyUsage(ctx context.Context, from, to time.Time) ([]UsageRow, error)
+	Save(ctx context.Context, forecast *Forecast) error
+	Latest(ctx context.Context) (*Forecast, error)
+	Tenants(ctx context.Context) ([]string, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// DailyUsage returns the packs used per day and size from the from time up to but excluding the to time
+func (r *gormRepository) DailyUsage(ctx context.Context, from, to time.Time) ([]UsageRow, error) {
+	var rows []UsageRow
+	tenantID := tenant.FromContext(ctx)
+	err := r.db.WithContext(ctx).Raw(dailyUsageQuery, tenantID, from, to, to, tenantID, from, to).Scan(&rows).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return rows, nil
+}
+
+func (r *gormRepository) Save(ctx context.Context, forecast *Forecast) error {
+	forecast.TenantID = tenant.FromContext(ctx)
+	return postgres.ClassifyError(r.db.WithContext(ctx).Create(forecast).Error)
+}
+
+// Latest returns the most recently generated forecast, or nil when none was generated yet
+func (r *gormRepository) Latest(ctx context.Context) (*Forecast, error) {
+	var forecast Forecast
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Order("generated_at DESC").First(&forecast).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &forecast, nil
+}
+
+// Tenants returns the tenants a forecast is generated for
+func (r *gormRepository) Tenants(ctx context.Context) ([]string, error) {
+	var tenants []string
+	err := r.db.WithContext(ctx).Raw(tenantsQuery).Scan(&tenants).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return tenants, nil
+}
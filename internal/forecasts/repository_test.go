@@ -0,0 +1,170 @@
+package forecasts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+func TestDailyUsage(t *testing.T) {
+	from := time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	t.Run("usage of calculations and cache hits", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM order_calculations c
+	WHERE c.tenant_id = $1 AND c.timestamp >= $2 AND c.timestamp < $3
+		AND NOT EXISTS (SELECT 1 FROM order_calculations a WHERE a.amends_id = c.id AND a.timestamp < $4)
+	UNION ALL
+	SELECT l.timestamp, c.result FROM calculation_cache_lookups l
+	JOIN order_calculations c ON c.id = l.calculation_id
+	WHERE l.tenant_id = $5 AND l.timestamp >= $6 AND l.timestamp < $7`)).
+			WithArgs("retail", from, to, to, "retail", from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"day", "size", "packs"}).
+				AddRow(from, 250, 4).
+				AddRow(from, 500, 1))
+
+		rows, err := repo.DailyUsage(tenant.NewContext(context.Background(), "retail"), from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []UsageRow{{Day: from, Size: 250, Packs: 4}, {Day: from, Size: 500, Packs: 1}}, rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM order_calculations`)).
+			WillReturnError(errors.New("database error"))
+
+		rows, err := repo.DailyUsage(context.Background(), from, to)
+
+		assert.Error(t, err)
+		assert.Nil(t, rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSave(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	forecast := &Forecast{
+		GeneratedAt: time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC),
+		Method:      MethodExponential,
+		HistoryDays: 28,
+		HorizonDays: 30,
+		Items:       []ForecastItem{{Size: 250, DailyUsage: 2, ProjectedUsage: 60, LeadTimeDays: 7, ReorderPoint: 14}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pack_forecasts" ("tenant_id","generated_at","method","history_days","horizon_days","items") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(tenant.Default, forecast.GeneratedAt, MethodExponential, 28, 30, `[{"size":250,"dailyUsage":2,"projectedUsage":60,"onHand":0,"leadTimeDays":7,"reorderPoint":14,"reorderNow":false,"reorderQuantity":0}]`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	err := repo.Save(context.Background(), forecast)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), forecast.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLatest(t *testing.T) {
+	t.Run("latest forecast", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		generatedAt := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_forecasts" WHERE tenant_id = $1 ORDER BY generated_at DESC,"pack_forecasts"."id" LIMIT $2`)).
+			WithArgs(tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "generated_at", "method", "history_days", "horizon_days", "items"}).
+				AddRow(5, generatedAt, MethodExponential, 28, 30, `[{"size":250,"dailyUsage":2}]`))
+
+		forecast, err := repo.Latest(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Forecast{
+			ID:          5,
+			GeneratedAt: generatedAt,
+			Method:      MethodExponential,
+			HistoryDays: 28,
+			HorizonDays: 30,
+			Items:       []ForecastItem{{Size: 250, DailyUsage: 2}},
+		}, forecast)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no forecast yet", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_forecasts"`)).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		forecast, err := repo.Latest(context.Background())
+
+		assert.NoError(t, err)
+		assert.Nil(t, forecast)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTenants(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery(`SELECT tenant_id FROM pack_configurations\s+UNION\s+SELECT tenant_id FROM warehouses\s+ORDER BY 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}).AddRow(tenant.Default).AddRow("retail"))
+
+	tenants, err := repo.Tenants(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tenant.Default, "retail"}, tenants)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
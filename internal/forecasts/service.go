@@ -0,0 +1,201 @@
+package forecasts
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/warehouses"
+)
+
+// ErrForecastNotFound is returned when no forecast was generated yet
+var ErrForecastNotFound = errors.New("no forecast generated yet")
+
+type Service interface {
+	Generate(ctx context.Context) (*Forecast, error)
+	Latest(ctx context.Context) (*Forecast, error)
+	Tenants(ctx context.Context) ([]string, error)
+}
+
+type service struct {
+	logger          *zap.Logger
+	repo            Repository
+	packsCfgRepo    pack_configurations.Repository
+	warehouseRepo   warehouses.Repository
+	reservationRepo reservations.Repository
+	settings        Settings
+	now             func() time.Time
+}
+
+func NewService(logger *zap.Logger, repo Repository, packsCfgRepo pack_configurations.Repository, warehouseRepo warehouses.Repository, reservationRepo reservations.Repository, settings Settings) Service {
+	return &service{
+		logger:          logger,
+		repo:            repo,
+		packsCfgRepo:    packsCfgRepo,
+		warehouseRepo:   warehouseRepo,
+		reservationRepo: reservationRepo,
+		settings:        settings,
+		now:             time.Now,
+	}
+}
+
+// Generate projects the consumption of every pack size of the tenant from the usage of
+// the last full days and stores the forecast. Sizes of the active configuration and stocked
+// sizes are forecast even without recent usage.
+func (s *service) Generate(ctx context.Context) (*Forecast, error) {
+	now := s.now()
+	today := now.UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -s.settings.HistoryDays)
+
+	usage, err := s.repo.DailyUsage(ctx, from, today)
+	if err != nil {
+		return nil, err
+	}
+	onHand, err := s.onHand(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[int][]int)
+	addSize := func(size int) {
+		if _, ok := series[size]; !ok {
+			series[size] = make([]int, s.settings.HistoryDays)
+		}
+	}
+	for _, row := range usage {
+		addSize(row.Size)
+		if day := int(row.Day.UTC().Sub(from) / (24 * time.Hour)); day >= 0 && day < s.settings.HistoryDays {
+			series[row.Size][day] += row.Packs
+		}
+	}
+	for size := range onHand {
+		addSize(size)
+	}
+	packCfg, err := s.packsCfgRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if packCfg != nil {
+		for _, size := range packCfg.PackSizes {
+			addSize(int(size))
+		}
+	}
+
+	sizes := make([]int, 0, len(series))
+	for size := range series {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+
+	forecast := &Forecast{
+		GeneratedAt: now,
+		Method:      s.settings.Method,
+		HistoryDays: s.settings.HistoryDays,
+		HorizonDays: s.settings.HorizonDays,
+		Items:       make([]ForecastItem, 0, len(sizes)),
+	}
+	for _, size := range sizes {
+		dailyUsage := s.dailyUsage(series[size])
+		forecast.Items = append(forecast.Items, planReorder(size, dailyUsage, onHand[size], s.settings.leadTime(size), s.settings.HorizonDays))
+	}
+
+	if err := s.repo.Save(ctx, forecast); err != nil {
+		return nil, err
+	}
+	return forecast, nil
+}
+
+// Latest returns the most recently generated forecast
+func (s *service) Latest(ctx context.Context) (*Forecast, error) {
+	forecast, err := s.repo.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if forecast == nil {
+		return nil, ErrForecastNotFound
+	}
+	return forecast, nil
+}
+
+// Tenants returns the tenants a forecast is generated for
+func (s *service) Tenants(ctx context.Context) ([]string, error) {
+	return s.repo.Tenants(ctx)
+}
+
+// onHand sums the stock of every warehouse per size, less the packs held by active reservations
+func (s *service) onHand(ctx context.Context, now time.Time) (map[int]int, error) {
+	stock, err := s.warehouseRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.reservationRepo.ListActive(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := reservations.Reserved(active)
+	onHand := make(map[int]int)
+	for _, warehouse := range stock {
+		for size, quantity := range warehouse.Inventory {
+			onHand[size] += max(quantity-reserved[warehouse.ID][size], 0)
+		}
+	}
+	return onHand, nil
+}
+
+// dailyUsage projects the daily consumption from a series of daily usage, oldest first
+func (s *service) dailyUsage(series []int) float64 {
+	if s.settings.Method == MethodMovingAverage {
+		return movingAverage(series)
+	}
+	return exponentialSmoothing(series, s.settings.Alpha)
+}
+
+// movingAverage returns the mean of the series
+func movingAverage(series []int) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	total := 0
+	for _, value := range series {
+		total += value
+	}
+	return float64(total) / float64(len(series))
+}
+
+// exponentialSmoothing returns the smoothed level of the series after its last value,
+// weighting each value by alpha and the level before it by 1 - alpha
+func exponentialSmoothing(series []int, alpha float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	level := float64(series[0])
+	for _, value := range series[1:] {
+		level = alpha*float64(value) + (1-alpha)*level
+	}
+	return level
+}
+
+// planReorder projects the consumption of a pack size over the horizon and suggests a
+// reorder once the stock on hand no longer covers the consumption during the lead time
+func planReorder(size int, dailyUsage float64, onHand, leadTime, horizon int) ForecastItem {
+	item := ForecastItem{
+		Size:           size,
+		DailyUsage:     math.Round(dailyUsage*100) / 100,
+		ProjectedUsage: int(math.Ceil(dailyUsage * float64(horizon))),
+		OnHand:         onHand,
+		LeadTimeDays:   leadTime,
+		ReorderPoint:   int(math.Ceil(dailyUsage * float64(leadTime))),
+	}
+	if dailyUsage > 0 && onHand <= item.ReorderPoint {
+		item.ReorderNow = true
+		item.ReorderQuantity = max(int(math.Ceil(dailyUsage*float64(leadTime+horizon)))-onHand, 0)
+	}
+	return item
+}
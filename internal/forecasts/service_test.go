@@ -0,0 +1,392 @@
+package forecasts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) DailyUsage(ctx context.Context, from, to time.Time) ([]UsageRow, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]UsageRow), args.Error(1)
+}
+
+func (m *MockRepository) Save(ctx context.Context, forecast *Forecast) error {
+	args := m.Called(ctx, forecast)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Latest(ctx context.Context) (*Forecast, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Forecast), args.Error(1)
+}
+
+func (m *MockRepository) Tenants(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// MockPackConfigRepository is a mock implementation of pack_configurations.Repository
+type MockPackConfigRepository struct {
+	mock.Mock
+}
+
+func (m *MockPackConfigRepository) Create(ctx context.Context, config *pack_configurations.PackConfiguration) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, config)
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetByID(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetBySignature(ctx context.Context, signature string) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) SetActive(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) Update(ctx context.Context, config *pack_configurations.PackConfiguration) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) List(ctx context.Context) ([]pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]pack_configurations.PackConfiguration), args.Error(1)
+}
+
+// MockWarehouseRepository is a mock implementation of warehouses.Repository
+type MockWarehouseRepository struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseRepository) Create(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) Update(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) GetByName(ctx context.Context, name string) (*warehouses.Warehouse, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*warehouses.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) List(ctx context.Context) ([]warehouses.Warehouse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]warehouses.Warehouse), args.Error(1)
+}
+
+// MockReservationRepository is a mock implementation of reservations.Repository
+type MockReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReservationRepository) Reserve(ctx context.Context, now time.Time, plan reservations.Plan) (*reservations.Reservation, error) {
+	args := m.Called(ctx, now, plan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reservations.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ListActive(ctx context.Context, now time.Time) ([]reservations.Reservation, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]reservations.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) Commit(ctx context.Context, id uint, now time.Time) (*reservations.Reservation, error) {
+	args := m.Called(ctx, id, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reservations.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestService_Generate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	settings := Settings{
+		Method:          MethodMovingAverage,
+		HistoryDays:     4,
+		HorizonDays:     10,
+		DefaultLeadTime: 5,
+		LeadTimes:       map[int]int{500: 2},
+	}
+
+	newService := func(repo *MockRepository, packRepo *MockPackConfigRepository, warehouseRepo *MockWarehouseRepository, reservationRepo *MockReservationRepository) *service {
+		s := NewService(zap.NewNop(), repo, packRepo, warehouseRepo, reservationRepo, settings).(*service)
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	t.Run("projects usage and suggests reorders", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPackRepo := new(MockPackConfigRepository)
+		mockWarehouseRepo := new(MockWarehouseRepository)
+		mockReservationRepo := new(MockReservationRepository)
+
+		mockRepo.On("DailyUsage", mock.Anything, from, to).Return([]UsageRow{
+			{Day: from, Size: 250, Packs: 4},
+			{Day: from.AddDate(0, 0, 2), Size: 250, Packs: 8},
+			{Day: from.AddDate(0, 0, 3), Size: 250, Packs: 4},
+			{Day: from.AddDate(0, 0, 3), Size: 500, Packs: 2},
+		}, nil)
+		mockWarehouseRepo.On("List", mock.Anything).Return([]warehouses.Warehouse{
+			{ID: 1, Name: "north", Inventory: map[int]int{250: 10, 1000: 3}},
+			{ID: 2, Name: "south", Inventory: map[int]int{250: 6, 500: 20}},
+		}, nil)
+		mockReservationRepo.On("ListActive", mock.Anything, now).Return([]reservations.Reservation{
+			{Items: []reservations.ReservationItem{{WarehouseID: 1, Size: 250, Quantity: 4}}},
+		}, nil)
+		mockPackRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+			ID:        1,
+			PackSizes: pq.Int64Array{250, 500, 1000, 2000},
+		}, nil)
+		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*forecasts.Forecast")).Return(nil)
+
+		got, err := newService(mockRepo, mockPackRepo, mockWarehouseRepo, mockReservationRepo).Generate(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, now, got.GeneratedAt)
+		assert.Equal(t, MethodMovingAverage, got.Method)
+		assert.Equal(t, []ForecastItem{
+			{Size: 250, DailyUsage: 4, ProjectedUsage: 40, OnHand: 12, LeadTimeDays: 5, ReorderPoint: 20, ReorderNow: true, ReorderQuantity: 48},
+			{Size: 500, DailyUsage: 0.5, ProjectedUsage: 5, OnHand: 20, LeadTimeDays: 2, ReorderPoint: 1},
+			{Size: 1000, OnHand: 3, LeadTimeDays: 5},
+			{Size: 2000, LeadTimeDays: 5},
+		}, got.Items)
+		mockRepo.AssertExpectations(t)
+		mockWarehouseRepo.AssertExpectations(t)
+		mockReservationRepo.AssertExpectations(t)
+		mockPackRepo.AssertExpectations(t)
+	})
+
+	t.Run("usage error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("DailyUsage", mock.Anything, from, to).Return(nil, errors.New("db error"))
+
+		got, err := newService(mockRepo, new(MockPackConfigRepository), new(MockWarehouseRepository), new(MockReservationRepository)).Generate(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPackRepo := new(MockPackConfigRepository)
+		mockWarehouseRepo := new(MockWarehouseRepository)
+		mockReservationRepo := new(MockReservationRepository)
+		mockRepo.On("DailyUsage", mock.Anything, from, to).Return([]UsageRow{}, nil)
+		mockWarehouseRepo.On("List", mock.Anything).Return([]warehouses.Warehouse{}, nil)
+		mockReservationRepo.On("ListActive", mock.Anything, now).Return([]reservations.Reservation{}, nil)
+		mockPackRepo.On("GetActive", mock.Anything).Return(nil, nil)
+		mockRepo.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		got, err := newService(mockRepo, mockPackRepo, mockWarehouseRepo, mockReservationRepo).Generate(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestService_Latest(t *testing.T) {
+	t.Run("latest forecast", func(t *testing.T) {
+		forecast := &Forecast{ID: 3, Method: MethodExponential}
+		mockRepo := new(MockRepository)
+		mockRepo.On("Latest", mock.Anything).Return(forecast, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil, nil, nil, Settings{}).Latest(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, forecast, got)
+	})
+
+	t.Run("no forecast yet", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Latest", mock.Anything).Return(nil, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo, nil, nil, nil, Settings{}).Latest(context.Background())
+
+		assert.ErrorIs(t, err, ErrForecastNotFound)
+		assert.Nil(t, got)
+	})
+}
+
+func TestDailyUsageMethods(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []int
+		alpha  float64
+		wantMA float64
+		wantES float64
+	}{
+		{name: "empty series", series: nil, alpha: 0.5, wantMA: 0, wantES: 0},
+		{name: "constant series", series: []int{3, 3, 3}, alpha: 0.3, wantMA: 3, wantES: 3},
+		{name: "recent values weigh more", series: []int{4, 0, 8, 4}, alpha: 0.5, wantMA: 4, wantES: 4.5},
+		{name: "alpha of one keeps the last value", series: []int{10, 2, 7}, alpha: 1, wantMA: 19.0 / 3, wantES: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.wantMA, movingAverage(tt.series), 1e-9)
+			assert.InDelta(t, tt.wantES, exponentialSmoothing(tt.series, tt.alpha), 1e-9)
+		})
+	}
+}
+
+func TestPlanReorder(t *testing.T) {
+	tests := []struct {
+		name       string
+		dailyUsage float64
+		onHand     int
+		want       ForecastItem
+	}{
+		{
+			name:       "stock above reorder point",
+			dailyUsage: 2,
+			onHand:     30,
+			want:       ForecastItem{Size: 250, DailyUsage: 2, ProjectedUsage: 20, OnHand: 30, LeadTimeDays: 7, ReorderPoint: 14},
+		},
+		{
+			name:       "stock at reorder point",
+			dailyUsage: 2,
+			onHand:     14,
+			want:       ForecastItem{Size: 250, DailyUsage: 2, ProjectedUsage: 20, OnHand: 14, LeadTimeDays: 7, ReorderPoint: 14, ReorderNow: true, ReorderQuantity: 20},
+		},
+		{
+			name:       "fractional usage rounds up",
+			dailyUsage: 0.25,
+			onHand:     0,
+			want:       ForecastItem{Size: 250, DailyUsage: 0.25, ProjectedUsage: 3, LeadTimeDays: 7, ReorderPoint: 2, ReorderNow: true, ReorderQuantity: 5},
+		},
+		{
+			name:       "no usage never reorders",
+			dailyUsage: 0,
+			onHand:     0,
+			want:       ForecastItem{Size: 250, LeadTimeDays: 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planReorder(250, tt.dailyUsage, tt.onHand, 7, 10))
+		})
+	}
+}
+
+func TestForecaster_Run(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockPackRepo := new(MockPackConfigRepository)
+	mockWarehouseRepo := new(MockWarehouseRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockRepo.On("Tenants", mock.Anything).Return([]string{tenant.Default, "retail"}, nil)
+	mockRepo.On("DailyUsage", mock.Anything, mock.Anything, mock.Anything).Return([]UsageRow{}, nil)
+	mockWarehouseRepo.On("List", mock.Anything).Return([]warehouses.Warehouse{}, nil)
+	mockReservationRepo.On("ListActive", mock.Anything, mock.Anything).Return([]reservations.Reservation{}, nil)
+	mockPackRepo.On("GetActive", mock.Anything).Return(nil, nil)
+
+	generated := make(chan string, 2)
+	mockRepo.On("Save", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			select {
+			case generated <- tenant.FromContext(args.Get(0).(context.Context)):
+			default:
+			}
+		}).
+		Return(nil)
+
+	service := NewService(zap.NewNop(), mockRepo, mockPackRepo, mockWarehouseRepo, mockReservationRepo, Settings{Method: MethodExponential, Alpha: 0.3, HistoryDays: 7, HorizonDays: 7})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		// The interval is long, so the forecasts must come from the run at startup
+		NewForecaster(zap.NewNop(), service, time.Hour).Run(ctx)
+		close(done)
+	}()
+
+	// Each tenant gets its own forecast
+	for _, want := range []string{tenant.Default, "retail"} {
+		select {
+		case got := <-generated:
+			assert.Equal(t, want, got)
+		case <-time.After(time.Second):
+			t.Fatal("forecaster did not generate a forecast at startup")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("forecaster did not stop after cancellation")
+	}
+}
@@ -0,0 +1,87 @@
+package forecasts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// setupDatabase migrates the database of TEST_DATABASE_URL and skips the test without one
+func setupDatabase(t *testing.T) *gorm.DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	migrator, err := migrate.New("file://../../migrations", url)
+	require.NoError(t, err)
+	defer migrator.Close()
+	if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+
+	db, err := postgres.NewConnection(url)
+	require.NoError(t, err)
+	return db
+}
+
+func TestDailyUsage_AmendedOrder(t *testing.T) {
+	db := setupDatabase(t)
+
+	tenantID := fmt.Sprintf("forecasts-%d", time.Now().UnixNano())
+	ctx := tenant.NewContext(context.Background(), tenantID)
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM order_calculations WHERE tenant_id = ?", tenantID)
+		db.Exec("DELETE FROM pack_configurations WHERE tenant_id = ?", tenantID)
+	})
+
+	var configID uint
+	require.NoError(t, db.Raw(`INSERT INTO pack_configurations (pack_sizes, signature, active, tenant_id) VALUES ('{500,1000}', ?, true, ?) RETURNING id`,
+		tenantID, tenantID).Scan(&configID).Error)
+
+	// An order of 1000 packed as 1 x 1000, amended to 1300 the next day by adding 1 x 500
+	var originalID uint
+	require.NoError(t, db.Raw(`INSERT INTO order_calculations (order_quantity, min_quantity, result, total_items, total_packs, configuration_id, timestamp, tenant_id)
+		VALUES (1000, 1000, '[{"size":1000,"quantity":1}]', 1000, 1, ?, ?, ?) RETURNING id`,
+		configID, day.Add(time.Hour), tenantID).Scan(&originalID).Error)
+	require.NoError(t, db.Exec(`INSERT INTO order_calculations (order_quantity, min_quantity, result, total_items, total_packs, configuration_id, timestamp, tenant_id, mode, amends_id)
+		VALUES (1300, 1300, '[{"size":1000,"quantity":1},{"size":500,"quantity":1}]', 1500, 2, ?, ?, ?, 'amendment', ?)`,
+		configID, day.AddDate(0, 0, 1).Add(time.Hour), tenantID, originalID).Error)
+
+	repo := NewRepository(db)
+
+	t.Run("amendment in the range supersedes the original", func(t *testing.T) {
+		rows, err := repo.DailyUsage(ctx, day, day.AddDate(0, 0, 2))
+
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		for _, row := range rows {
+			assert.True(t, row.Day.Equal(day.AddDate(0, 0, 1)))
+			assert.Equal(t, 1, row.Packs)
+		}
+	})
+
+	t.Run("original counts until the amendment is in the range", func(t *testing.T) {
+		rows, err := repo.DailyUsage(ctx, day, day.AddDate(0, 0, 1))
+
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.True(t, rows[0].Day.Equal(day))
+		assert.Equal(t, 1000, rows[0].Size)
+		assert.Equal(t, 1, rows[0].Packs)
+	})
+}
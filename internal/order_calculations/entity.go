@@ -1,40 +1,316 @@
 package order_calculations
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/pack-calculator/internal/experiments"
 	packcfg "github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/pkg/packsolver"
 )
 
-// OrderCalculation represents a calculation result entity in the database
+// OrderCalculation represents a calculation result entity in the database. Calculations
+// belong to the tenant that requested them.
 type OrderCalculation struct {
 	ID              uint                      `gorm:"column:id;primarykey;autoIncrement;column:id" json:"id"`
+	TenantID        string                    `gorm:"column:tenant_id;not null" json:"-"`
 	OrderQuantity   int                       `gorm:"column:order_quantity;not null" json:"orderQuantity"`
+	MinQuantity     int                       `gorm:"column:min_quantity;not null" json:"minQuantity"`
+	MaxQuantity     int                       `gorm:"column:max_quantity;not null" json:"maxQuantity"`
+	Mode            string                    `gorm:"column:mode;not null" json:"mode"`
+	AmendsID        *uint                     `gorm:"column:amends_id" json:"amendsId,omitempty"`
 	Result          []PackResult              `gorm:"column:result;serializer:json;not null" json:"result"`
+	Sourcing        []WarehouseAllocation     `gorm:"column:sourcing;serializer:json" json:"sourcing,omitempty"`
 	TotalItems      int                       `gorm:"column:total_items;not null" json:"totalItems"`
 	TotalPacks      int                       `gorm:"column:total_packs;not null" json:"totalPacks"`
 	ConfigurationID uint                      `gorm:"column:configuration_id;not null" json:"configurationId"`
 	Configuration   packcfg.PackConfiguration `gorm:"foreignKey:ConfigurationID" json:"-"`
+	SizesSignature  string                    `gorm:"column:sizes_signature;not null" json:"-"`
+	CustomerID      string                    `gorm:"column:customer_id;not null" json:"customerId,omitempty"`
 	Timestamp       time.Time                 `gorm:"column:timestamp;not null;default:CURRENT_TIMESTAMP" json:"timestamp"`
+	Shipments       []Shipment                `gorm:"foreignKey:CalculationID" json:"shipments,omitempty"`
+	Hierarchy       []PackResult              `gorm:"-" json:"hierarchy,omitempty"`
+	Cartons         *CartonPlan               `gorm:"-" json:"cartons,omitempty"`
+	Reservation     *reservations.Reservation `gorm:"-" json:"reservation,omitempty"`
+	Experiment      *experiments.Assignment   `gorm:"-" json:"experiment,omitempty"`
+	Reason          string                    `gorm:"-" json:"reason,omitempty"`
+	Backorder       int                       `gorm:"-" json:"backorder,omitempty"`
 }
 
-// PackResult represents a single pack in the result
+// Calculation modes recorded with each order calculation. Only standard
+// calculations hold the optimum for their window and are reused as a cache.
+const (
+	CalculationModeStandard  = "standard"
+	CalculationModePartial   = "partial"
+	CalculationModeAmendment = "amendment"
+	CalculationModeSourcing  = "sourcing"
+	CalculationModeBackorder = "backorder"
+)
+
+// Shipment kinds of a partially fulfilled order
+const (
+	ShipmentKindImmediate = "immediate"
+	ShipmentKindBackorder = "backorder"
+)
+
+// Shipment represents one shipment of a partially fulfilled order
+type Shipment struct {
+	ID            uint         `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	CalculationID uint         `gorm:"column:calculation_id;not null" json:"calculationId"`
+	Kind          string       `gorm:"column:kind;not null" json:"kind"`
+	Quantity      int          `gorm:"column:quantity;not null" json:"quantity"`
+	Result        []PackResult `gorm:"column:result;serializer:json;not null" json:"result"`
+	TotalItems    int          `gorm:"column:total_items;not null" json:"totalItems"`
+	TotalPacks    int          `gorm:"column:total_packs;not null" json:"totalPacks"`
+}
+
+// TableName overrides the default table name for shipments
+func (Shipment) TableName() string {
+	return "order_shipments"
+}
+
+// CacheLookup records whether an order was served from a cached calculation. CalculationID
+// is the cached calculation when the order was served with it and stored no calculation.
+type CacheLookup struct {
+	ID              uint      `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID        string    `gorm:"column:tenant_id;not null" json:"-"`
+	ConfigurationID uint      `gorm:"column:configuration_id;not null" json:"configurationId"`
+	SizesSignature  string    `gorm:"column:sizes_signature;not null" json:"-"`
+	OrderQuantity   int       `gorm:"column:order_quantity;not null" json:"orderQuantity"`
+	Hit             bool      `gorm:"column:hit;not null" json:"hit"`
+	CalculationID   *uint     `gorm:"column:calculation_id" json:"calculationId,omitempty"`
+	Timestamp       time.Time `gorm:"column:timestamp;not null;default:CURRENT_TIMESTAMP" json:"timestamp"`
+}
+
+// TableName overrides the default table name for cache lookups
+func (CacheLookup) TableName() string {
+	return "calculation_cache_lookups"
+}
+
+// WarehouseAllocation lists the packs a sourcing plan takes from one warehouse
+type WarehouseAllocation struct {
+	WarehouseID uint         `json:"warehouseId"`
+	Warehouse   string       `json:"warehouse"`
+	Packs       []PackResult `json:"packs"`
+	TotalItems  int          `json:"totalItems"`
+	TotalPacks  int          `json:"totalPacks"`
+}
+
+// PackResult represents a single pack in the result. In a nested hierarchy it can
+// also represent a logistics unit such as a case or pallet, in which case Size is the
+// number of items in one unit and Contents lists what one unit holds.
 type PackResult struct {
-	Size     int `json:"size"`
-	Quantity int `json:"quantity"`
+	Unit     string       `json:"unit,omitempty"`
+	Size     int          `json:"size"`
+	Quantity int          `json:"quantity"`
+	Contents []PackResult `json:"contents,omitempty"`
+}
+
+// OrderRequest describes the quantity window an order can be fulfilled with.
+// A zero MaxQuantity means the window has no upper bound, and a nil Overfill
+// falls back to the service's default policy. A non-nil Inventory holds the
+// packs available per size and requests partial fulfilment with a backorder.
+// CartonObjective selects whether cartons are planned for the fewest cartons or the lowest cost.
+// Sourcing requests a plan that takes the packs from the stock of the warehouses, and Reserve
+// holds the sourced packs for ReservationTTL, or the service default when it is zero.
+// OrderReference identifies the order when assigning it to an arm of a running experiment,
+// and CustomerID restricts the pack sizes to the ones allowed by the customer's profile.
+// A nil TieBreak falls back to the service's default tie-break policy.
+type OrderRequest struct {
+	OrderQuantity   int
+	MinQuantity     int
+	MaxQuantity     int
+	Overfill        *OverfillPolicy
+	Inventory       map[int]int
+	CartonObjective string
+	Sourcing        bool
+	Reserve         bool
+	ReservationTTL  time.Duration
+	OrderReference  string
+	CustomerID      string
+	TieBreak        *TieBreakPolicy
+}
+
+// OverfillPolicy caps how many items may be shipped above the order quantity.
+// Zero values disable the corresponding cap, and when both are set the stricter one applies.
+type OverfillPolicy struct {
+	MaxItems   int     `json:"maxItems,omitempty"`
+	MaxPercent float64 `json:"maxPercent,omitempty"`
+	Backorder  bool    `json:"backorder,omitempty"`
+}
+
+// maxOverfill returns the largest overfill allowed for the order quantity
+// and whether the policy caps overfill at all
+func (p OverfillPolicy) maxOverfill(orderQuantity int) (int, bool) {
+	limit, capped := 0, false
+	if p.MaxItems > 0 {
+		limit, capped = p.MaxItems, true
+	}
+	if p.MaxPercent > 0 {
+		percentLimit := int(float64(orderQuantity) * p.MaxPercent / 100)
+		if !capped || percentLimit < limit {
+			limit, capped = percentLimit, true
+		}
+	}
+	return limit, capped
+}
+
+// Tie-break policies choosing among combinations with the same number of items and packs
+const (
+	TieBreakLargerPacks   = packsolver.LargerPacks
+	TieBreakFewerSizes    = packsolver.FewerSizes
+	TieBreakLexicographic = packsolver.Lexicographic
+	TieBreakWeighted      = packsolver.Weighted
+)
+
+// MaxTieBreakWeight bounds the weight of a pack size
+const MaxTieBreakWeight = packsolver.MaxWeight
+
+// TieBreakPolicy chooses among the combinations with the same number of items and packs,
+// as described by packsolver.TieBreak
+type TieBreakPolicy = packsolver.TieBreak
+
+// Carton planning objectives
+const (
+	CartonObjectiveCount = "count"
+	CartonObjectiveCost  = "cost"
+)
+
+// CartonAssignment is one shipping carton and the packs placed in it
+type CartonAssignment struct {
+	Carton string       `json:"carton"`
+	Packs  []PackResult `json:"packs"`
+	Volume float64      `json:"volume"`
+	Weight float64      `json:"weight"`
+	Cost   float64      `json:"cost"`
+}
+
+// CartonPlan assigns the packs of a calculation to shipping cartons. Packs without
+// physical specs or that fit no carton type are listed as unassigned.
+type CartonPlan struct {
+	Cartons      []CartonAssignment `json:"cartons"`
+	TotalCartons int                `json:"totalCartons"`
+	TotalCost    float64            `json:"totalCost"`
+	Unassigned   []PackResult       `json:"unassigned,omitempty"`
+}
+
+// PackingOption is a candidate packing offered when no acceptable packing exists
+type PackingOption struct {
+	TotalItems int          `json:"totalItems"`
+	TotalPacks int          `json:"totalPacks"`
+	Packs      []PackResult `json:"packs"`
+}
+
+// NoAcceptablePackingError is returned when the optimal packing exceeds the overfill cap
+type NoAcceptablePackingError struct {
+	OrderQuantity int             `json:"orderQuantity"`
+	MaxOverfill   int             `json:"maxOverfill"`
+	Options       []PackingOption `json:"options"`
+}
+
+func (e *NoAcceptablePackingError) Error() string {
+	return fmt.Sprintf("no acceptable packing for %d items within an overfill of %d", e.OrderQuantity, e.MaxOverfill)
+}
+
+// Amendment is the outcome of raising the quantity of an already packed order
+type Amendment struct {
+	Calculation *OrderCalculation
+	FixedPacks  []PackResult
+	AddedPacks  []PackResult
+	FromScratch PackingOption
 }
 
 // CalculateAPIRequest represents an API request to calculate pack_configurations for an order
 type CalculateAPIRequest struct {
-	OrderQuantity int `json:"orderQuantity"`
+	OrderQuantity   int             `json:"orderQuantity"`
+	MinQuantity     int             `json:"minQuantity,omitempty"`
+	MaxQuantity     int             `json:"maxQuantity,omitempty"`
+	Overfill        *OverfillPolicy `json:"overfill,omitempty"`
+	Inventory       map[int]int     `json:"inventory,omitempty"`
+	CartonObjective string          `json:"cartonObjective,omitempty"`
+	Sourcing        bool            `json:"sourcing,omitempty"`
+	Reserve         bool            `json:"reserve,omitempty"`
+	ReservationTTL  int             `json:"reservationTtl,omitempty"`
+	OrderReference  string          `json:"orderReference,omitempty"`
+	CustomerID      string          `json:"customerId,omitempty"`
+	TieBreak        *TieBreakPolicy `json:"tieBreak,omitempty"`
 }
 
 // CalculateAPIResponse represents an API response for a calculation request
 type CalculateAPIResponse struct {
-	OrderQuantity int          `json:"orderQuantity"`
-	TotalItems    int          `json:"totalItems"`
-	TotalPacks    int          `json:"totalPacks"`
-	Packs         []PackResult `json:"pack_configurations"`
-	Success       bool         `json:"success"`
-	ErrorMessage  string       `json:"errorMessage,omitempty"`
+	OrderQuantity int                       `json:"orderQuantity"`
+	MinQuantity   int                       `json:"minQuantity,omitempty"`
+	MaxQuantity   int                       `json:"maxQuantity,omitempty"`
+	TotalItems    int                       `json:"totalItems"`
+	TotalPacks    int                       `json:"totalPacks"`
+	Packs         []PackResult              `json:"pack_configurations"`
+	Reason        string                    `json:"reason,omitempty"`
+	Backorder     int                       `json:"backorder,omitempty"`
+	Shipments     []Shipment                `json:"shipments,omitempty"`
+	Sourcing      []WarehouseAllocation     `json:"sourcing,omitempty"`
+	Hierarchy     []PackResult              `json:"hierarchy,omitempty"`
+	TopLevelUnits int                       `json:"topLevelUnits,omitempty"`
+	Cartons       *CartonPlan               `json:"cartons,omitempty"`
+	Reservation   *reservations.Reservation `json:"reservation,omitempty"`
+	Experiment    *experiments.Assignment   `json:"experiment,omitempty"`
+	Success       bool                      `json:"success"`
+	ErrorMessage  string                    `json:"errorMessage,omitempty"`
+}
+
+// CalculateV1APIResponse represents a version 1 API response for a calculation request
+type CalculateV1APIResponse struct {
+	CalculationID uint                      `json:"calculationId"`
+	OrderQuantity int                       `json:"orderQuantity"`
+	MinQuantity   int                       `json:"minQuantity,omitempty"`
+	MaxQuantity   int                       `json:"maxQuantity,omitempty"`
+	TotalItems    int                       `json:"totalItems"`
+	TotalPacks    int                       `json:"totalPacks"`
+	Packs         []PackResult              `json:"packs"`
+	Reason        string                    `json:"reason,omitempty"`
+	Backorder     int                       `json:"backorder,omitempty"`
+	Shipments     []Shipment                `json:"shipments,omitempty"`
+	Sourcing      []WarehouseAllocation     `json:"sourcing,omitempty"`
+	Hierarchy     []PackResult              `json:"hierarchy,omitempty"`
+	TopLevelUnits int                       `json:"topLevelUnits,omitempty"`
+	Cartons       *CartonPlan               `json:"cartons,omitempty"`
+	Reservation   *reservations.Reservation `json:"reservation,omitempty"`
+	Experiment    *experiments.Assignment   `json:"experiment,omitempty"`
+}
+
+// AmendAPIRequest represents an API request to raise the quantity of an existing calculation
+type AmendAPIRequest struct {
+	OrderQuantity int `json:"orderQuantity"`
+}
+
+// AmendAPIResponse represents an API response for an amendment request
+type AmendAPIResponse struct {
+	CalculationID uint          `json:"calculationId"`
+	AmendsID      uint          `json:"amendsId"`
+	OrderQuantity int           `json:"orderQuantity"`
+	TotalItems    int           `json:"totalItems"`
+	TotalPacks    int           `json:"totalPacks"`
+	FixedPacks    []PackResult  `json:"fixedPacks"`
+	AddedPacks    []PackResult  `json:"addedPacks"`
+	Packs         []PackResult  `json:"packs"`
+	FromScratch   PackingOption `json:"fromScratch"`
+	ExtraItems    int           `json:"extraItems"`
+	ExtraPacks    int           `json:"extraPacks"`
+}
+
+// Page sizes of calculation listings
+const (
+	DefaultCalculationsLimit = 50
+	MaxCalculationsLimit     = 500
+)
+
+// ListCalculationsAPIRequest represents the query parameters of a calculation listing
+type ListCalculationsAPIRequest struct {
+	Offset int `form:"offset"`
+	Limit  int `form:"limit"`
+}
+
+// CalculationsAPIResponse represents an API response listing calculations, newest first
+type CalculationsAPIResponse struct {
+	Calculations []OrderCalculation `json:"calculations"`
 }
@@ -1,7 +1,10 @@
 package order_calculations
 
 import (
+	stderrors "errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -23,30 +26,184 @@ func NewHandler(logger *zap.Logger, service Service) *Handler {
 
 // CalculatePacksForOrder calculates the optimal pack_configurations for an order
 func (h *Handler) CalculatePacksForOrder(c *gin.Context) {
+	request, calc, ok := h.processOrder(c)
+	if !ok {
+		return
+	}
+
+	response := CalculateAPIResponse{
+		OrderQuantity: request.OrderQuantity,
+		MinQuantity:   request.MinQuantity,
+		MaxQuantity:   request.MaxQuantity,
+		TotalItems:    calc.TotalItems,
+		TotalPacks:    calc.TotalPacks,
+		Packs:         calc.Result,
+		Reason:        calc.Reason,
+		Backorder:     calc.Backorder,
+		Shipments:     calc.Shipments,
+		Sourcing:      calc.Sourcing,
+		Hierarchy:     calc.Hierarchy,
+		TopLevelUnits: topLevelUnits(calc),
+		Cartons:       calc.Cartons,
+		Reservation:   calc.Reservation,
+		Experiment:    calc.Experiment,
+		Success:       true,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CalculatePacksForOrderV1 calculates the optimal packs for an order and responds with the version 1 schema
+func (h *Handler) CalculatePacksForOrderV1(c *gin.Context) {
+	request, calc, ok := h.processOrder(c)
+	if !ok {
+		return
+	}
+
+	response := CalculateV1APIResponse{
+		CalculationID: calc.ID,
+		OrderQuantity: request.OrderQuantity,
+		MinQuantity:   request.MinQuantity,
+		MaxQuantity:   request.MaxQuantity,
+		TotalItems:    calc.TotalItems,
+		TotalPacks:    calc.TotalPacks,
+		Packs:         calc.Result,
+		Reason:        calc.Reason,
+		Backorder:     calc.Backorder,
+		Shipments:     calc.Shipments,
+		Sourcing:      calc.Sourcing,
+		Hierarchy:     calc.Hierarchy,
+		TopLevelUnits: topLevelUnits(calc),
+		Cartons:       calc.Cartons,
+		Reservation:   calc.Reservation,
+		Experiment:    calc.Experiment,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// processOrder runs the validated order through the service. It writes the error response
+// and returns false when the order cannot be processed.
+func (h *Handler) processOrder(c *gin.Context) (*CalculateAPIRequest, *OrderCalculation, bool) {
 	payload, exists := c.Get("payload")
 	if !exists {
-		errMsg := "Failed to retrieve payload from context"
-		h.logger.Error(errMsg)
-		c.JSON(http.StatusInternalServerError, errors.NewInternalError(errMsg))
-		return
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return nil, nil, false
 	}
 	request := payload.(*CalculateAPIRequest)
 
 	// Calculate optimal pack_configurations
-	packs, totalItems, totalPacks, err := h.service.OrderProcessing(c, request.OrderQuantity)
+	calc, err := h.service.OrderProcessing(c.Request.Context(), OrderRequest{
+		OrderQuantity:   request.OrderQuantity,
+		MinQuantity:     request.MinQuantity,
+		MaxQuantity:     request.MaxQuantity,
+		Overfill:        request.Overfill,
+		Inventory:       request.Inventory,
+		CartonObjective: request.CartonObjective,
+		Sourcing:        request.Sourcing,
+		Reserve:         request.Reserve,
+		ReservationTTL:  time.Duration(request.ReservationTTL) * time.Second,
+		OrderReference:  request.OrderReference,
+		CustomerID:      request.CustomerID,
+		TieBreak:        request.TieBreak,
+	})
+	if err != nil {
+		var noAcceptable *NoAcceptablePackingError
+		if stderrors.As(err, &noAcceptable) {
+			c.Error(errors.NewUnprocessableErrorWrap("No acceptable packing within the overfill cap", noAcceptable))
+			return nil, nil, false
+		}
+		c.Error(errors.Wrap("Failed to process order request", err))
+		return nil, nil, false
+	}
+	return request, calc, true
+}
+
+// topLevelUnits counts the units at the top of the calculation's hierarchy
+func topLevelUnits(calc *OrderCalculation) int {
+	units := 0
+	for _, unit := range calc.Hierarchy {
+		units += unit.Quantity
+	}
+	return units
+}
+
+// AmendCalculation raises the quantity of an existing calculation, keeping its packs fixed
+func (h *Handler) AmendCalculation(c *gin.Context) {
+	calculationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		errMsg := "Failed to process order request"
-		h.logger.Error(errMsg, zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.NewInternalErrorWrap(errMsg, err))
+		c.Error(errors.NewValidationErrorWrap("Invalid calculation ID", err))
 		return
 	}
 
-	response := CalculateAPIResponse{
-		OrderQuantity: request.OrderQuantity,
-		TotalItems:    totalItems,
-		TotalPacks:    totalPacks,
-		Packs:         packs,
-		Success:       true,
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*AmendAPIRequest)
+
+	amendment, err := h.service.AmendOrder(c.Request.Context(), uint(calculationID), request.OrderQuantity)
+	if err != nil {
+		if stderrors.Is(err, ErrCalculationNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("Calculation not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to amend order calculation", err))
+		return
+	}
+
+	calc := amendment.Calculation
+	response := AmendAPIResponse{
+		CalculationID: calc.ID,
+		AmendsID:      uint(calculationID),
+		OrderQuantity: calc.OrderQuantity,
+		TotalItems:    calc.TotalItems,
+		TotalPacks:    calc.TotalPacks,
+		FixedPacks:    amendment.FixedPacks,
+		AddedPacks:    amendment.AddedPacks,
+		Packs:         calc.Result,
+		FromScratch:   amendment.FromScratch,
+		ExtraItems:    calc.TotalItems - amendment.FromScratch.TotalItems,
+		ExtraPacks:    calc.TotalPacks - amendment.FromScratch.TotalPacks,
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// ListCalculations returns a page of stored calculations, newest first
+func (h *Handler) ListCalculations(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*ListCalculationsAPIRequest)
+
+	calcs, err := h.service.ListCalculations(c.Request.Context(), request.Offset, request.Limit)
+	if err != nil {
+		c.Error(errors.Wrap("Failed to list order calculations", err))
+		return
+	}
+	if calcs == nil {
+		calcs = []OrderCalculation{}
+	}
+	c.JSON(http.StatusOK, CalculationsAPIResponse{Calculations: calcs})
+}
+
+// GetCalculation returns a stored calculation by ID
+func (h *Handler) GetCalculation(c *gin.Context) {
+	calculationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid calculation ID", err))
+		return
+	}
+
+	calc, err := h.service.GetCalculation(c.Request.Context(), uint(calculationID))
+	if err != nil {
+		if stderrors.Is(err, ErrCalculationNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("Calculation not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to retrieve order calculation", err))
+		return
+	}
+	c.JSON(http.StatusOK, calc)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -20,12 +21,12 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) OrderProcessing(ctx context.Context, orderQuantity int) ([]PackResult, int, int, error) {
-	args := m.Called(ctx, orderQuantity)
+func (m *MockService) OrderProcessing(ctx context.Context, order OrderRequest) (*OrderCalculation, error) {
+	args := m.Called(ctx, order)
 	if args.Get(0) == nil {
-		return nil, 0, 0, args.Error(3)
+		return nil, args.Error(1)
 	}
-	return args.Get(0).([]PackResult), args.Int(1), args.Int(2), args.Error(3)
+	return args.Get(0).(*OrderCalculation), args.Error(1)
 }
 
 func (m *MockService) CalculateOptimalPacks(ctx context.Context, orderQuantity int, packSizes []int) (map[int]int, error) {
@@ -36,6 +37,30 @@ func (m *MockService) CalculateOptimalPacks(ctx context.Context, orderQuantity i
 	return args.Get(0).(map[int]int), args.Error(1)
 }
 
+func (m *MockService) AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*Amendment, error) {
+	args := m.Called(ctx, calculationID, orderQuantity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Amendment), args.Error(1)
+}
+
+func (m *MockService) ListCalculations(ctx context.Context, offset, limit int) ([]OrderCalculation, error) {
+	args := m.Called(ctx, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]OrderCalculation), args.Error(1)
+}
+
+func (m *MockService) GetCalculation(ctx context.Context, id uint) (*OrderCalculation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*OrderCalculation), args.Error(1)
+}
+
 // ErrorResponse matches the JSON error response structure
 type ErrorResponse struct {
 	Type    string      `json:"Type"`
@@ -56,26 +81,81 @@ func TestHandler_CalculatePacksForOrder(t *testing.T) {
 		{
 			name: "success case",
 			setupContext: func(c *gin.Context) {
-				c.Set("payload", &CalculateAPIRequest{OrderQuantity: 10})
+				c.Set("payload", &CalculateAPIRequest{OrderQuantity: 10, MinQuantity: 10})
 			},
 			mockSetup: func(m *MockService) {
-				m.On("OrderProcessing", mock.Anything, 10).Return([]PackResult{
-					{Size: 5, Quantity: 2},
-				}, 10, 2, nil)
+				m.On("OrderProcessing", mock.Anything, OrderRequest{OrderQuantity: 10, MinQuantity: 10}).Return(&OrderCalculation{
+					Result:     []PackResult{{Size: 5, Quantity: 2}},
+					TotalItems: 10,
+					TotalPacks: 2,
+					Reason:     "exact match for the order quantity",
+				}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantBody: func() interface{} {
 				return &CalculateAPIResponse{
 					OrderQuantity: 10,
+					MinQuantity:   10,
 					TotalItems:    10,
 					TotalPacks:    2,
 					Packs: []PackResult{
 						{Size: 5, Quantity: 2},
 					},
+					Reason:  "exact match for the order quantity",
 					Success: true,
 				}
 			},
 		},
+		{
+			name: "no combination inside range",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &CalculateAPIRequest{OrderQuantity: 1000, MinQuantity: 990, MaxQuantity: 1010})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("OrderProcessing", mock.Anything, OrderRequest{OrderQuantity: 1000, MinQuantity: 990, MaxQuantity: 1010}).
+					Return(nil, apperrors.NewValidationError("No pack combination totals between 990 and 1010 items"))
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeInvalidRequest),
+					Message: "No pack combination totals between 990 and 1010 items",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
+		{
+			name: "no acceptable packing",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &CalculateAPIRequest{OrderQuantity: 2600, MinQuantity: 2600})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("OrderProcessing", mock.Anything, OrderRequest{OrderQuantity: 2600, MinQuantity: 2600}).
+					Return(nil, &NoAcceptablePackingError{
+						OrderQuantity: 2600,
+						MaxOverfill:   200,
+						Options:       []PackingOption{{TotalItems: 5000, TotalPacks: 1, Packs: []PackResult{{Size: 5000, Quantity: 1}}}},
+					})
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeUnprocessable),
+					Message: "No acceptable packing within the overfill cap",
+					Err: map[string]interface{}{
+						"orderQuantity": float64(2600),
+						"maxOverfill":   float64(200),
+						"options": []interface{}{
+							map[string]interface{}{
+								"totalItems": float64(5000),
+								"totalPacks": float64(1),
+								"packs":      []interface{}{map[string]interface{}{"size": float64(5000), "quantity": float64(1)}},
+							},
+						},
+					},
+				}
+			},
+		},
 		{
 			name: "missing payload",
 			setupContext: func(c *gin.Context) {
@@ -96,10 +176,10 @@ func TestHandler_CalculatePacksForOrder(t *testing.T) {
 		{
 			name: "service error",
 			setupContext: func(c *gin.Context) {
-				c.Set("payload", &CalculateAPIRequest{OrderQuantity: 10})
+				c.Set("payload", &CalculateAPIRequest{OrderQuantity: 10, MinQuantity: 10})
 			},
 			mockSetup: func(m *MockService) {
-				m.On("OrderProcessing", mock.Anything, 10).Return(nil, 0, 0, errors.New("service error"))
+				m.On("OrderProcessing", mock.Anything, OrderRequest{OrderQuantity: 10, MinQuantity: 10}).Return(nil, errors.New("service error"))
 			},
 			wantStatusCode: http.StatusInternalServerError,
 			wantBody: func() interface{} {
@@ -116,6 +196,7 @@ func TestHandler_CalculatePacksForOrder(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/calculate", nil)
 			mockService := new(MockService)
 			tt.mockSetup(mockService)
 
@@ -124,6 +205,7 @@ func TestHandler_CalculatePacksForOrder(t *testing.T) {
 			logger := zap.NewNop()
 			handler := NewHandler(logger, mockService)
 			handler.CalculatePacksForOrder(c)
+			renderErrors(c)
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
 
@@ -145,3 +227,359 @@ func TestHandler_CalculatePacksForOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_CalculatePacksForOrderV1(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success case", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/calculate", nil)
+		c.Set("payload", &CalculateAPIRequest{OrderQuantity: 10, MinQuantity: 10})
+		mockService := new(MockService)
+		mockService.On("OrderProcessing", mock.Anything, OrderRequest{OrderQuantity: 10, MinQuantity: 10}).Return(&OrderCalculation{
+			ID:         4,
+			Result:     []PackResult{{Size: 5, Quantity: 2}},
+			TotalItems: 10,
+			TotalPacks: 2,
+			Hierarchy:  []PackResult{{Unit: "case", Size: 10, Quantity: 1}},
+		}, nil)
+
+		NewHandler(zap.NewNop(), mockService).CalculatePacksForOrderV1(c)
+		renderErrors(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"calculationId": 4,
+			"orderQuantity": 10,
+			"minQuantity": 10,
+			"totalItems": 10,
+			"totalPacks": 2,
+			"packs": [{"size": 5, "quantity": 2}],
+			"hierarchy": [{"unit": "case", "size": 10, "quantity": 1}],
+			"topLevelUnits": 1
+		}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/calculate", nil)
+		c.Set("payload", &CalculateAPIRequest{OrderQuantity: 10, MinQuantity: 10})
+		mockService := new(MockService)
+		mockService.On("OrderProcessing", mock.Anything, mock.Anything).Return(nil, errors.New("service error"))
+
+		NewHandler(zap.NewNop(), mockService).CalculatePacksForOrderV1(c)
+		renderErrors(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_AmendCalculation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	amendsID := uint(1)
+	amendment := &Amendment{
+		Calculation: &OrderCalculation{
+			ID:            2,
+			OrderQuantity: 1300,
+			AmendsID:      &amendsID,
+			Result:        []PackResult{{Size: 250, Quantity: 2}, {Size: 1000, Quantity: 1}},
+			TotalItems:    1500,
+			TotalPacks:    3,
+		},
+		FixedPacks:  []PackResult{{Size: 1000, Quantity: 1}},
+		AddedPacks:  []PackResult{{Size: 250, Quantity: 2}},
+		FromScratch: PackingOption{TotalItems: 1500, TotalPacks: 2, Packs: []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 1}}},
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantBody       func() interface{}
+	}{
+		{
+			name: "success case",
+			id:   "1",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &AmendAPIRequest{OrderQuantity: 1300})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("AmendOrder", mock.Anything, uint(1), 1300).Return(amendment, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: func() interface{} {
+				return &AmendAPIResponse{
+					CalculationID: 2,
+					AmendsID:      1,
+					OrderQuantity: 1300,
+					TotalItems:    1500,
+					TotalPacks:    3,
+					FixedPacks:    []PackResult{{Size: 1000, Quantity: 1}},
+					AddedPacks:    []PackResult{{Size: 250, Quantity: 2}},
+					Packs:         []PackResult{{Size: 250, Quantity: 2}, {Size: 1000, Quantity: 1}},
+					FromScratch:   PackingOption{TotalItems: 1500, TotalPacks: 2, Packs: []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 1}}},
+					ExtraItems:    0,
+					ExtraPacks:    1,
+				}
+			},
+		},
+		{
+			name: "invalid id",
+			id:   "abc",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &AmendAPIRequest{OrderQuantity: 1300})
+			},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeInvalidRequest),
+					Message: "Invalid calculation ID",
+					Err:     map[string]interface{}{"Func": "ParseUint", "Num": "abc", "Err": map[string]interface{}{}},
+				}
+			},
+		},
+		{
+			name: "calculation not found",
+			id:   "9",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &AmendAPIRequest{OrderQuantity: 1300})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("AmendOrder", mock.Anything, uint(9), 1300).Return(nil, ErrCalculationNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeNotFound),
+					Message: "Calculation not found",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
+		{
+			name: "service error",
+			id:   "1",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &AmendAPIRequest{OrderQuantity: 1300})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("AmendOrder", mock.Anything, uint(1), 1300).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeInternal),
+					Message: "Failed to amend order calculation",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/calculations/1/amend", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			handler := NewHandler(zap.NewNop(), mockService)
+			handler.AmendCalculation(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+
+			var got interface{}
+			switch tt.wantStatusCode {
+			case http.StatusOK:
+				got = &AmendAPIResponse{}
+			default:
+				got = &ErrorResponse{}
+			}
+
+			err := json.Unmarshal(w.Body.Bytes(), got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBody(), got)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ListCalculations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	timestamp := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	calcs := []OrderCalculation{
+		{ID: 2, OrderQuantity: 501, MinQuantity: 501, Mode: CalculationModeStandard, Result: []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}}, TotalItems: 750, TotalPacks: 2, ConfigurationID: 1, Timestamp: timestamp},
+		{ID: 1, OrderQuantity: 1, MinQuantity: 1, Mode: CalculationModeStandard, Result: []PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1, ConfigurationID: 1, Timestamp: timestamp},
+	}
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantResponse   *CalculationsAPIResponse
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ListCalculationsAPIRequest{Offset: 10, Limit: 2})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("ListCalculations", mock.Anything, 10, 2).Return(calcs, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &CalculationsAPIResponse{Calculations: calcs},
+		},
+		{
+			name: "empty page",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ListCalculationsAPIRequest{Offset: 100, Limit: 50})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("ListCalculations", mock.Anything, 100, 50).Return(nil, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &CalculationsAPIResponse{Calculations: []OrderCalculation{}},
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &ListCalculationsAPIRequest{Limit: 50})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("ListCalculations", mock.Anything, 0, 50).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/calculations", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).ListCalculations(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantResponse != nil {
+				var got CalculationsAPIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantResponse, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetCalculation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calc := &OrderCalculation{
+		ID:              9,
+		OrderQuantity:   501,
+		MinQuantity:     501,
+		Mode:            CalculationModeStandard,
+		Result:          []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}},
+		TotalItems:      750,
+		TotalPacks:      2,
+		ConfigurationID: 1,
+		Timestamp:       time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantCalc       *OrderCalculation
+	}{
+		{
+			name: "success case",
+			id:   "9",
+			mockSetup: func(m *MockService) {
+				m.On("GetCalculation", mock.Anything, uint(9)).Return(calc, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantCalc:       calc,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "9",
+			mockSetup: func(m *MockService) {
+				m.On("GetCalculation", mock.Anything, uint(9)).Return(nil, ErrCalculationNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "9",
+			mockSetup: func(m *MockService) {
+				m.On("GetCalculation", mock.Anything, uint(9)).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/calculations/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).GetCalculation(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantCalc != nil {
+				var got OrderCalculation
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantCalc, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
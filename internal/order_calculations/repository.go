@@ -5,15 +5,22 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
 )
 
-// Repository defines the interface for order calculation persistence operations
+// Repository defines the interface for order calculation persistence operations. Every
+// operation acts on the calculations of the tenant of the context.
 type Repository interface {
 	Save(ctx context.Context, calc *OrderCalculation) error
 	GetByID(ctx context.Context, id uint) (*OrderCalculation, error)
-	GetByConfigurationIDAndOrderQuantity(ctx context.Context, OrderQuantity int, configID uint) (*OrderCalculation, error)
+	GetByConfigurationIDAndOrderQuantity(ctx context.Context, OrderQuantity int, configID uint, signature string) (*OrderCalculation, error)
+	GetByConfigurationIDAndQuantityRange(ctx context.Context, minQuantity, maxQuantity int, configID uint, signature string) (*OrderCalculation, error)
 	List(ctx context.Context, offset, limit int) ([]OrderCalculation, error)
 	Delete(ctx context.Context, id uint) error
+	RecordCacheLookup(ctx context.Context, lookup *CacheLookup) error
+	WithTx(tx *gorm.DB) Repository
 }
 
 type gormRepository struct {
@@ -25,51 +32,87 @@ func NewRepository(db *gorm.DB) Repository {
 	return &gormRepository{db: db}
 }
 
+// scoped returns a session restricted to the calculations of the tenant of ctx
+func (r *gormRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(tenant.Scope(ctx))
+}
+
+// WithTx returns a repository that acts within the transaction tx
+func (r *gormRepository) WithTx(tx *gorm.DB) Repository {
+	return &gormRepository{db: tx}
+}
+
+// Save stores the calculation for the tenant of ctx
 func (r *gormRepository) Save(ctx context.Context, calc *OrderCalculation) error {
-	return r.db.WithContext(ctx).Create(calc).Error
+	calc.TenantID = tenant.FromContext(ctx)
+	return postgres.ClassifyError(r.db.WithContext(ctx).Create(calc).Error)
 }
 
 func (r *gormRepository) GetByID(ctx context.Context, id uint) (*OrderCalculation, error) {
 	var calc OrderCalculation
-	err := r.db.WithContext(ctx).Preload("Configuration").First(&calc, id).Error
+	err := r.scoped(ctx).Preload("Configuration").Preload("Shipments").First(&calc, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &calc, nil
+}
+
+// GetByConfigurationIDAndOrderQuantity returns the standard calculation of an exact order
+// quantity that was solved with the set of sizes identified by signature
+func (r *gormRepository) GetByConfigurationIDAndOrderQuantity(ctx context.Context, orderQuantity int, configID uint, signature string) (*OrderCalculation, error) {
+	var calc OrderCalculation
+	err := r.scoped(ctx).
+		Where("order_quantity = ? AND configuration_id = ? AND sizes_signature = ?", orderQuantity, configID, signature).
+		Where("min_quantity = order_quantity AND max_quantity = 0 AND mode = ?", CalculationModeStandard).
+		Preload("Configuration").
+		First(&calc).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return &calc, nil
 }
 
-func (r *gormRepository) GetByConfigurationIDAndOrderQuantity(ctx context.Context, orderQuantity int, configID uint) (*OrderCalculation, error) {
+func (r *gormRepository) GetByConfigurationIDAndQuantityRange(ctx context.Context, minQuantity, maxQuantity int, configID uint, signature string) (*OrderCalculation, error) {
 	var calc OrderCalculation
-	err := r.db.WithContext(ctx).
-		Where("order_quantity = ? AND configuration_id = ?", orderQuantity, configID).
+	err := r.scoped(ctx).
+		Where("min_quantity = ? AND max_quantity = ? AND configuration_id = ? AND sizes_signature = ? AND mode = ?", minQuantity, maxQuantity, configID, signature, CalculationModeStandard).
 		Preload("Configuration").
 		First(&calc).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return &calc, nil
 }
 
 func (r *gormRepository) List(ctx context.Context, offset, limit int) ([]OrderCalculation, error) {
 	var calcs []OrderCalculation
-	err := r.db.WithContext(ctx).
+	err := r.scoped(ctx).
 		Preload("Configuration").
 		Offset(offset).
 		Limit(limit).
 		Order("timestamp DESC").
 		Find(&calcs).Error
 	if err != nil {
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return calcs, nil
 }
 
 func (r *gormRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&OrderCalculation{}, id).Error
+	return postgres.ClassifyError(r.scoped(ctx).Delete(&OrderCalculation{}, id).Error)
+}
+
+// RecordCacheLookup stores the lookup for the tenant of ctx
+func (r *gormRepository) RecordCacheLookup(ctx context.Context, lookup *CacheLookup) error {
+	lookup.TenantID = tenant.FromContext(ctx)
+	return postgres.ClassifyError(r.db.WithContext(ctx).Create(lookup).Error)
 }
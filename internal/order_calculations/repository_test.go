@@ -15,6 +15,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/tenant"
 )
 
 func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
@@ -55,6 +57,8 @@ func TestSave(t *testing.T) {
 
 		calc := &OrderCalculation{
 			OrderQuantity:   1250,
+			MinQuantity:     1250,
+			Mode:            CalculationModeStandard,
 			Result:          packResult,
 			TotalItems:      1250,
 			TotalPacks:      3,
@@ -66,8 +70,8 @@ func TestSave(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Use sqlmock.AnyArg() for the JSON result to avoid type comparison issues
-		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_calculations" ("order_quantity","result","total_items","total_packs","configuration_id","timestamp") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id","timestamp"`)).
-			WithArgs(calc.OrderQuantity, sqlmock.AnyArg(), calc.TotalItems, calc.TotalPacks, calc.ConfigurationID, sqlmock.AnyArg()).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_calculations" ("tenant_id","order_quantity","min_quantity","max_quantity","mode","amends_id","result","sourcing","total_items","total_packs","configuration_id","sizes_signature","customer_id","timestamp") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id","timestamp"`)).
+			WithArgs(tenant.Default, calc.OrderQuantity, calc.MinQuantity, calc.MaxQuantity, calc.Mode, calc.AmendsID, sqlmock.AnyArg(), nil, calc.TotalItems, calc.TotalPacks, calc.ConfigurationID, calc.SizesSignature, calc.CustomerID, sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(1, time.Now()))
 
 		// Expect the COMMIT
@@ -99,6 +103,8 @@ func TestSave(t *testing.T) {
 
 		calc := &OrderCalculation{
 			OrderQuantity:   1250,
+			MinQuantity:     1250,
+			Mode:            CalculationModeStandard,
 			Result:          packResult,
 			TotalItems:      1250,
 			TotalPacks:      3,
@@ -110,8 +116,8 @@ func TestSave(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Use sqlmock.AnyArg() for the JSON result to avoid type comparison issues
-		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_calculations" ("order_quantity","result","total_items","total_packs","configuration_id","timestamp") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id","timestamp"`)).
-			WithArgs(calc.OrderQuantity, sqlmock.AnyArg(), calc.TotalItems, calc.TotalPacks, calc.ConfigurationID, sqlmock.AnyArg()).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_calculations" ("tenant_id","order_quantity","min_quantity","max_quantity","mode","amends_id","result","sourcing","total_items","total_packs","configuration_id","sizes_signature","customer_id","timestamp") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id","timestamp"`)).
+			WithArgs(tenant.Default, calc.OrderQuantity, calc.MinQuantity, calc.MaxQuantity, calc.Mode, calc.AmendsID, sqlmock.AnyArg(), nil, calc.TotalItems, calc.TotalPacks, calc.ConfigurationID, calc.SizesSignature, calc.CustomerID, sqlmock.AnyArg()).
 			WillReturnError(errors.New("database error"))
 
 		// Expect the ROLLBACK
@@ -124,6 +130,54 @@ func TestSave(t *testing.T) {
 		assert.Error(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("save with shipments", func(t *testing.T) {
+		// Setup
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		ctx := context.Background()
+
+		calc := &OrderCalculation{
+			OrderQuantity:   1250,
+			MinQuantity:     1250,
+			Mode:            CalculationModePartial,
+			Result:          []PackResult{{Size: 500, Quantity: 2}},
+			TotalItems:      1000,
+			TotalPacks:      2,
+			ConfigurationID: 1,
+			Timestamp:       time.Now(),
+			Shipments: []Shipment{
+				{Kind: ShipmentKindImmediate, Quantity: 1000, Result: []PackResult{{Size: 500, Quantity: 2}}, TotalItems: 1000, TotalPacks: 2},
+				{Kind: ShipmentKindBackorder, Quantity: 250, Result: []PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1},
+			},
+		}
+
+		// Expect the BEGIN transaction
+		mock.ExpectBegin()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_calculations" ("tenant_id","order_quantity","min_quantity","max_quantity","mode","amends_id","result","sourcing","total_items","total_packs","configuration_id","sizes_signature","customer_id","timestamp") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id","timestamp"`)).
+			WithArgs(tenant.Default, calc.OrderQuantity, calc.MinQuantity, calc.MaxQuantity, calc.Mode, calc.AmendsID, sqlmock.AnyArg(), nil, calc.TotalItems, calc.TotalPacks, calc.ConfigurationID, calc.SizesSignature, calc.CustomerID, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(7, time.Now()))
+
+		// Expect the shipments to be inserted in the same transaction
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_shipments" ("calculation_id","kind","quantity","result","total_items","total_packs") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) ON CONFLICT ("id") DO UPDATE SET "calculation_id"="excluded"."calculation_id" RETURNING "id"`)).
+			WithArgs(7, ShipmentKindImmediate, 1000, sqlmock.AnyArg(), 1000, 2, 7, ShipmentKindBackorder, 250, sqlmock.AnyArg(), 250, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+		// Expect the COMMIT
+		mock.ExpectCommit()
+
+		// Execute
+		err := repo.Save(ctx, calc)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), calc.Shipments[0].CalculationID)
+		assert.Equal(t, uint(2), calc.Shipments[1].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestGetByID(t *testing.T) {
@@ -146,8 +200,8 @@ func TestGetByID(t *testing.T) {
 		timestamp := time.Now()
 
 		// Update to match GORM's parameterized LIMIT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 ORDER BY "order_calculations"."id" LIMIT $2`)).
-			WithArgs(id, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
+			WithArgs(id, tenant.Default, 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "order_quantity", "result", "total_items", "total_packs", "configuration_id", "timestamp"}).
 				AddRow(1, 1250, resultJSON, 1250, 3, 1, timestamp))
 
@@ -157,6 +211,11 @@ func TestGetByID(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "pack_sizes", "signature", "active"}).
 				AddRow(1, pq.Int64Array{250, 500, 1000}, "test-signature", true))
 
+		// Expect SELECT query for the shipments (preload)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_shipments" WHERE "order_shipments"."calculation_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "calculation_id", "kind", "quantity", "result", "total_items", "total_packs"}))
+
 		// Execute
 		result, err := repo.GetByID(ctx, id)
 
@@ -184,8 +243,8 @@ func TestGetByID(t *testing.T) {
 		id := uint(999)
 
 		// Update to match GORM's parameterized LIMIT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 ORDER BY "order_calculations"."id" LIMIT $2`)).
-			WithArgs(id, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
+			WithArgs(id, tenant.Default, 1).
 			WillReturnError(gorm.ErrRecordNotFound)
 
 		// Execute
@@ -207,8 +266,8 @@ func TestGetByID(t *testing.T) {
 		id := uint(1)
 
 		// Update to match GORM's parameterized LIMIT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 ORDER BY "order_calculations"."id" LIMIT $2`)).
-			WithArgs(id, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
+			WithArgs(id, tenant.Default, 1).
 			WillReturnError(errors.New("database error"))
 
 		// Execute
@@ -242,8 +301,8 @@ func TestGetByConfigurationIDAndOrderQuantity(t *testing.T) {
 		timestamp := time.Now()
 
 		// Update to match GORM's parameterized LIMIT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE order_quantity = $1 AND configuration_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
-			WithArgs(orderQuantity, configID, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE (order_quantity = $1 AND configuration_id = $2 AND sizes_signature = $3) AND (min_quantity = order_quantity AND max_quantity = 0 AND mode = $4) AND tenant_id = $5 ORDER BY "order_calculations"."id" LIMIT $6`)).
+			WithArgs(orderQuantity, configID, "sizes-signature", CalculationModeStandard, tenant.Default, 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "order_quantity", "result", "total_items", "total_packs", "configuration_id", "timestamp"}).
 				AddRow(1, orderQuantity, resultJSON, 1250, 3, configID, timestamp))
 
@@ -254,7 +313,7 @@ func TestGetByConfigurationIDAndOrderQuantity(t *testing.T) {
 				AddRow(1, pq.Int64Array{250, 500, 1000}, "test-signature", true))
 
 		// Execute
-		result, err := repo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, configID)
+		result, err := repo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, configID, "sizes-signature")
 
 		// Assert
 		assert.NoError(t, err)
@@ -277,12 +336,12 @@ func TestGetByConfigurationIDAndOrderQuantity(t *testing.T) {
 		configID := uint(999)
 
 		// Update to match GORM's parameterized LIMIT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE order_quantity = $1 AND configuration_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
-			WithArgs(orderQuantity, configID, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE (order_quantity = $1 AND configuration_id = $2 AND sizes_signature = $3) AND (min_quantity = order_quantity AND max_quantity = 0 AND mode = $4) AND tenant_id = $5 ORDER BY "order_calculations"."id" LIMIT $6`)).
+			WithArgs(orderQuantity, configID, "sizes-signature", CalculationModeStandard, tenant.Default, 1).
 			WillReturnError(gorm.ErrRecordNotFound)
 
 		// Execute
-		result, err := repo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, configID)
+		result, err := repo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, configID, "sizes-signature")
 
 		// Assert
 		assert.NoError(t, err)
@@ -301,12 +360,12 @@ func TestGetByConfigurationIDAndOrderQuantity(t *testing.T) {
 		configID := uint(1)
 
 		// Update to match GORM's parameterized LIMIT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE order_quantity = $1 AND configuration_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
-			WithArgs(orderQuantity, configID, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE (order_quantity = $1 AND configuration_id = $2 AND sizes_signature = $3) AND (min_quantity = order_quantity AND max_quantity = 0 AND mode = $4) AND tenant_id = $5 ORDER BY "order_calculations"."id" LIMIT $6`)).
+			WithArgs(orderQuantity, configID, "sizes-signature", CalculationModeStandard, tenant.Default, 1).
 			WillReturnError(errors.New("database error"))
 
 		// Execute
-		result, err := repo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, configID)
+		result, err := repo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, configID, "sizes-signature")
 
 		// Assert
 		assert.Error(t, err)
@@ -315,6 +374,71 @@ func TestGetByConfigurationIDAndOrderQuantity(t *testing.T) {
 	})
 }
 
+func TestGetByConfigurationIDAndQuantityRange(t *testing.T) {
+	t.Run("get existing calculation", func(t *testing.T) {
+		// Setup
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		ctx := context.Background()
+		minQuantity := 950
+		maxQuantity := 1050
+		configID := uint(1)
+
+		packResult := []PackResult{
+			{Size: 500, Quantity: 2},
+		}
+		resultJSON, err := json.Marshal(packResult)
+		require.NoError(t, err)
+
+		timestamp := time.Now()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE (min_quantity = $1 AND max_quantity = $2 AND configuration_id = $3 AND sizes_signature = $4 AND mode = $5) AND tenant_id = $6 ORDER BY "order_calculations"."id" LIMIT $7`)).
+			WithArgs(minQuantity, maxQuantity, configID, "sizes-signature", CalculationModeStandard, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_quantity", "min_quantity", "max_quantity", "result", "total_items", "total_packs", "configuration_id", "timestamp"}).
+				AddRow(1, 1000, minQuantity, maxQuantity, resultJSON, 1000, 2, configID, timestamp))
+
+		// Expect SELECT query for the configuration (preload)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1`)).
+			WithArgs(configID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "pack_sizes", "signature", "active"}).
+				AddRow(1, pq.Int64Array{250, 500, 1000}, "test-signature", true))
+
+		// Execute
+		result, err := repo.GetByConfigurationIDAndQuantityRange(ctx, minQuantity, maxQuantity, configID, "sizes-signature")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, minQuantity, result.MinQuantity)
+		assert.Equal(t, maxQuantity, result.MaxQuantity)
+		assert.Equal(t, packResult, result.Result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("record not found", func(t *testing.T) {
+		// Setup
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		ctx := context.Background()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE (min_quantity = $1 AND max_quantity = $2 AND configuration_id = $3 AND sizes_signature = $4 AND mode = $5) AND tenant_id = $6 ORDER BY "order_calculations"."id" LIMIT $7`)).
+			WithArgs(950, 1050, uint(1), "sizes-signature", CalculationModeStandard, tenant.Default, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		// Execute
+		result, err := repo.GetByConfigurationIDAndQuantityRange(ctx, 950, 1050, uint(1), "sizes-signature")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestList(t *testing.T) {
 	t.Run("successful list", func(t *testing.T) {
 		// Setup
@@ -333,8 +457,8 @@ func TestList(t *testing.T) {
 		resultJSON2, _ := json.Marshal(packResult2)
 
 		// Expect SELECT query for calculations
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" ORDER BY timestamp DESC LIMIT $1`)).
-			WithArgs(limit).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE tenant_id = $1 ORDER BY timestamp DESC LIMIT $2`)).
+			WithArgs(tenant.Default, limit).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "order_quantity", "result", "total_items", "total_packs", "configuration_id", "timestamp"}).
 				AddRow(1, 1250, resultJSON1, 1250, 3, 1, timestamp).
 				AddRow(2, 1500, resultJSON2, 1500, 2, 1, timestamp))
@@ -371,8 +495,8 @@ func TestList(t *testing.T) {
 		limit := 10
 
 		// Expect SELECT query returning empty result
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" ORDER BY timestamp DESC LIMIT $1`)).
-			WithArgs(limit).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE tenant_id = $1 ORDER BY timestamp DESC LIMIT $2`)).
+			WithArgs(tenant.Default, limit).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "order_quantity", "result", "total_items", "total_packs", "configuration_id", "timestamp"}))
 
 		// Execute
@@ -395,8 +519,8 @@ func TestList(t *testing.T) {
 		limit := 10
 
 		// Expect SELECT query with error
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" ORDER BY timestamp DESC LIMIT $1`)).
-			WithArgs(limit).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE tenant_id = $1 ORDER BY timestamp DESC LIMIT $2`)).
+			WithArgs(tenant.Default, limit).
 			WillReturnError(errors.New("database error"))
 
 		// Execute
@@ -423,8 +547,8 @@ func TestDelete(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect DELETE query
-		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_calculations" WHERE "order_calculations"."id" = $1`)).
-			WithArgs(id).
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2`)).
+			WithArgs(id, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		// Expect the COMMIT
@@ -451,8 +575,8 @@ func TestDelete(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect DELETE query with error
-		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_calculations" WHERE "order_calculations"."id" = $1`)).
-			WithArgs(id).
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2`)).
+			WithArgs(id, tenant.Default).
 			WillReturnError(errors.New("database error"))
 
 		// Expect the ROLLBACK
@@ -466,3 +590,110 @@ func TestDelete(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestRecordCacheLookup(t *testing.T) {
+	t.Run("successful insert", func(t *testing.T) {
+		// Setup
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		ctx := context.Background()
+		calculationID := uint(7)
+		lookup := &CacheLookup{ConfigurationID: 1, SizesSignature: "sizes-signature", OrderQuantity: 250, Hit: true, CalculationID: &calculationID}
+
+		// Expect the INSERT inside a transaction
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "calculation_cache_lookups" ("tenant_id","configuration_id","sizes_signature","order_quantity","hit","calculation_id") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id","timestamp"`)).
+			WithArgs(tenant.Default, lookup.ConfigurationID, lookup.SizesSignature, lookup.OrderQuantity, lookup.Hit, calculationID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(1, time.Now()))
+		mock.ExpectCommit()
+
+		// Execute
+		err := repo.RecordCacheLookup(ctx, lookup)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), lookup.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		// Setup
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		ctx := context.Background()
+
+		// Expect the INSERT to fail and roll back
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "calculation_cache_lookups"`)).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		// Execute
+		err := repo.RecordCacheLookup(ctx, &CacheLookup{ConfigurationID: 1, OrderQuantity: 250})
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestTenantIsolation tests that a tenant only reads and changes its own calculations
+func TestTenantIsolation(t *testing.T) {
+	retail := tenant.NewContext(context.Background(), "retail")
+
+	t.Run("calculation of another tenant is not found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		// Calculation 1 belongs to the default tenant, so no row matches the retail tenant
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2 ORDER BY "order_calculations"."id" LIMIT $3`)).
+			WithArgs(1, "retail", 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		calc, err := repo.GetByID(retail, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, calc)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete leaves other tenants unchanged", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_calculations" WHERE "order_calculations"."id" = $1 AND tenant_id = $2`)).
+			WithArgs(1, "retail").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Delete(retail, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("save uses the tenant of the context", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		calc := &OrderCalculation{OrderQuantity: 12, ConfigurationID: 4, Mode: CalculationModeStandard}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_calculations"`)).
+			WithArgs("retail", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "timestamp"}).AddRow(7, time.Now()))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Save(retail, calc))
+		assert.Equal(t, "retail", calc.TenantID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
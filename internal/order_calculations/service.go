@@ -3,71 +3,907 @@ package order_calculations
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
 	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/warehouses"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/packsolver"
 	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/utils"
 )
 
 type Service interface {
-	OrderProcessing(ctx context.Context, orderQuantity int) (packs []PackResult, totalItems int, totalPacks int, err error)
+	OrderProcessing(ctx context.Context, order OrderRequest) (*OrderCalculation, error)
 	CalculateOptimalPacks(ctx context.Context, orderQuantity int, packSizes []int) (packCounts map[int]int, err error)
+	AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*Amendment, error)
+	ListCalculations(ctx context.Context, offset, limit int) ([]OrderCalculation, error)
+	GetCalculation(ctx context.Context, id uint) (*OrderCalculation, error)
 }
 
+// ErrCalculationNotFound is returned when a referenced calculation does not exist
+var ErrCalculationNotFound = errors.New("calculation not found")
+
 type service struct {
 	logger          *zap.Logger
 	calculationRepo Repository
 	packsCfgRepo    pack_configurations.Repository
+	warehouseRepo   warehouses.Repository
+	reservationRepo reservations.Repository
+	experimentRepo  experiments.Repository
+	customerRepo    customers.Repository
+	overfillPolicy  OverfillPolicy
+	tieBreak        TieBreakPolicy
+	reservationTTL  time.Duration
+}
+
+// Deps holds the optional dependencies and policies of the service. Without Experiments
+// no experiment is applied, without Customers every customer is unknown, and sourcing
+// needs both Warehouses and Reservations.
+type Deps struct {
+	Warehouses     warehouses.Repository
+	Reservations   reservations.Repository
+	Experiments    experiments.Repository
+	Customers      customers.Repository
+	OverfillPolicy OverfillPolicy
+	TieBreak       TieBreakPolicy
+	ReservationTTL time.Duration
 }
 
-func NewService(logger *zap.Logger, calculationRepo Repository, packsCfgRepo pack_configurations.Repository) Service {
+func NewService(logger *zap.Logger, calculationRepo Repository, packsCfgRepo pack_configurations.Repository, deps Deps) Service {
 	return &service{
 		logger:          logger,
 		calculationRepo: calculationRepo,
 		packsCfgRepo:    packsCfgRepo,
+		warehouseRepo:   deps.Warehouses,
+		reservationRepo: deps.Reservations,
+		experimentRepo:  deps.Experiments,
+		customerRepo:    deps.Customers,
+		overfillPolicy:  deps.OverfillPolicy,
+		tieBreak:        deps.TieBreak,
+		reservationTTL:  deps.ReservationTTL,
 	}
 }
 
-func (s *service) OrderProcessing(ctx context.Context, orderQuantity int) ([]PackResult, int, int, error) {
+func (s *service) OrderProcessing(ctx context.Context, order OrderRequest) (*OrderCalculation, error) {
+	// An order without an explicit window starts at the order quantity
+	if order.MinQuantity == 0 {
+		order.MinQuantity = order.OrderQuantity
+	}
+
 	// Get available pack sizes
+	packCfg, assignment, err := s.resolveConfiguration(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	packSizes := postgres.Int64ArrayToIntSlice(packCfg.PackSizes)
+	sort.Ints(packSizes)
+
+	// Restrict the sizes to the ones the customer accepts
+	packSizes, err = s.effectiveSizes(ctx, order.CustomerID, packSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rules are validated when the configuration is created, so stored rules always parse
+	rules, err := pack_configurations.ParseRules(packCfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+	rules = pack_configurations.ActiveRules(rules, order.OrderQuantity)
+
+	calc, err := s.solveOrder(ctx, order, packCfg.ID, packSizes, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment != nil {
+		s.recordAssignment(ctx, assignment, calc)
+	}
+
+	// Nest the item-level optimum into the configured logistics units
+	if len(packCfg.Nesting) > 0 {
+		calc.Hierarchy = nestPacks(calc.Result, packCfg.Nesting)
+	}
+
+	// Assign the packs to shipping cartons
+	if len(packCfg.PackSpecs) > 0 && len(packCfg.Cartons) > 0 {
+		calc.Cartons = planCartons(calc.Result, packCfg.PackSpecs, packCfg.Cartons, order.CartonObjective)
+	}
+	return calc, nil
+}
+
+// resolveConfiguration returns the pack configuration to calculate an order with. Orders
+// with a reference are split between the active configuration and the candidate of the
+// running experiment, and the returned assignment records the arm the order landed in.
+// The experiment only applies to orders of the tenant its candidate belongs to.
+func (s *service) resolveConfiguration(ctx context.Context, order OrderRequest) (*pack_configurations.PackConfiguration, *experiments.Assignment, error) {
 	packCfg, err := s.packsCfgRepo.GetActive(ctx)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, nil, err
+	}
+	if packCfg == nil {
+		return nil, nil, apperrors.NewValidationError("No active pack configuration")
+	}
+	if order.OrderReference == "" || s.experimentRepo == nil {
+		return packCfg, nil, nil
+	}
+
+	experiment, err := s.experimentRepo.GetRunning(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if experiment == nil {
+		return packCfg, nil, nil
+	}
+
+	// The candidate is only found within its own tenant
+	candidate, err := s.packsCfgRepo.GetByID(ctx, experiment.ConfigurationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if candidate == nil {
+		return packCfg, nil, nil
+	}
+
+	assignment := &experiments.Assignment{
+		ExperimentID:   experiment.ID,
+		Arm:            experiment.Arm(order.OrderReference),
+		OrderReference: order.OrderReference,
+	}
+	if assignment.Arm == experiments.ArmControl {
+		return packCfg, assignment, nil
+	}
+	return candidate, assignment, nil
+}
+
+// effectiveSizes applies the profile of the customer to the configured pack sizes.
+// Orders without a customer are calculated with the configured sizes unchanged.
+func (s *service) effectiveSizes(ctx context.Context, customerID string, packSizes []int) ([]int, error) {
+	if customerID == "" {
+		return packSizes, nil
+	}
+
+	if s.customerRepo == nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Unknown customer %q", customerID))
+	}
+	profile, err := s.customerRepo.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Unknown customer %q", customerID))
 	}
 
-	// Check if the calculation already exists in the database
-	existingCalc, err := s.calculationRepo.GetByConfigurationIDAndOrderQuantity(ctx, orderQuantity, packCfg.ID)
+	sizes := profile.EffectiveSizes(packSizes)
+	if len(sizes) == 0 {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Customer %q accepts none of the available pack sizes", customerID))
+	}
+	return sizes, nil
+}
+
+// recordAssignment stores the outcome of an order calculated in an experiment.
+// Failing to record it does not fail the order.
+func (s *service) recordAssignment(ctx context.Context, assignment *experiments.Assignment, calc *OrderCalculation) {
+	assignment.CalculationID = calc.ID
+	assignment.OrderQuantity = calc.OrderQuantity
+	assignment.TotalItems = calc.TotalItems
+	assignment.TotalPacks = calc.TotalPacks
+	if err := s.experimentRepo.RecordAssignment(ctx, assignment); err != nil {
+		s.logger.Error("Failed to record experiment assignment", zap.Error(err))
+	}
+	calc.Experiment = assignment
+}
+
+// solveOrder finds the item-level packing for an order that satisfies the active pack rules,
+// reusing a cached calculation when possible
+func (s *service) solveOrder(ctx context.Context, order OrderRequest, configID uint, packSizes []int, rules []pack_configurations.Rule) (*OrderCalculation, error) {
+	// Partial fulfilment depends on the inventory at hand, so it is never served from the cache
+	if order.Inventory != nil {
+		if len(packSizes) == 0 {
+			return nil, errors.New("no pack sizes available")
+		}
+		if len(rules) > 0 {
+			return nil, apperrors.NewValidationError("Partial fulfilment is not available while pack rules apply to the order")
+		}
+		return s.partialFulfilment(ctx, order, configID, packSizes)
+	}
+
+	// Sourcing depends on the warehouse stock at hand, so it is never served from the cache either
+	if order.Sourcing {
+		if len(packSizes) == 0 {
+			return nil, errors.New("no pack sizes available")
+		}
+		if len(rules) > 0 {
+			return nil, apperrors.NewValidationError("Sourcing is not available while pack rules apply to the order")
+		}
+		if s.warehouseRepo == nil || s.reservationRepo == nil {
+			return nil, apperrors.NewValidationError("Sourcing is not available without warehouses")
+		}
+		return s.sourceFromWarehouses(ctx, order, configID, packSizes)
+	}
+
+	// Packings under rules are chosen in a fixed order, so they cannot follow a tie-break policy
+	if len(rules) > 0 && order.TieBreak != nil && order.TieBreak.Policy != TieBreakLargerPacks {
+		return nil, apperrors.NewValidationError("Tie-break policies other than larger_packs are not available while pack rules apply to the order")
+	}
+
+	// Check if the calculation already exists in the database. Calculations are only
+	// shared between orders solved with the same set of sizes, rules and tie-break policy.
+	tieBreak := s.tieBreakPolicy(order)
+	signature := sizesSignature(packSizes, rules, tieBreak)
+	var existingCalc *OrderCalculation
+	var err error
+	if order.MaxQuantity == 0 && order.MinQuantity == order.OrderQuantity {
+		existingCalc, err = s.calculationRepo.GetByConfigurationIDAndOrderQuantity(ctx, order.OrderQuantity, configID, signature)
+	} else {
+		existingCalc, err = s.calculationRepo.GetByConfigurationIDAndQuantityRange(ctx, order.MinQuantity, order.MaxQuantity, configID, signature)
+	}
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, err
 	}
+	lookup := &CacheLookup{ConfigurationID: configID, SizesSignature: signature, OrderQuantity: order.OrderQuantity, Hit: existingCalc != nil}
 
 	if existingCalc != nil {
-		s.logger.Info("Found existing calculation", zap.Int("orderQuantity", orderQuantity))
-		return existingCalc.Result, existingCalc.TotalItems, existingCalc.TotalPacks, nil
+		s.logger.Info("Found existing calculation", zap.Int("orderQuantity", order.OrderQuantity))
+		cachedID := existingCalc.ID
+		existingCalc.OrderQuantity = order.OrderQuantity
+		existingCalc.Reason = explainTotal(order, existingCalc.TotalItems)
+		calc, err := s.applyOverfillPolicy(order, packSizes, rules, existingCalc)
+		// A hit served with the cached packing stores no calculation, so the lookup keeps
+		// the cached calculation for the statistics to count the order
+		if err == nil && calc == existingCalc {
+			lookup.CalculationID = &cachedID
+		}
+		s.recordCacheLookup(ctx, lookup)
+		if err != nil {
+			return nil, err
+		}
+		// A backordered packing replaces the cached one and is saved as an order of its own
+		if calc != existingCalc {
+			if err := s.calculationRepo.Save(ctx, calc); err != nil {
+				return nil, err
+			}
+		}
+		return calc, nil
 	}
 
-	packSizes := postgres.Int64ArrayToIntSlice(packCfg.PackSizes)
+	s.recordCacheLookup(ctx, lookup)
+
 	if len(packSizes) == 0 {
-		return nil, 0, 0, errors.New("no pack sizes available")
+		return nil, errors.New("no pack sizes available")
 	}
 
 	// Handle edge cases
-	if orderQuantity == 0 || len(packSizes) == 0 {
-		return []PackResult{}, 0, 0, nil
+	if order.MinQuantity == 0 || len(packSizes) == 0 {
+		return &OrderCalculation{OrderQuantity: order.OrderQuantity, Result: []PackResult{}}, nil
+	}
+
+	// Calculate optimal packs for the lower bound of the window, the smallest
+	// achievable total at or above it is the best total inside the window
+	packCounts, err := solveWithRules(order.MinQuantity, packSizes, rules, packsolver.Options{TieBreak: tieBreak})
+	if err != nil {
+		return nil, err
+	}
+
+	packs, totalItems, totalPacks := summarizePacks(packCounts)
+
+	if order.MaxQuantity > 0 && totalItems > order.MaxQuantity {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("No pack combination totals between %d and %d items", order.MinQuantity, order.MaxQuantity))
+	}
+
+	calc := &OrderCalculation{
+		OrderQuantity:   order.OrderQuantity,
+		MinQuantity:     order.MinQuantity,
+		MaxQuantity:     order.MaxQuantity,
+		Mode:            CalculationModeStandard,
+		TotalItems:      totalItems,
+		TotalPacks:      totalPacks,
+		Result:          packs,
+		ConfigurationID: configID,
+		SizesSignature:  signature,
+		CustomerID:      order.CustomerID,
+	}
+
+	calc.Reason = explainTotal(order, totalItems)
+
+	// Apply the overfill policy first, so a rejected packing is never saved and a
+	// backordered one is saved in place of the optimum
+	calc, err = s.applyOverfillPolicy(order, packSizes, rules, calc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the order calculation to the database
+	if err := s.calculationRepo.Save(ctx, calc); err != nil {
+		return nil, err
+	}
+	return calc, nil
+}
+
+// sizesSignature identifies the pack sizes, active rules and tie-break policy an order is
+// solved with. The policy is left out when rules apply, since it does not affect the packing.
+func sizesSignature(packSizes []int, rules []pack_configurations.Rule, tieBreak TieBreakPolicy) string {
+	parts := append([]string{utils.CalculateArrayHash(packSizes)}, ruleTexts(rules)...)
+	if len(rules) == 0 {
+		parts = append(parts, tieBreak.String())
 	}
+	return utils.CalculateStringHash(strings.Join(parts, ";"))
+}
+
+// tieBreakPolicy returns the tie-break policy of the order, or the service default
+func (s *service) tieBreakPolicy(order OrderRequest) TieBreakPolicy {
+	if order.TieBreak != nil {
+		return *order.TieBreak
+	}
+	return s.tieBreak
+}
+
+func ruleTexts(rules []pack_configurations.Rule) []string {
+	texts := make([]string, len(rules))
+	for i, rule := range rules {
+		texts[i] = rule.Text
+	}
+	return texts
+}
+
+// recordCacheLookup stores the outcome of a cache lookup for the statistics.
+// Failing to record it does not fail the order.
+func (s *service) recordCacheLookup(ctx context.Context, lookup *CacheLookup) {
+	if err := s.calculationRepo.RecordCacheLookup(ctx, lookup); err != nil {
+		s.logger.Error("Failed to record cache lookup", zap.Error(err))
+	}
+}
 
-	// Sort pack sizes to ensure we work from smallest to largest
+// AmendOrder raises the quantity of an existing calculation. The packs of the original
+// result stay fixed and only the extra quantity is solved, using the original configuration.
+// The amended packing is compared against a from-scratch optimum for the new quantity.
+// Orders of a customer keep being restricted to the sizes the customer accepts.
+func (s *service) AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*Amendment, error) {
+	original, err := s.calculationRepo.GetByID(ctx, calculationID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, ErrCalculationNotFound
+	}
+
+	if orderQuantity < original.OrderQuantity {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Amended quantity must not be below the original quantity of %d", original.OrderQuantity))
+	}
+
+	packSizes := postgres.Int64ArrayToIntSlice(original.Configuration.PackSizes)
+	if len(packSizes) == 0 {
+		return nil, errors.New("no pack sizes available")
+	}
 	sort.Ints(packSizes)
 
-	// Calculate optimal packs
-	packCounts, err := s.CalculateOptimalPacks(ctx, orderQuantity, packSizes)
+	packSizes, err = s.effectiveSizes(ctx, original.CustomerID, packSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := pack_configurations.ParseRules(original.Configuration.Rules)
+	if err != nil {
+		return nil, err
+	}
+	rules = pack_configurations.ActiveRules(rules, orderQuantity)
+
+	fixedCounts := make(map[int]int, len(original.Result))
+	for _, pack := range original.Result {
+		fixedCounts[pack.Size] += pack.Quantity
+	}
+
+	// Solve only for what the already packed items do not cover. With pack rules the
+	// fixed packs count towards the rules, which may require packs even without extra items.
+	addedCounts := map[int]int{}
+	extra := orderQuantity - original.TotalItems
+	if len(rules) > 0 {
+		added, err := packsolver.Solve(packSizes, max(extra, 0), packsolver.Options{Constraints: pack_configurations.Constraints(rules), Fixed: fixedCounts})
+		if errors.Is(err, packsolver.ErrInfeasible) {
+			return nil, rulesInfeasibleError(orderQuantity, rules)
+		}
+		if err != nil {
+			return nil, err
+		}
+		addedCounts = added.Packs
+	} else if extra > 0 {
+		addedCounts, err = s.CalculateOptimalPacks(ctx, extra, packSizes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	addedPacks, _, _ := summarizePacks(addedCounts)
+
+	combinedCounts := make(map[int]int, len(addedCounts)+len(fixedCounts))
+	for size, quantity := range addedCounts {
+		combinedCounts[size] += quantity
+	}
+	for size, quantity := range fixedCounts {
+		combinedCounts[size] += quantity
+	}
+	packs, totalItems, totalPacks := summarizePacks(combinedCounts)
+
+	scratchCounts, err := solveWithRules(orderQuantity, packSizes, rules, packsolver.Options{TieBreak: s.tieBreak})
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, err
 	}
+	scratchPacks, scratchItems, scratchPackCount := summarizePacks(scratchCounts)
+
+	amendsID := original.ID
+	calc := &OrderCalculation{
+		OrderQuantity:   orderQuantity,
+		MinQuantity:     orderQuantity,
+		Mode:            CalculationModeAmendment,
+		AmendsID:        &amendsID,
+		Result:          packs,
+		TotalItems:      totalItems,
+		TotalPacks:      totalPacks,
+		ConfigurationID: original.ConfigurationID,
+		SizesSignature:  sizesSignature(packSizes, rules, s.tieBreak),
+		CustomerID:      original.CustomerID,
+	}
+
+	// Save the amended order calculation to the database
+	if err := s.calculationRepo.Save(ctx, calc); err != nil {
+		return nil, err
+	}
+
+	return &Amendment{
+		Calculation: calc,
+		FixedPacks:  original.Result,
+		AddedPacks:  addedPacks,
+		FromScratch: PackingOption{TotalItems: scratchItems, TotalPacks: scratchPackCount, Packs: scratchPacks},
+	}, nil
+}
+
+// ListCalculations returns a page of stored calculations, newest first
+func (s *service) ListCalculations(ctx context.Context, offset, limit int) ([]OrderCalculation, error) {
+	return s.calculationRepo.List(ctx, offset, limit)
+}
+
+// GetCalculation returns a stored calculation by ID
+func (s *service) GetCalculation(ctx context.Context, id uint) (*OrderCalculation, error) {
+	calc, err := s.calculationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if calc == nil {
+		return nil, ErrCalculationNotFound
+	}
+	return calc, nil
+}
 
-	var packs []PackResult
+// partialFulfilment ships what the available inventory allows now and backorders the rest.
+// The immediate shipment is the best packing from stock that does not exceed the optimal
+// total, and the backorder is solved optimally for the remaining quantity. Both are saved
+// as shipments of a single order calculation.
+func (s *service) partialFulfilment(ctx context.Context, order OrderRequest, configID uint, packSizes []int) (*OrderCalculation, error) {
+	tieBreak := s.tieBreakPolicy(order)
+	optimal, err := packsolver.Solve(packSizes, order.MinQuantity, packsolver.Options{TieBreak: tieBreak})
+	if err != nil {
+		return nil, err
+	}
+
+	fromStock, err := packsolver.SolveAtMost(packSizes, optimal.TotalItems, packsolver.Options{Inventory: order.Inventory})
+	if err != nil {
+		return nil, err
+	}
+	packs, shipped, totalPacks := summarizePacks(fromStock.Packs)
+
+	calc := &OrderCalculation{
+		OrderQuantity:   order.OrderQuantity,
+		MinQuantity:     order.MinQuantity,
+		MaxQuantity:     order.MaxQuantity,
+		Mode:            CalculationModePartial,
+		Result:          packs,
+		TotalItems:      shipped,
+		TotalPacks:      totalPacks,
+		ConfigurationID: configID,
+		SizesSignature:  sizesSignature(packSizes, nil, tieBreak),
+		CustomerID:      order.CustomerID,
+		Shipments:       []Shipment{},
+	}
+
+	if shipped > 0 {
+		// The shipment covers the items shipped, up to the most the order accepts
+		maxQuantity := max(order.MinQuantity, order.MaxQuantity)
+		calc.Shipments = append(calc.Shipments, Shipment{
+			Kind:       ShipmentKindImmediate,
+			Quantity:   min(shipped, maxQuantity),
+			Result:     packs,
+			TotalItems: shipped,
+			TotalPacks: totalPacks,
+		})
+	}
+
+	if remaining := order.MinQuantity - shipped; remaining > 0 {
+		backorder, err := packsolver.Solve(packSizes, remaining, packsolver.Options{TieBreak: tieBreak})
+		if err != nil {
+			return nil, err
+		}
+		backorderPacks, backorderItems, backorderPackCount := summarizePacks(backorder.Packs)
+		calc.Shipments = append(calc.Shipments, Shipment{
+			Kind:       ShipmentKindBackorder,
+			Quantity:   remaining,
+			Result:     backorderPacks,
+			TotalItems: backorderItems,
+			TotalPacks: backorderPackCount,
+		})
+		calc.Backorder = remaining
+	}
+
+	// Save the order calculation together with its shipments
+	if err := s.calculationRepo.Save(ctx, calc); err != nil {
+		return nil, err
+	}
+
+	if calc.Backorder > 0 {
+		calc.Reason = fmt.Sprintf("available inventory ships %d items now, %d items backordered", shipped, calc.Backorder)
+	} else {
+		calc.Reason = "available inventory covers the optimal packing"
+	}
+	return calc, nil
+}
+
+// sourceFromWarehouses builds a plan that takes the packs of an order from the warehouses.
+// Plans are ranked by overfill, then by the number of warehouses shipping, then by the
+// number of packs, so a single warehouse is used whenever it can ship the smallest total
+// the pooled stock allows. Packs held by active reservations are not available, and the
+// chosen packs are reserved when the order asks for it, with the warehouses locked until
+// the reservation is stored. The plan is saved but never reused as a cached result. A
+// reserved plan is saved in the transaction of its reservation, so it is only kept when
+// the reservation is.
+func (s *service) sourceFromWarehouses(ctx context.Context, order OrderRequest, configID uint, packSizes []int) (*OrderCalculation, error) {
+	now := time.Now()
+	if !order.Reserve {
+		stock, err := s.warehouseRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		active, err := s.reservationRepo.ListActive(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		calc, err := s.planSourcedCalculation(order, configID, packSizes, stock, active)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.calculationRepo.Save(ctx, calc); err != nil {
+			return nil, err
+		}
+		return calc, nil
+	}
+
+	ttl := order.ReservationTTL
+	if ttl == 0 {
+		ttl = s.reservationTTL
+	}
+	var calc *OrderCalculation
+	reservation, err := s.reservationRepo.Reserve(ctx, now, func(tx *gorm.DB, stock []warehouses.Warehouse, active []reservations.Reservation) (*reservations.Reservation, error) {
+		var err error
+		calc, err = s.planSourcedCalculation(order, configID, packSizes, stock, active)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.calculationRepo.WithTx(tx).Save(ctx, calc); err != nil {
+			return nil, err
+		}
+		return &reservations.Reservation{
+			CalculationID: calc.ID,
+			Status:        reservations.StatusActive,
+			Items:         reservationItems(calc.Sourcing),
+			ExpiresAt:     now.Add(ttl),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	calc.Reservation = reservation
+	return calc, nil
+}
+
+// planSourcedCalculation plans the sourcing of an order from the stock the active
+// reservations leave available. The calculation is returned unsaved.
+func (s *service) planSourcedCalculation(order OrderRequest, configID uint, packSizes []int, stock []warehouses.Warehouse, active []reservations.Reservation) (*OrderCalculation, error) {
+	stock = availableStock(stock, reservations.Reserved(active))
+
+	allocations := planSourcing(order.MinQuantity, packSizes, stock)
+	if allocations == nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Warehouse stock cannot cover %d items", order.MinQuantity))
+	}
+
+	packCounts := make(map[int]int)
+	for _, allocation := range allocations {
+		for _, pack := range allocation.Packs {
+			packCounts[pack.Size] += pack.Quantity
+		}
+	}
+	packs, totalItems, totalPacks := summarizePacks(packCounts)
+	if order.MaxQuantity > 0 && totalItems > order.MaxQuantity {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Warehouse stock cannot ship between %d and %d items", order.MinQuantity, order.MaxQuantity))
+	}
+
+	calc := &OrderCalculation{
+		OrderQuantity:   order.OrderQuantity,
+		MinQuantity:     order.MinQuantity,
+		MaxQuantity:     order.MaxQuantity,
+		Mode:            CalculationModeSourcing,
+		Result:          packs,
+		Sourcing:        allocations,
+		TotalItems:      totalItems,
+		TotalPacks:      totalPacks,
+		ConfigurationID: configID,
+		SizesSignature:  sizesSignature(packSizes, nil, s.tieBreakPolicy(order)),
+		CustomerID:      order.CustomerID,
+	}
+	calc.Reason = fmt.Sprintf("%s, sourced from %d of %d warehouses", explainTotal(order, totalItems), len(allocations), len(stock))
+	return calc, nil
+}
+
+// availableStock returns the warehouses with the reserved packs taken out of their inventory
+func availableStock(stock []warehouses.Warehouse, reserved map[uint]map[int]int) []warehouses.Warehouse {
+	available := make([]warehouses.Warehouse, 0, len(stock))
+	for _, warehouse := range stock {
+		inventory := make(map[int]int, len(warehouse.Inventory))
+		for size, quantity := range warehouse.Inventory {
+			inventory[size] = max(quantity-reserved[warehouse.ID][size], 0)
+		}
+		warehouse.Inventory = inventory
+		available = append(available, warehouse)
+	}
+	return available
+}
+
+// reservationItems lists the packs of a sourcing plan per warehouse and size
+func reservationItems(allocations []WarehouseAllocation) []reservations.ReservationItem {
+	var items []reservations.ReservationItem
+	for _, allocation := range allocations {
+		for _, pack := range allocation.Packs {
+			items = append(items, reservations.ReservationItem{
+				WarehouseID: allocation.WarehouseID,
+				Size:        pack.Size,
+				Quantity:    pack.Quantity,
+			})
+		}
+	}
+	return items
+}
+
+// maxSourcingCombinations bounds the warehouse combinations planSourcing solves, since
+// their number grows exponentially with the number of warehouses
+const maxSourcingCombinations = 1000
+
+// planSourcing finds the smallest total of at least the order quantity the pooled warehouse
+// stock can make, then the fewest warehouses that can make that total on their own, and
+// among those the combination using the fewest packs. Combinations are tried by size while
+// their number stays within maxSourcingCombinations; past that the warehouses are chosen
+// greedily. It returns nil when the pooled stock cannot cover the order quantity.
+func planSourcing(orderQuantity int, packSizes []int, stock []warehouses.Warehouse) []WarehouseAllocation {
+	var stocked []warehouses.Warehouse
+	pooled := make(map[int]int)
+	for _, warehouse := range stock {
+		held := 0
+		for _, size := range packSizes {
+			pooled[size] += warehouse.Inventory[size]
+			held += warehouse.Inventory[size]
+		}
+		if held > 0 {
+			stocked = append(stocked, warehouse)
+		}
+	}
+
+	cover, err := packsolver.Solve(packSizes, orderQuantity, packsolver.Options{Inventory: pooled})
+	if err != nil {
+		return nil
+	}
+	target := cover.TotalItems
+
+	tried := 0
+	for count := 1; count <= len(stocked); count++ {
+		tried += combinations(len(stocked), count, maxSourcingCombinations)
+		if tried > maxSourcingCombinations {
+			break
+		}
+
+		var bestSubset []int
+		var bestCounts map[int]int
+		bestPacks := 0
+
+		forEachCombination(len(stocked), count, func(subset []int) {
+			solution, ok := solveFromWarehouses(packSizes, target, subset, stocked)
+			if ok && (bestSubset == nil || solution.TotalPacks < bestPacks) {
+				bestSubset = append([]int(nil), subset...)
+				bestCounts = solution.Packs
+				bestPacks = solution.TotalPacks
+			}
+		})
+
+		if bestSubset != nil {
+			return allocateToWarehouses(bestCounts, bestSubset, stocked, packSizes)
+		}
+	}
+
+	subset := greedyWarehouses(packSizes, target, stocked)
+	solution, _ := solveFromWarehouses(packSizes, target, subset, stocked)
+	return allocateToWarehouses(solution.Packs, subset, stocked, packSizes)
+}
+
+// solveFromWarehouses finds the fewest packs making exactly the target from the pooled
+// stock of the warehouses in subset
+func solveFromWarehouses(packSizes []int, target int, subset []int, stocked []warehouses.Warehouse) (packsolver.Solution, bool) {
+	available := make(map[int]int)
+	for _, i := range subset {
+		for size, quantity := range stocked[i].Inventory {
+			available[size] += quantity
+		}
+	}
+	solution, err := packsolver.SolveAtMost(packSizes, target, packsolver.Options{Inventory: available})
+	return solution, err == nil && solution.TotalItems == target
+}
+
+// greedyWarehouses adds the warehouses holding the most items until together they can make
+// the target, then drops the ones the others can do without, smallest first. The pooled
+// stock of all warehouses makes the target, so some prefix of them does.
+func greedyWarehouses(packSizes []int, target int, stocked []warehouses.Warehouse) []int {
+	held := make([]int, len(stocked))
+	order := make([]int, len(stocked))
+	for i, warehouse := range stocked {
+		for _, size := range packSizes {
+			held[i] += size * warehouse.Inventory[size]
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return held[order[a]] > held[order[b]] })
+
+	var chosen []int
+	for _, i := range order {
+		chosen = append(chosen, i)
+		if _, ok := solveFromWarehouses(packSizes, target, chosen, stocked); ok {
+			break
+		}
+	}
+	for j := len(chosen) - 1; j >= 0 && len(chosen) > 1; j-- {
+		without := slices.Delete(slices.Clone(chosen), j, j+1)
+		if _, ok := solveFromWarehouses(packSizes, target, without, stocked); ok {
+			chosen = without
+		}
+	}
+
+	slices.Sort(chosen)
+	return chosen
+}
+
+// allocateToWarehouses splits pack counts over the chosen warehouses, taking each size
+// from the warehouses in order. Since no smaller set of warehouses can make the same total,
+// every chosen warehouse ends up with packs.
+func allocateToWarehouses(packCounts map[int]int, subset []int, stocked []warehouses.Warehouse, packSizes []int) []WarehouseAllocation {
+	taken := make([]map[int]int, len(subset))
+	for j := range subset {
+		taken[j] = make(map[int]int)
+	}
+	for _, size := range packSizes {
+		needed := packCounts[size]
+		for j, i := range subset {
+			quantity := min(needed, stocked[i].Inventory[size])
+			if quantity > 0 {
+				taken[j][size] = quantity
+				needed -= quantity
+			}
+		}
+	}
+
+	allocations := make([]WarehouseAllocation, 0, len(subset))
+	for j, i := range subset {
+		packs, totalItems, totalPacks := summarizePacks(taken[j])
+		allocations = append(allocations, WarehouseAllocation{
+			WarehouseID: stocked[i].ID,
+			Warehouse:   stocked[i].Name,
+			Packs:       packs,
+			TotalItems:  totalItems,
+			TotalPacks:  totalPacks,
+		})
+	}
+	return allocations
+}
+
+// combinations returns the number of combinations of k out of n, or limit+1 when it exceeds limit
+func combinations(n, k, limit int) int {
+	count := 1
+	for i := 0; i < k; i++ {
+		count = count * (n - i) / (i + 1)
+		if count > limit {
+			return limit + 1
+		}
+	}
+	return count
+}
+
+// forEachCombination calls fn with every combination of k indices out of n in lexicographic order
+func forEachCombination(n, k int, fn func(subset []int)) {
+	subset := make([]int, k)
+	var choose func(start, depth int)
+	choose = func(start, depth int) {
+		if depth == k {
+			fn(subset)
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			subset[depth] = i
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+}
+
+// applyOverfillPolicy checks the optimal calculation against the order's overfill policy.
+// When the cap is exceeded it either ships the best packing below the order quantity and
+// backorders the remainder, or reports the closest options as a NoAcceptablePackingError.
+func (s *service) applyOverfillPolicy(order OrderRequest, packSizes []int, rules []pack_configurations.Rule, calc *OrderCalculation) (*OrderCalculation, error) {
+	policy := s.overfillPolicy
+	if order.Overfill != nil {
+		policy = *order.Overfill
+	}
+
+	maxOverfill, capped := policy.maxOverfill(order.OrderQuantity)
+	if !capped || calc.TotalItems-order.OrderQuantity <= maxOverfill {
+		return calc, nil
+	}
+
+	// The best packing that does not exceed the order quantity, empty when the rules allow none
+	under, err := packsolver.SolveAtMost(packSizes, order.OrderQuantity, packsolver.Options{TieBreak: s.tieBreakPolicy(order), Constraints: pack_configurations.Constraints(rules)})
+	if err != nil && !errors.Is(err, packsolver.ErrInfeasible) {
+		return nil, err
+	}
+	underPacks, underTotal, underPackCount := summarizePacks(under.Packs)
+
+	if policy.Backorder {
+		return &OrderCalculation{
+			OrderQuantity:   order.OrderQuantity,
+			MinQuantity:     order.MinQuantity,
+			MaxQuantity:     order.MaxQuantity,
+			Mode:            CalculationModeBackorder,
+			Result:          underPacks,
+			TotalItems:      underTotal,
+			TotalPacks:      underPackCount,
+			ConfigurationID: calc.ConfigurationID,
+			SizesSignature:  calc.SizesSignature,
+			CustomerID:      order.CustomerID,
+			Backorder:       order.OrderQuantity - underTotal,
+			Reason:          fmt.Sprintf("optimal total of %d exceeds the overfill cap of %d items, remainder backordered", calc.TotalItems, maxOverfill),
+		}, nil
+	}
+
+	var options []PackingOption
+	if underTotal > 0 {
+		options = append(options, PackingOption{TotalItems: underTotal, TotalPacks: underPackCount, Packs: underPacks})
+	}
+	options = append(options, PackingOption{TotalItems: calc.TotalItems, TotalPacks: calc.TotalPacks, Packs: calc.Result})
+
+	return nil, &NoAcceptablePackingError{
+		OrderQuantity: order.OrderQuantity,
+		MaxOverfill:   maxOverfill,
+		Options:       options,
+	}
+}
+
+// summarizePacks converts pack counts into results sorted by pack size
+// along with the total number of items and packs
+func summarizePacks(packCounts map[int]int) ([]PackResult, int, int) {
+	packs := []PackResult{}
 	totalItems := 0
 	totalPacks := 0
 
@@ -88,107 +924,265 @@ func (s *service) OrderProcessing(ctx context.Context, orderQuantity int) ([]Pac
 		totalPacks += quantity
 	}
 
-	// Save the order calculation to the database
-	err = s.calculationRepo.Save(ctx, &OrderCalculation{
-		OrderQuantity:   orderQuantity,
-		TotalItems:      totalItems,
-		TotalPacks:      totalPacks,
-		Result:          packs,
-		ConfigurationID: packCfg.ID,
-	})
-	if err != nil {
-		return nil, 0, 0, err
-	}
+	return packs, totalItems, totalPacks
+}
 
-	return packs, totalItems, totalPacks, nil
+// explainTotal describes why a total was chosen for the given order
+func explainTotal(order OrderRequest, totalItems int) string {
+	switch {
+	case totalItems == order.OrderQuantity:
+		return "exact match for the order quantity"
+	case totalItems < order.OrderQuantity:
+		return fmt.Sprintf("smallest achievable total within the accepted range, %d below the order quantity", order.OrderQuantity-totalItems)
+	case order.MaxQuantity > 0 || order.MinQuantity != order.OrderQuantity:
+		return fmt.Sprintf("smallest achievable total within the accepted range, %d above the order quantity", totalItems-order.OrderQuantity)
+	default:
+		return fmt.Sprintf("smallest achievable total covering the order quantity, %d above it", totalItems-order.OrderQuantity)
+	}
 }
 
 // CalculateOptimalPacks finds the optimal combination of pack_configurations to fulfill an order
-// Returns a map where keys are pack sizes and values are the number of pack_configurations needed
+// Returns a map where keys are pack sizes and values are the number of pack_configurations needed.
+// Quantities below one are solved as one, so at least one pack is always returned.
 func (s *service) CalculateOptimalPacks(ctx context.Context, orderQuantity int, packSizes []int) (map[int]int, error) {
-	// Fast-path for orders smaller than minimum pack size
-	if orderQuantity <= packSizes[0] {
-		return map[int]int{packSizes[0]: 1}, nil
+	solution, err := packsolver.Solve(packSizes, max(orderQuantity, 1), packsolver.Options{TieBreak: s.tieBreak})
+	if err != nil {
+		return nil, err
 	}
+	return solution.Packs, nil
+}
 
-	// Step 1: Find the minimum total items needed to fulfill the order
-	minTotal := findMinTotalItems(orderQuantity, packSizes)
-
-	// Step 2: Find the minimal pack combination for this total
-	packCounts := findMinPacks(minTotal, packSizes)
+// solveWithRules finds the optimal packs for the quantity that satisfy the rules
+func solveWithRules(quantity int, packSizes []int, rules []pack_configurations.Rule, opts packsolver.Options) (map[int]int, error) {
+	opts.Constraints = pack_configurations.Constraints(rules)
+	solution, err := packsolver.Solve(packSizes, quantity, opts)
+	if errors.Is(err, packsolver.ErrInfeasible) {
+		return nil, rulesInfeasibleError(quantity, rules)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return solution.Packs, nil
+}
 
-	return packCounts, nil
+// rulesInfeasibleError reports that no packing for the quantity satisfies the rules
+func rulesInfeasibleError(quantity int, rules []pack_configurations.Rule) error {
+	return apperrors.NewValidationError(fmt.Sprintf("No pack combination for %d items satisfies the pack rules: %s", quantity, strings.Join(ruleTexts(rules), "; ")))
 }
 
-// findMinTotalItems finds the smallest possible total that can be created using
-// available pack_configurations and is at least the order quantity
-func findMinTotalItems(orderQuantity int, packSizes []int) int {
-	// If no pack_configurations available, return -1 (error)
-	if len(packSizes) == 0 {
-		return -1
+// nestPacks arranges packs into the logistics units described by the nesting rules and
+// returns the resulting tree grouped into identical units. Units are filled to capacity
+// level by level, so every unit that can be nested is, which minimises the number of
+// top-level units for the given packs. Packs no rule applies to stay at the top level.
+func nestPacks(packs []PackResult, rules []pack_configurations.NestingRule) []PackResult {
+	// Expand into individual packs, largest first
+	var loose []PackResult
+	for i := len(packs) - 1; i >= 0; i-- {
+		for q := 0; q < packs[i].Quantity; q++ {
+			loose = append(loose, PackResult{Unit: pack_configurations.NestingUnitPack, Size: packs[i].Size, Quantity: 1})
+		}
 	}
 
-	smallestPack := packSizes[0]
-	maxPossibleTotal := orderQuantity + smallestPack - 1
-
-	// dp[i] = true if we can make exactly i items using the available pack_configurations
-	dp := make([]bool, maxPossibleTotal+1)
-	dp[0] = true
+	// Each pass nests at least one level deeper, so the depth is bounded by the number of rules
+	for pass := 0; pass <= len(rules); pass++ {
+		nested := false
+		for _, rule := range rules {
+			var children, rest []PackResult
+			for _, unit := range loose {
+				if unit.Unit == rule.Contains && (rule.Size == 0 || unit.Size == rule.Size) {
+					children = append(children, unit)
+				} else {
+					rest = append(rest, unit)
+				}
+			}
+			if len(children) == 0 || rule.Capacity <= 0 {
+				continue
+			}
 
-	for _, packSize := range packSizes {
-		for i := packSize; i <= maxPossibleTotal; i++ {
-			if dp[i-packSize] {
-				dp[i] = true
+			for start := 0; start < len(children); start += rule.Capacity {
+				end := min(start+rule.Capacity, len(children))
+				items := 0
+				for _, child := range children[start:end] {
+					items += child.Size
+				}
+				rest = append(rest, PackResult{
+					Unit:     rule.Unit,
+					Size:     items,
+					Quantity: 1,
+					Contents: groupUnits(children[start:end]),
+				})
 			}
+			loose = rest
+			nested = true
+		}
+		if !nested {
+			break
 		}
 	}
 
-	// Find the smallest valid total that's at least the order quantity
-	for i := orderQuantity; i <= maxPossibleTotal; i++ {
-		if dp[i] {
-			return i
+	return groupUnits(loose)
+}
+
+// groupUnits merges identical units into a single entry with a summed quantity,
+// keeping the order in which units first appear
+func groupUnits(units []PackResult) []PackResult {
+	var grouped []PackResult
+	for _, unit := range units {
+		merged := false
+		for i := range grouped {
+			if grouped[i].Unit == unit.Unit && grouped[i].Size == unit.Size && reflect.DeepEqual(grouped[i].Contents, unit.Contents) {
+				grouped[i].Quantity += unit.Quantity
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			grouped = append(grouped, unit)
 		}
 	}
-
-	return -1 // Should never happen if at least one pack size exists
+	return grouped
 }
 
-// findMinPacks finds the minimum number of pack_configurations needed to make exactly the target total
-func findMinPacks(targetTotal int, packSizes []int) map[int]int {
-	if targetTotal <= 0 {
-		return make(map[int]int)
+// planCartons assigns packs to shipping cartons with first-fit decreasing by volume.
+// A pass is run for each carton type used to open new cartons, every carton is then
+// shrunk to the cheapest type its contents fit, and the best pass for the objective wins.
+func planCartons(packs []PackResult, specs []pack_configurations.PackSpec, cartonTypes []pack_configurations.CartonType, objective string) *CartonPlan {
+	specBySize := make(map[int]pack_configurations.PackSpec, len(specs))
+	for _, spec := range specs {
+		specBySize[spec.Size] = spec
+	}
+
+	// Expand into individual packs, leaving aside those that cannot be placed
+	var items []pack_configurations.PackSpec
+	unassigned := make(map[int]int)
+	for _, pack := range packs {
+		spec, ok := specBySize[pack.Size]
+		if !ok || largestCartonFor(spec, cartonTypes) == nil {
+			unassigned[pack.Size] += pack.Quantity
+			continue
+		}
+		for q := 0; q < pack.Quantity; q++ {
+			items = append(items, spec)
+		}
 	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Volume != items[j].Volume {
+			return items[i].Volume > items[j].Volume
+		}
+		return items[i].Weight > items[j].Weight
+	})
 
-	// dp[i] = minimum number of pack_configurations needed to make i items
-	dp := make([]int, targetTotal+1)
-	for i := range dp {
-		dp[i] = targetTotal + 1 // A value larger than any possible number of pack_configurations
+	var best *CartonPlan
+	for i := range cartonTypes {
+		plan := firstFitDecreasing(items, cartonTypes[i], cartonTypes)
+		if best == nil || betterCartonPlan(plan, best, objective) {
+			best = plan
+		}
 	}
-	dp[0] = 0
 
-	// lastPack[i] = which pack size was last used to achieve total i
-	lastPack := make([]int, targetTotal+1)
+	if len(unassigned) > 0 {
+		best.Unassigned, _, _ = summarizePacks(unassigned)
+	}
+	return best
+}
 
-	for i := 1; i <= targetTotal; i++ {
-		for _, packSize := range packSizes {
-			if i >= packSize && dp[i-packSize] != targetTotal+1 {
-				if dp[i-packSize]+1 < dp[i] {
-					dp[i] = dp[i-packSize] + 1
-					lastPack[i] = packSize
-				}
+// cartonBin is a carton being filled during first-fit decreasing
+type cartonBin struct {
+	carton pack_configurations.CartonType
+	counts map[int]int
+	volume float64
+	weight float64
+}
+
+// firstFitDecreasing places items, already sorted largest first, into the first carton
+// they fit. New cartons use the opener type when the item fits it, or the largest
+// type that holds the item otherwise.
+func firstFitDecreasing(items []pack_configurations.PackSpec, opener pack_configurations.CartonType, cartonTypes []pack_configurations.CartonType) *CartonPlan {
+	var bins []*cartonBin
+	for _, item := range items {
+		var target *cartonBin
+		for _, bin := range bins {
+			if cartonFits(bin.carton, bin.volume+item.Volume, bin.weight+item.Weight) {
+				target = bin
+				break
+			}
+		}
+		if target == nil {
+			carton := opener
+			if !cartonFits(carton, item.Volume, item.Weight) {
+				carton = *largestCartonFor(item, cartonTypes)
 			}
+			target = &cartonBin{carton: carton, counts: make(map[int]int)}
+			bins = append(bins, target)
 		}
+		target.counts[item.Size]++
+		target.volume += item.Volume
+		target.weight += item.Weight
 	}
 
-	// Reconstruct the solution
-	packCounts := make(map[int]int)
-	remainingTotal := targetTotal
+	plan := &CartonPlan{Cartons: []CartonAssignment{}}
+	for _, bin := range bins {
+		// Shrink to the cheapest carton type the contents fit
+		carton := bin.carton
+		for _, candidate := range cartonTypes {
+			if cartonFits(candidate, bin.volume, bin.weight) && candidate.Cost < carton.Cost {
+				carton = candidate
+			}
+		}
+		packs, _, _ := summarizePacks(bin.counts)
+		plan.Cartons = append(plan.Cartons, CartonAssignment{
+			Carton: carton.Name,
+			Packs:  packs,
+			Volume: bin.volume,
+			Weight: bin.weight,
+			Cost:   carton.Cost,
+		})
+		plan.TotalCartons++
+		plan.TotalCost += carton.Cost
+	}
+	return plan
+}
+
+// betterCartonPlan reports whether plan a beats plan b for the objective,
+// comparing the other measure when the objective is tied
+func betterCartonPlan(a, b *CartonPlan, objective string) bool {
+	if objective == CartonObjectiveCost {
+		if a.TotalCost != b.TotalCost {
+			return a.TotalCost < b.TotalCost
+		}
+		return a.TotalCartons < b.TotalCartons
+	}
+	if a.TotalCartons != b.TotalCartons {
+		return a.TotalCartons < b.TotalCartons
+	}
+	return a.TotalCost < b.TotalCost
+}
+
+// cartonFits reports whether a volume and weight fit within a carton type
+func cartonFits(carton pack_configurations.CartonType, volume, weight float64) bool {
+	return (carton.MaxVolume == 0 || volume <= carton.MaxVolume) &&
+		(carton.MaxWeight == 0 || weight <= carton.MaxWeight)
+}
 
-	for remainingTotal > 0 {
-		packSize := lastPack[remainingTotal]
-		packCounts[packSize]++
-		remainingTotal -= packSize
+// largestCartonFor returns the carton type with the most capacity that holds the pack, or nil
+func largestCartonFor(spec pack_configurations.PackSpec, cartonTypes []pack_configurations.CartonType) *pack_configurations.CartonType {
+	var largest *pack_configurations.CartonType
+	for i, carton := range cartonTypes {
+		if !cartonFits(carton, spec.Volume, spec.Weight) {
+			continue
+		}
+		if largest == nil || cartonCapacity(carton.MaxVolume) > cartonCapacity(largest.MaxVolume) ||
+			(carton.MaxVolume == largest.MaxVolume && cartonCapacity(carton.MaxWeight) > cartonCapacity(largest.MaxWeight)) {
+			largest = &cartonTypes[i]
+		}
 	}
+	return largest
+}
 
-	return packCounts
+// cartonCapacity treats an unlimited (zero) carton limit as the largest capacity
+func cartonCapacity(limit float64) float64 {
+	if limit == 0 {
+		return math.Inf(1)
+	}
+	return limit
 }
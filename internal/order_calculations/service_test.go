@@ -3,14 +3,25 @@ package order_calculations
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
 	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/warehouses"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/tenant"
 )
 
 // MockCalculationRepository is a mock implementation of Repository
@@ -31,8 +42,16 @@ func (m *MockCalculationRepository) GetByID(ctx context.Context, id uint) (*Orde
 	return args.Get(0).(*OrderCalculation), args.Error(1)
 }
 
-func (m *MockCalculationRepository) GetByConfigurationIDAndOrderQuantity(ctx context.Context, orderQuantity int, configID uint) (*OrderCalculation, error) {
-	args := m.Called(ctx, orderQuantity, configID)
+func (m *MockCalculationRepository) GetByConfigurationIDAndOrderQuantity(ctx context.Context, orderQuantity int, configID uint, signature string) (*OrderCalculation, error) {
+	args := m.Called(ctx, orderQuantity, configID, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*OrderCalculation), args.Error(1)
+}
+
+func (m *MockCalculationRepository) GetByConfigurationIDAndQuantityRange(ctx context.Context, minQuantity, maxQuantity int, configID uint, signature string) (*OrderCalculation, error) {
+	args := m.Called(ctx, minQuantity, maxQuantity, configID, signature)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -52,6 +71,16 @@ func (m *MockCalculationRepository) Delete(ctx context.Context, id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockCalculationRepository) WithTx(tx *gorm.DB) Repository {
+	args := m.Called(tx)
+	return args.Get(0).(Repository)
+}
+
+func (m *MockCalculationRepository) RecordCacheLookup(ctx context.Context, lookup *CacheLookup) error {
+	args := m.Called(ctx, lookup)
+	return args.Error(0)
+}
+
 // MockPackConfigRepository is a mock implementation of pack_configurations.Repository
 type MockPackConfigRepository struct {
 	mock.Mock
@@ -106,27 +135,169 @@ func (m *MockPackConfigRepository) List(ctx context.Context) ([]pack_configurati
 	return args.Get(0).([]pack_configurations.PackConfiguration), args.Error(1)
 }
 
+// MockWarehouseRepository is a mock implementation of warehouses.Repository
+type MockWarehouseRepository struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseRepository) Create(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) Update(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) GetByName(ctx context.Context, name string) (*warehouses.Warehouse, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*warehouses.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) List(ctx context.Context) ([]warehouses.Warehouse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]warehouses.Warehouse), args.Error(1)
+}
+
+// MockReservationRepository is a mock implementation of reservations.Repository
+type MockReservationRepository struct {
+	mock.Mock
+}
+
+// Reserve runs the plan over the stock and active reservations the mock returns, then
+// fails with the error the mock returns, like a reservation that cannot be stored
+func (m *MockReservationRepository) Reserve(ctx context.Context, now time.Time, plan reservations.Plan) (*reservations.Reservation, error) {
+	args := m.Called(ctx, now)
+	reservation, err := plan(nil, args.Get(0).([]warehouses.Warehouse), args.Get(1).([]reservations.Reservation))
+	if err != nil {
+		return nil, err
+	}
+	if err := args.Error(2); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (m *MockReservationRepository) ListActive(ctx context.Context, now time.Time) ([]reservations.Reservation, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]reservations.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) Commit(ctx context.Context, id uint, now time.Time) (*reservations.Reservation, error) {
+	args := m.Called(ctx, id, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reservations.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockExperimentRepository is a mock implementation of experiments.Repository
+type MockExperimentRepository struct {
+	mock.Mock
+}
+
+func (m *MockExperimentRepository) Start(ctx context.Context, experiment *experiments.Experiment) error {
+	args := m.Called(ctx, experiment)
+	return args.Error(0)
+}
+
+func (m *MockExperimentRepository) GetByID(ctx context.Context, id uint) (*experiments.Experiment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*experiments.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentRepository) GetRunning(ctx context.Context) (*experiments.Experiment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*experiments.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentRepository) Stop(ctx context.Context, id uint, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
+func (m *MockExperimentRepository) RecordAssignment(ctx context.Context, assignment *experiments.Assignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockExperimentRepository) ArmResults(ctx context.Context, experimentID uint) ([]experiments.ArmResult, error) {
+	args := m.Called(ctx, experimentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]experiments.ArmResult), args.Error(1)
+}
+
+// MockCustomerRepository is a mock implementation of customers.Repository
+type MockCustomerRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) Save(ctx context.Context, profile *customers.Profile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockCustomerRepository) GetByCustomerID(ctx context.Context, customerID string) (*customers.Profile, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*customers.Profile), args.Error(1)
+}
+
+func (m *MockCustomerRepository) List(ctx context.Context) ([]customers.Profile, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]customers.Profile), args.Error(1)
+}
+
 func TestService_OrderProcessing(t *testing.T) {
 	logger := zap.NewNop()
 
 	tests := []struct {
 		name          string
-		orderQuantity int
+		order         OrderRequest
 		mockSetup     func(*MockCalculationRepository, *MockPackConfigRepository)
 		wantPacks     []PackResult
 		wantTotal     int
 		wantTotalPack int
+		wantReason    string
 		wantErr       bool
 	}{
 		{
-			name:          "success - cache hit",
-			orderQuantity: 10,
+			name:  "success - cache hit",
+			order: OrderRequest{OrderQuantity: 10},
 			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
 				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
 					ID:        1,
 					PackSizes: pq.Int64Array{3, 5},
 				}, nil)
-				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 10, uint(1)).Return(&OrderCalculation{
+				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 10, uint(1), mock.Anything).Return(&OrderCalculation{
 					Result:     []PackResult{{Size: 5, Quantity: 2}},
 					TotalItems: 10,
 					TotalPacks: 2,
@@ -137,17 +308,18 @@ func TestService_OrderProcessing(t *testing.T) {
 			},
 			wantTotal:     10,
 			wantTotalPack: 2,
+			wantReason:    "exact match for the order quantity",
 			wantErr:       false,
 		},
 		{
-			name:          "success - new calculation",
-			orderQuantity: 8,
+			name:  "success - new calculation",
+			order: OrderRequest{OrderQuantity: 8},
 			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
 				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
 					ID:        1,
 					PackSizes: pq.Int64Array{3, 5},
 				}, nil)
-				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 8, uint(1)).Return(nil, nil)
+				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 8, uint(1), mock.Anything).Return(nil, nil)
 				calcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(nil)
 			},
 			wantPacks: []PackResult{
@@ -156,37 +328,94 @@ func TestService_OrderProcessing(t *testing.T) {
 			},
 			wantTotal:     8,
 			wantTotalPack: 2,
+			wantReason:    "exact match for the order quantity",
 			wantErr:       false,
 		},
 		{
-			name:          "error - no pack sizes",
-			orderQuantity: 10,
+			name:  "success - range below order quantity",
+			order: OrderRequest{OrderQuantity: 1000, MinQuantity: 950, MaxQuantity: 1050},
+			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
+				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+					ID:        1,
+					PackSizes: pq.Int64Array{240},
+				}, nil)
+				calcRepo.On("GetByConfigurationIDAndQuantityRange", mock.Anything, 950, 1050, uint(1), mock.Anything).Return(nil, nil)
+				calcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+					return calc.MinQuantity == 950 && calc.MaxQuantity == 1050 && calc.TotalItems == 960
+				})).Return(nil)
+			},
+			wantPacks: []PackResult{
+				{Size: 240, Quantity: 4},
+			},
+			wantTotal:     960,
+			wantTotalPack: 4,
+			wantReason:    "smallest achievable total within the accepted range, 40 below the order quantity",
+			wantErr:       false,
+		},
+		{
+			name:  "success - range cache hit",
+			order: OrderRequest{OrderQuantity: 1000, MinQuantity: 950, MaxQuantity: 1050},
+			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
+				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+					ID:        1,
+					PackSizes: pq.Int64Array{240},
+				}, nil)
+				calcRepo.On("GetByConfigurationIDAndQuantityRange", mock.Anything, 950, 1050, uint(1), mock.Anything).Return(&OrderCalculation{
+					OrderQuantity: 960,
+					Result:        []PackResult{{Size: 240, Quantity: 4}},
+					TotalItems:    960,
+					TotalPacks:    4,
+				}, nil)
+			},
+			wantPacks: []PackResult{
+				{Size: 240, Quantity: 4},
+			},
+			wantTotal:     960,
+			wantTotalPack: 4,
+			wantReason:    "smallest achievable total within the accepted range, 40 below the order quantity",
+			wantErr:       false,
+		},
+		{
+			name:  "error - no combination inside range",
+			order: OrderRequest{OrderQuantity: 1000, MinQuantity: 990, MaxQuantity: 1010},
+			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
+				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+					ID:        1,
+					PackSizes: pq.Int64Array{240},
+				}, nil)
+				calcRepo.On("GetByConfigurationIDAndQuantityRange", mock.Anything, 990, 1010, uint(1), mock.Anything).Return(nil, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:  "error - no pack sizes",
+			order: OrderRequest{OrderQuantity: 10},
 			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
 				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
 					ID:        1,
 					PackSizes: pq.Int64Array{},
 				}, nil)
-				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 10, uint(1)).Return(nil, nil)
+				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 10, uint(1), mock.Anything).Return(nil, nil)
 			},
 			wantErr: true,
 		},
 		{
-			name:          "error - database error on get active",
-			orderQuantity: 10,
+			name:  "error - database error on get active",
+			order: OrderRequest{OrderQuantity: 10},
 			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
 				packRepo.On("GetActive", mock.Anything).Return(nil, errors.New("db error"))
 			},
 			wantErr: true,
 		},
 		{
-			name:          "error - database error on save",
-			orderQuantity: 8,
+			name:  "error - database error on save",
+			order: OrderRequest{OrderQuantity: 8},
 			mockSetup: func(calcRepo *MockCalculationRepository, packRepo *MockPackConfigRepository) {
 				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
 					ID:        1,
 					PackSizes: pq.Int64Array{3, 5},
 				}, nil)
-				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 8, uint(1)).Return(nil, nil)
+				calcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 8, uint(1), mock.Anything).Return(nil, nil)
 				calcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(errors.New("db error"))
 			},
 			wantErr: true,
@@ -197,19 +426,21 @@ func TestService_OrderProcessing(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockCalcRepo := new(MockCalculationRepository)
 			mockPackRepo := new(MockPackConfigRepository)
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil).Maybe()
 			tt.mockSetup(mockCalcRepo, mockPackRepo)
 
-			s := NewService(logger, mockCalcRepo, mockPackRepo)
-			gotPacks, gotTotal, gotTotalPack, err := s.OrderProcessing(context.Background(), tt.orderQuantity)
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 			assert.NoError(t, err)
-			assert.Equal(t, tt.wantPacks, gotPacks)
-			assert.Equal(t, tt.wantTotal, gotTotal)
-			assert.Equal(t, tt.wantTotalPack, gotTotalPack)
+			assert.Equal(t, tt.wantPacks, got.Result)
+			assert.Equal(t, tt.wantTotal, got.TotalItems)
+			assert.Equal(t, tt.wantTotalPack, got.TotalPacks)
+			assert.Equal(t, tt.wantReason, got.Reason)
 
 			mockCalcRepo.AssertExpectations(t)
 			mockPackRepo.AssertExpectations(t)
@@ -217,66 +448,1360 @@ func TestService_OrderProcessing(t *testing.T) {
 	}
 }
 
-func TestService_CalculateOptimalPacks(t *testing.T) {
+func TestService_OrderProcessing_CacheLookups(t *testing.T) {
 	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{ID: 1, PackSizes: pq.Int64Array{3, 5}}
+
+	tests := []struct {
+		name              string
+		order             OrderRequest
+		cached            *OrderCalculation
+		recordErr         error
+		wantHit           bool
+		wantCalculationID uint
+	}{
+		{
+			name:              "hit",
+			order:             OrderRequest{OrderQuantity: 10},
+			cached:            &OrderCalculation{ID: 4, Result: []PackResult{{Size: 5, Quantity: 2}}, TotalItems: 10, TotalPacks: 2},
+			wantHit:           true,
+			wantCalculationID: 4,
+		},
+		{
+			name:    "miss",
+			order:   OrderRequest{OrderQuantity: 10},
+			wantHit: false,
+		},
+		{
+			name:              "range hit",
+			order:             OrderRequest{OrderQuantity: 10, MinQuantity: 9, MaxQuantity: 11},
+			cached:            &OrderCalculation{ID: 6, Result: []PackResult{{Size: 5, Quantity: 2}}, TotalItems: 10, TotalPacks: 2},
+			wantHit:           true,
+			wantCalculationID: 6,
+		},
+		{
+			name:      "recording failure does not fail the order",
+			order:     OrderRequest{OrderQuantity: 10},
+			recordErr: errors.New("db error"),
+			wantHit:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+			lookupMethod, lookupArgs := "GetByConfigurationIDAndOrderQuantity", []any{mock.Anything, 10, uint(1), mock.Anything}
+			if tt.order.MaxQuantity != 0 {
+				lookupMethod, lookupArgs = "GetByConfigurationIDAndQuantityRange", []any{mock.Anything, tt.order.MinQuantity, tt.order.MaxQuantity, uint(1), mock.Anything}
+			}
+			if tt.cached != nil {
+				mockCalcRepo.On(lookupMethod, lookupArgs...).Return(tt.cached, nil)
+			} else {
+				mockCalcRepo.On(lookupMethod, lookupArgs...).Return(nil, nil)
+				mockCalcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(nil)
+			}
+			// Only hits served with the cached packing point at the cached calculation
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.MatchedBy(func(lookup *CacheLookup) bool {
+				if tt.wantCalculationID == 0 {
+					if lookup.CalculationID != nil {
+						return false
+					}
+				} else if lookup.CalculationID == nil || *lookup.CalculationID != tt.wantCalculationID {
+					return false
+				}
+				return lookup.ConfigurationID == 1 && lookup.OrderQuantity == 10 && lookup.Hit == tt.wantHit
+			})).Return(tt.recordErr).Once()
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 10, got.TotalItems)
+			mockCalcRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_OrderProcessing_NoActiveConfiguration(t *testing.T) {
 	mockCalcRepo := new(MockCalculationRepository)
 	mockPackRepo := new(MockPackConfigRepository)
-	s := NewService(logger, mockCalcRepo, mockPackRepo)
+	mockPackRepo.On("GetActive", mock.Anything).Return(nil, nil)
+
+	s := NewService(zap.NewNop(), mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+	got, err := s.OrderProcessing(tenant.NewContext(context.Background(), "retail"), OrderRequest{OrderQuantity: 501})
+
+	assert.Nil(t, got)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+	assert.Contains(t, err.Error(), "No active pack configuration")
+	mockCalcRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestService_OrderProcessing_Customer(t *testing.T) {
+	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{ID: 1, PackSizes: pq.Int64Array{250, 500, 1000, 2000, 5000}}
+	noLargePacks := &customers.Profile{CustomerID: "acme", ExcludedSizes: []int{5000}}
+	largePacksOnly := &customers.Profile{CustomerID: "bulk", MinSize: 1000}
+	tinyPacksOnly := &customers.Profile{CustomerID: "tiny", MaxSize: 100}
 
 	tests := []struct {
-		name          string
-		orderQuantity int
-		packSizes     []int
-		want          map[int]int
-		wantErr       bool
+		name       string
+		order      OrderRequest
+		profile    *customers.Profile
+		wantSizes  []int
+		wantPacks  []PackResult
+		wantErrMsg string
 	}{
 		{
-			name:          "small order - fast path",
-			orderQuantity: 2,
-			packSizes:     []int{3, 5},
-			want:          map[int]int{3: 1},
-			wantErr:       false,
+			name:      "without customer all active sizes are used",
+			order:     OrderRequest{OrderQuantity: 5000},
+			wantSizes: []int{250, 500, 1000, 2000, 5000},
+			wantPacks: []PackResult{{Size: 5000, Quantity: 1}},
 		},
 		{
-			name:          "exact match",
-			orderQuantity: 10,
-			packSizes:     []int{3, 5},
-			want:          map[int]int{5: 2},
-			wantErr:       false,
+			name:      "excluded size is not used",
+			order:     OrderRequest{OrderQuantity: 5000, CustomerID: "acme"},
+			profile:   noLargePacks,
+			wantSizes: []int{250, 500, 1000, 2000},
+			wantPacks: []PackResult{{Size: 1000, Quantity: 1}, {Size: 2000, Quantity: 2}},
 		},
 		{
-			name:          "needs multiple pack sizes",
-			orderQuantity: 8,
-			packSizes:     []int{3, 5},
-			want:          map[int]int{3: 1, 5: 1},
-			wantErr:       false,
+			name:      "sizes below the minimum are not used",
+			order:     OrderRequest{OrderQuantity: 300, CustomerID: "bulk"},
+			profile:   largePacksOnly,
+			wantSizes: []int{1000, 2000, 5000},
+			wantPacks: []PackResult{{Size: 1000, Quantity: 1}},
 		},
 		{
-			name:          "zero order quantity",
-			orderQuantity: 0,
-			packSizes:     []int{3, 5},
-			want:          map[int]int{3: 1}, // Updated to match implementation
-			wantErr:       false,
+			name:       "unknown customer",
+			order:      OrderRequest{OrderQuantity: 300, CustomerID: "nobody"},
+			wantErrMsg: `Unknown customer "nobody"`,
 		},
 		{
-			name:          "large order",
-			orderQuantity: 28,
-			packSizes:     []int{3, 5, 10},
-			want:          map[int]int{3: 1, 5: 1, 10: 2},
-			wantErr:       false,
+			name:       "customer accepts no active size",
+			order:      OrderRequest{OrderQuantity: 300, CustomerID: "tiny"},
+			profile:    tinyPacksOnly,
+			wantErrMsg: `Customer "tiny" accepts none of the available pack sizes`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := s.CalculateOptimalPacks(context.Background(), tt.orderQuantity, tt.packSizes)
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockCustomerRepo := new(MockCustomerRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+			if tt.order.CustomerID != "" {
+				mockCustomerRepo.On("GetByCustomerID", mock.Anything, tt.order.CustomerID).Return(tt.profile, nil)
+			}
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			// The cache is keyed by the signature of the sizes the order is solved with
+			signature := sizesSignature(tt.wantSizes, nil, TieBreakPolicy{Policy: TieBreakLargerPacks})
+			mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, tt.order.OrderQuantity, uint(1), signature).Return(nil, nil).Maybe()
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.MatchedBy(func(lookup *CacheLookup) bool {
+				return lookup.SizesSignature == signature
+			})).Return(nil).Maybe()
+			mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+				return calc.SizesSignature == signature && calc.CustomerID == tt.order.CustomerID
+			})).Return(nil).Maybe()
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{Customers: mockCustomerRepo, TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
+
+			if tt.wantErrMsg != "" {
+				assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				mockCalcRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
 				return
 			}
 			assert.NoError(t, err)
-			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPacks, got.Result)
+			mockCalcRepo.AssertExpectations(t)
+			mockCustomerRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_OrderProcessing_Rules(t *testing.T) {
+	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{
+		ID:        1,
+		PackSizes: pq.Int64Array{250, 500, 1000, 2000, 5000},
+		Rules:     []string{"at least 1 x 5000 when quantity > 20000", "never mix 250 and 5000", "at least 1 x 250 when quantity < 100", "at least 1 x 5000 when quantity < 100"},
+	}
+	plainSignature := sizesSignature([]int{250, 500, 1000, 2000, 5000}, nil, TieBreakPolicy{Policy: TieBreakLargerPacks})
+
+	tests := []struct {
+		name       string
+		order      OrderRequest
+		wantPacks  []PackResult
+		wantErrMsg string
+	}{
+		{
+			name:      "rules change the optimum",
+			order:     OrderRequest{OrderQuantity: 20251},
+			wantPacks: []PackResult{{Size: 500, Quantity: 1}, {Size: 5000, Quantity: 4}},
+		},
+		{
+			name:      "conditional rule does not apply",
+			order:     OrderRequest{OrderQuantity: 5250},
+			wantPacks: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}, {Size: 2000, Quantity: 2}},
+		},
+		{
+			name:       "infeasible rules are reported",
+			order:      OrderRequest{OrderQuantity: 50},
+			wantErrMsg: "No pack combination for 50 items satisfies the pack rules",
+		},
+		{
+			name:       "partial fulfilment is rejected",
+			order:      OrderRequest{OrderQuantity: 5250, Inventory: map[int]int{250: 1}},
+			wantErrMsg: "Partial fulfilment is not available while pack rules apply to the order",
+		},
+		{
+			name:       "tie-break policy is rejected",
+			order:      OrderRequest{OrderQuantity: 5250, TieBreak: &TieBreakPolicy{Policy: TieBreakFewerSizes}},
+			wantErrMsg: "Tie-break policies other than larger_packs are not available while pack rules apply to the order",
+		},
+		{
+			name:      "larger packs tie-break policy is accepted",
+			order:     OrderRequest{OrderQuantity: 5250, TieBreak: &TieBreakPolicy{Policy: TieBreakLargerPacks}},
+			wantPacks: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}, {Size: 2000, Quantity: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+			mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, tt.order.OrderQuantity, uint(1), mock.Anything).Return(nil, nil).Maybe()
+			mockCalcRepo.On("GetByConfigurationIDAndQuantityRange", mock.Anything, tt.order.MinQuantity, tt.order.MaxQuantity, uint(1), mock.Anything).Return(nil, nil).Maybe()
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+				// Orders solved with rules do not share the cache with unconstrained orders
+				return calc.SizesSignature != plainSignature
+			})).Return(nil).Maybe()
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
+
+			if tt.wantErrMsg != "" {
+				assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				mockCalcRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPacks, got.Result)
+			mockCalcRepo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestService_OrderProcessing_Experiment(t *testing.T) {
+	logger := zap.NewNop()
+	active := &pack_configurations.PackConfiguration{ID: 1, Active: true, PackSizes: pq.Int64Array{3, 5}}
+	candidate := &pack_configurations.PackConfiguration{ID: 2, PackSizes: pq.Int64Array{4}}
+
+	tests := []struct {
+		name           string
+		orderReference string
+		experiment     *experiments.Experiment
+		candidate      *pack_configurations.PackConfiguration
+		recordErr      error
+		wantConfigID   uint
+		wantTotal      int
+		wantArm        string
+	}{
+		{
+			name:         "no order reference",
+			wantConfigID: 1,
+			wantTotal:    8,
+		},
+		{
+			name:           "no running experiment",
+			orderReference: "order-1",
+			wantConfigID:   1,
+			wantTotal:      8,
+		},
+		{
+			name:           "candidate arm",
+			orderReference: "order-1",
+			experiment:     &experiments.Experiment{ID: 7, ConfigurationID: 2, TrafficShare: 100, Status: experiments.StatusRunning},
+			candidate:      candidate,
+			wantConfigID:   2,
+			wantTotal:      8,
+			wantArm:        experiments.ArmCandidate,
+		},
+		{
+			name:           "control arm",
+			orderReference: "order-1",
+			experiment:     &experiments.Experiment{ID: 7, ConfigurationID: 2, TrafficShare: 0, Status: experiments.StatusRunning},
+			candidate:      candidate,
+			wantConfigID:   1,
+			wantTotal:      8,
+			wantArm:        experiments.ArmControl,
+		},
+		{
+			name:           "recording failure does not fail the order",
+			orderReference: "order-1",
+			experiment:     &experiments.Experiment{ID: 7, ConfigurationID: 2, TrafficShare: 100, Status: experiments.StatusRunning},
+			candidate:      candidate,
+			recordErr:      errors.New("db error"),
+			wantConfigID:   2,
+			wantTotal:      8,
+			wantArm:        experiments.ArmCandidate,
+		},
+		{
+			name:           "candidate of another tenant",
+			orderReference: "order-1",
+			experiment:     &experiments.Experiment{ID: 7, ConfigurationID: 2, TrafficShare: 100, Status: experiments.StatusRunning},
+			wantConfigID:   1,
+			wantTotal:      8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockExperimentRepo := new(MockExperimentRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(active, nil)
+			if tt.orderReference != "" {
+				if tt.experiment != nil {
+					mockExperimentRepo.On("GetRunning", mock.Anything).Return(tt.experiment, nil)
+				} else {
+					mockExperimentRepo.On("GetRunning", mock.Anything).Return(nil, nil)
+				}
+			}
+			if tt.experiment != nil {
+				if tt.candidate != nil {
+					mockPackRepo.On("GetByID", mock.Anything, uint(2)).Return(tt.candidate, nil)
+				} else {
+					mockPackRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, nil)
+				}
+			}
+			mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 8, tt.wantConfigID, mock.Anything).Return(nil, nil).Maybe()
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+				return calc.ConfigurationID == tt.wantConfigID
+			})).Run(func(args mock.Arguments) {
+				args.Get(1).(*OrderCalculation).ID = 42
+			}).Return(nil).Maybe()
+			if tt.wantArm != "" {
+				mockExperimentRepo.On("RecordAssignment", mock.Anything, &experiments.Assignment{
+					ExperimentID:   7,
+					Arm:            tt.wantArm,
+					CalculationID:  42,
+					OrderReference: tt.orderReference,
+					OrderQuantity:  8,
+					TotalItems:     8,
+					TotalPacks:     2,
+				}).Return(tt.recordErr)
+			}
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{Experiments: mockExperimentRepo, TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), OrderRequest{OrderQuantity: 8, OrderReference: tt.orderReference})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, got.TotalItems)
+			if tt.wantArm == "" {
+				assert.Nil(t, got.Experiment)
+			} else {
+				assert.Equal(t, tt.wantArm, got.Experiment.Arm)
+			}
+			mockExperimentRepo.AssertExpectations(t)
+			mockPackRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_OrderProcessing_OverfillPolicy(t *testing.T) {
+	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{
+		ID:        1,
+		PackSizes: pq.Int64Array{1000, 5000},
+	}
+
+	tests := []struct {
+		name          string
+		defaultPolicy OverfillPolicy
+		order         OrderRequest
+		wantTotal     int
+		wantBackorder int
+		wantMode      string
+		wantErr       *NoAcceptablePackingError
+	}{
+		{
+			name:          "within cap",
+			defaultPolicy: OverfillPolicy{MaxItems: 500},
+			order:         OrderRequest{OrderQuantity: 2600},
+			wantTotal:     3000,
+			wantMode:      CalculationModeStandard,
+		},
+		{
+			name:          "no cap configured",
+			defaultPolicy: OverfillPolicy{},
+			order:         OrderRequest{OrderQuantity: 2600},
+			wantTotal:     3000,
+			wantMode:      CalculationModeStandard,
+		},
+		{
+			name:          "exceeds absolute cap",
+			defaultPolicy: OverfillPolicy{MaxItems: 200},
+			order:         OrderRequest{OrderQuantity: 2600},
+			wantErr: &NoAcceptablePackingError{
+				OrderQuantity: 2600,
+				MaxOverfill:   200,
+				Options: []PackingOption{
+					{TotalItems: 2000, TotalPacks: 2, Packs: []PackResult{{Size: 1000, Quantity: 2}}},
+					{TotalItems: 3000, TotalPacks: 3, Packs: []PackResult{{Size: 1000, Quantity: 3}}},
+				},
+			},
+		},
+		{
+			name:          "percentage cap is stricter",
+			defaultPolicy: OverfillPolicy{MaxItems: 500, MaxPercent: 10},
+			order:         OrderRequest{OrderQuantity: 2600},
+			wantErr: &NoAcceptablePackingError{
+				OrderQuantity: 2600,
+				MaxOverfill:   260,
+				Options: []PackingOption{
+					{TotalItems: 2000, TotalPacks: 2, Packs: []PackResult{{Size: 1000, Quantity: 2}}},
+					{TotalItems: 3000, TotalPacks: 3, Packs: []PackResult{{Size: 1000, Quantity: 3}}},
+				},
+			},
+		},
+		{
+			name:          "request overrides default",
+			defaultPolicy: OverfillPolicy{MaxItems: 200},
+			order:         OrderRequest{OrderQuantity: 2600, Overfill: &OverfillPolicy{MaxPercent: 20}},
+			wantTotal:     3000,
+			wantMode:      CalculationModeStandard,
+		},
+		{
+			name:          "backorder remainder",
+			defaultPolicy: OverfillPolicy{MaxItems: 200, Backorder: true},
+			order:         OrderRequest{OrderQuantity: 2600},
+			wantTotal:     2000,
+			wantBackorder: 600,
+			wantMode:      CalculationModeBackorder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+			mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 2600, uint(1), mock.Anything).Return(nil, nil)
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil)
+			var saved []*OrderCalculation
+			mockCalcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Run(func(args mock.Arguments) {
+				calc := args.Get(1).(*OrderCalculation)
+				calc.ID = uint(len(saved) + 1)
+				saved = append(saved, calc)
+			}).Return(nil)
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{OverfillPolicy: tt.defaultPolicy, TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
+
+			if tt.wantErr != nil {
+				var noAcceptable *NoAcceptablePackingError
+				assert.True(t, errors.As(err, &noAcceptable))
+				assert.Equal(t, tt.wantErr, noAcceptable)
+				assert.Empty(t, saved, "a rejected calculation must not be saved")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, got.TotalItems)
+			assert.Equal(t, tt.wantBackorder, got.Backorder)
+			assert.Equal(t, tt.wantMode, got.Mode)
+			if assert.Len(t, saved, 1) {
+				assert.Same(t, got, saved[0])
+				assert.NotZero(t, got.ID)
+			}
+		})
+	}
+}
+
+func TestService_OrderProcessing_CachedBackorder(t *testing.T) {
+	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{ID: 1, Active: true, PackSizes: pq.Int64Array{1000, 5000}}
+	cached := &OrderCalculation{
+		ID:              9,
+		OrderQuantity:   2600,
+		MinQuantity:     2600,
+		Mode:            CalculationModeStandard,
+		Result:          []PackResult{{Size: 1000, Quantity: 3}},
+		TotalItems:      3000,
+		TotalPacks:      3,
+		ConfigurationID: 1,
+	}
+
+	mockCalcRepo := new(MockCalculationRepository)
+	mockPackRepo := new(MockPackConfigRepository)
+	mockExperimentRepo := new(MockExperimentRepository)
+	mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+	mockExperimentRepo.On("GetRunning", mock.Anything).Return(&experiments.Experiment{ID: 7, ConfigurationID: 1, TrafficShare: 0, Status: experiments.StatusRunning}, nil)
+	mockPackRepo.On("GetByID", mock.Anything, uint(1)).Return(packCfg, nil)
+	mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 2600, uint(1), mock.Anything).Return(cached, nil)
+	mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil)
+	mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+		return calc.Mode == CalculationModeBackorder && calc.TotalItems == 2000
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*OrderCalculation).ID = 42
+	}).Return(nil).Once()
+	mockExperimentRepo.On("RecordAssignment", mock.Anything, &experiments.Assignment{
+		ExperimentID:   7,
+		Arm:            experiments.ArmControl,
+		CalculationID:  42,
+		OrderReference: "order-1",
+		OrderQuantity:  2600,
+		TotalItems:     2000,
+		TotalPacks:     2,
+	}).Return(nil)
+
+	s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{
+		Experiments:    mockExperimentRepo,
+		OverfillPolicy: OverfillPolicy{MaxItems: 200, Backorder: true},
+		TieBreak:       TieBreakPolicy{Policy: TieBreakLargerPacks},
+	})
+	got, err := s.OrderProcessing(context.Background(), OrderRequest{OrderQuantity: 2600, OrderReference: "order-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), got.ID)
+	assert.Equal(t, 600, got.Backorder)
+	mockCalcRepo.AssertExpectations(t)
+	mockExperimentRepo.AssertExpectations(t)
+}
+
+func TestService_OrderProcessing_PartialFulfilment(t *testing.T) {
+	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{
+		ID:        1,
+		PackSizes: pq.Int64Array{250, 500, 1000, 2000, 5000},
+	}
+
+	tests := []struct {
+		name          string
+		order         OrderRequest
+		wantShipped   int
+		wantBackorder int
+		wantShipments []Shipment
+	}{
+		{
+			name:        "inventory covers the order",
+			order:       OrderRequest{OrderQuantity: 501, Inventory: map[int]int{250: 1, 500: 3}},
+			wantShipped: 750,
+			wantShipments: []Shipment{
+				{Kind: ShipmentKindImmediate, Quantity: 501, Result: []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}}, TotalItems: 750, TotalPacks: 2},
+			},
+		},
+		{
+			name:        "shipment inside the quantity range covers what is shipped",
+			order:       OrderRequest{OrderQuantity: 600, MinQuantity: 501, MaxQuantity: 800, Inventory: map[int]int{250: 1, 500: 3}},
+			wantShipped: 750,
+			wantShipments: []Shipment{
+				{Kind: ShipmentKindImmediate, Quantity: 750, Result: []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}}, TotalItems: 750, TotalPacks: 2},
+			},
+		},
+		{
+			name:        "shipment above the quantity range covers the maximum",
+			order:       OrderRequest{OrderQuantity: 600, MinQuantity: 501, MaxQuantity: 700, Inventory: map[int]int{250: 1, 500: 3}},
+			wantShipped: 750,
+			wantShipments: []Shipment{
+				{Kind: ShipmentKindImmediate, Quantity: 700, Result: []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}}, TotalItems: 750, TotalPacks: 2},
+			},
+		},
+		{
+			name:          "short stock is backordered",
+			order:         OrderRequest{OrderQuantity: 12001, Inventory: map[int]int{5000: 1, 2000: 2}},
+			wantShipped:   9000,
+			wantBackorder: 3001,
+			wantShipments: []Shipment{
+				{Kind: ShipmentKindImmediate, Quantity: 9000, Result: []PackResult{{Size: 2000, Quantity: 2}, {Size: 5000, Quantity: 1}}, TotalItems: 9000, TotalPacks: 3},
+				{Kind: ShipmentKindBackorder, Quantity: 3001, Result: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}, {Size: 2000, Quantity: 1}}, TotalItems: 3250, TotalPacks: 3},
+			},
+		},
+		{
+			name:          "no stock",
+			order:         OrderRequest{OrderQuantity: 251, Inventory: map[int]int{}},
+			wantShipped:   0,
+			wantBackorder: 251,
+			wantShipments: []Shipment{
+				{Kind: ShipmentKindBackorder, Quantity: 251, Result: []PackResult{{Size: 500, Quantity: 1}}, TotalItems: 500, TotalPacks: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+			mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+				return calc.Mode == CalculationModePartial
+			})).Return(nil)
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantShipped, got.TotalItems)
+			assert.Equal(t, tt.wantBackorder, got.Backorder)
+			assert.Equal(t, tt.wantShipments, got.Shipments)
+			assert.Equal(t, sizesSignature([]int{250, 500, 1000, 2000, 5000}, nil, TieBreakPolicy{Policy: TieBreakLargerPacks}), got.SizesSignature)
+
+			mockCalcRepo.AssertExpectations(t)
+			mockCalcRepo.AssertNotCalled(t, "GetByConfigurationIDAndOrderQuantity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_AmendOrder(t *testing.T) {
+	logger := zap.NewNop()
+	original := &OrderCalculation{
+		ID:              1,
+		OrderQuantity:   1000,
+		Result:          []PackResult{{Size: 1000, Quantity: 1}},
+		TotalItems:      1000,
+		TotalPacks:      1,
+		ConfigurationID: 3,
+		Configuration: pack_configurations.PackConfiguration{
+			ID:        3,
+			PackSizes: pq.Int64Array{250, 500, 1000, 2000, 5000},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		calculationID uint
+		orderQuantity int
+		mockSetup     func(*MockCalculationRepository)
+		wantPacks     []PackResult
+		wantAdded     []PackResult
+		wantScratch   PackingOption
+		wantErr       error
+	}{
+		{
+			name:          "extra quantity solved on top of fixed packs",
+			calculationID: 1,
+			orderQuantity: 1300,
+			mockSetup: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("GetByID", mock.Anything, uint(1)).Return(original, nil)
+				calcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+					return calc.Mode == CalculationModeAmendment && *calc.AmendsID == 1 && calc.ConfigurationID == 3
+				})).Return(nil)
+			},
+			wantPacks:   []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 1}},
+			wantAdded:   []PackResult{{Size: 500, Quantity: 1}},
+			wantScratch: PackingOption{TotalItems: 1500, TotalPacks: 2, Packs: []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 1}}},
+		},
+		{
+			name:          "fixed packs differ from scratch optimum",
+			calculationID: 1,
+			orderQuantity: 2001,
+			mockSetup: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("GetByID", mock.Anything, uint(1)).Return(original, nil)
+				calcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(nil)
+			},
+			wantPacks:   []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 2}},
+			wantAdded:   []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}},
+			wantScratch: PackingOption{TotalItems: 2250, TotalPacks: 2, Packs: []PackResult{{Size: 250, Quantity: 1}, {Size: 2000, Quantity: 1}}},
+		},
+		{
+			name:          "already covered by packed items",
+			calculationID: 1,
+			orderQuantity: 1000,
+			mockSetup: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("GetByID", mock.Anything, uint(1)).Return(original, nil)
+				calcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(nil)
+			},
+			wantPacks:   []PackResult{{Size: 1000, Quantity: 1}},
+			wantAdded:   []PackResult{},
+			wantScratch: PackingOption{TotalItems: 1000, TotalPacks: 1, Packs: []PackResult{{Size: 1000, Quantity: 1}}},
+		},
+		{
+			name:          "calculation not found",
+			calculationID: 9,
+			orderQuantity: 1300,
+			mockSetup: func(calcRepo *MockCalculationRepository) {
+				calcRepo.On("GetByID", mock.Anything, uint(9)).Return(nil, nil)
+			},
+			wantErr: ErrCalculationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			tt.mockSetup(mockCalcRepo)
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+			got, err := s.AmendOrder(context.Background(), tt.calculationID, tt.orderQuantity)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPacks, got.Calculation.Result)
+			assert.Equal(t, tt.wantAdded, got.AddedPacks)
+			assert.Equal(t, original.Result, got.FixedPacks)
+			assert.Equal(t, tt.wantScratch, got.FromScratch)
+
+			mockCalcRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("quantity below original", func(t *testing.T) {
+		mockCalcRepo := new(MockCalculationRepository)
+		mockCalcRepo.On("GetByID", mock.Anything, uint(1)).Return(original, nil)
+
+		s := NewService(logger, mockCalcRepo, new(MockPackConfigRepository), Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+		_, err := s.AmendOrder(context.Background(), 1, 999)
+
+		assert.Error(t, err)
+		mockCalcRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+	t.Run("pack rules apply to the added packs", func(t *testing.T) {
+		ruledOriginal := *original
+		ruledOriginal.OrderQuantity = 5000
+		ruledOriginal.Result = []PackResult{{Size: 5000, Quantity: 1}}
+		ruledOriginal.TotalItems = 5000
+		ruledOriginal.Configuration.Rules = []string{"never mix 250 and 5000"}
+		mockCalcRepo := new(MockCalculationRepository)
+		mockCalcRepo.On("GetByID", mock.Anything, uint(1)).Return(&ruledOriginal, nil)
+		mockCalcRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(nil)
+
+		s := NewService(logger, mockCalcRepo, new(MockPackConfigRepository), Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+		got, err := s.AmendOrder(context.Background(), 1, 5100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []PackResult{{Size: 500, Quantity: 1}}, got.AddedPacks)
+		assert.Equal(t, 5250, got.FromScratch.TotalItems)
+	})
+
+	t.Run("customer restriction is kept", func(t *testing.T) {
+		customerOriginal := *original
+		customerOriginal.CustomerID = "acme"
+		mockCalcRepo := new(MockCalculationRepository)
+		mockCustomerRepo := new(MockCustomerRepository)
+		mockCalcRepo.On("GetByID", mock.Anything, uint(1)).Return(&customerOriginal, nil)
+		mockCustomerRepo.On("GetByCustomerID", mock.Anything, "acme").Return(&customers.Profile{CustomerID: "acme", ExcludedSizes: []int{2000}}, nil)
+		mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+			return calc.CustomerID == "acme" && calc.SizesSignature == sizesSignature([]int{250, 500, 1000, 5000}, nil, TieBreakPolicy{Policy: TieBreakLargerPacks})
+		})).Return(nil)
+
+		s := NewService(logger, mockCalcRepo, new(MockPackConfigRepository), Deps{Customers: mockCustomerRepo, TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+		got, err := s.AmendOrder(context.Background(), 1, 2001)
+
+		assert.NoError(t, err)
+		assert.Equal(t, PackingOption{TotalItems: 2250, TotalPacks: 3, Packs: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 2}}}, got.FromScratch)
+		mockCalcRepo.AssertExpectations(t)
+	})
+}
+
+func TestNestPacks(t *testing.T) {
+	caseOf1000 := pack_configurations.NestingRule{Unit: "case", Contains: "pack", Size: 1000, Capacity: 4}
+	pallet := pack_configurations.NestingRule{Unit: "pallet", Contains: "case", Capacity: 2}
+
+	tests := []struct {
+		name  string
+		packs []PackResult
+		rules []pack_configurations.NestingRule
+		want  []PackResult
+	}{
+		{
+			name:  "full and partial cases",
+			packs: []PackResult{{Size: 1000, Quantity: 5}},
+			rules: []pack_configurations.NestingRule{caseOf1000},
+			want: []PackResult{
+				{Unit: "case", Size: 4000, Quantity: 1, Contents: []PackResult{{Unit: "pack", Size: 1000, Quantity: 4}}},
+				{Unit: "case", Size: 1000, Quantity: 1, Contents: []PackResult{{Unit: "pack", Size: 1000, Quantity: 1}}},
+			},
+		},
+		{
+			name:  "packs without a rule stay loose",
+			packs: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 4}},
+			rules: []pack_configurations.NestingRule{caseOf1000},
+			want: []PackResult{
+				{Unit: "pack", Size: 250, Quantity: 1},
+				{Unit: "case", Size: 4000, Quantity: 1, Contents: []PackResult{{Unit: "pack", Size: 1000, Quantity: 4}}},
+			},
+		},
+		{
+			name:  "pallets of cases regardless of rule order",
+			packs: []PackResult{{Size: 1000, Quantity: 12}},
+			rules: []pack_configurations.NestingRule{pallet, caseOf1000},
+			want: []PackResult{
+				{Unit: "pallet", Size: 8000, Quantity: 1, Contents: []PackResult{
+					{Unit: "case", Size: 4000, Quantity: 2, Contents: []PackResult{{Unit: "pack", Size: 1000, Quantity: 4}}},
+				}},
+				{Unit: "pallet", Size: 4000, Quantity: 1, Contents: []PackResult{
+					{Unit: "case", Size: 4000, Quantity: 1, Contents: []PackResult{{Unit: "pack", Size: 1000, Quantity: 4}}},
+				}},
+			},
+		},
+		{
+			name:  "mixed sizes in any-size case",
+			packs: []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}},
+			rules: []pack_configurations.NestingRule{{Unit: "case", Contains: "pack", Capacity: 10}},
+			want: []PackResult{
+				{Unit: "case", Size: 750, Quantity: 1, Contents: []PackResult{{Unit: "pack", Size: 500, Quantity: 1}, {Unit: "pack", Size: 250, Quantity: 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nestPacks(tt.packs, tt.rules))
+		})
+	}
+}
+
+func TestService_OrderProcessing_Hierarchy(t *testing.T) {
+	mockCalcRepo := new(MockCalculationRepository)
+	mockPackRepo := new(MockPackConfigRepository)
+	mockPackRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+		ID:        1,
+		PackSizes: pq.Int64Array{1000},
+		Nesting:   []pack_configurations.NestingRule{{Unit: "case", Contains: "pack", Size: 1000, Capacity: 4}},
+	}, nil)
+	mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 8000, uint(1), mock.Anything).Return(&OrderCalculation{
+		Result:     []PackResult{{Size: 1000, Quantity: 8}},
+		TotalItems: 8000,
+		TotalPacks: 8,
+	}, nil)
+	mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil)
+
+	s := NewService(zap.NewNop(), mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+	got, err := s.OrderProcessing(context.Background(), OrderRequest{OrderQuantity: 8000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PackResult{{Size: 1000, Quantity: 8}}, got.Result)
+	assert.Equal(t, []PackResult{
+		{Unit: "case", Size: 4000, Quantity: 2, Contents: []PackResult{{Unit: "pack", Size: 1000, Quantity: 4}}},
+	}, got.Hierarchy)
+}
+
+func TestPlanCartons(t *testing.T) {
+	specs := []pack_configurations.PackSpec{
+		{Size: 250, Volume: 1, Weight: 2},
+		{Size: 500, Volume: 2, Weight: 4},
+		{Size: 1000, Volume: 4, Weight: 8},
+	}
+	small := pack_configurations.CartonType{Name: "small", MaxVolume: 4, MaxWeight: 10, Cost: 0.5}
+	large := pack_configurations.CartonType{Name: "large", MaxVolume: 10, MaxWeight: 20, Cost: 3}
+
+	tests := []struct {
+		name      string
+		packs     []PackResult
+		objective string
+		want      *CartonPlan
+	}{
+		{
+			name:      "fewest cartons",
+			packs:     []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 2}},
+			objective: CartonObjectiveCount,
+			want: &CartonPlan{
+				Cartons: []CartonAssignment{
+					{Carton: "large", Packs: []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 2}}, Volume: 10, Weight: 20, Cost: 3},
+				},
+				TotalCartons: 1,
+				TotalCost:    3,
+			},
+		},
+		{
+			name:      "cheapest cartons",
+			packs:     []PackResult{{Size: 500, Quantity: 1}, {Size: 1000, Quantity: 2}},
+			objective: CartonObjectiveCost,
+			want: &CartonPlan{
+				Cartons: []CartonAssignment{
+					{Carton: "small", Packs: []PackResult{{Size: 1000, Quantity: 1}}, Volume: 4, Weight: 8, Cost: 0.5},
+					{Carton: "small", Packs: []PackResult{{Size: 1000, Quantity: 1}}, Volume: 4, Weight: 8, Cost: 0.5},
+					{Carton: "small", Packs: []PackResult{{Size: 500, Quantity: 1}}, Volume: 2, Weight: 4, Cost: 0.5},
+				},
+				TotalCartons: 3,
+				TotalCost:    1.5,
+			},
+		},
+		{
+			name:      "shrinks partly filled cartons",
+			packs:     []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}},
+			objective: CartonObjectiveCount,
+			want: &CartonPlan{
+				Cartons: []CartonAssignment{
+					{Carton: "small", Packs: []PackResult{{Size: 250, Quantity: 1}, {Size: 500, Quantity: 1}}, Volume: 3, Weight: 6, Cost: 0.5},
+				},
+				TotalCartons: 1,
+				TotalCost:    0.5,
+			},
+		},
+		{
+			name:      "packs without specs are unassigned",
+			packs:     []PackResult{{Size: 250, Quantity: 1}, {Size: 5000, Quantity: 1}},
+			objective: CartonObjectiveCount,
+			want: &CartonPlan{
+				Cartons: []CartonAssignment{
+					{Carton: "small", Packs: []PackResult{{Size: 250, Quantity: 1}}, Volume: 1, Weight: 2, Cost: 0.5},
+				},
+				TotalCartons: 1,
+				TotalCost:    0.5,
+				Unassigned:   []PackResult{{Size: 5000, Quantity: 1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planCartons(tt.packs, specs, []pack_configurations.CartonType{small, large}, tt.objective)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_CalculateOptimalPacks(t *testing.T) {
+	logger := zap.NewNop()
+	mockCalcRepo := new(MockCalculationRepository)
+	mockPackRepo := new(MockPackConfigRepository)
+	s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+
+	tests := []struct {
+		name          string
+		orderQuantity int
+		packSizes     []int
+		want          map[int]int
+		wantErr       bool
+	}{
+		{
+			name:          "small order - fast path",
+			orderQuantity: 2,
+			packSizes:     []int{3, 5},
+			want:          map[int]int{3: 1},
+			wantErr:       false,
+		},
+		{
+			name:          "exact match",
+			orderQuantity: 10,
+			packSizes:     []int{3, 5},
+			want:          map[int]int{5: 2},
+			wantErr:       false,
+		},
+		{
+			name:          "needs multiple pack sizes",
+			orderQuantity: 8,
+			packSizes:     []int{3, 5},
+			want:          map[int]int{3: 1, 5: 1},
+			wantErr:       false,
+		},
+		{
+			name:          "zero order quantity",
+			orderQuantity: 0,
+			packSizes:     []int{3, 5},
+			want:          map[int]int{3: 1}, // Updated to match implementation
+			wantErr:       false,
+		},
+		{
+			name:          "large order",
+			orderQuantity: 28,
+			packSizes:     []int{3, 5, 10},
+			want:          map[int]int{3: 1, 5: 1, 10: 2},
+			wantErr:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CalculateOptimalPacks(context.Background(), tt.orderQuantity, tt.packSizes)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_OrderProcessing_TieBreak(t *testing.T) {
+	logger := zap.NewNop()
+	packCfg := &pack_configurations.PackConfiguration{ID: 1, PackSizes: pq.Int64Array{250, 500, 750, 1000, 1250}}
+	larger := TieBreakPolicy{Policy: TieBreakLargerPacks}
+	fewer := TieBreakPolicy{Policy: TieBreakFewerSizes}
+
+	tests := []struct {
+		name          string
+		defaultPolicy TieBreakPolicy
+		order         OrderRequest
+		wantPolicy    TieBreakPolicy
+		wantPacks     []PackResult
+	}{
+		{
+			name:          "default policy",
+			defaultPolicy: larger,
+			order:         OrderRequest{OrderQuantity: 2000},
+			wantPolicy:    larger,
+			wantPacks:     []PackResult{{Size: 750, Quantity: 1}, {Size: 1250, Quantity: 1}},
+		},
+		{
+			name:          "configured default policy",
+			defaultPolicy: fewer,
+			order:         OrderRequest{OrderQuantity: 2000},
+			wantPolicy:    fewer,
+			wantPacks:     []PackResult{{Size: 1000, Quantity: 2}},
+		},
+		{
+			name:          "request overrides the default policy",
+			defaultPolicy: larger,
+			order:         OrderRequest{OrderQuantity: 2000, TieBreak: &fewer},
+			wantPolicy:    fewer,
+			wantPacks:     []PackResult{{Size: 1000, Quantity: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+
+			// Results cached under one policy must not be served for another
+			signature := sizesSignature([]int{250, 500, 750, 1000, 1250}, nil, tt.wantPolicy)
+			mockCalcRepo.On("GetByConfigurationIDAndOrderQuantity", mock.Anything, 2000, uint(1), signature).Return(nil, nil)
+			mockCalcRepo.On("RecordCacheLookup", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockCalcRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+				return calc.SizesSignature == signature
+			})).Return(nil)
+
+			s := NewService(logger, mockCalcRepo, mockPackRepo, Deps{TieBreak: tt.defaultPolicy})
+			got, err := s.OrderProcessing(context.Background(), tt.order)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPacks, got.Result)
+			mockCalcRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSizesSignature_RulesIgnoreTieBreak tests that packings under rules, which do not
+// depend on the tie-break policy, share a signature across policies
+func TestSizesSignature_RulesIgnoreTieBreak(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	rules := []pack_configurations.Rule{{Text: "never mix 250 and 1000"}}
+	larger := TieBreakPolicy{Policy: TieBreakLargerPacks}
+	weighted := TieBreakPolicy{Policy: TieBreakWeighted, Weights: map[int]int{250: 1}}
+
+	assert.NotEqual(t, sizesSignature(sizes, nil, larger), sizesSignature(sizes, nil, weighted))
+	assert.Equal(t, sizesSignature(sizes, rules, larger), sizesSignature(sizes, rules, weighted))
+	assert.NotEqual(t, sizesSignature(sizes, nil, larger), sizesSignature(sizes, rules, larger))
+}
+
+func TestPlanSourcing(t *testing.T) {
+	packSizes := []int{250, 500, 1000}
+
+	tests := []struct {
+		name          string
+		orderQuantity int
+		stock         []warehouses.Warehouse
+		want          []WarehouseAllocation
+	}{
+		{
+			name:          "single warehouse preferred",
+			orderQuantity: 1250,
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+				{ID: 2, Name: "south", Inventory: map[int]int{250: 1, 1000: 1}},
+			},
+			want: []WarehouseAllocation{
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}}, TotalItems: 1250, TotalPacks: 2},
+			},
+		},
+		{
+			name:          "split when no warehouse can ship alone",
+			orderQuantity: 1250,
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+				{ID: 2, Name: "south", Inventory: map[int]int{250: 1}},
+			},
+			want: []WarehouseAllocation{
+				{WarehouseID: 1, Warehouse: "north", Packs: []PackResult{{Size: 1000, Quantity: 1}}, TotalItems: 1000, TotalPacks: 1},
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1},
+			},
+		},
+		{
+			name:          "less overfill beats a single warehouse",
+			orderQuantity: 1250,
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 2}},
+				{ID: 2, Name: "south", Inventory: map[int]int{250: 1}},
+			},
+			want: []WarehouseAllocation{
+				{WarehouseID: 1, Warehouse: "north", Packs: []PackResult{{Size: 1000, Quantity: 1}}, TotalItems: 1000, TotalPacks: 1},
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1},
+			},
+		},
+		{
+			name:          "fewest packs among single warehouses",
+			orderQuantity: 1000,
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{250: 4}},
+				{ID: 2, Name: "south", Inventory: map[int]int{500: 2}},
+			},
+			want: []WarehouseAllocation{
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 500, Quantity: 2}}, TotalItems: 1000, TotalPacks: 2},
+			},
+		},
+		{
+			name:          "stock cannot cover",
+			orderQuantity: 5000,
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planSourcing(tt.orderQuantity, packSizes, tt.stock))
+		})
+	}
+}
+
+// TestPlanSourcing_ManyWarehouses tests that sourcing from many warehouses stays fast once
+// the combinations exceed maxSourcingCombinations
+func TestPlanSourcing_ManyWarehouses(t *testing.T) {
+	packSizes := []int{250, 500, 1000}
+	var stock []warehouses.Warehouse
+	for i := 1; i <= 60; i++ {
+		stock = append(stock, warehouses.Warehouse{ID: uint(i), Name: fmt.Sprintf("w%d", i), Inventory: map[int]int{250: 1}})
+	}
+
+	t.Run("single warehouse found exhaustively", func(t *testing.T) {
+		withLarge := append(slices.Clone(stock), warehouses.Warehouse{ID: 61, Name: "large", Inventory: map[int]int{1000: 3}})
+
+		got := planSourcing(3000, packSizes, withLarge)
+
+		assert.Equal(t, []WarehouseAllocation{
+			{WarehouseID: 61, Warehouse: "large", Packs: []PackResult{{Size: 1000, Quantity: 3}}, TotalItems: 3000, TotalPacks: 3},
+		}, got)
+	})
+
+	t.Run("many warehouses chosen greedily", func(t *testing.T) {
+		withLarge := append(slices.Clone(stock), warehouses.Warehouse{ID: 61, Name: "large", Inventory: map[int]int{500: 2}})
+
+		done := make(chan []WarehouseAllocation)
+		go func() { done <- planSourcing(3000, packSizes, withLarge) }()
+
+		var got []WarehouseAllocation
+		select {
+		case got = <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("planning the sourcing from 61 warehouses did not finish")
+		}
+
+		require.Len(t, got, 9)
+		assert.Equal(t, uint(1), got[0].WarehouseID)
+		assert.Equal(t, uint(61), got[8].WarehouseID)
+		assert.Equal(t, []PackResult{{Size: 500, Quantity: 2}}, got[8].Packs)
+		total := 0
+		for _, allocation := range got {
+			total += allocation.TotalItems
+		}
+		assert.Equal(t, 3000, total)
+	})
+}
+
+func TestService_OrderProcessing_Sourcing(t *testing.T) {
+	packCfg := &pack_configurations.PackConfiguration{
+		ID:        1,
+		PackSizes: pq.Int64Array{250, 500, 1000},
+	}
+
+	tests := []struct {
+		name            string
+		order           OrderRequest
+		stock           []warehouses.Warehouse
+		active          []reservations.Reservation
+		wantPacks       []PackResult
+		wantSourcing    []WarehouseAllocation
+		wantReason      string
+		wantReservation []reservations.ReservationItem
+		wantErr         bool
+	}{
+		{
+			name:  "split over warehouses",
+			order: OrderRequest{OrderQuantity: 1100, Sourcing: true},
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+				{ID: 2, Name: "south", Inventory: map[int]int{250: 1}},
+			},
+			wantPacks: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}},
+			wantSourcing: []WarehouseAllocation{
+				{WarehouseID: 1, Warehouse: "north", Packs: []PackResult{{Size: 1000, Quantity: 1}}, TotalItems: 1000, TotalPacks: 1},
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1},
+			},
+			wantReason: "smallest achievable total covering the order quantity, 150 above it, sourced from 2 of 2 warehouses",
+		},
+		{
+			name:  "reserved stock is not available",
+			order: OrderRequest{OrderQuantity: 1000, Sourcing: true},
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+				{ID: 2, Name: "south", Inventory: map[int]int{500: 2}},
+			},
+			active: []reservations.Reservation{
+				{ID: 4, Status: reservations.StatusActive, Items: []reservations.ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 1}}},
+			},
+			wantPacks: []PackResult{{Size: 500, Quantity: 2}},
+			wantSourcing: []WarehouseAllocation{
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 500, Quantity: 2}}, TotalItems: 1000, TotalPacks: 2},
+			},
+			wantReason: "exact match for the order quantity, sourced from 1 of 2 warehouses",
+		},
+		{
+			name:  "reserve chosen packs",
+			order: OrderRequest{OrderQuantity: 1100, Sourcing: true, Reserve: true, ReservationTTL: time.Minute},
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+				{ID: 2, Name: "south", Inventory: map[int]int{250: 1}},
+			},
+			wantPacks: []PackResult{{Size: 250, Quantity: 1}, {Size: 1000, Quantity: 1}},
+			wantSourcing: []WarehouseAllocation{
+				{WarehouseID: 1, Warehouse: "north", Packs: []PackResult{{Size: 1000, Quantity: 1}}, TotalItems: 1000, TotalPacks: 1},
+				{WarehouseID: 2, Warehouse: "south", Packs: []PackResult{{Size: 250, Quantity: 1}}, TotalItems: 250, TotalPacks: 1},
+			},
+			wantReason: "smallest achievable total covering the order quantity, 150 above it, sourced from 2 of 2 warehouses",
+			wantReservation: []reservations.ReservationItem{
+				{WarehouseID: 1, Size: 1000, Quantity: 1},
+				{WarehouseID: 2, Size: 250, Quantity: 1},
+			},
+		},
+		{
+			name:  "stock cannot cover",
+			order: OrderRequest{OrderQuantity: 5000, Sourcing: true},
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name:  "reserved stock cannot cover",
+			order: OrderRequest{OrderQuantity: 1000, Sourcing: true, Reserve: true},
+			stock: []warehouses.Warehouse{
+				{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}},
+			},
+			active: []reservations.Reservation{
+				{ID: 4, Status: reservations.StatusActive, Items: []reservations.ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 1}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalcRepo := new(MockCalculationRepository)
+			mockPackRepo := new(MockPackConfigRepository)
+			mockWarehouseRepo := new(MockWarehouseRepository)
+			mockReservationRepo := new(MockReservationRepository)
+
+			mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+			// Reserved plans are saved in the transaction of the reservation
+			saveRepo := mockCalcRepo
+			if tt.order.Reserve {
+				saveRepo = new(MockCalculationRepository)
+				mockCalcRepo.On("WithTx", mock.Anything).Return(saveRepo).Maybe()
+				mockReservationRepo.On("Reserve", mock.Anything, mock.Anything).Return(tt.stock, tt.active, nil)
+			} else {
+				mockWarehouseRepo.On("List", mock.Anything).Return(tt.stock, nil)
+				mockReservationRepo.On("ListActive", mock.Anything, mock.Anything).Return(tt.active, nil)
+			}
+			if !tt.wantErr {
+				saveRepo.On("Save", mock.Anything, mock.MatchedBy(func(calc *OrderCalculation) bool {
+					return calc.Mode == CalculationModeSourcing
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*OrderCalculation).ID = 9
+				}).Return(nil)
+			}
+
+			s := NewService(zap.NewNop(), mockCalcRepo, mockPackRepo, Deps{Warehouses: mockWarehouseRepo, Reservations: mockReservationRepo, TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}, ReservationTTL: 15 * time.Minute})
+			before := time.Now()
+			calc, err := s.OrderProcessing(context.Background(), tt.order)
+
+			if tt.wantErr {
+				assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPacks, calc.Result)
+			assert.Equal(t, tt.wantSourcing, calc.Sourcing)
+			assert.Equal(t, tt.wantReason, calc.Reason)
+			assert.Equal(t, sizesSignature([]int{250, 500, 1000}, nil, TieBreakPolicy{Policy: TieBreakLargerPacks}), calc.SizesSignature)
+			if tt.wantReservation != nil {
+				assert.Equal(t, uint(9), calc.Reservation.CalculationID)
+				assert.Equal(t, reservations.StatusActive, calc.Reservation.Status)
+				assert.Equal(t, tt.wantReservation, calc.Reservation.Items)
+				assert.WithinDuration(t, before.Add(tt.order.ReservationTTL), calc.Reservation.ExpiresAt, time.Second)
+			} else {
+				assert.Nil(t, calc.Reservation)
+			}
+
+			mockCalcRepo.AssertExpectations(t)
+			saveRepo.AssertExpectations(t)
+			mockReservationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_OrderProcessing_SourcingReservationFails(t *testing.T) {
+	packCfg := &pack_configurations.PackConfiguration{ID: 1, PackSizes: pq.Int64Array{250, 500, 1000}}
+	stock := []warehouses.Warehouse{{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}}}
+	reserveErr := errors.New("audit insert failed")
+
+	mockCalcRepo := new(MockCalculationRepository)
+	txRepo := new(MockCalculationRepository)
+	mockPackRepo := new(MockPackConfigRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockPackRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+	mockCalcRepo.On("WithTx", mock.Anything).Return(txRepo)
+	txRepo.On("Save", mock.Anything, mock.AnythingOfType("*order_calculations.OrderCalculation")).Return(nil)
+	mockReservationRepo.On("Reserve", mock.Anything, mock.Anything).Return(stock, []reservations.Reservation{}, reserveErr)
+
+	s := NewService(zap.NewNop(), mockCalcRepo, mockPackRepo, Deps{Warehouses: new(MockWarehouseRepository), Reservations: mockReservationRepo, TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+	calc, err := s.OrderProcessing(context.Background(), OrderRequest{OrderQuantity: 1000, Sourcing: true, Reserve: true})
+
+	// The calculation was only saved in the transaction that the failed reservation rolls back
+	assert.ErrorIs(t, err, reserveErr)
+	assert.Nil(t, calc)
+	txRepo.AssertExpectations(t)
+	mockCalcRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestService_GetCalculation(t *testing.T) {
+	t.Run("existing calculation", func(t *testing.T) {
+		mockCalcRepo := new(MockCalculationRepository)
+		mockCalcRepo.On("GetByID", mock.Anything, uint(9)).Return(&OrderCalculation{ID: 9, OrderQuantity: 501}, nil)
+		s := NewService(zap.NewNop(), mockCalcRepo, new(MockPackConfigRepository), Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+
+		got, err := s.GetCalculation(context.Background(), 9)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &OrderCalculation{ID: 9, OrderQuantity: 501}, got)
+	})
+
+	t.Run("missing calculation", func(t *testing.T) {
+		mockCalcRepo := new(MockCalculationRepository)
+		mockCalcRepo.On("GetByID", mock.Anything, uint(9)).Return(nil, nil)
+		s := NewService(zap.NewNop(), mockCalcRepo, new(MockPackConfigRepository), Deps{TieBreak: TieBreakPolicy{Policy: TieBreakLargerPacks}})
+
+		_, err := s.GetCalculation(context.Background(), 9)
+
+		assert.ErrorIs(t, err, ErrCalculationNotFound)
+	})
+}
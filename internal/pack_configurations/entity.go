@@ -4,20 +4,69 @@ import (
 	"github.com/lib/pq"
 )
 
-// PackConfiguration represents a pack configuration entity in the database
+// PackConfiguration represents a pack configuration entity in the database. Each tenant
+// has its own configurations, one of which is active.
 type PackConfiguration struct {
 	ID        uint          `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID  string        `gorm:"column:tenant_id;not null;uniqueIndex:idx_pack_configurations_tenant_signature" json:"tenantId"`
 	PackSizes pq.Int64Array `gorm:"column:pack_sizes;type:int[];not null" json:"packSizes"`
-	Signature string        `gorm:"column:signature;uniqueIndex" json:"signature"`
+	Signature string        `gorm:"column:signature;uniqueIndex:idx_pack_configurations_tenant_signature" json:"signature"`
 	Active    bool          `gorm:"column:active;default:false" json:"active"`
+	Nesting   []NestingRule `gorm:"column:nesting;serializer:json" json:"nesting,omitempty"`
+	PackSpecs []PackSpec    `gorm:"column:pack_specs;serializer:json" json:"packSpecs,omitempty"`
+	Cartons   []CartonType  `gorm:"column:cartons;serializer:json" json:"cartons,omitempty"`
+	Rules     []string      `gorm:"column:rules;serializer:json" json:"rules,omitempty"`
 }
 
+// NestingRule describes a logistics unit that holds a number of smaller units,
+// for example a case that holds 4 packs of 1000 or a pallet that holds 10 cases
+type NestingRule struct {
+	Unit     string `json:"unit"`
+	Contains string `json:"contains"`
+	Size     int    `json:"size,omitempty"`
+	Capacity int    `json:"capacity"`
+}
+
+// PackSpec holds the physical volume and weight of one pack of a given size
+type PackSpec struct {
+	Size   int     `json:"size"`
+	Volume float64 `json:"volume"`
+	Weight float64 `json:"weight"`
+}
+
+// CartonType is a shipping carton packs can be placed in. A zero MaxVolume
+// or MaxWeight leaves that dimension unlimited.
+type CartonType struct {
+	Name      string  `json:"name"`
+	MaxVolume float64 `json:"maxVolume,omitempty"`
+	MaxWeight float64 `json:"maxWeight,omitempty"`
+	Cost      float64 `json:"cost,omitempty"`
+}
+
+// NestingUnitPack is the unit name of a single pack in nesting rules
+const NestingUnitPack = "pack"
+
 // PackCfgAPIRequest represents an API request to update pack sizes
 type PackCfgAPIRequest struct {
-	PackSizes []int `json:"packSizes"`
+	PackSizes []int         `json:"packSizes"`
+	Nesting   []NestingRule `json:"nesting,omitempty"`
+	PackSpecs []PackSpec    `json:"packSpecs,omitempty"`
+	Cartons   []CartonType  `json:"cartons,omitempty"`
+	Rules     []string      `json:"rules,omitempty"`
 }
 
 // PackCfgAPIResponse represents an API response for getting pack sizes
 type PackCfgAPIResponse struct {
-	PackSizes []int `json:"packSizes"`
+	ID        uint          `json:"id,omitempty"`
+	Active    bool          `json:"active,omitempty"`
+	PackSizes []int         `json:"packSizes"`
+	Nesting   []NestingRule `json:"nesting,omitempty"`
+	PackSpecs []PackSpec    `json:"packSpecs,omitempty"`
+	Cartons   []CartonType  `json:"cartons,omitempty"`
+	Rules     []string      `json:"rules,omitempty"`
+}
+
+// PackCfgsAPIResponse represents an API response listing pack configurations
+type PackCfgsAPIResponse struct {
+	Configurations []PackCfgAPIResponse `json:"configurations"`
 }
@@ -1,7 +1,9 @@
 package pack_configurations
 
 import (
+	stderrors "errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -26,42 +28,116 @@ func NewHandler(logger *zap.Logger, service Service) *Handler {
 func (h *Handler) GetActivePackConfiguration(c *gin.Context) {
 	packCfg, err := h.service.GetActive(c.Request.Context())
 	if err != nil {
-		errMsg := "Failed to retrieve pack configuration"
-		h.logger.Error(errMsg, zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.NewInternalErrorWrap(errMsg, err))
+		if stderrors.Is(err, ErrConfigurationNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("No active pack configuration", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to retrieve pack configuration", err))
 		return
 	}
 
-	response := PackCfgAPIResponse{
-		PackSizes: postgres.Int64ArrayToIntSlice(packCfg.PackSizes),
-	}
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, toAPIResponse(packCfg))
 }
 
 func (h *Handler) CreatePackConfiguration(c *gin.Context) {
 	payload, exists := c.Get("payload")
 	if !exists {
-		errMsg := "Failed to retrieve payload from context"
-		h.logger.Error(errMsg)
-		c.JSON(http.StatusInternalServerError, errors.NewInternalError(errMsg))
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
 		return
 	}
 
 	packCfg := payload.(*PackCfgAPIRequest)
 	newPackConfiguration := &PackConfiguration{
 		PackSizes: postgres.IntSliceToPqArray(packCfg.PackSizes),
+		Nesting:   packCfg.Nesting,
+		PackSpecs: packCfg.PackSpecs,
+		Cartons:   packCfg.Cartons,
+		Rules:     packCfg.Rules,
 	}
 
-	err := h.service.Create(c.Request.Context(), newPackConfiguration)
+	_, err := h.service.Create(c.Request.Context(), newPackConfiguration)
 	if err != nil {
-		errMsg := "Failed to create pack configuration"
-		h.logger.Error(errMsg, zap.Error(err))
-		c.JSON(http.StatusInternalServerError, errors.NewInternalErrorWrap(errMsg, err))
+		c.Error(errors.Wrap("Failed to create pack configuration", err))
 		return
 	}
 
 	response := PackCfgAPIResponse{
 		PackSizes: packCfg.PackSizes,
+		Nesting:   packCfg.Nesting,
+		PackSpecs: packCfg.PackSpecs,
+		Cartons:   packCfg.Cartons,
+		Rules:     packCfg.Rules,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListPackConfigurations returns all pack configurations, marking the active one
+func (h *Handler) ListPackConfigurations(c *gin.Context) {
+	configs, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.Error(errors.Wrap("Failed to list pack configurations", err))
+		return
+	}
+
+	response := PackCfgsAPIResponse{Configurations: make([]PackCfgAPIResponse, 0, len(configs))}
+	for i := range configs {
+		response.Configurations = append(response.Configurations, toAPIResponse(&configs[i]))
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// GetPackConfiguration returns a pack configuration by ID
+func (h *Handler) GetPackConfiguration(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid pack configuration ID", err))
+		return
+	}
+
+	packCfg, err := h.service.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if stderrors.Is(err, ErrConfigurationNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("Pack configuration not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to retrieve pack configuration", err))
+		return
+	}
+	c.JSON(http.StatusOK, toAPIResponse(packCfg))
+}
+
+// ActivatePackConfiguration makes an existing pack configuration the active one
+func (h *Handler) ActivatePackConfiguration(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid pack configuration ID", err))
+		return
+	}
+
+	packCfg, err := h.service.Activate(c.Request.Context(), uint(id))
+	if err != nil {
+		if stderrors.Is(err, ErrConfigurationNotFound) {
+			c.Error(errors.NewNotFoundErrorWrap("Pack configuration not found", err))
+			return
+		}
+		c.Error(errors.Wrap("Failed to activate pack configuration", err))
+		return
+	}
+	c.JSON(http.StatusOK, toAPIResponse(packCfg))
+}
+
+// toAPIResponse converts a pack configuration into its API representation
+func toAPIResponse(packCfg *PackConfiguration) PackCfgAPIResponse {
+	if packCfg == nil {
+		return PackCfgAPIResponse{}
+	}
+	return PackCfgAPIResponse{
+		ID:        packCfg.ID,
+		Active:    packCfg.Active,
+		PackSizes: postgres.Int64ArrayToIntSlice(packCfg.PackSizes),
+		Nesting:   packCfg.Nesting,
+		PackSpecs: packCfg.PackSpecs,
+		Cartons:   packCfg.Cartons,
+		Rules:     packCfg.Rules,
+	}
+}
@@ -21,9 +21,12 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) Create(ctx context.Context, config *PackConfiguration) error {
+func (m *MockService) Create(ctx context.Context, config *PackConfiguration) (*PackConfiguration, error) {
 	args := m.Called(ctx, config)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PackConfiguration), args.Error(1)
 }
 
 func (m *MockService) GetActive(ctx context.Context) (*PackConfiguration, error) {
@@ -34,6 +37,30 @@ func (m *MockService) GetActive(ctx context.Context) (*PackConfiguration, error)
 	return args.Get(0).(*PackConfiguration), args.Error(1)
 }
 
+func (m *MockService) List(ctx context.Context) ([]PackConfiguration, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]PackConfiguration), args.Error(1)
+}
+
+func (m *MockService) Get(ctx context.Context, id uint) (*PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PackConfiguration), args.Error(1)
+}
+
+func (m *MockService) Activate(ctx context.Context, id uint) (*PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PackConfiguration), args.Error(1)
+}
+
 // ErrorResponse matches the JSON error response structure
 type ErrorResponse struct {
 	Type    string      `json:"Type"`
@@ -64,6 +91,20 @@ func TestHandler_GetActivePackConfiguration(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "tenant without a configuration",
+			mockSetup: func(m *MockService) {
+				m.On("GetActive", mock.Anything).Return(nil, ErrConfigurationNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeNotFound),
+					Message: "No active pack configuration",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
 		{
 			name: "service error",
 			mockSetup: func(m *MockService) {
@@ -95,6 +136,7 @@ func TestHandler_GetActivePackConfiguration(t *testing.T) {
 			logger := zap.NewNop()
 			handler := NewHandler(logger, mockService)
 			handler.GetActivePackConfiguration(c)
+			renderErrors(c)
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
 
@@ -138,7 +180,7 @@ func TestHandler_CreatePackConfiguration(t *testing.T) {
 				m.On("Create", mock.Anything, mock.MatchedBy(func(cfg *PackConfiguration) bool {
 					sizes := []int64(cfg.PackSizes)
 					return len(sizes) == 3 && sizes[0] == 250 && sizes[1] == 500 && sizes[2] == 1000
-				})).Return(nil)
+				})).Return(&PackConfiguration{ID: 1, PackSizes: pq.Int64Array{250, 500, 1000}, Active: true}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantBody: func() interface{} {
@@ -172,7 +214,7 @@ func TestHandler_CreatePackConfiguration(t *testing.T) {
 				})
 			},
 			mockSetup: func(m *MockService) {
-				m.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
 			},
 			wantStatusCode: http.StatusInternalServerError,
 			wantBody: func() interface{} {
@@ -203,6 +245,7 @@ func TestHandler_CreatePackConfiguration(t *testing.T) {
 			logger := zap.NewNop()
 			handler := NewHandler(logger, mockService)
 			handler.CreatePackConfiguration(c)
+			renderErrors(c)
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
 
@@ -224,3 +267,207 @@ func TestHandler_CreatePackConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ListPackConfigurations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantResponse   *PackCfgsAPIResponse
+	}{
+		{
+			name: "success case",
+			mockSetup: func(m *MockService) {
+				m.On("List", mock.Anything).Return([]PackConfiguration{
+					{ID: 1, PackSizes: pq.Int64Array{250, 500}},
+					{ID: 2, PackSizes: pq.Int64Array{250, 500, 1000}, Active: true, Rules: []string{"at most 2 x 250"}},
+				}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &PackCfgsAPIResponse{Configurations: []PackCfgAPIResponse{
+				{ID: 1, PackSizes: []int{250, 500}},
+				{ID: 2, Active: true, PackSizes: []int{250, 500, 1000}, Rules: []string{"at most 2 x 250"}},
+			}},
+		},
+		{
+			name: "no configurations",
+			mockSetup: func(m *MockService) {
+				m.On("List", mock.Anything).Return([]PackConfiguration{}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &PackCfgsAPIResponse{Configurations: []PackCfgAPIResponse{}},
+		},
+		{
+			name: "service error",
+			mockSetup: func(m *MockService) {
+				m.On("List", mock.Anything).Return(nil, errors.New("db error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/packs/configurations", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).ListPackConfigurations(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantResponse != nil {
+				var got PackCfgsAPIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantResponse, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetPackConfiguration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantResponse   *PackCfgAPIResponse
+	}{
+		{
+			name: "success case",
+			id:   "2",
+			mockSetup: func(m *MockService) {
+				m.On("Get", mock.Anything, uint(2)).Return(&PackConfiguration{ID: 2, PackSizes: pq.Int64Array{250, 500}}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &PackCfgAPIResponse{ID: 2, PackSizes: []int{250, 500}},
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "2",
+			mockSetup: func(m *MockService) {
+				m.On("Get", mock.Anything, uint(2)).Return(nil, ErrConfigurationNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "2",
+			mockSetup: func(m *MockService) {
+				m.On("Get", mock.Anything, uint(2)).Return(nil, errors.New("db error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/packs/configurations/"+tt.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).GetPackConfiguration(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantResponse != nil {
+				var got PackCfgAPIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantResponse, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ActivatePackConfiguration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantResponse   *PackCfgAPIResponse
+	}{
+		{
+			name: "success case",
+			id:   "1",
+			mockSetup: func(m *MockService) {
+				m.On("Activate", mock.Anything, uint(1)).Return(&PackConfiguration{ID: 1, PackSizes: pq.Int64Array{250, 500}, Active: true}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   &PackCfgAPIResponse{ID: 1, Active: true, PackSizes: []int{250, 500}},
+		},
+		{
+			name:           "invalid id",
+			id:             "-1",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "1",
+			mockSetup: func(m *MockService) {
+				m.On("Activate", mock.Anything, uint(1)).Return(nil, ErrConfigurationNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "1",
+			mockSetup: func(m *MockService) {
+				m.On("Activate", mock.Anything, uint(1)).Return(nil, errors.New("db error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/packs/configurations/"+tt.id+"/activate", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).ActivatePackConfiguration(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantResponse != nil {
+				var got PackCfgAPIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, *tt.wantResponse, got)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
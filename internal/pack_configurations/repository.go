@@ -3,11 +3,17 @@ package pack_configurations
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
 )
 
-// Repository defines the interface for pack configuration persistence operations
+// Repository defines the interface for pack configuration persistence operations. Every
+// operation acts on the configurations of the tenant of the context.
 type Repository interface {
 	Create(ctx context.Context, config *PackConfiguration) (*PackConfiguration, error)
 	GetByID(ctx context.Context, id uint) (*PackConfiguration, error)
@@ -28,75 +34,122 @@ func NewRepository(db *gorm.DB) Repository {
 	return &gormRepository{db: db}
 }
 
+// scoped returns a session restricted to the configurations of the tenant of ctx
+func (r *gormRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Scopes(tenant.Scope(ctx))
+}
+
+// Create stores the configuration for the tenant of ctx and records its creation in the
+// audit trail
 func (r *gormRepository) Create(ctx context.Context, config *PackConfiguration) (*PackConfiguration, error) {
-	err := r.db.WithContext(ctx).Create(config).Error
-	return config, err
+	config.TenantID = tenant.FromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(config).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionPackConfigurationCreate,
+			ResourceType: audit_events.ResourcePackConfiguration,
+			ResourceID:   strconv.FormatUint(uint64(config.ID), 10),
+			After:        config,
+		})
+	})
+	return config, postgres.ClassifyError(err)
 }
 
 func (r *gormRepository) GetByID(ctx context.Context, id uint) (*PackConfiguration, error) {
 	var config PackConfiguration
-	err := r.db.WithContext(ctx).First(&config, id).Error
+	err := r.scoped(ctx).First(&config, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return &config, nil
 }
 
 func (r *gormRepository) GetBySignature(ctx context.Context, signature string) (*PackConfiguration, error) {
 	var config PackConfiguration
-	err := r.db.WithContext(ctx).Where("signature = ?", signature).First(&config).Error
+	err := r.scoped(ctx).Where("signature = ?", signature).First(&config).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return &config, nil
 }
 
 func (r *gormRepository) GetActive(ctx context.Context) (*PackConfiguration, error) {
 	var config PackConfiguration
-	err := r.db.WithContext(ctx).Where("active = ?", true).First(&config).Error
+	err := r.scoped(ctx).Where("active = ?", true).First(&config).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return &config, nil
 }
 
+// activation is the audited state of an activation: the configuration that is active
+type activation struct {
+	ActiveConfigurationID *uint `json:"activeConfigurationId"`
+}
+
+// SetActive makes the configuration the only active one of its tenant and records the
+// activation in the audit trail
 func (r *gormRepository) SetActive(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scoped := func() *gorm.DB { return tx.Scopes(tenant.Scope(ctx)).Model(&PackConfiguration{}) }
+
+		// Remember the active configuration for the audit trail
+		var previous []uint
+		if err := scoped().Where("active = ?", true).Pluck("id", &previous).Error; err != nil {
+			return err
+		}
+
 		// Deactivate current active configuration if exists
-		if err := tx.Model(&PackConfiguration{}).Where("active = ?", true).Update("active", false).Error; err != nil {
+		if err := scoped().Where("active = ?", true).Update("active", false).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Model(&PackConfiguration{}).Where("id = ?", id).Update("active", true).Error; err != nil {
+		if err := scoped().Where("id = ?", id).Update("active", true).Error; err != nil {
 			return err
 		}
 
-		return nil
+		var before activation
+		if len(previous) > 0 {
+			before.ActiveConfigurationID = &previous[0]
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionPackConfigurationActivate,
+			ResourceType: audit_events.ResourcePackConfiguration,
+			ResourceID:   strconv.FormatUint(uint64(id), 10),
+			Before:       before,
+			After:        activation{ActiveConfigurationID: &id},
+		})
 	})
+	return postgres.ClassifyError(err)
 }
 
+// Update saves every field of the configuration. Unlike Save, it never inserts, so a
+// configuration of another tenant is left unchanged.
 func (r *gormRepository) Update(ctx context.Context, config *PackConfiguration) error {
-	return r.db.WithContext(ctx).Save(config).Error
+	config.TenantID = tenant.FromContext(ctx)
+	return postgres.ClassifyError(r.scoped(ctx).Model(config).Select("*").Omit("id").Updates(config).Error)
 }
 
 func (r *gormRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&PackConfiguration{}, id).Error
+	return postgres.ClassifyError(r.scoped(ctx).Delete(&PackConfiguration{}, id).Error)
 }
 
 func (r *gormRepository) List(ctx context.Context) ([]PackConfiguration, error) {
 	var configs []PackConfiguration
-	err := r.db.WithContext(ctx).Find(&configs).Error
+	err := r.scoped(ctx).Find(&configs).Error
 	if err != nil {
-		return nil, err
+		return nil, postgres.ClassifyError(err)
 	}
 	return configs, nil
 }
@@ -3,6 +3,7 @@ package pack_configurations
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"regexp"
 	"testing"
@@ -13,6 +14,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/tenant"
 )
 
 func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
@@ -35,6 +39,17 @@ func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
 	return db, mock, sqlDB
 }
 
+// expectAuditEvent expects an event to be appended to the audit trail with the states
+func expectAuditEvent(mock sqlmock.Sqlmock, action, resourceID string, before, after driver.Value) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+		WithArgs(sqlmock.AnyArg(), action, audit_events.ResourcePackConfiguration, resourceID, "anonymous", "", "", before, after, sqlmock.AnyArg(), "previous", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
 // TestCreate tests the Create method
 func TestCreate(t *testing.T) {
 	t.Run("successful creation", func(t *testing.T) {
@@ -55,10 +70,13 @@ func TestCreate(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect INSERT query with returning ID
-		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pack_configurations" ("pack_sizes","signature","active") VALUES ($1,$2,$3) RETURNING "id"`)).
-			WithArgs(config.PackSizes, config.Signature, config.Active).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pack_configurations" ("tenant_id","pack_sizes","signature","active","nesting","pack_specs","cartons","rules") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)).
+			WithArgs(tenant.Default, config.PackSizes, config.Signature, config.Active, nil, nil, nil, nil).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
+		// Expect the creation to be recorded
+		expectAuditEvent(mock, audit_events.ActionPackConfigurationCreate, "1", nil, sqlmock.AnyArg())
+
 		// Expect the COMMIT
 		mock.ExpectCommit()
 
@@ -90,8 +108,8 @@ func TestCreate(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect INSERT query with an error
-		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pack_configurations" ("pack_sizes","signature","active") VALUES ($1,$2,$3) RETURNING "id"`)).
-			WithArgs(config.PackSizes, config.Signature, config.Active).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pack_configurations" ("tenant_id","pack_sizes","signature","active","nesting","pack_specs","cartons","rules") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)).
+			WithArgs(tenant.Default, config.PackSizes, config.Signature, config.Active, nil, nil, nil, nil).
 			WillReturnError(errors.New("database error"))
 
 		// Expect the ROLLBACK
@@ -120,8 +138,8 @@ func TestGetByID(t *testing.T) {
 		limit := uint(1)
 
 		// Expect SELECT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(id, limit).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(id, tenant.Default, limit).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "pack_sizes", "signature", "active"}).
 				AddRow(1, pq.Int64Array{1, 2, 3}, "test-signature", false))
 
@@ -146,8 +164,8 @@ func TestGetByID(t *testing.T) {
 		id := uint(999)
 
 		// Expect SELECT query that returns not found
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(id, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(id, tenant.Default, 1).
 			WillReturnError(gorm.ErrRecordNotFound)
 
 		// Execute
@@ -169,8 +187,8 @@ func TestGetByID(t *testing.T) {
 		id := uint(1)
 
 		// Expect SELECT query that returns an error
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(id, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(id, tenant.Default, 1).
 			WillReturnError(errors.New("database error"))
 
 		// Execute
@@ -195,8 +213,8 @@ func TestGetBySignature(t *testing.T) {
 		signature := "test-signature"
 
 		// Expect SELECT query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE signature = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(signature, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE signature = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(signature, tenant.Default, 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "pack_sizes", "signature", "active"}).
 				AddRow(1, pq.Int64Array{1, 2, 3}, signature, false))
 
@@ -222,8 +240,8 @@ func TestGetBySignature(t *testing.T) {
 		signature := "non-existent-signature"
 
 		// Expect SELECT query that returns not found
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE signature = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(signature, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE signature = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(signature, tenant.Default, 1).
 			WillReturnError(gorm.ErrRecordNotFound)
 
 		// Execute
@@ -245,8 +263,8 @@ func TestGetBySignature(t *testing.T) {
 		signature := "test-signature"
 
 		// Expect SELECT query that returns an error
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE signature = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(signature, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE signature = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(signature, tenant.Default, 1).
 			WillReturnError(errors.New("database error"))
 
 		// Execute
@@ -270,8 +288,8 @@ func TestGetActive(t *testing.T) {
 		ctx := context.Background()
 
 		// Expect SELECT query for active config
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(true, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(true, tenant.Default, 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "pack_sizes", "signature", "active"}).
 				AddRow(1, pq.Int64Array{1, 2, 3}, "active-signature", true))
 
@@ -296,8 +314,8 @@ func TestGetActive(t *testing.T) {
 		ctx := context.Background()
 
 		// Expect SELECT query that returns no active config
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(true, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(true, tenant.Default, 1).
 			WillReturnError(gorm.ErrRecordNotFound)
 
 		// Execute
@@ -318,8 +336,8 @@ func TestGetActive(t *testing.T) {
 		ctx := context.Background()
 
 		// Expect SELECT query that returns an error
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 ORDER BY "pack_configurations"."id" LIMIT $2`)).
-			WithArgs(true, 1).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(true, tenant.Default, 1).
 			WillReturnError(errors.New("database error"))
 
 		// Execute
@@ -353,8 +371,8 @@ func TestUpdate(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect UPDATE query
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "pack_sizes"=$1,"signature"=$2,"active"=$3 WHERE "id" = $4`)).
-			WithArgs(config.PackSizes, config.Signature, config.Active, config.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "tenant_id"=$1,"pack_sizes"=$2,"signature"=$3,"active"=$4,"nesting"=$5,"pack_specs"=$6,"cartons"=$7,"rules"=$8 WHERE tenant_id = $9 AND "id" = $10`)).
+			WithArgs(tenant.Default, config.PackSizes, config.Signature, config.Active, nil, nil, nil, nil, tenant.Default, config.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		// Expect the COMMIT
@@ -387,8 +405,8 @@ func TestUpdate(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect UPDATE query with error
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "pack_sizes"=$1,"signature"=$2,"active"=$3 WHERE "id" = $4`)).
-			WithArgs(config.PackSizes, config.Signature, config.Active, config.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "tenant_id"=$1,"pack_sizes"=$2,"signature"=$3,"active"=$4,"nesting"=$5,"pack_specs"=$6,"cartons"=$7,"rules"=$8 WHERE tenant_id = $9 AND "id" = $10`)).
+			WithArgs(tenant.Default, config.PackSizes, config.Signature, config.Active, nil, nil, nil, nil, tenant.Default, config.ID).
 			WillReturnError(errors.New("database error"))
 
 		// Expect the ROLLBACK
@@ -418,8 +436,8 @@ func TestDelete(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect DELETE query
-		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pack_configurations" WHERE "pack_configurations"."id" = $1`)).
-			WithArgs(id).
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 AND tenant_id = $2`)).
+			WithArgs(id, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		// Expect the COMMIT
@@ -446,8 +464,8 @@ func TestDelete(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Expect DELETE query with error
-		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pack_configurations" WHERE "pack_configurations"."id" = $1`)).
-			WithArgs(id).
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 AND tenant_id = $2`)).
+			WithArgs(id, tenant.Default).
 			WillReturnError(errors.New("database error"))
 
 		// Expect the ROLLBACK
@@ -548,16 +566,24 @@ func TestSetActive(t *testing.T) {
 		// Expect begin transaction
 		mock.ExpectBegin()
 
+		// Expect the active configuration to be read for the audit trail
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2`)).
+			WithArgs(true, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
 		// Expect update to deactivate current active
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2`)).
-			WithArgs(false, true).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2 AND tenant_id = $3`)).
+			WithArgs(false, true, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		// Expect update to activate new one
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2`)).
-			WithArgs(true, id).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2 AND tenant_id = $3`)).
+			WithArgs(true, id, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
+		// Expect the activation to be recorded
+		expectAuditEvent(mock, audit_events.ActionPackConfigurationActivate, "1", `{"activeConfigurationId":2}`, `{"activeConfigurationId":1}`)
+
 		// Expect commit
 		mock.ExpectCommit()
 
@@ -581,16 +607,24 @@ func TestSetActive(t *testing.T) {
 		// Expect begin transaction
 		mock.ExpectBegin()
 
+		// Expect the active configuration to be read for the audit trail
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2`)).
+			WithArgs(true, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
 		// Expect update to deactivate (returns 0 rows affected)
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2`)).
-			WithArgs(false, true).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2 AND tenant_id = $3`)).
+			WithArgs(false, true, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
 		// Expect update to activate
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2`)).
-			WithArgs(true, id).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2 AND tenant_id = $3`)).
+			WithArgs(true, id, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
+		// Expect the activation to be recorded
+		expectAuditEvent(mock, audit_events.ActionPackConfigurationActivate, "1", `{"activeConfigurationId":null}`, `{"activeConfigurationId":1}`)
+
 		// Expect commit
 		mock.ExpectCommit()
 
@@ -614,9 +648,14 @@ func TestSetActive(t *testing.T) {
 		// Expect begin transaction
 		mock.ExpectBegin()
 
+		// Expect the active configuration to be read for the audit trail
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2`)).
+			WithArgs(true, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
 		// Expect update with error
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2`)).
-			WithArgs(false, true).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2 AND tenant_id = $3`)).
+			WithArgs(false, true, tenant.Default).
 			WillReturnError(errors.New("database error"))
 
 		// Expect rollback
@@ -642,14 +681,19 @@ func TestSetActive(t *testing.T) {
 		// Expect begin transaction
 		mock.ExpectBegin()
 
+		// Expect the active configuration to be read for the audit trail
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2`)).
+			WithArgs(true, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
 		// Expect first update success
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2`)).
-			WithArgs(false, true).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2 AND tenant_id = $3`)).
+			WithArgs(false, true, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		// Expect second update with error
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2`)).
-			WithArgs(true, id).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2 AND tenant_id = $3`)).
+			WithArgs(true, id, tenant.Default).
 			WillReturnError(errors.New("database error"))
 
 		// Expect rollback
@@ -675,16 +719,24 @@ func TestSetActive(t *testing.T) {
 		// Expect begin transaction
 		mock.ExpectBegin()
 
+		// Expect the active configuration to be read for the audit trail
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2`)).
+			WithArgs(true, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
 		// Expect update to deactivate
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2`)).
-			WithArgs(false, true).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2 AND tenant_id = $3`)).
+			WithArgs(false, true, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		// Expect update to activate non-existent returns 0 rows
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2`)).
-			WithArgs(true, id).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2 AND tenant_id = $3`)).
+			WithArgs(true, id, tenant.Default).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
+		// Expect the activation to be recorded
+		expectAuditEvent(mock, audit_events.ActionPackConfigurationActivate, "999", `{"activeConfigurationId":1}`, `{"activeConfigurationId":999}`)
+
 		// Expect commit
 		mock.ExpectCommit()
 
@@ -696,3 +748,99 @@ func TestSetActive(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+// TestTenantIsolation tests that a tenant only reads and changes its own configurations
+func TestTenantIsolation(t *testing.T) {
+	retail := tenant.NewContext(context.Background(), "retail")
+	columns := []string{"id", "tenant_id", "pack_sizes", "signature", "active"}
+
+	t.Run("configuration of another tenant is not found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		// Configuration 1 belongs to the default tenant, so no row matches the retail tenant
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE "pack_configurations"."id" = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(1, "retail", 1).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		config, err := repo.GetByID(retail, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, config)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("each tenant has its own active configuration", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(true, "retail", 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(4, "retail", pq.Int64Array{6, 12}, "retail-signature", true))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2 ORDER BY "pack_configurations"."id" LIMIT $3`)).
+			WithArgs(true, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, tenant.Default, pq.Int64Array{250, 500}, "default-signature", true))
+
+		retailConfig, err := repo.GetActive(retail)
+		assert.NoError(t, err)
+		defaultConfig, err := repo.GetActive(context.Background())
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint(4), retailConfig.ID)
+		assert.Equal(t, "retail", retailConfig.TenantID)
+		assert.Equal(t, uint(1), defaultConfig.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("activation leaves other tenants unchanged", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "pack_configurations" WHERE active = $1 AND tenant_id = $2`)).
+			WithArgs(true, "retail").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE active = $2 AND tenant_id = $3`)).
+			WithArgs(false, true, "retail").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// Configuration 1 of the default tenant is not activated for the retail tenant
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pack_configurations" SET "active"=$1 WHERE id = $2 AND tenant_id = $3`)).
+			WithArgs(true, 1, "retail").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		expectAuditEvent(mock, audit_events.ActionPackConfigurationActivate, "1", `{"activeConfigurationId":null}`, `{"activeConfigurationId":1}`)
+		mock.ExpectCommit()
+
+		err := repo.SetActive(retail, 1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("configurations are created for the tenant of the request", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		config := &PackConfiguration{TenantID: tenant.Default, PackSizes: pq.Int64Array{6, 12}, Signature: "retail-signature"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pack_configurations"`)).
+			WithArgs("retail", config.PackSizes, config.Signature, false, nil, nil, nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		expectAuditEvent(mock, audit_events.ActionPackConfigurationCreate, "4", nil, sqlmock.AnyArg())
+		mock.ExpectCommit()
+
+		created, err := repo.Create(retail, config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "retail", created.TenantID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,225 @@
+package pack_configurations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pack-calculator/pkg/packsolver"
+)
+
+// MaxRuleCount bounds the pack count of an at most or at least rule, so the items a rule
+// requires always fit in an int
+const MaxRuleCount = 1_000_000
+
+// Rule kinds of the pack selection rule language
+const (
+	RuleAtMost   = "at most"
+	RuleAtLeast  = "at least"
+	RuleNeverMix = "never mix"
+)
+
+// Rule is a parsed pack selection rule. Rules are written as one of
+//
+//	at most <count> x <size>
+//	at least <count> x <size>
+//	never mix <size> and <size>
+//
+// optionally followed by a condition on the order quantity such as
+// "when quantity > 20000", using one of the operators >, >=, < and <=.
+// "<count> packs of <size>" may be written instead of "<count> x <size>".
+type Rule struct {
+	Text      string
+	Kind      string
+	Count     int
+	Size      int
+	OtherSize int
+	Condition *QuantityCondition
+}
+
+// QuantityCondition limits a rule to orders whose quantity compares to Quantity
+type QuantityCondition struct {
+	Operator string
+	Quantity int
+}
+
+// Matches reports whether the order quantity satisfies the condition
+func (c QuantityCondition) Matches(orderQuantity int) bool {
+	switch c.Operator {
+	case ">":
+		return orderQuantity > c.Quantity
+	case ">=":
+		return orderQuantity >= c.Quantity
+	case "<":
+		return orderQuantity < c.Quantity
+	default:
+		return orderQuantity <= c.Quantity
+	}
+}
+
+// Applies reports whether the rule applies to an order of the given quantity
+func (r Rule) Applies(orderQuantity int) bool {
+	return r.Condition == nil || r.Condition.Matches(orderQuantity)
+}
+
+// Sizes returns the pack sizes the rule refers to
+func (r Rule) Sizes() []int {
+	if r.Kind == RuleNeverMix {
+		return []int{r.Size, r.OtherSize}
+	}
+	return []int{r.Size}
+}
+
+// ParseRules parses the rules of a pack configuration
+func ParseRules(texts []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(texts))
+	for _, text := range texts {
+		rule, err := ParseRule(text)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ActiveRules returns the rules that apply to an order of the given quantity
+func ActiveRules(rules []Rule, orderQuantity int) []Rule {
+	var active []Rule
+	for _, rule := range rules {
+		if rule.Applies(orderQuantity) {
+			active = append(active, rule)
+		}
+	}
+	return active
+}
+
+// Constraints converts rules into solver constraints, keeping the strictest count per size
+func Constraints(rules []Rule) packsolver.Constraints {
+	var constraints packsolver.Constraints
+	for _, rule := range rules {
+		switch rule.Kind {
+		case RuleAtLeast:
+			if constraints.AtLeast == nil {
+				constraints.AtLeast = make(map[int]int)
+			}
+			constraints.AtLeast[rule.Size] = max(constraints.AtLeast[rule.Size], rule.Count)
+		case RuleAtMost:
+			if constraints.AtMost == nil {
+				constraints.AtMost = make(map[int]int)
+			}
+			if limit, ok := constraints.AtMost[rule.Size]; !ok || rule.Count < limit {
+				constraints.AtMost[rule.Size] = rule.Count
+			}
+		case RuleNeverMix:
+			constraints.NeverMix = append(constraints.NeverMix, [2]int{rule.Size, rule.OtherSize})
+		}
+	}
+	return constraints
+}
+
+// ParseRule parses a single pack selection rule
+func ParseRule(text string) (Rule, error) {
+	tokens := strings.Fields(strings.ToLower(text))
+	rule := Rule{Text: strings.Join(tokens, " ")}
+
+	// Split off the optional condition
+	for i, token := range tokens {
+		if token != "when" {
+			continue
+		}
+		condition, err := parseCondition(tokens[i+1:])
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid rule %q: %w", text, err)
+		}
+		rule.Condition = condition
+		tokens = tokens[:i]
+		break
+	}
+
+	if len(tokens) < 2 {
+		return Rule{}, fmt.Errorf("invalid rule %q: expected at most, at least or never mix", text)
+	}
+
+	var err error
+	switch kind := tokens[0] + " " + tokens[1]; kind {
+	case RuleAtMost, RuleAtLeast:
+		rule.Kind = kind
+		rule.Count, rule.Size, err = parsePackCount(tokens[2:])
+		if err == nil && kind == RuleAtLeast && rule.Count == 0 {
+			err = fmt.Errorf("at least requires a positive count")
+		}
+	case RuleNeverMix:
+		rule.Kind = kind
+		if len(tokens) != 5 || tokens[3] != "and" {
+			err = fmt.Errorf("expected never mix <size> and <size>")
+			break
+		}
+		if rule.Size, err = parsePositive(tokens[2]); err != nil {
+			break
+		}
+		if rule.OtherSize, err = parsePositive(tokens[4]); err != nil {
+			break
+		}
+		if rule.Size == rule.OtherSize {
+			err = fmt.Errorf("never mix requires two different sizes")
+		}
+	default:
+		err = fmt.Errorf("expected at most, at least or never mix")
+	}
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %w", text, err)
+	}
+	return rule, nil
+}
+
+// parsePackCount parses "<count> x <size>" or "<count> packs of <size>"
+func parsePackCount(tokens []string) (int, int, error) {
+	var sizeToken string
+	switch {
+	case len(tokens) == 3 && tokens[1] == "x":
+		sizeToken = tokens[2]
+	case len(tokens) == 4 && (tokens[1] == "packs" || tokens[1] == "pack") && tokens[2] == "of":
+		sizeToken = tokens[3]
+	default:
+		return 0, 0, fmt.Errorf("expected <count> x <size>")
+	}
+
+	count, err := strconv.Atoi(tokens[0])
+	if err != nil || count < 0 {
+		return 0, 0, fmt.Errorf("count must be a non-negative integer")
+	}
+	if count > MaxRuleCount {
+		return 0, 0, fmt.Errorf("count must be at most %d", MaxRuleCount)
+	}
+	size, err := parsePositive(sizeToken)
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, size, nil
+}
+
+// parseCondition parses "quantity <operator> <number>"
+func parseCondition(tokens []string) (*QuantityCondition, error) {
+	if len(tokens) != 3 || tokens[0] != "quantity" {
+		return nil, fmt.Errorf("expected when quantity <operator> <number>")
+	}
+	switch tokens[1] {
+	case ">", ">=", "<", "<=":
+	default:
+		return nil, fmt.Errorf("unknown operator %q", tokens[1])
+	}
+	quantity, err := strconv.Atoi(tokens[2])
+	if err != nil || quantity < 0 {
+		return nil, fmt.Errorf("quantity must be a non-negative integer")
+	}
+	return &QuantityCondition{Operator: tokens[1], Quantity: quantity}, nil
+}
+
+func parsePositive(token string) (int, error) {
+	size, err := strconv.Atoi(token)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("pack size must be a positive integer")
+	}
+	return size, nil
+}
@@ -0,0 +1,122 @@
+package pack_configurations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/pkg/packsolver"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			name: "at most",
+			text: "at most 2 x 250",
+			want: Rule{Text: "at most 2 x 250", Kind: RuleAtMost, Count: 2, Size: 250},
+		},
+		{
+			name: "packs of form with extra whitespace and case",
+			text: "  At Most 2 packs of 250 ",
+			want: Rule{Text: "at most 2 packs of 250", Kind: RuleAtMost, Count: 2, Size: 250},
+		},
+		{
+			name: "at least with condition",
+			text: "at least 1 x 5000 when quantity > 20000",
+			want: Rule{Text: "at least 1 x 5000 when quantity > 20000", Kind: RuleAtLeast, Count: 1, Size: 5000, Condition: &QuantityCondition{Operator: ">", Quantity: 20000}},
+		},
+		{
+			name: "never mix",
+			text: "never mix 250 and 5000",
+			want: Rule{Text: "never mix 250 and 5000", Kind: RuleNeverMix, Size: 250, OtherSize: 5000},
+		},
+		{
+			name:    "unknown kind",
+			text:    "prefer 5000",
+			wantErr: true,
+		},
+		{
+			name:    "missing size",
+			text:    "at most 2 x",
+			wantErr: true,
+		},
+		{
+			name:    "at least zero",
+			text:    "at least 0 x 250",
+			wantErr: true,
+		},
+		{
+			name: "count at the maximum",
+			text: "at most 1000000 x 250",
+			want: Rule{Text: "at most 1000000 x 250", Kind: RuleAtMost, Count: 1000000, Size: 250},
+		},
+		{
+			name:    "count above the maximum",
+			text:    "at least 4611686018427387904 x 250",
+			wantErr: true,
+		},
+		{
+			name:    "never mix the same size",
+			text:    "never mix 250 and 250",
+			wantErr: true,
+		},
+		{
+			name:    "unknown operator",
+			text:    "at most 1 x 250 when quantity = 10",
+			wantErr: true,
+		},
+		{
+			name:    "condition on something else",
+			text:    "at most 1 x 250 when weight > 10",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRule(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestActiveRules(t *testing.T) {
+	rules, err := ParseRules([]string{
+		"at most 2 x 250",
+		"at least 1 x 5000 when quantity > 20000",
+		"never mix 250 and 5000 when quantity <= 100",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Rule{rules[0]}, ActiveRules(rules, 20000))
+	assert.Equal(t, []Rule{rules[0], rules[1]}, ActiveRules(rules, 20001))
+	assert.Equal(t, []Rule{rules[0], rules[2]}, ActiveRules(rules, 100))
+}
+
+func TestConstraints(t *testing.T) {
+	rules, err := ParseRules([]string{
+		"at most 3 x 250",
+		"at most 2 x 250",
+		"at least 1 x 5000",
+		"at least 2 x 5000",
+		"never mix 250 and 5000",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, packsolver.Constraints{
+		AtLeast:  map[int]int{5000: 2},
+		AtMost:   map[int]int{250: 2},
+		NeverMix: [][2]int{{250, 5000}},
+	}, Constraints(rules))
+	assert.Equal(t, packsolver.Constraints{}, Constraints(nil))
+}
@@ -2,18 +2,28 @@ package pack_configurations
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sort"
 
 	"go.uber.org/zap"
 
+	"github.com/pack-calculator/pkg/auth"
 	"github.com/pack-calculator/pkg/postgres"
 	"github.com/pack-calculator/pkg/utils"
 )
 
 type Service interface {
-	Create(ctx context.Context, config *PackConfiguration) error
+	Create(ctx context.Context, config *PackConfiguration) (*PackConfiguration, error)
 	GetActive(ctx context.Context) (*PackConfiguration, error)
+	List(ctx context.Context) ([]PackConfiguration, error)
+	Get(ctx context.Context, id uint) (*PackConfiguration, error)
+	Activate(ctx context.Context, id uint) (*PackConfiguration, error)
 }
 
+// ErrConfigurationNotFound is returned when a referenced pack configuration does not exist
+var ErrConfigurationNotFound = errors.New("pack configuration not found")
+
 type service struct {
 	logger *zap.Logger
 	repo   Repository
@@ -26,24 +36,88 @@ func NewService(logger *zap.Logger, repo Repository) Service {
 	}
 }
 
-func (s *service) Create(ctx context.Context, config *PackConfiguration) error {
+func (s *service) Create(ctx context.Context, config *PackConfiguration) (*PackConfiguration, error) {
 	// Calculate hash signature
 	packSizes := postgres.Int64ArrayToIntSlice(config.PackSizes)
 	config.Signature = utils.CalculateArrayHash(packSizes)
 
+	// Configurations that only differ in nesting rules, carton data or pack rules must not share a signature
+	if len(config.Nesting) > 0 || len(config.PackSpecs) > 0 || len(config.Cartons) > 0 || len(config.Rules) > 0 {
+		extras, err := json.Marshal(struct {
+			Nesting   []NestingRule `json:"nesting,omitempty"`
+			PackSpecs []PackSpec    `json:"packSpecs,omitempty"`
+			Cartons   []CartonType  `json:"cartons,omitempty"`
+			Rules     []string      `json:"rules,omitempty"`
+		}{config.Nesting, config.PackSpecs, config.Cartons, config.Rules})
+		if err != nil {
+			return nil, err
+		}
+		config.Signature = utils.CalculateStringHash(config.Signature + string(extras))
+	}
+
 	packConfiguration, err := s.repo.GetBySignature(ctx, config.Signature)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if packConfiguration == nil {
 		packConfiguration, err = s.repo.Create(ctx, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return s.repo.SetActive(ctx, packConfiguration.ID)
+	if err := s.repo.SetActive(ctx, packConfiguration.ID); err != nil {
+		return nil, err
+	}
+	packConfiguration.Active = true
+	s.logger.Info("pack configuration activated", zap.Uint("id", packConfiguration.ID), zap.String("actor", auth.Subject(ctx)))
+	return packConfiguration, nil
 }
 
+// GetActive returns the active pack configuration of the tenant, or ErrConfigurationNotFound
+// when the tenant has none
 func (s *service) GetActive(ctx context.Context) (*PackConfiguration, error) {
-	return s.repo.GetActive(ctx)
+	config, err := s.repo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, ErrConfigurationNotFound
+	}
+	return config, nil
+}
+
+// List returns all pack configurations in the order they were created
+func (s *service) List(ctx context.Context) ([]PackConfiguration, error) {
+	configs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].ID < configs[j].ID })
+	return configs, nil
+}
+
+// Get returns a pack configuration by ID
+func (s *service) Get(ctx context.Context, id uint) (*PackConfiguration, error) {
+	config, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, ErrConfigurationNotFound
+	}
+	return config, nil
+}
+
+// Activate makes an existing pack configuration the active one
+func (s *service) Activate(ctx context.Context, id uint) (*PackConfiguration, error) {
+	config, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetActive(ctx, id); err != nil {
+		return nil, err
+	}
+	s.logger.Info("pack configuration activated", zap.Uint("id", id), zap.String("actor", auth.Subject(ctx)))
+	config.Active = true
+	return config, nil
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/utils"
 )
 
 // MockRepository is a mock implementation of Repository interface
@@ -78,6 +80,7 @@ func TestService_Create(t *testing.T) {
 		name    string
 		config  *PackConfiguration
 		mock    func(*MockRepository)
+		wantID  uint
 		wantErr bool
 	}{
 		{
@@ -93,7 +96,7 @@ func TestService_Create(t *testing.T) {
 				repo.On("SetActive", mock.Anything, uint(1)).
 					Return(nil)
 			},
-			wantErr: false,
+			wantID: 1,
 		},
 		{
 			name: "success - existing configuration",
@@ -102,11 +105,11 @@ func TestService_Create(t *testing.T) {
 			},
 			mock: func(repo *MockRepository) {
 				repo.On("GetBySignature", mock.Anything, mock.AnythingOfType("string")).
-					Return(&PackConfiguration{ID: 1}, nil)
-				repo.On("SetActive", mock.Anything, uint(1)).
+					Return(&PackConfiguration{ID: 3}, nil)
+				repo.On("SetActive", mock.Anything, uint(3)).
 					Return(nil)
 			},
-			wantErr: false,
+			wantID: 3,
 		},
 		{
 			name: "error - repository create error",
@@ -142,26 +145,72 @@ func TestService_Create(t *testing.T) {
 			tt.mock(mockRepo)
 
 			s := NewService(logger, mockRepo)
-			err := s.Create(context.Background(), tt.config)
+			got, err := s.Create(context.Background(), tt.config)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, got.ID)
+				assert.True(t, got.Active)
 			}
 			mockRepo.AssertExpectations(t)
 		})
 	}
 }
 
+func TestService_Create_NestingSignature(t *testing.T) {
+	packSizes := pq.Int64Array{250, 500, 1000}
+	flatSignature := utils.CalculateArrayHash([]int{250, 500, 1000})
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetBySignature", mock.Anything, mock.MatchedBy(func(signature string) bool {
+		return signature != flatSignature
+	})).Return(&PackConfiguration{ID: 2}, nil)
+	mockRepo.On("SetActive", mock.Anything, uint(2)).Return(nil)
+
+	s := NewService(zap.NewNop(), mockRepo)
+	config := &PackConfiguration{
+		PackSizes: packSizes,
+		Nesting:   []NestingRule{{Unit: "case", Contains: NestingUnitPack, Size: 1000, Capacity: 4}},
+	}
+	_, err := s.Create(context.Background(), config)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, flatSignature, config.Signature)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_Create_RulesSignature(t *testing.T) {
+	flatSignature := utils.CalculateArrayHash([]int{250, 500, 1000})
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetBySignature", mock.Anything, mock.MatchedBy(func(signature string) bool {
+		return signature != flatSignature
+	})).Return(&PackConfiguration{ID: 3}, nil)
+	mockRepo.On("SetActive", mock.Anything, uint(3)).Return(nil)
+
+	s := NewService(zap.NewNop(), mockRepo)
+	config := &PackConfiguration{
+		PackSizes: pq.Int64Array{250, 500, 1000},
+		Rules:     []string{"at most 2 x 250"},
+	}
+	_, err := s.Create(context.Background(), config)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, flatSignature, config.Signature)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestService_GetActive(t *testing.T) {
 	logger := zap.NewNop()
 
 	tests := []struct {
-		name    string
-		mock    func(*MockRepository)
-		want    *PackConfiguration
-		wantErr bool
+		name      string
+		mock      func(*MockRepository)
+		want      *PackConfiguration
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "success",
@@ -189,6 +238,16 @@ func TestService_GetActive(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "tenant without a configuration",
+			mock: func(repo *MockRepository) {
+				repo.On("GetActive", mock.Anything).
+					Return(nil, nil)
+			},
+			want:      nil,
+			wantErr:   true,
+			wantErrIs: ErrConfigurationNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -201,6 +260,9 @@ func TestService_GetActive(t *testing.T) {
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
 				assert.Nil(t, got)
 			} else {
 				assert.NoError(t, err)
@@ -210,3 +272,65 @@ func TestService_GetActive(t *testing.T) {
 		})
 	}
 }
+
+func TestService_List(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("List", mock.Anything).Return([]PackConfiguration{
+		{ID: 3, PackSizes: pq.Int64Array{250}},
+		{ID: 1, PackSizes: pq.Int64Array{500}},
+	}, nil)
+
+	got, err := NewService(zap.NewNop(), mockRepo).List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PackConfiguration{
+		{ID: 1, PackSizes: pq.Int64Array{500}},
+		{ID: 3, PackSizes: pq.Int64Array{250}},
+	}, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_Get(t *testing.T) {
+	t.Run("existing configuration", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(2)).Return(&PackConfiguration{ID: 2, PackSizes: pq.Int64Array{250}}, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo).Get(context.Background(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &PackConfiguration{ID: 2, PackSizes: pq.Int64Array{250}}, got)
+	})
+
+	t.Run("missing configuration", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, nil)
+
+		_, err := NewService(zap.NewNop(), mockRepo).Get(context.Background(), 2)
+
+		assert.ErrorIs(t, err, ErrConfigurationNotFound)
+	})
+}
+
+func TestService_Activate(t *testing.T) {
+	t.Run("activates the configuration", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(2)).Return(&PackConfiguration{ID: 2, PackSizes: pq.Int64Array{250}}, nil)
+		mockRepo.On("SetActive", mock.Anything, uint(2)).Return(nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo).Activate(context.Background(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &PackConfiguration{ID: 2, PackSizes: pq.Int64Array{250}, Active: true}, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing configuration is not activated", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetByID", mock.Anything, uint(2)).Return(nil, nil)
+
+		_, err := NewService(zap.NewNop(), mockRepo).Activate(context.Background(), 2)
+
+		assert.ErrorIs(t, err, ErrConfigurationNotFound)
+		mockRepo.AssertNotCalled(t, "SetActive", mock.Anything, mock.Anything)
+	})
+}
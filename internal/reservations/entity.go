@@ -0,0 +1,45 @@
+package reservations
+
+import (
+	"time"
+)
+
+// Reservation holds packs of a calculation against warehouse stock until it is
+// committed or expires
+type Reservation struct {
+	ID            uint              `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID      string            `gorm:"column:tenant_id;not null" json:"-"`
+	CalculationID uint              `gorm:"column:calculation_id;not null" json:"calculationId"`
+	Status        string            `gorm:"column:status;not null" json:"status"`
+	Items         []ReservationItem `gorm:"column:items;serializer:json;not null" json:"items"`
+	ExpiresAt     time.Time         `gorm:"column:expires_at;not null" json:"expiresAt"`
+	CommittedAt   *time.Time        `gorm:"column:committed_at" json:"committedAt,omitempty"`
+}
+
+// Reservation statuses. Active reservations that have not expired hold stock.
+const (
+	StatusActive    = "active"
+	StatusCommitted = "committed"
+	StatusReleased  = "released"
+)
+
+// ReservationItem is a number of packs of one size reserved in one warehouse
+type ReservationItem struct {
+	WarehouseID uint `json:"warehouseId"`
+	Size        int  `json:"size"`
+	Quantity    int  `json:"quantity"`
+}
+
+// Reserved sums the packs held by the reservations per warehouse and pack size
+func Reserved(reservations []Reservation) map[uint]map[int]int {
+	reserved := make(map[uint]map[int]int)
+	for _, reservation := range reservations {
+		for _, item := range reservation.Items {
+			if reserved[item.WarehouseID] == nil {
+				reserved[item.WarehouseID] = make(map[int]int)
+			}
+			reserved[item.WarehouseID][item.Size] += item.Quantity
+		}
+	}
+	return reserved
+}
@@ -0,0 +1,50 @@
+package reservations
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// CommitReservation confirms a reservation and takes its packs out of warehouse stock
+func (h *Handler) CommitReservation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewValidationErrorWrap("Invalid reservation ID", err))
+		return
+	}
+
+	reservation, err := h.service.Commit(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case stderrors.Is(err, ErrReservationNotFound):
+			c.Error(errors.NewNotFoundErrorWrap("Reservation not found", err))
+		case stderrors.Is(err, ErrReservationNotActive):
+			c.Error(errors.NewConflictErrorWrap("Reservation has expired or was already committed", err))
+		case stderrors.Is(err, ErrInsufficientStock):
+			c.Error(errors.NewConflictErrorWrap("Warehouse stock no longer holds the reserved packs", err))
+		default:
+			c.Error(errors.Wrap("Failed to commit reservation", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
@@ -0,0 +1,109 @@
+package reservations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Commit(ctx context.Context, id uint) (*Reservation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockService) ReleaseExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestHandler_CommitReservation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockService)
+		wantStatusCode int
+	}{
+		{
+			name: "success case",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Commit", mock.Anything, uint(4)).Return(&Reservation{ID: 4, Status: StatusCommitted}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "5",
+			mockSetup: func(m *MockService) {
+				m.On("Commit", mock.Anything, uint(5)).Return(nil, ErrReservationNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "expired",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Commit", mock.Anything, uint(4)).Return(nil, ErrReservationNotActive)
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "service error",
+			id:   "4",
+			mockSetup: func(m *MockService) {
+				m.On("Commit", mock.Anything, uint(4)).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/reservations/"+tt.id+"/commit", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			NewHandler(zap.NewNop(), mockService).CommitReservation(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
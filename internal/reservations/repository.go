@@ -0,0 +1,199 @@
+package reservations
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for reservation persistence operations. Every
+// operation but ReleaseExpired acts on the reservations of the tenant of the context.
+type Repository interface {
+	Reserve(ctx context.Context, now time.Time, plan Plan) (*Reservation, error)
+	ListActive(ctx context.Context, now time.Time) ([]Reservation, error)
+	Commit(ctx context.Context, id uint, now time.Time) (*Reservation, error)
+	ReleaseExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// Plan chooses the packs to reserve from the warehouses of a tenant and the reservations
+// active in them. A nil reservation reserves nothing. Records the plan stores with tx, such
+// as the calculation the reservation belongs to, are rolled back when the reservation fails.
+type Plan func(tx *gorm.DB, stock []warehouses.Warehouse, active []Reservation) (*Reservation, error)
+
+// Reserve stores the reservation chosen by plan and records it in the audit trail. The
+// warehouses of the tenant stay locked from reading their stock until the reservation is
+// stored, so concurrent reservations, on any instance, never hold the same packs.
+func (r *gormRepository) Reserve(ctx context.Context, now time.Time, plan Plan) (*Reservation, error) {
+	var reservation *Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stock []warehouses.Warehouse
+		if err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&stock).Error; err != nil {
+			return err
+		}
+		var active []Reservation
+		if err := tx.Scopes(tenant.Scope(ctx)).Where("status = ? AND expires_at > ?", StatusActive, now).Find(&active).Error; err != nil {
+			return err
+		}
+
+		var err error
+		reservation, err = plan(tx, stock, active)
+		if err != nil || reservation == nil {
+			return err
+		}
+		reservation.TenantID = tenant.FromContext(ctx)
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionReservationCreate,
+			ResourceType: audit_events.ResourceReservation,
+			ResourceID:   strconv.FormatUint(uint64(reservation.ID), 10),
+			After:        reservation,
+		})
+	})
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return reservation, nil
+}
+
+// ListActive returns the reservations that still hold stock at the given time
+func (r *gormRepository) ListActive(ctx context.Context, now time.Time) ([]Reservation, error) {
+	var reservations []Reservation
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Where("status = ? AND expires_at > ?", StatusActive, now).Find(&reservations).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return reservations, nil
+}
+
+// commitState is the audited state of a reservation and the stock of its warehouses
+type commitState struct {
+	Status    string               `json:"status"`
+	Inventory map[uint]map[int]int `json:"inventory"`
+}
+
+// Commit takes the reserved packs out of warehouse stock and marks the reservation committed,
+// recording the change in the audit trail. The reservation and the warehouses are locked for
+// the duration of the transaction.
+func (r *gormRepository) Commit(ctx context.Context, id uint, now time.Time) (*Reservation, error) {
+	var reservation Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReservationNotFound
+			}
+			return err
+		}
+		if reservation.Status != StatusActive || !reservation.ExpiresAt.After(now) {
+			return ErrReservationNotActive
+		}
+
+		before := commitState{Status: reservation.Status, Inventory: make(map[uint]map[int]int)}
+		after := commitState{Status: StatusCommitted, Inventory: make(map[uint]map[int]int)}
+		reserved := Reserved([]Reservation{reservation})
+		// Lock the warehouses in the order Reserve locks them
+		for _, warehouseID := range slices.Sorted(maps.Keys(reserved)) {
+			packs := reserved[warehouseID]
+			var warehouse warehouses.Warehouse
+			if err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).First(&warehouse, warehouseID).Error; err != nil {
+				return err
+			}
+			before.Inventory[warehouseID] = maps.Clone(warehouse.Inventory)
+			for size, quantity := range packs {
+				if warehouse.Inventory[size] < quantity {
+					return ErrInsufficientStock
+				}
+				warehouse.Inventory[size] -= quantity
+			}
+			if err := tx.Save(&warehouse).Error; err != nil {
+				return err
+			}
+			after.Inventory[warehouseID] = warehouse.Inventory
+		}
+
+		reservation.Status = StatusCommitted
+		reservation.CommittedAt = &now
+		err := tx.Model(&reservation).Updates(map[string]interface{}{
+			"status":       StatusCommitted,
+			"committed_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionReservationCommit,
+			ResourceType: audit_events.ResourceReservation,
+			ResourceID:   strconv.FormatUint(uint64(reservation.ID), 10),
+			Before:       before,
+			After:        after,
+		})
+	})
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return &reservation, nil
+}
+
+// ReleaseExpired releases the active reservations of every tenant that expired at the
+// given time, recording each release in the audit trail of its tenant
+func (r *gormRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	var expired []Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND expires_at <= ?", StatusActive, now).
+			Order("id").
+			Find(&expired).Error
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(expired))
+		for i, reservation := range expired {
+			ids[i] = reservation.ID
+		}
+		if err := tx.Model(&Reservation{}).Where("id IN ?", ids).Update("status", StatusReleased).Error; err != nil {
+			return err
+		}
+
+		for _, reservation := range expired {
+			released := reservation
+			released.Status = StatusReleased
+			err := audit_events.Record(tenant.NewContext(ctx, reservation.TenantID), tx, audit_events.Change{
+				Action:       audit_events.ActionReservationRelease,
+				ResourceType: audit_events.ResourceReservation,
+				ResourceID:   strconv.FormatUint(uint64(reservation.ID), 10),
+				Before:       reservation,
+				After:        released,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, postgres.ClassifyError(err)
+	}
+	return int64(len(expired)), nil
+}
@@ -0,0 +1,363 @@
+package reservations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+func TestReserve(t *testing.T) {
+	lockStock := regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE tenant_id = $1 ORDER BY id FOR UPDATE`)
+	selectActive := regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE (status = $1 AND expires_at > $2) AND tenant_id = $3`)
+	now := time.Now()
+	expiresAt := now.Add(time.Minute)
+
+	t.Run("plan reserved with the stock locked", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStock).
+			WithArgs("retail").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}).AddRow(1, "retail", "north", `{"1000":3}`))
+		mock.ExpectQuery(selectActive).
+			WithArgs(StatusActive, now, "retail").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "calculation_id", "status", "items", "expires_at"}).
+				AddRow(3, "retail", 8, StatusActive, `[{"warehouseId":1,"size":1000,"quantity":2}]`, expiresAt))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reservations" ("tenant_id","calculation_id","status","items","expires_at","committed_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+			WithArgs("retail", 9, StatusActive, `[{"warehouseId":1,"size":1000,"quantity":1}]`, expiresAt, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+			WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+			WithArgs("retail", audit_events.ActionReservationCreate, audit_events.ResourceReservation, "4", "anonymous", "", "",
+				nil, sqlmock.AnyArg(), sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		reservation, err := repo.Reserve(tenant.NewContext(context.Background(), "retail"), now, func(tx *gorm.DB, stock []warehouses.Warehouse, active []Reservation) (*Reservation, error) {
+			assert.Equal(t, map[int]int{1000: 3}, stock[0].Inventory)
+			assert.Equal(t, map[uint]map[int]int{1: {1000: 2}}, Reserved(active))
+			return &Reservation{
+				CalculationID: 9,
+				Status:        StatusActive,
+				Items:         []ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 1}},
+				ExpiresAt:     expiresAt,
+			}, nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), reservation.ID)
+		assert.Equal(t, "retail", reservation.TenantID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("plan error rolls back", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		planErr := errors.New("stock cannot cover")
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStock).
+			WithArgs(tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}))
+		mock.ExpectQuery(selectActive).
+			WithArgs(StatusActive, now, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		reservation, err := repo.Reserve(context.Background(), now, func(tx *gorm.DB, stock []warehouses.Warehouse, active []Reservation) (*Reservation, error) {
+			return nil, planErr
+		})
+
+		assert.ErrorIs(t, err, planErr)
+		assert.Nil(t, reservation)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed reservation rolls back what the plan stored", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStock).
+			WithArgs(tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}).AddRow(1, tenant.Default, "north", `{"1000":3}`))
+		mock.ExpectQuery(selectActive).
+			WithArgs(StatusActive, now, tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_calculations (id) VALUES (9)`)).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reservations"`)).
+			WillReturnError(errors.New("insert failed"))
+		mock.ExpectRollback()
+
+		reservation, err := repo.Reserve(context.Background(), now, func(tx *gorm.DB, stock []warehouses.Warehouse, active []Reservation) (*Reservation, error) {
+			if err := tx.Exec(`INSERT INTO order_calculations (id) VALUES (9)`).Error; err != nil {
+				return nil, err
+			}
+			return &Reservation{CalculationID: 9, Status: StatusActive, Items: []ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 1}}, ExpiresAt: expiresAt}, nil
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, reservation)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListActive(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE (status = $1 AND expires_at > $2) AND tenant_id = $3`)).
+		WithArgs(StatusActive, now, tenant.Default).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "calculation_id", "status", "items", "expires_at"}).
+			AddRow(4, 9, StatusActive, `[{"warehouseId":1,"size":1000,"quantity":1}]`, now.Add(time.Minute)))
+
+	reservations, err := repo.ListActive(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Len(t, reservations, 1)
+	assert.Equal(t, []ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 1}}, reservations[0].Items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommit(t *testing.T) {
+	now := time.Now()
+	reservationRows := func(status string, expiresAt time.Time) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "calculation_id", "status", "items", "expires_at"}).
+			AddRow(4, 9, status, `[{"warehouseId":1,"size":1000,"quantity":1}]`, expiresAt)
+	}
+
+	t.Run("successful commit", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE "reservations"."id" = $1 AND tenant_id = $2 ORDER BY "reservations"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(4, tenant.Default, 1).
+			WillReturnRows(reservationRows(StatusActive, now.Add(time.Minute)))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE "warehouses"."id" = $1 AND tenant_id = $2 ORDER BY "warehouses"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(1, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}).AddRow(1, tenant.Default, "north", `{"1000":3}`))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "warehouses" SET "tenant_id"=$1,"name"=$2,"inventory"=$3 WHERE "id" = $4`)).
+			WithArgs(tenant.Default, "north", `{"1000":2}`, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reservations" SET "committed_at"=$1,"status"=$2 WHERE "id" = $3`)).
+			WithArgs(now, StatusCommitted, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+			WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+			WithArgs(tenant.Default, audit_events.ActionReservationCommit, audit_events.ResourceReservation, "4", "anonymous", "", "",
+				`{"status":"active","inventory":{"1":{"1000":3}}}`, `{"status":"committed","inventory":{"1":{"1000":2}}}`,
+				sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		reservation, err := repo.Commit(context.Background(), 4, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusCommitted, reservation.Status)
+		assert.Equal(t, &now, reservation.CommittedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("expired reservation", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE "reservations"."id" = $1 AND tenant_id = $2 ORDER BY "reservations"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(4, tenant.Default, 1).
+			WillReturnRows(reservationRows(StatusActive, now.Add(-time.Minute)))
+		mock.ExpectRollback()
+
+		reservation, err := repo.Commit(context.Background(), 4, now)
+
+		assert.ErrorIs(t, err, ErrReservationNotActive)
+		assert.Nil(t, reservation)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reservation not found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE "reservations"."id" = $1 AND tenant_id = $2 ORDER BY "reservations"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(4, tenant.Default, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+		mock.ExpectRollback()
+
+		_, err := repo.Commit(context.Background(), 4, now)
+
+		assert.ErrorIs(t, err, ErrReservationNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insufficient stock", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE "reservations"."id" = $1 AND tenant_id = $2 ORDER BY "reservations"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(4, tenant.Default, 1).
+			WillReturnRows(reservationRows(StatusActive, now.Add(time.Minute)))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE "warehouses"."id" = $1 AND tenant_id = $2 ORDER BY "warehouses"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(1, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}).AddRow(1, tenant.Default, "north", `{"1000":0}`))
+		mock.ExpectRollback()
+
+		_, err := repo.Commit(context.Background(), 4, now)
+
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseExpired(t *testing.T) {
+	selectExpired := regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE status = $1 AND expires_at <= $2 ORDER BY id FOR UPDATE`)
+
+	t.Run("expired reservations released", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+		expiresAt := now.Add(-time.Minute)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectExpired).
+			WithArgs(StatusActive, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "calculation_id", "status", "items", "expires_at"}).
+				AddRow(4, "retail", 9, StatusActive, `[{"warehouseId":1,"size":1000,"quantity":1}]`, expiresAt).
+				AddRow(5, "wholesale", 10, StatusActive, `[]`, expiresAt))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reservations" SET "status"=$1 WHERE id IN ($2,$3)`)).
+			WithArgs(StatusReleased, 4, 5).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		for _, release := range []struct {
+			tenant, id, before, after string
+		}{
+			{
+				"retail", "4",
+				`{"id":4,"calculationId":9,"status":"active","items":[{"warehouseId":1,"size":1000,"quantity":1}],"expiresAt":"2026-10-18T08:59:00Z"}`,
+				`{"id":4,"calculationId":9,"status":"released","items":[{"warehouseId":1,"size":1000,"quantity":1}],"expiresAt":"2026-10-18T08:59:00Z"}`,
+			},
+			{
+				"wholesale", "5",
+				`{"id":5,"calculationId":10,"status":"active","items":[],"expiresAt":"2026-10-18T08:59:00Z"}`,
+				`{"id":5,"calculationId":10,"status":"released","items":[],"expiresAt":"2026-10-18T08:59:00Z"}`,
+			},
+		} {
+			mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+				WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+			mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+				WithArgs(release.tenant, audit_events.ActionReservationRelease, audit_events.ResourceReservation, release.id, "anonymous", "", "",
+					release.before, release.after, sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		}
+		mock.ExpectCommit()
+
+		released, err := repo.ReleaseExpired(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), released)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing expired", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectExpired).
+			WithArgs(StatusActive, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		released, err := repo.ReleaseExpired(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Zero(t, released)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectExpired).
+			WithArgs(StatusActive, now).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		_, err := repo.ReleaseExpired(context.Background(), now)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
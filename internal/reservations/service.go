@@ -0,0 +1,43 @@
+package reservations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Errors returned when committing a reservation
+var (
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrReservationNotActive = errors.New("reservation is no longer active")
+	ErrInsufficientStock    = errors.New("warehouse stock no longer holds the reserved packs")
+)
+
+type Service interface {
+	Commit(ctx context.Context, id uint) (*Reservation, error)
+	ReleaseExpired(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	logger *zap.Logger
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Commit confirms a reservation, taking its packs out of warehouse stock
+func (s *service) Commit(ctx context.Context, id uint) (*Reservation, error) {
+	return s.repo.Commit(ctx, id, time.Now())
+}
+
+// ReleaseExpired releases every active reservation past its expiry
+func (s *service) ReleaseExpired(ctx context.Context) (int64, error) {
+	return s.repo.ReleaseExpired(ctx, time.Now())
+}
@@ -0,0 +1,124 @@
+package reservations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+)
+
+// MockRepository is a mock implementation of Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Reserve(ctx context.Context, now time.Time, plan Plan) (*Reservation, error) {
+	args := m.Called(ctx, now, plan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockRepository) ListActive(ctx context.Context, now time.Time) ([]Reservation, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Reservation), args.Error(1)
+}
+
+func (m *MockRepository) Commit(ctx context.Context, id uint, now time.Time) (*Reservation, error) {
+	args := m.Called(ctx, id, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestService_Commit(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "committed"},
+		{name: "not active", repoErr: ErrReservationNotActive, wantErr: ErrReservationNotActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			if tt.repoErr != nil {
+				mockRepo.On("Commit", mock.Anything, uint(4), mock.AnythingOfType("time.Time")).Return(nil, tt.repoErr)
+			} else {
+				mockRepo.On("Commit", mock.Anything, uint(4), mock.AnythingOfType("time.Time")).Return(&Reservation{ID: 4, Status: StatusCommitted}, nil)
+			}
+
+			s := NewService(zap.NewNop(), mockRepo)
+			reservation, err := s.Commit(context.Background(), 4)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, StatusCommitted, reservation.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSweeper_Run(t *testing.T) {
+	mockRepo := new(MockRepository)
+	swept := make(chan struct{}, 1)
+	mockRepo.On("ReleaseExpired", mock.MatchedBy(func(ctx context.Context) bool {
+		return auth.Subject(ctx) == SweeperSubject
+	}), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			select {
+			case swept <- struct{}{}:
+			default:
+			}
+		}).
+		Return(int64(1), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewSweeper(zap.NewNop(), NewService(zap.NewNop(), mockRepo), time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not release expired reservations")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after cancellation")
+	}
+}
+
+func TestReserved(t *testing.T) {
+	reserved := Reserved([]Reservation{
+		{Items: []ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 1}, {WarehouseID: 2, Size: 250, Quantity: 2}}},
+		{Items: []ReservationItem{{WarehouseID: 1, Size: 1000, Quantity: 2}}},
+	})
+
+	assert.Equal(t, map[uint]map[int]int{1: {1000: 3}, 2: {250: 2}}, reserved)
+}
@@ -0,0 +1,55 @@
+package reservations
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/auth"
+)
+
+// SweeperSubject identifies the sweeper as the actor of the releases it records
+const SweeperSubject = "system:reservation_sweeper"
+
+// Sweeper periodically releases expired reservations in the background
+type Sweeper struct {
+	logger   *zap.Logger
+	service  Service
+	interval time.Duration
+}
+
+func NewSweeper(logger *zap.Logger, service Service, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		logger:   logger,
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run releases expired reservations every interval until the context is cancelled
+func (s *Sweeper) Run(ctx context.Context) {
+	ctx = auth.NewContext(ctx, &auth.Principal{Subject: SweeperSubject, Name: "reservation sweeper"})
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	released, err := s.service.ReleaseExpired(ctx)
+	if err != nil {
+		s.logger.Error("Failed to release expired reservations", zap.Error(err))
+		return
+	}
+	if released > 0 {
+		s.logger.Info("Released expired reservations", zap.Int64("count", released))
+	}
+}
@@ -0,0 +1,74 @@
+package shipping_rates
+
+import (
+	"github.com/pack-calculator/internal/order_calculations"
+)
+
+// Rate is one weight break of a carrier rate table. A shipment to the zone that
+// weighs up to MaxWeight costs Cost with the carrier.
+type Rate struct {
+	ID        uint    `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID  string  `gorm:"column:tenant_id;not null" json:"-"`
+	Carrier   string  `gorm:"column:carrier;not null" json:"carrier"`
+	Zone      string  `gorm:"column:zone;not null" json:"zone"`
+	MaxWeight float64 `gorm:"column:max_weight;not null" json:"maxWeight"`
+	Cost      float64 `gorm:"column:cost;not null" json:"cost"`
+}
+
+// TableName overrides the default table name for rates
+func (Rate) TableName() string {
+	return "shipping_rates"
+}
+
+// QuoteRequest describes an order to quote shipping for. An empty Carrier
+// quotes every carrier with rates for the zone and picks the cheapest.
+type QuoteRequest struct {
+	OrderQuantity int
+	Zone          string
+	Carrier       string
+}
+
+// QuoteOption is a pack solution together with its cheapest shipping cost
+type QuoteOption struct {
+	Carrier      string                          `json:"carrier"`
+	Packs        []order_calculations.PackResult `json:"packs"`
+	TotalItems   int                             `json:"totalItems"`
+	TotalPacks   int                             `json:"totalPacks"`
+	Weight       float64                         `json:"weight"`
+	ShippingCost float64                         `json:"shippingCost"`
+}
+
+// Quote compares the item-optimal pack solution with the one that is cheapest to ship
+type Quote struct {
+	OrderQuantity int
+	Zone          string
+	ItemOptimal   QuoteOption
+	CostOptimal   QuoteOption
+}
+
+// RatesAPIRequest represents an uploaded CSV rate table
+type RatesAPIRequest struct {
+	Rates []Rate
+}
+
+// RatesAPIResponse represents an API response listing rate tables
+type RatesAPIResponse struct {
+	Rates []Rate `json:"rates"`
+}
+
+// QuoteAPIRequest represents an API request to quote shipping for an order
+type QuoteAPIRequest struct {
+	OrderQuantity int    `json:"orderQuantity"`
+	Zone          string `json:"zone"`
+	Carrier       string `json:"carrier,omitempty"`
+}
+
+// QuoteAPIResponse represents an API response for a quote request
+type QuoteAPIResponse struct {
+	OrderQuantity int         `json:"orderQuantity"`
+	Zone          string      `json:"zone"`
+	ItemOptimal   QuoteOption `json:"itemOptimal"`
+	CostOptimal   QuoteOption `json:"costOptimal"`
+	Savings       float64     `json:"savings"`
+	ExtraItems    int         `json:"extraItems"`
+}
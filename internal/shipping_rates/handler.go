@@ -0,0 +1,80 @@
+package shipping_rates
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// ListRates returns every stored carrier rate table
+func (h *Handler) ListRates(c *gin.Context) {
+	rates, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.Error(errors.Wrap("Failed to retrieve shipping rates", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, RatesAPIResponse{Rates: rates})
+}
+
+// UploadRates replaces the rate tables of the carriers in an uploaded CSV file
+func (h *Handler) UploadRates(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*RatesAPIRequest)
+
+	if err := h.service.Upload(c.Request.Context(), request.Rates); err != nil {
+		c.Error(errors.Wrap("Failed to store shipping rates", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, RatesAPIResponse{Rates: request.Rates})
+}
+
+// QuoteOrder quotes shipping for the item-optimal and the cost-optimal pack solution of an order
+func (h *Handler) QuoteOrder(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*QuoteAPIRequest)
+
+	quote, err := h.service.Quote(c.Request.Context(), QuoteRequest{
+		OrderQuantity: request.OrderQuantity,
+		Zone:          request.Zone,
+		Carrier:       request.Carrier,
+	})
+	if err != nil {
+		c.Error(errors.Wrap("Failed to quote shipping", err))
+		return
+	}
+
+	response := QuoteAPIResponse{
+		OrderQuantity: quote.OrderQuantity,
+		Zone:          quote.Zone,
+		ItemOptimal:   quote.ItemOptimal,
+		CostOptimal:   quote.CostOptimal,
+		Savings:       quote.ItemOptimal.ShippingCost - quote.CostOptimal.ShippingCost,
+		ExtraItems:    quote.CostOptimal.TotalItems - quote.ItemOptimal.TotalItems,
+	}
+	c.JSON(http.StatusOK, response)
+}
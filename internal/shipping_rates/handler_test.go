@@ -0,0 +1,257 @@
+package shipping_rates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/order_calculations"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Upload(ctx context.Context, rates []Rate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockService) List(ctx context.Context) ([]Rate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockService) Quote(ctx context.Context, request QuoteRequest) (*Quote, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Quote), args.Error(1)
+}
+
+// ErrorResponse matches the JSON error response structure
+type ErrorResponse struct {
+	Type    string      `json:"Type"`
+	Message string      `json:"Message"`
+	Err     interface{} `json:"Err"`
+}
+
+func TestHandler_QuoteOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	itemOptimal := QuoteOption{
+		Carrier:      "zoom",
+		Packs:        []order_calculations.PackResult{{Size: 1000, Quantity: 1}},
+		TotalItems:   1000,
+		TotalPacks:   1,
+		Weight:       20,
+		ShippingCost: 11,
+	}
+	costOptimal := QuoteOption{
+		Carrier:      "acme",
+		Packs:        []order_calculations.PackResult{{Size: 250, Quantity: 4}},
+		TotalItems:   1000,
+		TotalPacks:   4,
+		Weight:       8,
+		ShippingCost: 8,
+	}
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantBody       func() interface{}
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &QuoteAPIRequest{OrderQuantity: 1000, Zone: "eu"})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Quote", mock.Anything, QuoteRequest{OrderQuantity: 1000, Zone: "eu"}).Return(&Quote{
+					OrderQuantity: 1000,
+					Zone:          "eu",
+					ItemOptimal:   itemOptimal,
+					CostOptimal:   costOptimal,
+				}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: func() interface{} {
+				return &QuoteAPIResponse{
+					OrderQuantity: 1000,
+					Zone:          "eu",
+					ItemOptimal:   itemOptimal,
+					CostOptimal:   costOptimal,
+					Savings:       3,
+					ExtraItems:    0,
+				}
+			},
+		},
+		{
+			name: "no rates for zone",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &QuoteAPIRequest{OrderQuantity: 1000, Zone: "us"})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Quote", mock.Anything, QuoteRequest{OrderQuantity: 1000, Zone: "us"}).
+					Return(nil, apperrors.NewValidationError("No shipping rates for zone us"))
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeInvalidRequest),
+					Message: "No shipping rates for zone us",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeInternal),
+					Message: "Failed to retrieve payload from context",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &QuoteAPIRequest{OrderQuantity: 1000, Zone: "eu"})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Quote", mock.Anything, QuoteRequest{OrderQuantity: 1000, Zone: "eu"}).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody: func() interface{} {
+				return &ErrorResponse{
+					Type:    string(apperrors.ErrorTypeInternal),
+					Message: "Failed to quote shipping",
+					Err:     map[string]interface{}{},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/quote", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			handler := NewHandler(zap.NewNop(), mockService)
+			handler.QuoteOrder(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+
+			var got interface{}
+			switch tt.wantStatusCode {
+			case http.StatusOK:
+				got = &QuoteAPIResponse{}
+			default:
+				got = &ErrorResponse{}
+			}
+
+			err := json.Unmarshal(w.Body.Bytes(), got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBody(), got)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_UploadRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rates := []Rate{{Carrier: "acme", Zone: "eu", MaxWeight: 10, Cost: 8}}
+
+	t.Run("success case", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/rates", nil)
+		c.Set("payload", &RatesAPIRequest{Rates: rates})
+
+		mockService := new(MockService)
+		mockService.On("Upload", mock.Anything, rates).Return(nil)
+
+		NewHandler(zap.NewNop(), mockService).UploadRates(c)
+		renderErrors(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var got RatesAPIResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, rates, got.Rates)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/rates", nil)
+		c.Set("payload", &RatesAPIRequest{Rates: rates})
+
+		mockService := new(MockService)
+		mockService.On("Upload", mock.Anything, rates).Return(errors.New("db error"))
+
+		NewHandler(zap.NewNop(), mockService).UploadRates(c)
+		renderErrors(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestHandler_ListRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/rates", nil)
+
+	rates := []Rate{{ID: 1, Carrier: "acme", Zone: "eu", MaxWeight: 10, Cost: 8}}
+	mockService := new(MockService)
+	mockService.On("List", mock.Anything).Return(rates, nil)
+
+	NewHandler(zap.NewNop(), mockService).ListRates(c)
+	renderErrors(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got RatesAPIResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, rates, got.Rates)
+	mockService.AssertExpectations(t)
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
@@ -0,0 +1,101 @@
+package shipping_rates
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for shipping rate persistence operations. Every
+// operation acts on the rate tables of the tenant of the context.
+type Repository interface {
+	ReplaceCarriers(ctx context.Context, rates []Rate) error
+	List(ctx context.Context) ([]Rate, error)
+	ListByZone(ctx context.Context, zone string) ([]Rate, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// ReplaceCarriers replaces the rate tables of the tenant for every carrier present in rates,
+// recording the replaced table of each carrier in the audit trail
+func (r *gormRepository) ReplaceCarriers(ctx context.Context, rates []Rate) error {
+	carriers := make([]string, 0)
+	seen := make(map[string]bool)
+	for i, rate := range rates {
+		rates[i].TenantID = tenant.FromContext(ctx)
+		if !seen[rate.Carrier] {
+			seen[rate.Carrier] = true
+			carriers = append(carriers, rate.Carrier)
+		}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var replaced []Rate
+		err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("carrier IN ?", carriers).Order("carrier, zone, max_weight").Find(&replaced).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Scopes(tenant.Scope(ctx)).Where("carrier IN ?", carriers).Delete(&Rate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&rates).Error; err != nil {
+			return err
+		}
+
+		before, after := ratesByCarrier(replaced), ratesByCarrier(rates)
+		for _, carrier := range carriers {
+			err := audit_events.Record(ctx, tx, audit_events.Change{
+				Action:       audit_events.ActionRateTableReplace,
+				ResourceType: audit_events.ResourceRateTable,
+				ResourceID:   carrier,
+				Before:       before[carrier],
+				After:        after[carrier],
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return postgres.ClassifyError(err)
+}
+
+// ratesByCarrier groups rates by their carrier
+func ratesByCarrier(rates []Rate) map[string][]Rate {
+	grouped := make(map[string][]Rate)
+	for _, rate := range rates {
+		grouped[rate.Carrier] = append(grouped[rate.Carrier], rate)
+	}
+	return grouped
+}
+
+func (r *gormRepository) List(ctx context.Context) ([]Rate, error) {
+	var rates []Rate
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Order("carrier, zone, max_weight").Find(&rates).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return rates, nil
+}
+
+func (r *gormRepository) ListByZone(ctx context.Context, zone string) ([]Rate, error) {
+	var rates []Rate
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Where("zone = ?", zone).Order("carrier, max_weight").Find(&rates).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return rates, nil
+}
@@ -0,0 +1,158 @@
+package shipping_rates
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+func TestReplaceCarriers(t *testing.T) {
+	rates := []Rate{
+		{Carrier: "acme", Zone: "eu", MaxWeight: 10, Cost: 8},
+		{Carrier: "acme", Zone: "us", MaxWeight: 10, Cost: 9},
+	}
+	selectReplaced := regexp.QuoteMeta(`SELECT * FROM "shipping_rates" WHERE carrier IN ($1) AND tenant_id = $2 ORDER BY carrier, zone, max_weight FOR UPDATE`)
+
+	t.Run("successful replace", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectReplaced).
+			WithArgs("acme", "retail").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "carrier", "zone", "max_weight", "cost"}).
+				AddRow(7, "retail", "acme", "eu", 20.0, 12.0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "shipping_rates" WHERE carrier IN ($1) AND tenant_id = $2`)).
+			WithArgs("acme", "retail").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "shipping_rates" ("tenant_id","carrier","zone","max_weight","cost") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) RETURNING "id"`)).
+			WithArgs("retail", "acme", "eu", 10.0, 8.0, "retail", "acme", "us", 10.0, 9.0).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+			WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+			WithArgs("retail", audit_events.ActionRateTableReplace, audit_events.ResourceRateTable, "acme", "anonymous", "", "",
+				`[{"id":7,"carrier":"acme","zone":"eu","maxWeight":20,"cost":12}]`,
+				`[{"id":1,"carrier":"acme","zone":"eu","maxWeight":10,"cost":8},{"id":2,"carrier":"acme","zone":"us","maxWeight":10,"cost":9}]`,
+				sqlmock.AnyArg(), "previous", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		err := repo.ReplaceCarriers(tenant.NewContext(context.Background(), "retail"), rates)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete error rolls back", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectReplaced).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "shipping_rates" WHERE carrier IN ($1) AND tenant_id = $2`)).
+			WithArgs("acme", "retail").
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := repo.ReplaceCarriers(tenant.NewContext(context.Background(), "retail"), rates)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestList(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "shipping_rates" WHERE tenant_id = $1 ORDER BY carrier, zone, max_weight`)).
+		WithArgs(tenant.Default).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "zone", "max_weight", "cost"}).
+			AddRow(1, "acme", "eu", 10.0, 8.0))
+
+	rates, err := repo.List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Rate{{ID: 1, Carrier: "acme", Zone: "eu", MaxWeight: 10, Cost: 8}}, rates)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListByZone(t *testing.T) {
+	t.Run("rates found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "shipping_rates" WHERE zone = $1 AND tenant_id = $2 ORDER BY carrier, max_weight`)).
+			WithArgs("eu", tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "zone", "max_weight", "cost"}).
+				AddRow(1, "acme", "eu", 10.0, 8.0).
+				AddRow(2, "acme", "eu", 20.0, 12.0))
+
+		rates, err := repo.ListByZone(context.Background(), "eu")
+
+		assert.NoError(t, err)
+		assert.Len(t, rates, 2)
+		assert.Equal(t, 20.0, rates[1].MaxWeight)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "shipping_rates" WHERE zone = $1 AND tenant_id = $2 ORDER BY carrier, max_weight`)).
+			WithArgs("eu", tenant.Default).
+			WillReturnError(errors.New("database error"))
+
+		rates, err := repo.ListByZone(context.Background(), "eu")
+
+		assert.Error(t, err)
+		assert.Nil(t, rates)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,296 @@
+package shipping_rates
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/postgres"
+)
+
+// csvHeader lists the columns of an uploaded rate table
+var csvHeader = []string{"carrier", "zone", "max_weight", "cost"}
+
+type Service interface {
+	Upload(ctx context.Context, rates []Rate) error
+	List(ctx context.Context) ([]Rate, error)
+	Quote(ctx context.Context, request QuoteRequest) (*Quote, error)
+}
+
+type service struct {
+	logger             *zap.Logger
+	repo               Repository
+	packsCfgRepo       pack_configurations.Repository
+	calculationService order_calculations.Service
+}
+
+func NewService(logger *zap.Logger, repo Repository, packsCfgRepo pack_configurations.Repository, calculationService order_calculations.Service) Service {
+	return &service{
+		logger:             logger,
+		repo:               repo,
+		packsCfgRepo:       packsCfgRepo,
+		calculationService: calculationService,
+	}
+}
+
+// Upload replaces the rate tables of the carriers in rates
+func (s *service) Upload(ctx context.Context, rates []Rate) error {
+	return s.repo.ReplaceCarriers(ctx, rates)
+}
+
+func (s *service) List(ctx context.Context) ([]Rate, error) {
+	return s.repo.List(ctx)
+}
+
+// Quote prices the item-optimal pack solution and the pack solution that is cheapest
+// to ship to the zone, so the cost of shipping extra items can be weighed against
+// the shipping saved
+func (s *service) Quote(ctx context.Context, request QuoteRequest) (*Quote, error) {
+	packCfg, err := s.packsCfgRepo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if packCfg == nil {
+		return nil, errors.NewValidationError("No active pack configuration")
+	}
+
+	packSizes := postgres.Int64ArrayToIntSlice(packCfg.PackSizes)
+	sort.Ints(packSizes)
+
+	weights := make(map[int]float64, len(packCfg.PackSpecs))
+	for _, spec := range packCfg.PackSpecs {
+		weights[spec.Size] = spec.Weight
+	}
+	for _, size := range packSizes {
+		if _, ok := weights[size]; !ok {
+			return nil, errors.NewValidationError("Pack weights must be configured for every pack size to quote shipping")
+		}
+	}
+
+	rates, err := s.repo.ListByZone(ctx, request.Zone)
+	if err != nil {
+		return nil, err
+	}
+	tables := rateTables(rates, request.Carrier)
+	if len(tables) == 0 {
+		return nil, errors.NewValidationError(fmt.Sprintf("No shipping rates for zone %s", request.Zone))
+	}
+
+	itemCounts, err := s.calculationService.CalculateOptimalPacks(ctx, request.OrderQuantity, packSizes)
+	if err != nil {
+		return nil, err
+	}
+	itemOptimal := priceOption(itemCounts, weights, tables)
+
+	// The cost-optimal solution is never worse than the item-optimal one
+	costOptimal := itemOptimal
+	if candidate := findCheapestPacks(request.OrderQuantity, packSizes, weights, tables); candidate.ShippingCost < costOptimal.ShippingCost {
+		costOptimal = candidate
+	}
+
+	return &Quote{
+		OrderQuantity: request.OrderQuantity,
+		Zone:          request.Zone,
+		ItemOptimal:   itemOptimal,
+		CostOptimal:   costOptimal,
+	}, nil
+}
+
+// rateTables groups rates by carrier into weight breaks sorted by weight,
+// keeping only the requested carrier when one is given
+func rateTables(rates []Rate, carrier string) map[string][]Rate {
+	tables := make(map[string][]Rate)
+	for _, rate := range rates {
+		if carrier != "" && rate.Carrier != carrier {
+			continue
+		}
+		tables[rate.Carrier] = append(tables[rate.Carrier], rate)
+	}
+	for _, breaks := range tables {
+		sort.Slice(breaks, func(i, j int) bool {
+			return breaks[i].MaxWeight < breaks[j].MaxWeight
+		})
+	}
+	return tables
+}
+
+// shipmentCost prices a weight with the weight breaks of one carrier. Weight above
+// the heaviest break is split into full shipments at the heaviest break plus a
+// shipment for the remainder.
+func shipmentCost(breaks []Rate, weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	heaviest := breaks[len(breaks)-1]
+	fullShipments := int(weight / heaviest.MaxWeight)
+	cost := float64(fullShipments) * heaviest.Cost
+
+	remainder := weight - float64(fullShipments)*heaviest.MaxWeight
+	if remainder > 0 {
+		for _, rate := range breaks {
+			if remainder <= rate.MaxWeight {
+				cost += rate.Cost
+				break
+			}
+		}
+	}
+	return cost
+}
+
+// cheapestCarrier returns the carrier that ships the weight for the lowest cost,
+// preferring carriers in name order on ties
+func cheapestCarrier(weight float64, tables map[string][]Rate) (string, float64) {
+	carriers := make([]string, 0, len(tables))
+	for carrier := range tables {
+		carriers = append(carriers, carrier)
+	}
+	sort.Strings(carriers)
+
+	best, bestCost := "", 0.0
+	for _, carrier := range carriers {
+		cost := shipmentCost(tables[carrier], weight)
+		if best == "" || cost < bestCost {
+			best, bestCost = carrier, cost
+		}
+	}
+	return best, bestCost
+}
+
+// priceOption summarizes pack counts and prices them with the cheapest carrier
+func priceOption(packCounts map[int]int, weights map[int]float64, tables map[string][]Rate) QuoteOption {
+	sizes := make([]int, 0, len(packCounts))
+	for size := range packCounts {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+
+	option := QuoteOption{Packs: []order_calculations.PackResult{}}
+	for _, size := range sizes {
+		quantity := packCounts[size]
+		option.Packs = append(option.Packs, order_calculations.PackResult{Size: size, Quantity: quantity})
+		option.TotalItems += size * quantity
+		option.TotalPacks += quantity
+		option.Weight += weights[size] * float64(quantity)
+	}
+	option.Carrier, option.ShippingCost = cheapestCarrier(option.Weight, tables)
+	return option
+}
+
+// findCheapestPacks finds the pack combination covering the order quantity that is
+// cheapest to ship. For every achievable total it keeps the lightest combination,
+// then prices each total and picks the cheapest, preferring fewer items on ties.
+// Totals at or above the order quantity plus the largest pack are never needed,
+// since dropping a pack from them still covers the order without adding weight.
+func findCheapestPacks(orderQuantity int, packSizes []int, weights map[int]float64, tables map[string][]Rate) QuoteOption {
+	maxTotal := orderQuantity + packSizes[len(packSizes)-1] - 1
+
+	// lightest[i] is the lowest weight of a combination of exactly i items, -1 when unreachable,
+	// and last[i] is the pack size added last to reach it
+	lightest := make([]float64, maxTotal+1)
+	packs := make([]int, maxTotal+1)
+	last := make([]int, maxTotal+1)
+	for i := 1; i <= maxTotal; i++ {
+		lightest[i] = -1
+	}
+
+	for i := 1; i <= maxTotal; i++ {
+		for _, size := range packSizes {
+			if size > i || lightest[i-size] < 0 {
+				continue
+			}
+			weight := lightest[i-size] + weights[size]
+			if lightest[i] < 0 || weight < lightest[i] || (weight == lightest[i] && packs[i-size]+1 < packs[i]) {
+				lightest[i] = weight
+				packs[i] = packs[i-size] + 1
+				last[i] = size
+			}
+		}
+	}
+
+	bestTotal, bestCost := -1, 0.0
+	for i := orderQuantity; i <= maxTotal; i++ {
+		if lightest[i] < 0 {
+			continue
+		}
+		if _, cost := cheapestCarrier(lightest[i], tables); bestTotal < 0 || cost < bestCost {
+			bestTotal, bestCost = i, cost
+		}
+	}
+
+	packCounts := make(map[int]int)
+	for i := bestTotal; i > 0; i -= last[i] {
+		packCounts[last[i]]++
+	}
+	return priceOption(packCounts, weights, tables)
+}
+
+// ParseRatesCSV reads a rate table with the columns carrier, zone, max_weight and cost.
+// Errors are returned as validation errors describing the offending line.
+func ParseRatesCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.NewValidationErrorWrap("Rate table must start with a header line", err)
+	}
+	if len(header) != len(csvHeader) {
+		return nil, errors.NewValidationError("Rate table header must be carrier,zone,max_weight,cost")
+	}
+	for i, column := range header {
+		if strings.ToLower(strings.TrimSpace(column)) != csvHeader[i] {
+			return nil, errors.NewValidationError("Rate table header must be carrier,zone,max_weight,cost")
+		}
+	}
+
+	rates := []Rate{}
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewValidationErrorWrap("Invalid rate table CSV", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		rate := Rate{
+			Carrier: strings.TrimSpace(record[0]),
+			Zone:    strings.TrimSpace(record[1]),
+		}
+		if rate.Carrier == "" || rate.Zone == "" {
+			return nil, errors.NewValidationError(fmt.Sprintf("Line %d: carrier and zone are required", line))
+		}
+		rate.MaxWeight, err = strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil || rate.MaxWeight <= 0 {
+			return nil, errors.NewValidationError(fmt.Sprintf("Line %d: max_weight must be a positive number", line))
+		}
+		rate.Cost, err = strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err != nil || rate.Cost < 0 {
+			return nil, errors.NewValidationError(fmt.Sprintf("Line %d: cost must be a non-negative number", line))
+		}
+
+		key := fmt.Sprintf("%s/%s/%g", rate.Carrier, rate.Zone, rate.MaxWeight)
+		if seen[key] {
+			return nil, errors.NewValidationError(fmt.Sprintf("Line %d: duplicate weight break for carrier %s in zone %s", line, rate.Carrier, rate.Zone))
+		}
+		seen[key] = true
+		rates = append(rates, rate)
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.NewValidationError("Rate table must contain at least one rate")
+	}
+	return rates, nil
+}
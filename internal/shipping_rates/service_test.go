@@ -0,0 +1,392 @@
+package shipping_rates
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+// MockRepository is a mock implementation of Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) ReplaceCarriers(ctx context.Context, rates []Rate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(ctx context.Context) ([]Rate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockRepository) ListByZone(ctx context.Context, zone string) ([]Rate, error) {
+	args := m.Called(ctx, zone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+// MockPackConfigRepository is a mock implementation of pack_configurations.Repository
+type MockPackConfigRepository struct {
+	mock.Mock
+}
+
+func (m *MockPackConfigRepository) Create(ctx context.Context, config *pack_configurations.PackConfiguration) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, config)
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetByID(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetBySignature(ctx context.Context, signature string) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pack_configurations.PackConfiguration), args.Error(1)
+}
+
+func (m *MockPackConfigRepository) SetActive(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) Update(ctx context.Context, config *pack_configurations.PackConfiguration) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPackConfigRepository) List(ctx context.Context) ([]pack_configurations.PackConfiguration, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]pack_configurations.PackConfiguration), args.Error(1)
+}
+
+// MockCalculationService is a mock implementation of order_calculations.Service
+type MockCalculationService struct {
+	mock.Mock
+}
+
+func (m *MockCalculationService) OrderProcessing(ctx context.Context, order order_calculations.OrderRequest) (*order_calculations.OrderCalculation, error) {
+	args := m.Called(ctx, order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*order_calculations.OrderCalculation), args.Error(1)
+}
+
+func (m *MockCalculationService) CalculateOptimalPacks(ctx context.Context, orderQuantity int, packSizes []int) (map[int]int, error) {
+	args := m.Called(ctx, orderQuantity, packSizes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *MockCalculationService) AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*order_calculations.Amendment, error) {
+	args := m.Called(ctx, calculationID, orderQuantity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*order_calculations.Amendment), args.Error(1)
+}
+
+func (m *MockCalculationService) ListCalculations(ctx context.Context, offset, limit int) ([]order_calculations.OrderCalculation, error) {
+	args := m.Called(ctx, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]order_calculations.OrderCalculation), args.Error(1)
+}
+
+func (m *MockCalculationService) GetCalculation(ctx context.Context, id uint) (*order_calculations.OrderCalculation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*order_calculations.OrderCalculation), args.Error(1)
+}
+
+func TestService_Quote(t *testing.T) {
+	// Heavy 1000 packs make four 250 packs cheaper to ship than a single 1000
+	packCfg := &pack_configurations.PackConfiguration{
+		ID:        1,
+		PackSizes: pq.Int64Array{1000, 250, 500},
+		PackSpecs: []pack_configurations.PackSpec{
+			{Size: 250, Weight: 2},
+			{Size: 500, Weight: 6},
+			{Size: 1000, Weight: 20},
+		},
+	}
+	rates := []Rate{
+		{Carrier: "acme", Zone: "eu", MaxWeight: 10, Cost: 8},
+		{Carrier: "acme", Zone: "eu", MaxWeight: 20, Cost: 12},
+		{Carrier: "zoom", Zone: "eu", MaxWeight: 10, Cost: 9},
+		{Carrier: "zoom", Zone: "eu", MaxWeight: 20, Cost: 11},
+	}
+
+	tests := []struct {
+		name        string
+		request     QuoteRequest
+		mockSetup   func(*MockRepository, *MockPackConfigRepository, *MockCalculationService)
+		wantItem    QuoteOption
+		wantCost    QuoteOption
+		wantErr     bool
+		wantInvalid bool
+	}{
+		{
+			name:    "lighter packs ship cheaper",
+			request: QuoteRequest{OrderQuantity: 1000, Zone: "eu"},
+			mockSetup: func(repo *MockRepository, packRepo *MockPackConfigRepository, calcService *MockCalculationService) {
+				packRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+				repo.On("ListByZone", mock.Anything, "eu").Return(rates, nil)
+				calcService.On("CalculateOptimalPacks", mock.Anything, 1000, []int{250, 500, 1000}).Return(map[int]int{1000: 1}, nil)
+			},
+			wantItem: QuoteOption{
+				Carrier:      "zoom",
+				Packs:        []order_calculations.PackResult{{Size: 1000, Quantity: 1}},
+				TotalItems:   1000,
+				TotalPacks:   1,
+				Weight:       20,
+				ShippingCost: 11,
+			},
+			wantCost: QuoteOption{
+				Carrier:      "acme",
+				Packs:        []order_calculations.PackResult{{Size: 250, Quantity: 4}},
+				TotalItems:   1000,
+				TotalPacks:   4,
+				Weight:       8,
+				ShippingCost: 8,
+			},
+		},
+		{
+			name:    "carrier filter",
+			request: QuoteRequest{OrderQuantity: 1000, Zone: "eu", Carrier: "zoom"},
+			mockSetup: func(repo *MockRepository, packRepo *MockPackConfigRepository, calcService *MockCalculationService) {
+				packRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+				repo.On("ListByZone", mock.Anything, "eu").Return(rates, nil)
+				calcService.On("CalculateOptimalPacks", mock.Anything, 1000, []int{250, 500, 1000}).Return(map[int]int{1000: 1}, nil)
+			},
+			wantItem: QuoteOption{
+				Carrier:      "zoom",
+				Packs:        []order_calculations.PackResult{{Size: 1000, Quantity: 1}},
+				TotalItems:   1000,
+				TotalPacks:   1,
+				Weight:       20,
+				ShippingCost: 11,
+			},
+			wantCost: QuoteOption{
+				Carrier:      "zoom",
+				Packs:        []order_calculations.PackResult{{Size: 250, Quantity: 4}},
+				TotalItems:   1000,
+				TotalPacks:   4,
+				Weight:       8,
+				ShippingCost: 9,
+			},
+		},
+		{
+			name:    "extra items ship cheaper",
+			request: QuoteRequest{OrderQuantity: 9, Zone: "eu"},
+			mockSetup: func(repo *MockRepository, packRepo *MockPackConfigRepository, calcService *MockCalculationService) {
+				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+					PackSizes: pq.Int64Array{3, 5},
+					PackSpecs: []pack_configurations.PackSpec{{Size: 3, Weight: 10}, {Size: 5, Weight: 1}},
+				}, nil)
+				repo.On("ListByZone", mock.Anything, "eu").Return([]Rate{
+					{Carrier: "acme", Zone: "eu", MaxWeight: 5, Cost: 2},
+					{Carrier: "acme", Zone: "eu", MaxWeight: 40, Cost: 9},
+				}, nil)
+				calcService.On("CalculateOptimalPacks", mock.Anything, 9, []int{3, 5}).Return(map[int]int{3: 3}, nil)
+			},
+			wantItem: QuoteOption{
+				Carrier:      "acme",
+				Packs:        []order_calculations.PackResult{{Size: 3, Quantity: 3}},
+				TotalItems:   9,
+				TotalPacks:   3,
+				Weight:       30,
+				ShippingCost: 9,
+			},
+			wantCost: QuoteOption{
+				Carrier:      "acme",
+				Packs:        []order_calculations.PackResult{{Size: 5, Quantity: 2}},
+				TotalItems:   10,
+				TotalPacks:   2,
+				Weight:       2,
+				ShippingCost: 2,
+			},
+		},
+		{
+			name:    "missing pack weights",
+			request: QuoteRequest{OrderQuantity: 1000, Zone: "eu"},
+			mockSetup: func(repo *MockRepository, packRepo *MockPackConfigRepository, calcService *MockCalculationService) {
+				packRepo.On("GetActive", mock.Anything).Return(&pack_configurations.PackConfiguration{
+					PackSizes: pq.Int64Array{250, 500},
+					PackSpecs: []pack_configurations.PackSpec{{Size: 250, Weight: 2}},
+				}, nil)
+			},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:    "no rates for zone",
+			request: QuoteRequest{OrderQuantity: 1000, Zone: "us"},
+			mockSetup: func(repo *MockRepository, packRepo *MockPackConfigRepository, calcService *MockCalculationService) {
+				packRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+				repo.On("ListByZone", mock.Anything, "us").Return([]Rate{}, nil)
+			},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:    "repository error",
+			request: QuoteRequest{OrderQuantity: 1000, Zone: "eu"},
+			mockSetup: func(repo *MockRepository, packRepo *MockPackConfigRepository, calcService *MockCalculationService) {
+				packRepo.On("GetActive", mock.Anything).Return(packCfg, nil)
+				repo.On("ListByZone", mock.Anything, "eu").Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			packRepo := new(MockPackConfigRepository)
+			calcService := new(MockCalculationService)
+			tt.mockSetup(repo, packRepo, calcService)
+
+			svc := NewService(zap.NewNop(), repo, packRepo, calcService)
+			quote, err := svc.Quote(context.Background(), tt.request)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantInvalid, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantItem, quote.ItemOptimal)
+			assert.Equal(t, tt.wantCost, quote.CostOptimal)
+
+			repo.AssertExpectations(t)
+			packRepo.AssertExpectations(t)
+			calcService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShipmentCost(t *testing.T) {
+	breaks := []Rate{
+		{MaxWeight: 5, Cost: 4},
+		{MaxWeight: 10, Cost: 7},
+	}
+
+	tests := []struct {
+		name   string
+		weight float64
+		want   float64
+	}{
+		{name: "nothing to ship", weight: 0, want: 0},
+		{name: "first break", weight: 3, want: 4},
+		{name: "on a break", weight: 10, want: 7},
+		{name: "split above the heaviest break", weight: 23, want: 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, shipmentCost(breaks, tt.weight))
+		})
+	}
+}
+
+func TestParseRatesCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []Rate
+		wantErr string
+	}{
+		{
+			name: "valid table",
+			csv:  "carrier,zone,max_weight,cost\nacme,eu,10,8\nacme, eu, 20, 12.5\n",
+			want: []Rate{
+				{Carrier: "acme", Zone: "eu", MaxWeight: 10, Cost: 8},
+				{Carrier: "acme", Zone: "eu", MaxWeight: 20, Cost: 12.5},
+			},
+		},
+		{
+			name:    "wrong header",
+			csv:     "carrier,zone,weight,cost\nacme,eu,10,8\n",
+			wantErr: "Rate table header must be carrier,zone,max_weight,cost",
+		},
+		{
+			name:    "invalid weight",
+			csv:     "carrier,zone,max_weight,cost\nacme,eu,heavy,8\n",
+			wantErr: "Line 2: max_weight must be a positive number",
+		},
+		{
+			name:    "negative cost",
+			csv:     "carrier,zone,max_weight,cost\nacme,eu,10,-1\n",
+			wantErr: "Line 2: cost must be a non-negative number",
+		},
+		{
+			name:    "duplicate break",
+			csv:     "carrier,zone,max_weight,cost\nacme,eu,10,8\nacme,eu,10,9\n",
+			wantErr: "Line 3: duplicate weight break for carrier acme in zone eu",
+		},
+		{
+			name:    "no rates",
+			csv:     "carrier,zone,max_weight,cost\n",
+			wantErr: "Rate table must contain at least one rate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := ParseRatesCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				var appErr *apperrors.Error
+				assert.True(t, errors.As(err, &appErr))
+				assert.Equal(t, tt.wantErr, appErr.Message)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, rates)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package stats
+
+import "time"
+
+// Periods statistics can be grouped by
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Request selects the calculations to aggregate, from the From time up to but excluding
+// the To time. A zero ConfigurationID covers every configuration and zero From or To
+// times leave the range open on that side.
+type Request struct {
+	Period          string
+	BucketSize      int
+	ConfigurationID uint
+	From            time.Time
+	To              time.Time
+}
+
+// OrderQuantityRow counts the calculations of one order quantity bucket in a period
+type OrderQuantityRow struct {
+	Period          time.Time `gorm:"column:period"`
+	ConfigurationID uint      `gorm:"column:configuration_id"`
+	Bucket          int       `gorm:"column:bucket"`
+	Calculations    int       `gorm:"column:calculations"`
+	TotalOverfill   int       `gorm:"column:total_overfill"`
+}
+
+// PackUsageRow counts the packs of one size used in a period
+type PackUsageRow struct {
+	Period          time.Time `gorm:"column:period"`
+	ConfigurationID uint      `gorm:"column:configuration_id"`
+	Size            int       `gorm:"column:size"`
+	Packs           int       `gorm:"column:packs"`
+}
+
+// CacheRow counts the cache lookups and hits in a period
+type CacheRow struct {
+	Period          time.Time `gorm:"column:period"`
+	ConfigurationID uint      `gorm:"column:configuration_id"`
+	Lookups         int       `gorm:"column:lookups"`
+	Hits            int       `gorm:"column:hits"`
+}
+
+// HistogramBucket counts the calculations with an order quantity between From and To inclusive
+type HistogramBucket struct {
+	From         int `json:"from"`
+	To           int `json:"to"`
+	Calculations int `json:"calculations"`
+}
+
+// PackUsage counts the packs of one size used by the calculations
+type PackUsage struct {
+	Size  int `json:"size"`
+	Packs int `json:"packs"`
+}
+
+// Group aggregates the calculations of one configuration in one period
+type Group struct {
+	Period          time.Time         `json:"period"`
+	ConfigurationID uint              `json:"configurationId"`
+	Calculations    int               `json:"calculations"`
+	Histogram       []HistogramBucket `json:"histogram"`
+	TotalOverfill   int               `json:"totalOverfill"`
+	AverageOverfill float64           `json:"averageOverfill"`
+	PackUsage       []PackUsage       `json:"packUsage"`
+	CacheLookups    int               `json:"cacheLookups"`
+	CacheHits       int               `json:"cacheHits"`
+	CacheHitRate    float64           `json:"cacheHitRate"`
+}
+
+// Stats holds the groups of a statistics request ordered by period and configuration
+type Stats struct {
+	Period     string
+	BucketSize int
+	Groups     []Group
+}
+
+// StatsAPIRequest represents the query parameters of a statistics request.
+// From and To are inclusive dates.
+type StatsAPIRequest struct {
+	Period          string    `form:"period"`
+	BucketSize      int       `form:"bucketSize"`
+	ConfigurationID uint      `form:"configurationId"`
+	From            time.Time `form:"from" time_format:"2006-01-02"`
+	To              time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// StatsAPIResponse represents an API response for a statistics request
+type StatsAPIResponse struct {
+	Period     string  `json:"period"`
+	BucketSize int     `json:"bucketSize"`
+	Groups     []Group `json:"groups"`
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// GetStats returns the calculation statistics grouped by period and configuration
+func (h *Handler) GetStats(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*StatsAPIRequest)
+
+	statsRequest := Request{
+		Period:          request.Period,
+		BucketSize:      request.BucketSize,
+		ConfigurationID: request.ConfigurationID,
+		From:            request.From,
+	}
+	// The to date is inclusive, so the range ends at the start of the next day
+	if !request.To.IsZero() {
+		statsRequest.To = request.To.AddDate(0, 0, 1)
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), statsRequest)
+	if err != nil {
+		c.Error(errors.Wrap("Failed to retrieve statistics", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, StatsAPIResponse{
+		Period:     stats.Period,
+		BucketSize: stats.BucketSize,
+		Groups:     stats.Groups,
+	})
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Stats(ctx context.Context, request Request) (*Stats, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Stats), args.Error(1)
+}
+
+func (m *MockService) Refresh(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestHandler_GetStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+	groups := []Group{{Period: from, ConfigurationID: 1, Calculations: 2}}
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+		wantGroups     []Group
+	}{
+		{
+			name: "success case with an inclusive to date",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &StatsAPIRequest{Period: PeriodMonth, BucketSize: 10, ConfigurationID: 1, From: from, To: to})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Stats", mock.Anything, Request{
+					Period:          PeriodMonth,
+					BucketSize:      10,
+					ConfigurationID: 1,
+					From:            from,
+					To:              time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(&Stats{Period: PeriodMonth, BucketSize: 10, Groups: groups}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantGroups:     groups,
+		},
+		{
+			name: "success case with an open range",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &StatsAPIRequest{Period: PeriodDay, BucketSize: 1})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Stats", mock.Anything, Request{Period: PeriodDay, BucketSize: 1}).
+					Return(&Stats{Period: PeriodDay, BucketSize: 1, Groups: []Group{}}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantGroups:     []Group{},
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &StatsAPIRequest{Period: PeriodDay, BucketSize: 1})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Stats", mock.Anything, mock.Anything).Return(nil, errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).GetStats(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantGroups != nil {
+				var got StatsAPIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.wantGroups, got.Groups)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
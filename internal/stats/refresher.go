@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Refresher periodically refreshes the statistics rollups in the background
+type Refresher struct {
+	logger   *zap.Logger
+	service  Service
+	interval time.Duration
+}
+
+func NewRefresher(logger *zap.Logger, service Service, interval time.Duration) *Refresher {
+	return &Refresher{
+		logger:   logger,
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Run refreshes the rollups every interval until the context is cancelled
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+func (r *Refresher) refresh(ctx context.Context) {
+	if err := r.service.Refresh(ctx); err != nil {
+		r.logger.Error("Failed to refresh statistics", zap.Error(err))
+	}
+}
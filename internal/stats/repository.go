@@ -0,0 +1,101 @@
+package stats
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// rollups lists the materialised views the statistics are served from, in refresh order
+var rollups = []string{
+	"order_calculation_daily_stats",
+	"order_calculation_daily_pack_usage",
+	"calculation_cache_daily_stats",
+}
+
+// Repository defines the interface for reading the statistics rollups. The statistics
+// cover the tenant of the context, while Refresh recomputes the rollups of every tenant.
+type Repository interface {
+	OrderQuantities(ctx context.Context, request Request) ([]OrderQuantityRow, error)
+	PackUsage(ctx context.Context, request Request) ([]PackUsageRow, error)
+	CacheLookups(ctx context.Context, request Request) ([]CacheRow, error)
+	Refresh(ctx context.Context) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) OrderQuantities(ctx context.Context, request Request) ([]OrderQuantityRow, error) {
+	var rows []OrderQuantityRow
+	err := r.rollup(ctx, "order_calculation_daily_stats", request).
+		Select("date_trunc(?, day) AS period, configuration_id, (order_quantity / ?) * ? AS bucket, SUM(calculations) AS calculations, SUM(total_overfill) AS total_overfill",
+			request.Period, request.BucketSize, request.BucketSize).
+		Group("1, 2, 3").
+		Order("1, 2, 3").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return rows, nil
+}
+
+func (r *gormRepository) PackUsage(ctx context.Context, request Request) ([]PackUsageRow, error) {
+	var rows []PackUsageRow
+	err := r.rollup(ctx, "order_calculation_daily_pack_usage", request).
+		Select("date_trunc(?, day) AS period, configuration_id, size, SUM(packs) AS packs", request.Period).
+		Group("1, 2, 3").
+		Order("1, 2, 3").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return rows, nil
+}
+
+func (r *gormRepository) CacheLookups(ctx context.Context, request Request) ([]CacheRow, error) {
+	var rows []CacheRow
+	err := r.rollup(ctx, "calculation_cache_daily_stats", request).
+		Select("date_trunc(?, day) AS period, configuration_id, SUM(lookups) AS lookups, SUM(hits) AS hits", request.Period).
+		Group("1, 2").
+		Order("1, 2").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return rows, nil
+}
+
+// Refresh recomputes the rollups without blocking concurrent reads
+func (r *gormRepository) Refresh(ctx context.Context) error {
+	for _, view := range rollups {
+		if err := r.db.WithContext(ctx).Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY " + view).Error; err != nil {
+			return postgres.ClassifyError(err)
+		}
+	}
+	return nil
+}
+
+// rollup starts a query on a daily rollup filtered by the tenant of ctx and the
+// configuration and range of the request
+func (r *gormRepository) rollup(ctx context.Context, view string, request Request) *gorm.DB {
+	query := r.db.WithContext(ctx).Table(view).Scopes(tenant.Scope(ctx))
+	if request.ConfigurationID != 0 {
+		query = query.Where("configuration_id = ?", request.ConfigurationID)
+	}
+	if !request.From.IsZero() {
+		query = query.Where("day >= ?", request.From)
+	}
+	if !request.To.IsZero() {
+		query = query.Where("day < ?", request.To)
+	}
+	return query
+}
@@ -0,0 +1,159 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+func TestOrderQuantities(t *testing.T) {
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	t.Run("filtered by configuration and range", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		request := Request{
+			Period:          PeriodWeek,
+			BucketSize:      100,
+			ConfigurationID: 2,
+			From:            day,
+			To:              day.AddDate(0, 0, 7),
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT date_trunc($1, day) AS period, configuration_id, (order_quantity / $2) * $3 AS bucket, SUM(calculations) AS calculations, SUM(total_overfill) AS total_overfill FROM "order_calculation_daily_stats" WHERE configuration_id = $4 AND day >= $5 AND day < $6 AND tenant_id = $7 GROUP BY 1, 2, 3 ORDER BY 1, 2, 3`)).
+			WithArgs(PeriodWeek, 100, 100, 2, request.From, request.To, "retail").
+			WillReturnRows(sqlmock.NewRows([]string{"period", "configuration_id", "bucket", "calculations", "total_overfill"}).
+				AddRow(day, 2, 200, 3, 120))
+
+		rows, err := repo.OrderQuantities(tenant.NewContext(context.Background(), "retail"), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []OrderQuantityRow{{Period: day, ConfigurationID: 2, Bucket: 200, Calculations: 3, TotalOverfill: 120}}, rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM "order_calculation_daily_stats" WHERE tenant_id = $4 GROUP BY 1, 2, 3`)).
+			WillReturnError(errors.New("database error"))
+
+		rows, err := repo.OrderQuantities(context.Background(), Request{Period: PeriodDay, BucketSize: 1})
+
+		assert.Error(t, err)
+		assert.Nil(t, rows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPackUsage(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT date_trunc($1, day) AS period, configuration_id, size, SUM(packs) AS packs FROM "order_calculation_daily_pack_usage" WHERE tenant_id = $2 GROUP BY 1, 2, 3 ORDER BY 1, 2, 3`)).
+		WithArgs(PeriodMonth, tenant.Default).
+		WillReturnRows(sqlmock.NewRows([]string{"period", "configuration_id", "size", "packs"}).
+			AddRow(day, 1, 250, 4).
+			AddRow(day, 1, 500, 2))
+
+	rows, err := repo.PackUsage(context.Background(), Request{Period: PeriodMonth, BucketSize: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PackUsageRow{
+		{Period: day, ConfigurationID: 1, Size: 250, Packs: 4},
+		{Period: day, ConfigurationID: 1, Size: 500, Packs: 2},
+	}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCacheLookups(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT date_trunc($1, day) AS period, configuration_id, SUM(lookups) AS lookups, SUM(hits) AS hits FROM "calculation_cache_daily_stats" WHERE configuration_id = $2 AND tenant_id = $3 GROUP BY 1, 2 ORDER BY 1, 2`)).
+		WithArgs(PeriodDay, 1, tenant.Default).
+		WillReturnRows(sqlmock.NewRows([]string{"period", "configuration_id", "lookups", "hits"}).
+			AddRow(day, 1, 8, 6))
+
+	rows, err := repo.CacheLookups(context.Background(), Request{Period: PeriodDay, BucketSize: 1, ConfigurationID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []CacheRow{{Period: day, ConfigurationID: 1, Lookups: 8, Hits: 6}}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefresh(t *testing.T) {
+	t.Run("refreshes every rollup", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		for _, view := range rollups {
+			mock.ExpectExec(regexp.QuoteMeta("REFRESH MATERIALIZED VIEW CONCURRENTLY " + view)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+
+		err := repo.Refresh(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectExec(regexp.QuoteMeta("REFRESH MATERIALIZED VIEW CONCURRENTLY " + rollups[0])).
+			WillReturnError(errors.New("database error"))
+
+		err := repo.Refresh(context.Background())
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
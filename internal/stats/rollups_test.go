@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// setupDatabase migrates the database of TEST_DATABASE_URL and skips the test without one
+func setupDatabase(t *testing.T) *gorm.DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	migrator, err := migrate.New("file://../../migrations", url)
+	require.NoError(t, err)
+	defer migrator.Close()
+	if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+
+	db, err := postgres.NewConnection(url)
+	require.NoError(t, err)
+	return db
+}
+
+func TestRollups_AmendedOrder(t *testing.T) {
+	db := setupDatabase(t)
+
+	tenantID := fmt.Sprintf("stats-%d", time.Now().UnixNano())
+	ctx := tenant.NewContext(context.Background(), tenantID)
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM order_calculations WHERE tenant_id = ?", tenantID)
+		db.Exec("DELETE FROM pack_configurations WHERE tenant_id = ?", tenantID)
+		db.Exec("REFRESH MATERIALIZED VIEW order_calculation_daily_stats")
+		db.Exec("REFRESH MATERIALIZED VIEW order_calculation_daily_pack_usage")
+	})
+
+	var configID uint
+	require.NoError(t, db.Raw(`INSERT INTO pack_configurations (pack_sizes, signature, active, tenant_id) VALUES ('{500,1000}', ?, true, ?) RETURNING id`,
+		tenantID, tenantID).Scan(&configID).Error)
+
+	// An order of 1000 packed as 1 x 1000, then amended to 1300 by adding 1 x 500
+	var originalID uint
+	require.NoError(t, db.Raw(`INSERT INTO order_calculations (order_quantity, min_quantity, result, total_items, total_packs, configuration_id, timestamp, tenant_id)
+		VALUES (1000, 1000, '[{"size":1000,"quantity":1}]', 1000, 1, ?, ?, ?) RETURNING id`,
+		configID, day.Add(time.Hour), tenantID).Scan(&originalID).Error)
+	require.NoError(t, db.Exec(`INSERT INTO order_calculations (order_quantity, min_quantity, result, total_items, total_packs, configuration_id, timestamp, tenant_id, mode, amends_id)
+		VALUES (1300, 1300, '[{"size":1000,"quantity":1},{"size":500,"quantity":1}]', 1500, 2, ?, ?, ?, 'amendment', ?)`,
+		configID, day.Add(2*time.Hour), tenantID, originalID).Error)
+
+	repo := NewRepository(db)
+	require.NoError(t, repo.Refresh(ctx))
+	request := Request{Period: PeriodDay, BucketSize: 100}
+
+	quantities, err := repo.OrderQuantities(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, quantities, 1)
+	assert.Equal(t, 1300, quantities[0].Bucket)
+	assert.Equal(t, 1, quantities[0].Calculations)
+	assert.Equal(t, 200, quantities[0].TotalOverfill)
+
+	usage, err := repo.PackUsage(ctx, request)
+	require.NoError(t, err)
+	packs := make(map[int]int)
+	for _, row := range usage {
+		packs[row.Size] += row.Packs
+	}
+	assert.Equal(t, map[int]int{500: 1, 1000: 1}, packs)
+}
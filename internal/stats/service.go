@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Stats(ctx context.Context, request Request) (*Stats, error)
+	Refresh(ctx context.Context) error
+}
+
+type service struct {
+	logger *zap.Logger
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// groupKey identifies the group of one configuration in one period
+type groupKey struct {
+	period          time.Time
+	configurationID uint
+}
+
+// Stats aggregates the calculations of the request into groups per period and configuration
+func (s *service) Stats(ctx context.Context, request Request) (*Stats, error) {
+	quantities, err := s.repo.OrderQuantities(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.repo.PackUsage(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	lookups, err := s.repo.CacheLookups(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Period:     request.Period,
+		BucketSize: request.BucketSize,
+		Groups:     mergeGroups(request.BucketSize, quantities, usage, lookups),
+	}, nil
+}
+
+// Refresh recomputes the rollups the statistics are served from
+func (s *service) Refresh(ctx context.Context) error {
+	return s.repo.Refresh(ctx)
+}
+
+// mergeGroups combines the rows of the rollups into one group per period and configuration.
+// A period can have cache lookups without served orders, such as hits the overfill policy
+// rejected, so groups are created from whichever rollup mentions them first.
+func mergeGroups(bucketSize int, quantities []OrderQuantityRow, usage []PackUsageRow, lookups []CacheRow) []Group {
+	groups := []Group{}
+	index := make(map[groupKey]int)
+	group := func(period time.Time, configurationID uint) *Group {
+		key := groupKey{period: period.UTC(), configurationID: configurationID}
+		if i, ok := index[key]; ok {
+			return &groups[i]
+		}
+		index[key] = len(groups)
+		groups = append(groups, Group{
+			Period:          key.period,
+			ConfigurationID: configurationID,
+			Histogram:       []HistogramBucket{},
+			PackUsage:       []PackUsage{},
+		})
+		return &groups[len(groups)-1]
+	}
+
+	for _, row := range quantities {
+		g := group(row.Period, row.ConfigurationID)
+		g.Calculations += row.Calculations
+		g.TotalOverfill += row.TotalOverfill
+		g.Histogram = append(g.Histogram, HistogramBucket{
+			From:         row.Bucket,
+			To:           row.Bucket + bucketSize - 1,
+			Calculations: row.Calculations,
+		})
+	}
+	for _, row := range usage {
+		g := group(row.Period, row.ConfigurationID)
+		g.PackUsage = append(g.PackUsage, PackUsage{Size: row.Size, Packs: row.Packs})
+	}
+	for _, row := range lookups {
+		g := group(row.Period, row.ConfigurationID)
+		g.CacheLookups += row.Lookups
+		g.CacheHits += row.Hits
+	}
+
+	for i := range groups {
+		g := &groups[i]
+		if g.Calculations > 0 {
+			g.AverageOverfill = float64(g.TotalOverfill) / float64(g.Calculations)
+		}
+		if g.CacheLookups > 0 {
+			g.CacheHitRate = float64(g.CacheHits) / float64(g.CacheLookups)
+		}
+	}
+
+	sortGroups(groups)
+	return groups
+}
+
+// sortGroups orders groups by period, then configuration
+func sortGroups(groups []Group) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		if !groups[i].Period.Equal(groups[j].Period) {
+			return groups[i].Period.Before(groups[j].Period)
+		}
+		return groups[i].ConfigurationID < groups[j].ConfigurationID
+	})
+}
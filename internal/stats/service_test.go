@@ -0,0 +1,156 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) OrderQuantities(ctx context.Context, request Request) ([]OrderQuantityRow, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]OrderQuantityRow), args.Error(1)
+}
+
+func (m *MockRepository) PackUsage(ctx context.Context, request Request) ([]PackUsageRow, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]PackUsageRow), args.Error(1)
+}
+
+func (m *MockRepository) CacheLookups(ctx context.Context, request Request) ([]CacheRow, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CacheRow), args.Error(1)
+}
+
+func (m *MockRepository) Refresh(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestService_Stats(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	nextMonday := monday.AddDate(0, 0, 7)
+	request := Request{Period: PeriodWeek, BucketSize: 100}
+
+	t.Run("merges rollups per period and configuration", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("OrderQuantities", mock.Anything, request).Return([]OrderQuantityRow{
+			{Period: monday, ConfigurationID: 1, Bucket: 200, Calculations: 3, TotalOverfill: 150},
+			{Period: monday, ConfigurationID: 1, Bucket: 500, Calculations: 1, TotalOverfill: 50},
+			{Period: monday, ConfigurationID: 2, Bucket: 0, Calculations: 2, TotalOverfill: 0},
+		}, nil)
+		mockRepo.On("PackUsage", mock.Anything, request).Return([]PackUsageRow{
+			{Period: monday, ConfigurationID: 1, Size: 250, Packs: 6},
+			{Period: monday, ConfigurationID: 1, Size: 500, Packs: 1},
+			{Period: monday, ConfigurationID: 2, Size: 23, Packs: 4},
+		}, nil)
+		mockRepo.On("CacheLookups", mock.Anything, request).Return([]CacheRow{
+			{Period: monday, ConfigurationID: 1, Lookups: 8, Hits: 6},
+			{Period: nextMonday, ConfigurationID: 1, Lookups: 2, Hits: 2},
+		}, nil)
+
+		got, err := NewService(zap.NewNop(), mockRepo).Stats(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Stats{
+			Period:     PeriodWeek,
+			BucketSize: 100,
+			Groups: []Group{
+				{
+					Period:          monday,
+					ConfigurationID: 1,
+					Calculations:    4,
+					Histogram: []HistogramBucket{
+						{From: 200, To: 299, Calculations: 3},
+						{From: 500, To: 599, Calculations: 1},
+					},
+					TotalOverfill:   200,
+					AverageOverfill: 50,
+					PackUsage:       []PackUsage{{Size: 250, Packs: 6}, {Size: 500, Packs: 1}},
+					CacheLookups:    8,
+					CacheHits:       6,
+					CacheHitRate:    0.75,
+				},
+				{
+					Period:          monday,
+					ConfigurationID: 2,
+					Calculations:    2,
+					Histogram:       []HistogramBucket{{From: 0, To: 99, Calculations: 2}},
+					PackUsage:       []PackUsage{{Size: 23, Packs: 4}},
+				},
+				{
+					Period:          nextMonday,
+					ConfigurationID: 1,
+					Histogram:       []HistogramBucket{},
+					PackUsage:       []PackUsage{},
+					CacheLookups:    2,
+					CacheHits:       2,
+					CacheHitRate:    1,
+				},
+			},
+		}, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("OrderQuantities", mock.Anything, request).Return([]OrderQuantityRow{}, nil)
+		mockRepo.On("PackUsage", mock.Anything, request).Return(nil, errors.New("db error"))
+
+		got, err := NewService(zap.NewNop(), mockRepo).Stats(context.Background(), request)
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestRefresher_Run(t *testing.T) {
+	mockRepo := new(MockRepository)
+	refreshed := make(chan struct{}, 1)
+	mockRepo.On("Refresh", mock.Anything).
+		Run(func(args mock.Arguments) {
+			select {
+			case refreshed <- struct{}{}:
+			default:
+			}
+		}).
+		Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewRefresher(zap.NewNop(), NewService(zap.NewNop(), mockRepo), time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("refresher did not refresh the rollups")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refresher did not stop after cancellation")
+	}
+}
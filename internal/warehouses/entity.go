@@ -0,0 +1,20 @@
+package warehouses
+
+// Warehouse represents a stocking location with the number of packs it holds per size
+type Warehouse struct {
+	ID        uint        `gorm:"column:id;primarykey;autoIncrement" json:"id"`
+	TenantID  string      `gorm:"column:tenant_id;uniqueIndex:idx_warehouses_tenant_name;not null" json:"-"`
+	Name      string      `gorm:"column:name;uniqueIndex:idx_warehouses_tenant_name;not null" json:"name"`
+	Inventory map[int]int `gorm:"column:inventory;serializer:json;not null" json:"inventory"`
+}
+
+// WarehouseAPIRequest represents an API request to create or update a warehouse
+type WarehouseAPIRequest struct {
+	Name      string      `json:"name"`
+	Inventory map[int]int `json:"inventory"`
+}
+
+// WarehousesAPIResponse represents an API response listing warehouses
+type WarehousesAPIResponse struct {
+	Warehouses []Warehouse `json:"warehouses"`
+}
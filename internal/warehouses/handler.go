@@ -0,0 +1,54 @@
+package warehouses
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/pkg/errors"
+)
+
+type Handler struct {
+	logger  *zap.Logger
+	service Service
+}
+
+func NewHandler(logger *zap.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// ListWarehouses returns every warehouse with its inventory
+func (h *Handler) ListWarehouses(c *gin.Context) {
+	warehouses, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.Error(errors.Wrap("Failed to retrieve warehouses", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, WarehousesAPIResponse{Warehouses: warehouses})
+}
+
+// SaveWarehouse creates a warehouse or replaces the inventory of an existing one
+func (h *Handler) SaveWarehouse(c *gin.Context) {
+	payload, exists := c.Get("payload")
+	if !exists {
+		c.Error(errors.NewInternalError("Failed to retrieve payload from context"))
+		return
+	}
+	request := payload.(*WarehouseAPIRequest)
+
+	warehouse := &Warehouse{
+		Name:      request.Name,
+		Inventory: request.Inventory,
+	}
+	if err := h.service.Save(c.Request.Context(), warehouse); err != nil {
+		c.Error(errors.Wrap("Failed to save warehouse", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
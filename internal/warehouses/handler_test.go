@@ -0,0 +1,120 @@
+package warehouses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Save(ctx context.Context, warehouse *Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockService) List(ctx context.Context) ([]Warehouse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Warehouse), args.Error(1)
+}
+
+func TestHandler_SaveWarehouse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		setupContext   func(*gin.Context)
+		mockSetup      func(*MockService)
+		wantStatusCode int
+	}{
+		{
+			name: "success case",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &WarehouseAPIRequest{Name: "north", Inventory: map[int]int{250: 4}})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Save", mock.Anything, &Warehouse{Name: "north", Inventory: map[int]int{250: 4}}).Return(nil)
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing payload",
+			setupContext:   func(c *gin.Context) {},
+			mockSetup:      func(m *MockService) {},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "service error",
+			setupContext: func(c *gin.Context) {
+				c.Set("payload", &WarehouseAPIRequest{Name: "north", Inventory: map[int]int{}})
+			},
+			mockSetup: func(m *MockService) {
+				m.On("Save", mock.Anything, mock.Anything).Return(errors.New("service error"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/warehouses", nil)
+			mockService := new(MockService)
+			tt.mockSetup(mockService)
+
+			tt.setupContext(c)
+
+			NewHandler(zap.NewNop(), mockService).SaveWarehouse(c)
+			renderErrors(c)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ListWarehouses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/warehouses", nil)
+
+	stock := []Warehouse{{ID: 1, Name: "north", Inventory: map[int]int{1000: 1}}}
+	mockService := new(MockService)
+	mockService.On("List", mock.Anything).Return(stock, nil)
+
+	NewHandler(zap.NewNop(), mockService).ListWarehouses(c)
+	renderErrors(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got WarehousesAPIResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, stock, got.Warehouses)
+	mockService.AssertExpectations(t)
+}
+
+// renderErrors responds with the error the handler added to the context, as the error
+// middleware does for the legacy routes
+func renderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 {
+		return
+	}
+	appErr := apperrors.Wrap("Internal server error", c.Errors.Last().Err)
+	c.JSON(apperrors.HTTPStatus(appErr.Type), appErr)
+}
@@ -0,0 +1,99 @@
+package warehouses
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/postgres"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// Repository defines the interface for warehouse persistence operations. Every
+// operation acts on the warehouses of the tenant of the context.
+type Repository interface {
+	Create(ctx context.Context, warehouse *Warehouse) error
+	Update(ctx context.Context, warehouse *Warehouse) error
+	GetByName(ctx context.Context, name string) (*Warehouse, error)
+	List(ctx context.Context) ([]Warehouse, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new GORM-based repository
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// Create stores the warehouse and records its creation in the audit trail
+func (r *gormRepository) Create(ctx context.Context, warehouse *Warehouse) error {
+	warehouse.TenantID = tenant.FromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(warehouse).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionWarehouseCreate,
+			ResourceType: audit_events.ResourceWarehouse,
+			ResourceID:   strconv.FormatUint(uint64(warehouse.ID), 10),
+			After:        warehouse,
+		})
+	})
+	return postgres.ClassifyError(err)
+}
+
+// Update saves the warehouse and records the change in the audit trail. The stored
+// warehouse is locked for the duration of the transaction.
+func (r *gormRepository) Update(ctx context.Context, warehouse *Warehouse) error {
+	warehouse.TenantID = tenant.FromContext(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before *Warehouse
+		var stored Warehouse
+		err := tx.Scopes(tenant.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).First(&stored, warehouse.ID).Error
+		switch {
+		case err == nil:
+			before = &stored
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		if err := tx.Save(warehouse).Error; err != nil {
+			return err
+		}
+		return audit_events.Record(ctx, tx, audit_events.Change{
+			Action:       audit_events.ActionWarehouseUpdate,
+			ResourceType: audit_events.ResourceWarehouse,
+			ResourceID:   strconv.FormatUint(uint64(warehouse.ID), 10),
+			Before:       before,
+			After:        warehouse,
+		})
+	})
+	return postgres.ClassifyError(err)
+}
+
+func (r *gormRepository) GetByName(ctx context.Context, name string) (*Warehouse, error) {
+	var warehouse Warehouse
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Where("name = ?", name).First(&warehouse).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, postgres.ClassifyError(err)
+	}
+	return &warehouse, nil
+}
+
+func (r *gormRepository) List(ctx context.Context) ([]Warehouse, error) {
+	var warehouses []Warehouse
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Order("id").Find(&warehouses).Error
+	if err != nil {
+		return nil, postgres.ClassifyError(err)
+	}
+	return warehouses, nil
+}
@@ -0,0 +1,191 @@
+package warehouses
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+	// Create a new SQL mock
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Failed to create sqlmock")
+
+	// Create GORM dialector using the mock DB
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+
+	// Open GORM DB with the dialector
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err, "Failed to open GORM DB with mock")
+
+	return db, mock, sqlDB
+}
+
+// expectAuditEvent expects an event to be appended to the audit trail with the states
+func expectAuditEvent(mock sqlmock.Sqlmock, action, resourceID string, before, after driver.Value) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "audit_events"`)).
+		WithArgs(sqlmock.AnyArg(), action, audit_events.ResourceWarehouse, resourceID, "anonymous", "", "", before, after, sqlmock.AnyArg(), "previous", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func TestCreate(t *testing.T) {
+	db, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	repo := NewRepository(db)
+	warehouse := &Warehouse{Name: "north", Inventory: map[int]int{250: 4}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "warehouses" ("tenant_id","name","inventory") VALUES ($1,$2,$3) RETURNING "id"`)).
+		WithArgs("retail", "north", `{"250":4}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	expectAuditEvent(mock, audit_events.ActionWarehouseCreate, "3", nil, `{"id":3,"name":"north","inventory":{"250":4}}`)
+	mock.ExpectCommit()
+
+	err := repo.Create(tenant.NewContext(context.Background(), "retail"), warehouse)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), warehouse.ID)
+	assert.Equal(t, "retail", warehouse.TenantID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		warehouse := &Warehouse{ID: 3, Name: "north", Inventory: map[int]int{250: 2}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE "warehouses"."id" = $1 AND tenant_id = $2 ORDER BY "warehouses"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(3, tenant.Default, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}).AddRow(3, tenant.Default, "north", `{"250":4}`))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "warehouses" SET "tenant_id"=$1,"name"=$2,"inventory"=$3 WHERE "id" = $4`)).
+			WithArgs(tenant.Default, "north", `{"250":2}`, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectAuditEvent(mock, audit_events.ActionWarehouseUpdate, "3",
+			`{"id":3,"name":"north","inventory":{"250":4}}`, `{"id":3,"name":"north","inventory":{"250":2}}`)
+		mock.ExpectCommit()
+
+		err := repo.Update(context.Background(), warehouse)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+		warehouse := &Warehouse{ID: 3, Name: "north", Inventory: map[int]int{250: 2}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE "warehouses"."id" = $1 AND tenant_id = $2 ORDER BY "warehouses"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(3, tenant.Default, 1).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := repo.Update(context.Background(), warehouse)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetByName(t *testing.T) {
+	t.Run("warehouse found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE name = $1 AND tenant_id = $2 ORDER BY "warehouses"."id" LIMIT $3`)).
+			WithArgs("north", "retail", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name", "inventory"}).AddRow(3, "retail", "north", `{"250":4}`))
+
+		warehouse, err := repo.GetByName(tenant.NewContext(context.Background(), "retail"), "north")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Warehouse{ID: 3, TenantID: "retail", Name: "north", Inventory: map[int]int{250: 4}}, warehouse)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("warehouse not found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE name = $1 AND tenant_id = $2 ORDER BY "warehouses"."id" LIMIT $3`)).
+			WithArgs("south", tenant.Default, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		warehouse, err := repo.GetByName(context.Background(), "south")
+
+		assert.NoError(t, err)
+		assert.Nil(t, warehouse)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Run("warehouses found", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE tenant_id = $1 ORDER BY id`)).
+			WithArgs(tenant.Default).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "inventory"}).
+				AddRow(1, "north", `{"1000":1}`).
+				AddRow(2, "south", `{"250":1}`))
+
+		warehouses, err := repo.List(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, warehouses, 2)
+		assert.Equal(t, map[int]int{250: 1}, warehouses[1].Inventory)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, sqlDB := setupTestDB(t)
+		defer sqlDB.Close()
+
+		repo := NewRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE tenant_id = $1 ORDER BY id`)).
+			WillReturnError(errors.New("database error"))
+
+		warehouses, err := repo.List(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, warehouses)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,42 @@
+package warehouses
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Save(ctx context.Context, warehouse *Warehouse) error
+	List(ctx context.Context) ([]Warehouse, error)
+}
+
+type service struct {
+	logger *zap.Logger
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, repo Repository) Service {
+	return &service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Save creates the warehouse, or replaces the inventory of the warehouse with the same name
+func (s *service) Save(ctx context.Context, warehouse *Warehouse) error {
+	existing, err := s.repo.GetByName(ctx, warehouse.Name)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return s.repo.Create(ctx, warehouse)
+	}
+
+	warehouse.ID = existing.ID
+	return s.repo.Update(ctx, warehouse)
+}
+
+func (s *service) List(ctx context.Context) ([]Warehouse, error) {
+	return s.repo.List(ctx)
+}
@@ -0,0 +1,96 @@
+package warehouses
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockRepository is a mock implementation of Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, warehouse *Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Update(ctx context.Context, warehouse *Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByName(ctx context.Context, name string) (*Warehouse, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Warehouse), args.Error(1)
+}
+
+func (m *MockRepository) List(ctx context.Context) ([]Warehouse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Warehouse), args.Error(1)
+}
+
+func TestService_Save(t *testing.T) {
+	tests := []struct {
+		name      string
+		warehouse *Warehouse
+		mockSetup func(*MockRepository)
+		wantID    uint
+		wantErr   bool
+	}{
+		{
+			name:      "new warehouse",
+			warehouse: &Warehouse{Name: "north", Inventory: map[int]int{250: 4}},
+			mockSetup: func(repo *MockRepository) {
+				repo.On("GetByName", mock.Anything, "north").Return(nil, nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*warehouses.Warehouse")).Return(nil)
+			},
+		},
+		{
+			name:      "existing warehouse",
+			warehouse: &Warehouse{Name: "north", Inventory: map[int]int{250: 2}},
+			mockSetup: func(repo *MockRepository) {
+				repo.On("GetByName", mock.Anything, "north").Return(&Warehouse{ID: 3, Name: "north"}, nil)
+				repo.On("Update", mock.Anything, mock.AnythingOfType("*warehouses.Warehouse")).Return(nil)
+			},
+			wantID: 3,
+		},
+		{
+			name:      "lookup error",
+			warehouse: &Warehouse{Name: "north"},
+			mockSetup: func(repo *MockRepository) {
+				repo.On("GetByName", mock.Anything, "north").Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tt.mockSetup(mockRepo)
+
+			s := NewService(zap.NewNop(), mockRepo)
+			err := s.Save(context.Background(), tt.warehouse)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, tt.warehouse.ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
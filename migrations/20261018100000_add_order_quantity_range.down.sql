@@ -0,0 +1,6 @@
+-- Drop quantity window index
+DROP INDEX IF EXISTS idx_order_calculations_quantity_range;
+
+-- Drop quantity window columns
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS max_quantity;
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS min_quantity;
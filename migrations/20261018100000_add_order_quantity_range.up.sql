@@ -0,0 +1,10 @@
+-- Add accepted quantity window to order_calculations
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS min_quantity INTEGER;
+UPDATE order_calculations SET min_quantity = order_quantity WHERE min_quantity IS NULL;
+ALTER TABLE order_calculations ALTER COLUMN min_quantity SET NOT NULL;
+
+-- A zero max_quantity means the window has no upper bound
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS max_quantity INTEGER NOT NULL DEFAULT 0;
+
+-- Create index on the quantity window for range lookups
+CREATE INDEX IF NOT EXISTS idx_order_calculations_quantity_range ON order_calculations(configuration_id, min_quantity, max_quantity);
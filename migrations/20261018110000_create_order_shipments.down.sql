@@ -0,0 +1,5 @@
+-- Drop order_shipments table
+DROP TABLE IF EXISTS order_shipments;
+
+-- Drop calculation mode column
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS mode;
@@ -0,0 +1,17 @@
+-- Record how each calculation was produced, only standard calculations are reused as a cache
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS mode TEXT NOT NULL DEFAULT 'standard';
+
+-- Create order_shipments table
+CREATE TABLE IF NOT EXISTS order_shipments (
+    id SERIAL PRIMARY KEY,
+    calculation_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    result JSON NOT NULL,
+    total_items INTEGER NOT NULL,
+    total_packs INTEGER NOT NULL,
+    FOREIGN KEY (calculation_id) REFERENCES order_calculations(id) ON DELETE CASCADE
+);
+
+-- Create index on calculation_id for better query performance
+CREATE INDEX IF NOT EXISTS idx_order_shipments_calculation_id ON order_shipments(calculation_id);
@@ -0,0 +1,5 @@
+-- Drop amends index
+DROP INDEX IF EXISTS idx_order_calculations_amends_id;
+
+-- Drop amends column
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS amends_id;
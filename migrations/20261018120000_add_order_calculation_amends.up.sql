@@ -0,0 +1,5 @@
+-- Link amended calculations to the calculation they amend
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS amends_id INTEGER REFERENCES order_calculations(id);
+
+-- Create index on amends_id for better query performance
+CREATE INDEX IF NOT EXISTS idx_order_calculations_amends_id ON order_calculations(amends_id);
@@ -0,0 +1,2 @@
+-- Drop nesting rules
+ALTER TABLE pack_configurations DROP COLUMN IF EXISTS nesting;
@@ -0,0 +1,2 @@
+-- Add nesting rules for logistics units such as cases and pallets
+ALTER TABLE pack_configurations ADD COLUMN IF NOT EXISTS nesting JSON;
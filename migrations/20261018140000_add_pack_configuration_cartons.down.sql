@@ -0,0 +1,3 @@
+-- Drop pack specs and carton catalogue
+ALTER TABLE pack_configurations DROP COLUMN IF EXISTS cartons;
+ALTER TABLE pack_configurations DROP COLUMN IF EXISTS pack_specs;
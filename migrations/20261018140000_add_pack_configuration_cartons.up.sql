@@ -0,0 +1,3 @@
+-- Add physical pack specs and the carton catalogue used to plan shipping cartons
+ALTER TABLE pack_configurations ADD COLUMN IF NOT EXISTS pack_specs JSON;
+ALTER TABLE pack_configurations ADD COLUMN IF NOT EXISTS cartons JSON;
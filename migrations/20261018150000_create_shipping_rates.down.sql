@@ -0,0 +1,2 @@
+-- Drop shipping_rates table
+DROP TABLE IF EXISTS shipping_rates;
@@ -0,0 +1,12 @@
+-- Create shipping_rates table holding carrier weight breaks per zone
+CREATE TABLE IF NOT EXISTS shipping_rates (
+    id SERIAL PRIMARY KEY,
+    carrier TEXT NOT NULL,
+    zone TEXT NOT NULL,
+    max_weight DOUBLE PRECISION NOT NULL,
+    cost DOUBLE PRECISION NOT NULL,
+    UNIQUE (carrier, zone, max_weight)
+);
+
+-- Create index on zone for better query performance
+CREATE INDEX IF NOT EXISTS idx_shipping_rates_zone ON shipping_rates(zone);
@@ -0,0 +1,5 @@
+-- Drop warehouse sourcing plan column
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS sourcing;
+
+-- Drop warehouses table
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,9 @@
+-- Create warehouses table holding the packs stocked per size
+CREATE TABLE IF NOT EXISTS warehouses (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    inventory JSON NOT NULL
+);
+
+-- Record the warehouse sourcing plan of sourced calculations
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS sourcing JSON;
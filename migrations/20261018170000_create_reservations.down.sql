@@ -0,0 +1,2 @@
+-- Drop reservations table
+DROP TABLE IF EXISTS reservations;
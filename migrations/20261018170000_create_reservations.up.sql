@@ -0,0 +1,13 @@
+-- Create reservations table holding packs of a calculation against warehouse stock
+CREATE TABLE IF NOT EXISTS reservations (
+    id SERIAL PRIMARY KEY,
+    calculation_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    items JSON NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    committed_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (calculation_id) REFERENCES order_calculations(id) ON DELETE CASCADE
+);
+
+-- Create index on status and expiry for the sweeper and availability lookups
+CREATE INDEX IF NOT EXISTS idx_reservations_status_expires_at ON reservations(status, expires_at);
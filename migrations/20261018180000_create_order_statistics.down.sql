@@ -0,0 +1,7 @@
+-- Drop statistics rollups
+DROP MATERIALIZED VIEW IF EXISTS calculation_cache_daily_stats;
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_pack_usage;
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_stats;
+
+-- Drop cache lookups table
+DROP TABLE IF EXISTS calculation_cache_lookups;
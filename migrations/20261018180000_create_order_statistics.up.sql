@@ -0,0 +1,49 @@
+-- Create table recording the outcome of exact order quantity cache lookups
+CREATE TABLE IF NOT EXISTS calculation_cache_lookups (
+    id SERIAL PRIMARY KEY,
+    configuration_id INTEGER NOT NULL,
+    order_quantity INTEGER NOT NULL,
+    hit BOOLEAN NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (configuration_id) REFERENCES pack_configurations(id)
+);
+
+-- Daily rollup of calculations per configuration and order quantity
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_stats AS
+SELECT
+    date_trunc('day', timestamp) AS day,
+    configuration_id,
+    order_quantity,
+    COUNT(*) AS calculations,
+    SUM(GREATEST(total_items - order_quantity, 0)) AS total_overfill
+FROM order_calculations
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_stats
+    ON order_calculation_daily_stats(day, configuration_id, order_quantity);
+
+-- Daily rollup of packs used per configuration and pack size
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_pack_usage AS
+SELECT
+    date_trunc('day', c.timestamp) AS day,
+    c.configuration_id,
+    (pack->>'size')::INTEGER AS size,
+    SUM((pack->>'quantity')::INTEGER) AS packs
+FROM order_calculations c, json_array_elements(c.result) AS pack
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_pack_usage
+    ON order_calculation_daily_pack_usage(day, configuration_id, size);
+
+-- Daily rollup of cache lookups per configuration
+CREATE MATERIALIZED VIEW IF NOT EXISTS calculation_cache_daily_stats AS
+SELECT
+    date_trunc('day', timestamp) AS day,
+    configuration_id,
+    COUNT(*) AS lookups,
+    COUNT(*) FILTER (WHERE hit) AS hits
+FROM calculation_cache_lookups
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calculation_cache_daily_stats
+    ON calculation_cache_daily_stats(day, configuration_id);
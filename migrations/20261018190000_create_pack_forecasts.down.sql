@@ -0,0 +1,6 @@
+-- Drop calculation and cache lookup time indexes
+DROP INDEX IF EXISTS idx_order_calculations_timestamp;
+DROP INDEX IF EXISTS idx_calculation_cache_lookups_timestamp;
+
+-- Drop pack forecasts table
+DROP TABLE IF EXISTS pack_forecasts;
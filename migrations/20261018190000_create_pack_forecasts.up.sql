@@ -0,0 +1,16 @@
+-- Create pack forecasts table holding each run of the replenishment forecast
+CREATE TABLE IF NOT EXISTS pack_forecasts (
+    id SERIAL PRIMARY KEY,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    method TEXT NOT NULL,
+    history_days INTEGER NOT NULL,
+    horizon_days INTEGER NOT NULL,
+    items JSON NOT NULL
+);
+
+-- Create index on generation time for the latest forecast lookup
+CREATE INDEX IF NOT EXISTS idx_pack_forecasts_generated_at ON pack_forecasts(generated_at);
+
+-- Create indexes on calculation and cache lookup time for the usage history of forecasts
+CREATE INDEX IF NOT EXISTS idx_order_calculations_timestamp ON order_calculations(timestamp);
+CREATE INDEX IF NOT EXISTS idx_calculation_cache_lookups_timestamp ON calculation_cache_lookups(timestamp);
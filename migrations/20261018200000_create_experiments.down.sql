@@ -0,0 +1,5 @@
+-- Drop experiment assignments table
+DROP TABLE IF EXISTS experiment_assignments;
+
+-- Drop experiments table
+DROP TABLE IF EXISTS experiments;
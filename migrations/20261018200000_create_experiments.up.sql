@@ -0,0 +1,32 @@
+-- Create experiments table trialling a candidate configuration on a share of the orders
+CREATE TABLE IF NOT EXISTS experiments (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    configuration_id INTEGER NOT NULL,
+    traffic_share DOUBLE PRECISION NOT NULL,
+    status TEXT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    stopped_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (configuration_id) REFERENCES pack_configurations(id)
+);
+
+-- Allow only one running experiment at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_experiments_running ON experiments(status) WHERE status = 'running';
+
+-- Create experiment assignments table recording the arm each order was calculated in
+CREATE TABLE IF NOT EXISTS experiment_assignments (
+    id SERIAL PRIMARY KEY,
+    experiment_id INTEGER NOT NULL,
+    arm TEXT NOT NULL,
+    calculation_id INTEGER NOT NULL,
+    order_reference TEXT NOT NULL,
+    order_quantity INTEGER NOT NULL,
+    total_items INTEGER NOT NULL,
+    total_packs INTEGER NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+    FOREIGN KEY (calculation_id) REFERENCES order_calculations(id) ON DELETE CASCADE
+);
+
+-- Create index on experiment and arm for the results
+CREATE INDEX IF NOT EXISTS idx_experiment_assignments_experiment_arm ON experiment_assignments(experiment_id, arm);
@@ -0,0 +1,11 @@
+-- Restore the cache index without the sizes signature
+DROP INDEX IF EXISTS idx_order_calculations_quantity_range;
+CREATE INDEX IF NOT EXISTS idx_order_calculations_quantity_range ON order_calculations(configuration_id, min_quantity, max_quantity);
+
+-- Drop the customer and sizes signature columns
+ALTER TABLE calculation_cache_lookups DROP COLUMN IF EXISTS sizes_signature;
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS sizes_signature;
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS customer_id;
+
+-- Drop customer profiles table
+DROP TABLE IF EXISTS customer_profiles;
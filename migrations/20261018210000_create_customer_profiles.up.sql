@@ -0,0 +1,29 @@
+-- Create customer profiles table restricting or extending the active pack sizes per customer
+CREATE TABLE IF NOT EXISTS customer_profiles (
+    customer_id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    extra_sizes JSON NOT NULL DEFAULT '[]',
+    excluded_sizes JSON NOT NULL DEFAULT '[]',
+    min_size INTEGER NOT NULL DEFAULT 0,
+    max_size INTEGER NOT NULL DEFAULT 0
+);
+
+-- Record the customer and the set of sizes each calculation was solved with
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS customer_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS sizes_signature TEXT NOT NULL DEFAULT '';
+ALTER TABLE calculation_cache_lookups ADD COLUMN IF NOT EXISTS sizes_signature TEXT NOT NULL DEFAULT '';
+
+-- Existing calculations were solved with all sizes of their configuration
+UPDATE order_calculations c
+SET sizes_signature = encode(sha256(convert_to(array_to_string(ARRAY(SELECT unnest(pc.pack_sizes) ORDER BY 1), ','), 'UTF8')), 'hex')
+FROM pack_configurations pc
+WHERE pc.id = c.configuration_id;
+
+UPDATE calculation_cache_lookups l
+SET sizes_signature = encode(sha256(convert_to(array_to_string(ARRAY(SELECT unnest(pc.pack_sizes) ORDER BY 1), ','), 'UTF8')), 'hex')
+FROM pack_configurations pc
+WHERE pc.id = l.configuration_id;
+
+-- Replace the cache index with one that includes the sizes signature
+DROP INDEX IF EXISTS idx_order_calculations_quantity_range;
+CREATE INDEX IF NOT EXISTS idx_order_calculations_quantity_range ON order_calculations(configuration_id, sizes_signature, min_quantity, max_quantity);
@@ -0,0 +1,2 @@
+-- Drop pack selection rules
+ALTER TABLE pack_configurations DROP COLUMN IF EXISTS rules;
@@ -0,0 +1,2 @@
+-- Add pack selection rules to pack configurations
+ALTER TABLE pack_configurations ADD COLUMN IF NOT EXISTS rules JSON;
@@ -0,0 +1,3 @@
+-- Drop the audit trail
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
@@ -0,0 +1,21 @@
+-- Drop tenants, keeping the configurations and calculations of every tenant
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_order_calculations_tenant_timestamp;
+ALTER TABLE order_calculations DROP COLUMN IF EXISTS tenant_id;
+
+-- Several tenants may hold a configuration with the same signature. The configuration of
+-- the default tenant, or else of the first tenant by name, keeps its signature and the
+-- others are suffixed with their ID, so signatures are unique again.
+UPDATE pack_configurations p
+SET signature = p.signature || ':' || p.id
+FROM (
+    SELECT id,
+           ROW_NUMBER() OVER (PARTITION BY signature ORDER BY tenant_id <> 'default', tenant_id, id) AS rank
+    FROM pack_configurations
+) ranked
+WHERE ranked.id = p.id AND ranked.rank > 1;
+
+DROP INDEX IF EXISTS idx_pack_configurations_tenant_signature;
+ALTER TABLE pack_configurations DROP COLUMN IF EXISTS tenant_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pack_configurations_signature ON pack_configurations(signature);
@@ -0,0 +1,11 @@
+-- Give pack configurations and calculations a tenant. Existing rows belong to the default
+-- tenant, and signatures are only unique within a tenant.
+ALTER TABLE pack_configurations ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_pack_configurations_signature;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pack_configurations_tenant_signature ON pack_configurations(tenant_id, signature);
+
+ALTER TABLE order_calculations ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_order_calculations_tenant_timestamp ON order_calculations(tenant_id, timestamp);
+
+-- Bind API keys to a tenant; existing keys stay unbound
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
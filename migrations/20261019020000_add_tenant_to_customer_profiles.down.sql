@@ -0,0 +1,13 @@
+-- Drop the tenant of customer profiles. A customer known to several tenants keeps the
+-- profile of the default tenant, or else of the first tenant by name.
+DELETE FROM customer_profiles p
+USING (
+    SELECT tenant_id, customer_id,
+           ROW_NUMBER() OVER (PARTITION BY customer_id ORDER BY tenant_id <> 'default', tenant_id) AS rank
+    FROM customer_profiles
+) ranked
+WHERE ranked.tenant_id = p.tenant_id AND ranked.customer_id = p.customer_id AND ranked.rank > 1;
+
+ALTER TABLE customer_profiles DROP CONSTRAINT IF EXISTS customer_profiles_pkey;
+ALTER TABLE customer_profiles DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE customer_profiles ADD PRIMARY KEY (customer_id);
@@ -0,0 +1,5 @@
+-- Give customer profiles a tenant, so each tenant names its own customers. Existing
+-- profiles belong to the default tenant.
+ALTER TABLE customer_profiles ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE customer_profiles DROP CONSTRAINT IF EXISTS customer_profiles_pkey;
+ALTER TABLE customer_profiles ADD PRIMARY KEY (tenant_id, customer_id);
@@ -0,0 +1,17 @@
+-- Drop the tenant of experiments. Only the experiment of the default tenant, or else of
+-- the first tenant by name, keeps running.
+UPDATE experiments e
+SET status = 'stopped', stopped_at = CURRENT_TIMESTAMP
+FROM (
+    SELECT id,
+           ROW_NUMBER() OVER (ORDER BY tenant_id <> 'default', tenant_id) AS rank
+    FROM experiments
+    WHERE status = 'running'
+) ranked
+WHERE ranked.id = e.id AND ranked.rank > 1;
+
+DROP INDEX IF EXISTS idx_experiments_running;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_experiments_running ON experiments(status) WHERE status = 'running';
+
+ALTER TABLE experiment_assignments DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE experiments DROP COLUMN IF EXISTS tenant_id;
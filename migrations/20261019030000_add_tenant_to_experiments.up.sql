@@ -0,0 +1,8 @@
+-- Give experiments and their assignments a tenant, so each tenant runs its own
+-- experiment. Existing experiments belong to the default tenant.
+ALTER TABLE experiments ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE experiment_assignments ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+-- Allow only one running experiment per tenant
+DROP INDEX IF EXISTS idx_experiments_running;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_experiments_running ON experiments(tenant_id) WHERE status = 'running';
@@ -0,0 +1,2 @@
+-- Drop the tenant of reservations
+ALTER TABLE reservations DROP COLUMN IF EXISTS tenant_id;
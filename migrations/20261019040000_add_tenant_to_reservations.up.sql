@@ -0,0 +1,3 @@
+-- Give reservations a tenant, so a tenant only sees and commits its own reservations.
+-- Existing reservations belong to the default tenant.
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
@@ -0,0 +1,15 @@
+-- Drop the tenant of warehouses. A name used by several tenants is suffixed with the
+-- warehouse ID, except for the warehouse of the default tenant, or else of the first
+-- tenant by name, so the names are unique again.
+UPDATE warehouses w
+SET name = w.name || ':' || w.id
+FROM (
+    SELECT id,
+           ROW_NUMBER() OVER (PARTITION BY name ORDER BY tenant_id <> 'default', tenant_id) AS rank
+    FROM warehouses
+) ranked
+WHERE ranked.id = w.id AND ranked.rank > 1;
+
+DROP INDEX IF EXISTS idx_warehouses_tenant_name;
+ALTER TABLE warehouses DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE warehouses ADD CONSTRAINT warehouses_name_key UNIQUE (name);
@@ -0,0 +1,5 @@
+-- Give warehouses a tenant, so each tenant stocks and names its own warehouses.
+-- Existing warehouses belong to the default tenant.
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE warehouses DROP CONSTRAINT IF EXISTS warehouses_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouses_tenant_name ON warehouses(tenant_id, name);
@@ -0,0 +1,18 @@
+-- Drop the tenant of shipping rates. A carrier's rate tables are kept only for the
+-- default tenant, or else for the first tenant by name that uploaded them.
+DELETE FROM shipping_rates r
+USING (
+    SELECT carrier, MIN(tenant_id) FILTER (WHERE tenant_id <> 'default') AS first_tenant,
+           BOOL_OR(tenant_id = 'default') AS has_default
+    FROM shipping_rates
+    GROUP BY carrier
+) kept
+WHERE kept.carrier = r.carrier
+  AND r.tenant_id <> CASE WHEN kept.has_default THEN 'default' ELSE kept.first_tenant END;
+
+DROP INDEX IF EXISTS idx_shipping_rates_tenant_zone;
+CREATE INDEX IF NOT EXISTS idx_shipping_rates_zone ON shipping_rates(zone);
+
+ALTER TABLE shipping_rates DROP CONSTRAINT IF EXISTS shipping_rates_tenant_carrier_zone_max_weight_key;
+ALTER TABLE shipping_rates DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE shipping_rates ADD CONSTRAINT shipping_rates_carrier_zone_max_weight_key UNIQUE (carrier, zone, max_weight);
@@ -0,0 +1,9 @@
+-- Give shipping rates a tenant, so each tenant uploads its own carrier rate tables.
+-- Existing rates belong to the default tenant.
+ALTER TABLE shipping_rates ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE shipping_rates DROP CONSTRAINT IF EXISTS shipping_rates_carrier_zone_max_weight_key;
+ALTER TABLE shipping_rates ADD CONSTRAINT shipping_rates_tenant_carrier_zone_max_weight_key UNIQUE (tenant_id, carrier, zone, max_weight);
+
+-- Replace the zone index with one on the tenant and zone
+DROP INDEX IF EXISTS idx_shipping_rates_zone;
+CREATE INDEX IF NOT EXISTS idx_shipping_rates_tenant_zone ON shipping_rates(tenant_id, zone);
@@ -0,0 +1,4 @@
+-- Give audit events a tenant, so each tenant reads the changes made for it. Existing
+-- events belong to the default tenant.
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_audit_events_tenant ON audit_events(tenant_id, id);
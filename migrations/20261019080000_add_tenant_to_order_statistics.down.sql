@@ -0,0 +1,46 @@
+-- Restore the statistics rollups across tenants and drop the tenant of cache lookups
+DROP MATERIALIZED VIEW IF EXISTS calculation_cache_daily_stats;
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_pack_usage;
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_stats;
+
+ALTER TABLE calculation_cache_lookups DROP COLUMN IF EXISTS tenant_id;
+
+-- Daily rollup of calculations per configuration and order quantity
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_stats AS
+SELECT
+    date_trunc('day', timestamp) AS day,
+    configuration_id,
+    order_quantity,
+    COUNT(*) AS calculations,
+    SUM(GREATEST(total_items - order_quantity, 0)) AS total_overfill
+FROM order_calculations
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_stats
+    ON order_calculation_daily_stats(day, configuration_id, order_quantity);
+
+-- Daily rollup of packs used per configuration and pack size
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_pack_usage AS
+SELECT
+    date_trunc('day', c.timestamp) AS day,
+    c.configuration_id,
+    (pack->>'size')::INTEGER AS size,
+    SUM((pack->>'quantity')::INTEGER) AS packs
+FROM order_calculations c, json_array_elements(c.result) AS pack
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_pack_usage
+    ON order_calculation_daily_pack_usage(day, configuration_id, size);
+
+-- Daily rollup of cache lookups per configuration
+CREATE MATERIALIZED VIEW IF NOT EXISTS calculation_cache_daily_stats AS
+SELECT
+    date_trunc('day', timestamp) AS day,
+    configuration_id,
+    COUNT(*) AS lookups,
+    COUNT(*) FILTER (WHERE hit) AS hits
+FROM calculation_cache_lookups
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calculation_cache_daily_stats
+    ON calculation_cache_daily_stats(day, configuration_id);
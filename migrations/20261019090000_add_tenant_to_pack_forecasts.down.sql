@@ -0,0 +1,4 @@
+-- Drop the tenant of forecasts, keeping the forecasts of every tenant
+DROP INDEX IF EXISTS idx_pack_forecasts_tenant_generated_at;
+ALTER TABLE pack_forecasts DROP COLUMN IF EXISTS tenant_id;
+CREATE INDEX IF NOT EXISTS idx_pack_forecasts_generated_at ON pack_forecasts(generated_at);
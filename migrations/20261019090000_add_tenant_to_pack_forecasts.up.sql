@@ -0,0 +1,5 @@
+-- Give forecasts a tenant, so each tenant is forecast from its own usage and stock.
+-- Existing forecasts belong to the default tenant.
+ALTER TABLE pack_forecasts ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_pack_forecasts_generated_at;
+CREATE INDEX IF NOT EXISTS idx_pack_forecasts_tenant_generated_at ON pack_forecasts(tenant_id, generated_at);
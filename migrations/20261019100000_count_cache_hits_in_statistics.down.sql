@@ -0,0 +1,35 @@
+-- Restore the rollups of stored calculations only and drop the calculation of cache hits
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_pack_usage;
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_stats;
+
+DROP INDEX IF EXISTS idx_calculation_cache_lookups_calculation_id;
+ALTER TABLE calculation_cache_lookups DROP COLUMN IF EXISTS calculation_id;
+
+-- Daily rollup of calculations per tenant, configuration and order quantity
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_stats AS
+SELECT
+    date_trunc('day', timestamp) AS day,
+    tenant_id,
+    configuration_id,
+    order_quantity,
+    COUNT(*) AS calculations,
+    SUM(GREATEST(total_items - order_quantity, 0)) AS total_overfill
+FROM order_calculations
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_stats
+    ON order_calculation_daily_stats(day, tenant_id, configuration_id, order_quantity);
+
+-- Daily rollup of packs used per tenant, configuration and pack size
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_pack_usage AS
+SELECT
+    date_trunc('day', c.timestamp) AS day,
+    c.tenant_id,
+    c.configuration_id,
+    (pack->>'size')::INTEGER AS size,
+    SUM((pack->>'quantity')::INTEGER) AS packs
+FROM order_calculations c, json_array_elements(c.result) AS pack
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_pack_usage
+    ON order_calculation_daily_pack_usage(day, tenant_id, configuration_id, size);
@@ -0,0 +1,44 @@
+-- Count amended orders and the calculations they amend separately again
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_pack_usage;
+DROP MATERIALIZED VIEW IF EXISTS order_calculation_daily_stats;
+
+-- Daily rollup of served orders per tenant, configuration and order quantity
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_stats AS
+SELECT
+    date_trunc('day', o.timestamp) AS day,
+    o.tenant_id,
+    o.configuration_id,
+    o.order_quantity,
+    COUNT(*) AS calculations,
+    SUM(GREATEST(o.total_items - o.order_quantity, 0)) AS total_overfill
+FROM (
+    SELECT timestamp, tenant_id, configuration_id, order_quantity, total_items FROM order_calculations
+    UNION ALL
+    SELECT l.timestamp, l.tenant_id, l.configuration_id, l.order_quantity, c.total_items
+    FROM calculation_cache_lookups l
+    JOIN order_calculations c ON c.id = l.calculation_id
+) o
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_stats
+    ON order_calculation_daily_stats(day, tenant_id, configuration_id, order_quantity);
+
+-- Daily rollup of packs used per tenant, configuration and pack size
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_calculation_daily_pack_usage AS
+SELECT
+    date_trunc('day', o.timestamp) AS day,
+    o.tenant_id,
+    o.configuration_id,
+    (pack->>'size')::INTEGER AS size,
+    SUM((pack->>'quantity')::INTEGER) AS packs
+FROM (
+    SELECT timestamp, tenant_id, configuration_id, result FROM order_calculations
+    UNION ALL
+    SELECT l.timestamp, l.tenant_id, l.configuration_id, c.result
+    FROM calculation_cache_lookups l
+    JOIN order_calculations c ON c.id = l.calculation_id
+) o, json_array_elements(o.result) AS pack
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_calculation_daily_pack_usage
+    ON order_calculation_daily_pack_usage(day, tenant_id, configuration_id, size);
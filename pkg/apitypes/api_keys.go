@@ -0,0 +1,34 @@
+package apitypes
+
+import "time"
+
+// APIKey is an issued API key, without the key itself
+type APIKey struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Tenant     string     `json:"tenantId,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IssueAPIKeyRequest issues an API key
+type IssueAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Tenant string   `json:"tenantId,omitempty"`
+}
+
+// IssueAPIKeyResponse returns an issued key. The key itself is only ever returned here.
+type IssueAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeysResponse lists API keys
+type APIKeysResponse struct {
+	Keys []APIKey `json:"keys"`
+}
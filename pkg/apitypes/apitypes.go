@@ -0,0 +1,5 @@
+// Package apitypes holds the request and response bodies of the pack calculator HTTP API.
+// It only depends on the standard library and the packing solver, so clients can use it
+// without pulling in the server. The JSON fields match the types the server encodes, as
+// checked by the tests of this package.
+package apitypes
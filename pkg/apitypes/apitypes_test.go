@@ -0,0 +1,119 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
+	"github.com/pack-calculator/internal/forecasts"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/shipping_rates"
+	"github.com/pack-calculator/internal/stats"
+	"github.com/pack-calculator/internal/warehouses"
+)
+
+// jsonFields lists the JSON fields of t and of the types nested in it by their path,
+// along with the kind of value encoded at the path. A type nested in itself is listed
+// once, by its name.
+func jsonFields(t reflect.Type) map[string]string {
+	fields := make(map[string]string)
+	visiting := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type, path string)
+	walk = func(t reflect.Type, path string) {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice && t != reflect.TypeOf(json.RawMessage{}) {
+			t = t.Elem()
+			path += "[]"
+		}
+		if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+			kind := t.Kind().String()
+			if t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(json.RawMessage{}) {
+				kind = t.String()
+			}
+			fields[path] = kind
+			return
+		}
+		if visiting[t] {
+			fields[path] = "recursive " + t.Name()
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" || !field.IsExported() {
+				continue
+			}
+			if field.Anonymous && tag == "" {
+				walk(field.Type, path)
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = field.Name
+			}
+			walk(field.Type, path+"."+name+","+options)
+		}
+	}
+	walk(t, "")
+	return fields
+}
+
+func TestTypesMatchTheServer(t *testing.T) {
+	tests := []struct {
+		public any
+		server any
+	}{
+		{PackConfigurationRequest{}, pack_configurations.PackCfgAPIRequest{}},
+		{PackConfiguration{}, pack_configurations.PackCfgAPIResponse{}},
+		{PackConfigurationsResponse{}, pack_configurations.PackCfgsAPIResponse{}},
+		{CalculateRequest{}, order_calculations.CalculateAPIRequest{}},
+		{CalculateResponse{}, order_calculations.CalculateAPIResponse{}},
+		{NoAcceptablePackingError{}, order_calculations.NoAcceptablePackingError{}},
+		{Calculation{}, order_calculations.OrderCalculation{}},
+		{CalculationsResponse{}, order_calculations.CalculationsAPIResponse{}},
+		{AmendRequest{}, order_calculations.AmendAPIRequest{}},
+		{AmendResponse{}, order_calculations.AmendAPIResponse{}},
+		{RatesResponse{}, shipping_rates.RatesAPIResponse{}},
+		{QuoteRequest{}, shipping_rates.QuoteAPIRequest{}},
+		{QuoteResponse{}, shipping_rates.QuoteAPIResponse{}},
+		{WarehouseRequest{}, warehouses.WarehouseAPIRequest{}},
+		{WarehousesResponse{}, warehouses.WarehousesAPIResponse{}},
+		{Reservation{}, reservations.Reservation{}},
+		{StatsResponse{}, stats.StatsAPIResponse{}},
+		{Forecast{}, forecasts.Forecast{}},
+		{ExperimentRequest{}, experiments.ExperimentAPIRequest{}},
+		{ExperimentResults{}, experiments.Results{}},
+		{CustomerProfileRequest{}, customers.ProfileAPIRequest{}},
+		{CustomerProfilesResponse{}, customers.ProfilesAPIResponse{}},
+		{IssueAPIKeyRequest{}, api_keys.IssueAPIRequest{}},
+		{IssueAPIKeyResponse{}, api_keys.IssueAPIResponse{}},
+		{APIKeysResponse{}, api_keys.KeysAPIResponse{}},
+		{AuditEventsResponse{}, audit_events.EventsAPIResponse{}},
+		{AuditVerification{}, audit_events.Verification{}},
+	}
+
+	for _, tt := range tests {
+		public, server := reflect.TypeOf(tt.public), reflect.TypeOf(tt.server)
+		t.Run(public.Name(), func(t *testing.T) {
+			assert.Equal(t, jsonFields(server), jsonFields(public), "JSON fields of %s", server)
+		})
+	}
+}
+
+func TestLimitsMatchTheServer(t *testing.T) {
+	assert.Equal(t, order_calculations.DefaultCalculationsLimit, DefaultCalculationsLimit)
+	assert.Equal(t, order_calculations.MaxCalculationsLimit, MaxCalculationsLimit)
+	assert.Equal(t, audit_events.DefaultEventsLimit, DefaultEventsLimit)
+	assert.Equal(t, audit_events.MaxEventsLimit, MaxEventsLimit)
+}
@@ -0,0 +1,55 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Page sizes of audit trail listings
+const (
+	DefaultEventsLimit = 50
+	MaxEventsLimit     = 500
+)
+
+// AuditEvent is an entry of the audit trail. Before and After are the JSON states of the
+// resource, null when it did not exist before or after the change.
+type AuditEvent struct {
+	ID           uint            `json:"id"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resourceType"`
+	ResourceID   string          `json:"resourceId"`
+	Actor        string          `json:"actor"`
+	RequestID    string          `json:"requestId"`
+	ClientIP     string          `json:"clientIp"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	PrevHash     string          `json:"prevHash"`
+	Hash         string          `json:"hash"`
+}
+
+// ListAuditEventsRequest filters an audit trail listing. Empty fields match every event,
+// and a zero Limit uses the server default.
+type ListAuditEventsRequest struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	Offset       int
+	Limit        int
+}
+
+// AuditEventsResponse lists audit events, newest first
+type AuditEventsResponse struct {
+	Events []AuditEvent `json:"events"`
+}
+
+// AuditVerification is the outcome of checking the hash chain of the audit trail.
+// BrokenAt is the first event whose hash or link to the event before it does not match.
+type AuditVerification struct {
+	Valid    bool  `json:"valid"`
+	Events   int   `json:"events"`
+	BrokenAt *uint `json:"brokenAt,omitempty"`
+}
@@ -0,0 +1,184 @@
+package apitypes
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pack-calculator/pkg/packsolver"
+)
+
+// Page sizes of calculation listings
+const (
+	DefaultCalculationsLimit = 50
+	MaxCalculationsLimit     = 500
+)
+
+// PackResult is a number of packs of one size. In a nested hierarchy it can also be a
+// logistics unit such as a case or pallet, in which case Size is the number of items in
+// one unit and Contents lists what one unit holds.
+type PackResult struct {
+	Unit     string       `json:"unit,omitempty"`
+	Size     int          `json:"size"`
+	Quantity int          `json:"quantity"`
+	Contents []PackResult `json:"contents,omitempty"`
+}
+
+// OverfillPolicy caps how many items may be shipped above the order quantity.
+// Zero values disable the corresponding cap, and when both are set the stricter one applies.
+type OverfillPolicy struct {
+	MaxItems   int     `json:"maxItems,omitempty"`
+	MaxPercent float64 `json:"maxPercent,omitempty"`
+	Backorder  bool    `json:"backorder,omitempty"`
+}
+
+// TieBreakPolicy chooses among the combinations with the same number of items and packs
+type TieBreakPolicy = packsolver.TieBreak
+
+// Shipment is one shipment of a partially fulfilled order
+type Shipment struct {
+	ID            uint         `json:"id"`
+	CalculationID uint         `json:"calculationId"`
+	Kind          string       `json:"kind"`
+	Quantity      int          `json:"quantity"`
+	Result        []PackResult `json:"result"`
+	TotalItems    int          `json:"totalItems"`
+	TotalPacks    int          `json:"totalPacks"`
+}
+
+// WarehouseAllocation lists the packs a sourcing plan takes from one warehouse
+type WarehouseAllocation struct {
+	WarehouseID uint         `json:"warehouseId"`
+	Warehouse   string       `json:"warehouse"`
+	Packs       []PackResult `json:"packs"`
+	TotalItems  int          `json:"totalItems"`
+	TotalPacks  int          `json:"totalPacks"`
+}
+
+// CartonAssignment is one shipping carton and the packs placed in it
+type CartonAssignment struct {
+	Carton string       `json:"carton"`
+	Packs  []PackResult `json:"packs"`
+	Volume float64      `json:"volume"`
+	Weight float64      `json:"weight"`
+	Cost   float64      `json:"cost"`
+}
+
+// CartonPlan assigns the packs of a calculation to shipping cartons
+type CartonPlan struct {
+	Cartons      []CartonAssignment `json:"cartons"`
+	TotalCartons int                `json:"totalCartons"`
+	TotalCost    float64            `json:"totalCost"`
+	Unassigned   []PackResult       `json:"unassigned,omitempty"`
+}
+
+// Assignment is the arm of a running experiment an order was calculated in
+type Assignment struct {
+	ExperimentID   uint   `json:"experimentId"`
+	Arm            string `json:"arm"`
+	CalculationID  uint   `json:"calculationId"`
+	OrderReference string `json:"orderReference"`
+}
+
+// PackingOption is a candidate packing offered when no acceptable packing exists
+type PackingOption struct {
+	TotalItems int          `json:"totalItems"`
+	TotalPacks int          `json:"totalPacks"`
+	Packs      []PackResult `json:"packs"`
+}
+
+// NoAcceptablePackingError details a calculation whose optimal packing exceeds the overfill cap
+type NoAcceptablePackingError struct {
+	OrderQuantity int             `json:"orderQuantity"`
+	MaxOverfill   int             `json:"maxOverfill"`
+	Options       []PackingOption `json:"options"`
+}
+
+func (e *NoAcceptablePackingError) Error() string {
+	return fmt.Sprintf("no acceptable packing for %d items within an overfill of %d", e.OrderQuantity, e.MaxOverfill)
+}
+
+// CalculateRequest calculates the packs of an order
+type CalculateRequest struct {
+	OrderQuantity   int             `json:"orderQuantity"`
+	MinQuantity     int             `json:"minQuantity,omitempty"`
+	MaxQuantity     int             `json:"maxQuantity,omitempty"`
+	Overfill        *OverfillPolicy `json:"overfill,omitempty"`
+	Inventory       map[int]int     `json:"inventory,omitempty"`
+	CartonObjective string          `json:"cartonObjective,omitempty"`
+	Sourcing        bool            `json:"sourcing,omitempty"`
+	Reserve         bool            `json:"reserve,omitempty"`
+	ReservationTTL  int             `json:"reservationTtl,omitempty"`
+	OrderReference  string          `json:"orderReference,omitempty"`
+	CustomerID      string          `json:"customerId,omitempty"`
+	TieBreak        *TieBreakPolicy `json:"tieBreak,omitempty"`
+}
+
+// CalculateResponse is the outcome of a calculation
+type CalculateResponse struct {
+	OrderQuantity int                   `json:"orderQuantity"`
+	MinQuantity   int                   `json:"minQuantity,omitempty"`
+	MaxQuantity   int                   `json:"maxQuantity,omitempty"`
+	TotalItems    int                   `json:"totalItems"`
+	TotalPacks    int                   `json:"totalPacks"`
+	Packs         []PackResult          `json:"pack_configurations"`
+	Reason        string                `json:"reason,omitempty"`
+	Backorder     int                   `json:"backorder,omitempty"`
+	Shipments     []Shipment            `json:"shipments,omitempty"`
+	Sourcing      []WarehouseAllocation `json:"sourcing,omitempty"`
+	Hierarchy     []PackResult          `json:"hierarchy,omitempty"`
+	TopLevelUnits int                   `json:"topLevelUnits,omitempty"`
+	Cartons       *CartonPlan           `json:"cartons,omitempty"`
+	Reservation   *Reservation          `json:"reservation,omitempty"`
+	Experiment    *Assignment           `json:"experiment,omitempty"`
+	Success       bool                  `json:"success"`
+	ErrorMessage  string                `json:"errorMessage,omitempty"`
+}
+
+// Calculation is a saved order calculation
+type Calculation struct {
+	ID              uint                  `json:"id"`
+	OrderQuantity   int                   `json:"orderQuantity"`
+	MinQuantity     int                   `json:"minQuantity"`
+	MaxQuantity     int                   `json:"maxQuantity"`
+	Mode            string                `json:"mode"`
+	AmendsID        *uint                 `json:"amendsId,omitempty"`
+	Result          []PackResult          `json:"result"`
+	Sourcing        []WarehouseAllocation `json:"sourcing,omitempty"`
+	TotalItems      int                   `json:"totalItems"`
+	TotalPacks      int                   `json:"totalPacks"`
+	ConfigurationID uint                  `json:"configurationId"`
+	CustomerID      string                `json:"customerId,omitempty"`
+	Timestamp       time.Time             `json:"timestamp"`
+	Shipments       []Shipment            `json:"shipments,omitempty"`
+	Hierarchy       []PackResult          `json:"hierarchy,omitempty"`
+	Cartons         *CartonPlan           `json:"cartons,omitempty"`
+	Reservation     *Reservation          `json:"reservation,omitempty"`
+	Experiment      *Assignment           `json:"experiment,omitempty"`
+	Reason          string                `json:"reason,omitempty"`
+	Backorder       int                   `json:"backorder,omitempty"`
+}
+
+// CalculationsResponse lists calculations, newest first
+type CalculationsResponse struct {
+	Calculations []Calculation `json:"calculations"`
+}
+
+// AmendRequest raises the quantity of an existing calculation
+type AmendRequest struct {
+	OrderQuantity int `json:"orderQuantity"`
+}
+
+// AmendResponse is the outcome of an amendment
+type AmendResponse struct {
+	CalculationID uint          `json:"calculationId"`
+	AmendsID      uint          `json:"amendsId"`
+	OrderQuantity int           `json:"orderQuantity"`
+	TotalItems    int           `json:"totalItems"`
+	TotalPacks    int           `json:"totalPacks"`
+	FixedPacks    []PackResult  `json:"fixedPacks"`
+	AddedPacks    []PackResult  `json:"addedPacks"`
+	Packs         []PackResult  `json:"packs"`
+	FromScratch   PackingOption `json:"fromScratch"`
+	ExtraItems    int           `json:"extraItems"`
+	ExtraPacks    int           `json:"extraPacks"`
+}
@@ -0,0 +1,26 @@
+package apitypes
+
+// CustomerProfile restricts or extends the active pack sizes for one customer
+type CustomerProfile struct {
+	CustomerID    string `json:"customerId"`
+	Name          string `json:"name"`
+	ExtraSizes    []int  `json:"extraSizes"`
+	ExcludedSizes []int  `json:"excludedSizes"`
+	MinSize       int    `json:"minSize,omitempty"`
+	MaxSize       int    `json:"maxSize,omitempty"`
+}
+
+// CustomerProfileRequest creates a customer profile or replaces it
+type CustomerProfileRequest struct {
+	CustomerID    string `json:"customerId"`
+	Name          string `json:"name"`
+	ExtraSizes    []int  `json:"extraSizes,omitempty"`
+	ExcludedSizes []int  `json:"excludedSizes,omitempty"`
+	MinSize       int    `json:"minSize,omitempty"`
+	MaxSize       int    `json:"maxSize,omitempty"`
+}
+
+// CustomerProfilesResponse lists customer profiles
+type CustomerProfilesResponse struct {
+	Profiles []CustomerProfile `json:"profiles"`
+}
@@ -0,0 +1,37 @@
+package apitypes
+
+import "time"
+
+// ExperimentRequest starts trialling a candidate configuration on a share of the orders
+type ExperimentRequest struct {
+	Name            string  `json:"name"`
+	ConfigurationID uint    `json:"configurationId"`
+	TrafficShare    float64 `json:"trafficShare"`
+}
+
+// Experiment trials a candidate pack configuration on TrafficShare percent of the orders
+type Experiment struct {
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	ConfigurationID uint       `json:"configurationId"`
+	TrafficShare    float64    `json:"trafficShare"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"startedAt"`
+	StoppedAt       *time.Time `json:"stoppedAt,omitempty"`
+}
+
+// ArmComparison reports the average overfill and pack count of one arm
+type ArmComparison struct {
+	Arm             string  `json:"arm"`
+	Orders          int     `json:"orders"`
+	TotalOverfill   int     `json:"totalOverfill"`
+	AverageOverfill float64 `json:"averageOverfill"`
+	TotalPacks      int     `json:"totalPacks"`
+	AveragePacks    float64 `json:"averagePacks"`
+}
+
+// ExperimentResults compares the arms of an experiment
+type ExperimentResults struct {
+	Experiment Experiment      `json:"experiment"`
+	Arms       []ArmComparison `json:"arms"`
+}
@@ -0,0 +1,25 @@
+package apitypes
+
+import "time"
+
+// Forecast is one run of the replenishment forecast
+type Forecast struct {
+	ID          uint           `json:"id"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Method      string         `json:"method"`
+	HistoryDays int            `json:"historyDays"`
+	HorizonDays int            `json:"horizonDays"`
+	Items       []ForecastItem `json:"items"`
+}
+
+// ForecastItem projects the consumption of one pack size and suggests a reorder
+type ForecastItem struct {
+	Size            int     `json:"size"`
+	DailyUsage      float64 `json:"dailyUsage"`
+	ProjectedUsage  int     `json:"projectedUsage"`
+	OnHand          int     `json:"onHand"`
+	LeadTimeDays    int     `json:"leadTimeDays"`
+	ReorderPoint    int     `json:"reorderPoint"`
+	ReorderNow      bool    `json:"reorderNow"`
+	ReorderQuantity int     `json:"reorderQuantity"`
+}
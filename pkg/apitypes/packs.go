@@ -0,0 +1,51 @@
+package apitypes
+
+// NestingRule describes a logistics unit that holds a number of smaller units,
+// for example a case that holds 4 packs of 1000 or a pallet that holds 10 cases
+type NestingRule struct {
+	Unit     string `json:"unit"`
+	Contains string `json:"contains"`
+	Size     int    `json:"size,omitempty"`
+	Capacity int    `json:"capacity"`
+}
+
+// PackSpec holds the physical volume and weight of one pack of a given size
+type PackSpec struct {
+	Size   int     `json:"size"`
+	Volume float64 `json:"volume"`
+	Weight float64 `json:"weight"`
+}
+
+// CartonType is a shipping carton packs can be placed in. A zero MaxVolume
+// or MaxWeight leaves that dimension unlimited.
+type CartonType struct {
+	Name      string  `json:"name"`
+	MaxVolume float64 `json:"maxVolume,omitempty"`
+	MaxWeight float64 `json:"maxWeight,omitempty"`
+	Cost      float64 `json:"cost,omitempty"`
+}
+
+// PackConfigurationRequest creates a pack configuration
+type PackConfigurationRequest struct {
+	PackSizes []int         `json:"packSizes"`
+	Nesting   []NestingRule `json:"nesting,omitempty"`
+	PackSpecs []PackSpec    `json:"packSpecs,omitempty"`
+	Cartons   []CartonType  `json:"cartons,omitempty"`
+	Rules     []string      `json:"rules,omitempty"`
+}
+
+// PackConfiguration is a pack configuration of the tenant
+type PackConfiguration struct {
+	ID        uint          `json:"id,omitempty"`
+	Active    bool          `json:"active,omitempty"`
+	PackSizes []int         `json:"packSizes"`
+	Nesting   []NestingRule `json:"nesting,omitempty"`
+	PackSpecs []PackSpec    `json:"packSpecs,omitempty"`
+	Cartons   []CartonType  `json:"cartons,omitempty"`
+	Rules     []string      `json:"rules,omitempty"`
+}
+
+// PackConfigurationsResponse lists pack configurations
+type PackConfigurationsResponse struct {
+	Configurations []PackConfiguration `json:"configurations"`
+}
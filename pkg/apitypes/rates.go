@@ -0,0 +1,43 @@
+package apitypes
+
+// Rate is one weight break of a carrier rate table
+type Rate struct {
+	ID        uint    `json:"id"`
+	Carrier   string  `json:"carrier"`
+	Zone      string  `json:"zone"`
+	MaxWeight float64 `json:"maxWeight"`
+	Cost      float64 `json:"cost"`
+}
+
+// RatesResponse lists rate tables
+type RatesResponse struct {
+	Rates []Rate `json:"rates"`
+}
+
+// QuoteRequest quotes shipping for an order. An empty Carrier quotes every carrier
+// with rates for the zone.
+type QuoteRequest struct {
+	OrderQuantity int    `json:"orderQuantity"`
+	Zone          string `json:"zone"`
+	Carrier       string `json:"carrier,omitempty"`
+}
+
+// QuoteOption is a pack solution together with its cheapest shipping cost
+type QuoteOption struct {
+	Carrier      string       `json:"carrier"`
+	Packs        []PackResult `json:"packs"`
+	TotalItems   int          `json:"totalItems"`
+	TotalPacks   int          `json:"totalPacks"`
+	Weight       float64      `json:"weight"`
+	ShippingCost float64      `json:"shippingCost"`
+}
+
+// QuoteResponse compares the item-optimal pack solution with the one that is cheapest to ship
+type QuoteResponse struct {
+	OrderQuantity int         `json:"orderQuantity"`
+	Zone          string      `json:"zone"`
+	ItemOptimal   QuoteOption `json:"itemOptimal"`
+	CostOptimal   QuoteOption `json:"costOptimal"`
+	Savings       float64     `json:"savings"`
+	ExtraItems    int         `json:"extraItems"`
+}
@@ -0,0 +1,47 @@
+package apitypes
+
+import "time"
+
+// StatsRequest selects the calculations to aggregate. From and To are inclusive dates,
+// and zero values use the server defaults.
+type StatsRequest struct {
+	Period          string
+	BucketSize      int
+	ConfigurationID uint
+	From            time.Time
+	To              time.Time
+}
+
+// HistogramBucket counts the calculations with an order quantity between From and To inclusive
+type HistogramBucket struct {
+	From         int `json:"from"`
+	To           int `json:"to"`
+	Calculations int `json:"calculations"`
+}
+
+// PackUsage counts the packs of one size used by the calculations
+type PackUsage struct {
+	Size  int `json:"size"`
+	Packs int `json:"packs"`
+}
+
+// StatsGroup aggregates the calculations of one configuration in one period
+type StatsGroup struct {
+	Period          time.Time         `json:"period"`
+	ConfigurationID uint              `json:"configurationId"`
+	Calculations    int               `json:"calculations"`
+	Histogram       []HistogramBucket `json:"histogram"`
+	TotalOverfill   int               `json:"totalOverfill"`
+	AverageOverfill float64           `json:"averageOverfill"`
+	PackUsage       []PackUsage       `json:"packUsage"`
+	CacheLookups    int               `json:"cacheLookups"`
+	CacheHits       int               `json:"cacheHits"`
+	CacheHitRate    float64           `json:"cacheHitRate"`
+}
+
+// StatsResponse holds the groups of a statistics request ordered by period and configuration
+type StatsResponse struct {
+	Period     string       `json:"period"`
+	BucketSize int          `json:"bucketSize"`
+	Groups     []StatsGroup `json:"groups"`
+}
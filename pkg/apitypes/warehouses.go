@@ -0,0 +1,39 @@
+package apitypes
+
+import "time"
+
+// Warehouse is a stocking location with the number of packs it holds per size
+type Warehouse struct {
+	ID        uint        `json:"id"`
+	Name      string      `json:"name"`
+	Inventory map[int]int `json:"inventory"`
+}
+
+// WarehouseRequest creates a warehouse or replaces its inventory
+type WarehouseRequest struct {
+	Name      string      `json:"name"`
+	Inventory map[int]int `json:"inventory"`
+}
+
+// WarehousesResponse lists warehouses
+type WarehousesResponse struct {
+	Warehouses []Warehouse `json:"warehouses"`
+}
+
+// Reservation holds packs of a calculation against warehouse stock until it is
+// committed or expires
+type Reservation struct {
+	ID            uint              `json:"id"`
+	CalculationID uint              `json:"calculationId"`
+	Status        string            `json:"status"`
+	Items         []ReservationItem `json:"items"`
+	ExpiresAt     time.Time         `json:"expiresAt"`
+	CommittedAt   *time.Time        `json:"committedAt,omitempty"`
+}
+
+// ReservationItem is a number of packs of one size reserved in one warehouse
+type ReservationItem struct {
+	WarehouseID uint `json:"warehouseId"`
+	Size        int  `json:"size"`
+	Quantity    int  `json:"quantity"`
+}
@@ -0,0 +1,96 @@
+// Package auth holds the authenticated principal of a request and the scopes that
+// authorize it. The HTTP and gRPC layers put the principal in the request context
+// so services can tell who made a change.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Scopes that can be granted to a principal. ScopeAdmin grants every scope.
+const (
+	ScopeCalculate  = "calculate"
+	ScopePacksRead  = "packs:read"
+	ScopePacksWrite = "packs:write"
+	ScopeAdmin      = "admin"
+)
+
+// Scopes lists every scope that can be granted
+var Scopes = []string{ScopeCalculate, ScopePacksRead, ScopePacksWrite, ScopeAdmin}
+
+// ValidScope reports whether scope is one of Scopes
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// Roles that bearer tokens map to. Each role grants a fixed set of scopes.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// roleScopes lists the scopes each role grants
+var roleScopes = map[string][]string{
+	RoleViewer:   {ScopeCalculate, ScopePacksRead},
+	RoleOperator: {ScopeCalculate, ScopePacksRead, ScopePacksWrite},
+	RoleAdmin:    {ScopeAdmin},
+}
+
+// ValidRole reports whether role is one of the roles
+func ValidRole(role string) bool {
+	_, ok := roleScopes[role]
+	return ok
+}
+
+// ScopesOf returns the scopes granted by the roles, without duplicates
+func ScopesOf(roles []string) []string {
+	var scopes []string
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// Principal is the authenticated caller of a request. Subject identifies the caller
+// in logs and the audit trail, for example "api_key:3" or "user:alice". Roles are
+// only set for principals authenticated with a bearer token. Tenant is the tenant the
+// principal is bound to, empty when it may act for any tenant its scopes allow.
+type Principal struct {
+	Subject string
+	Name    string
+	Roles   []string
+	Scopes  []string
+	Tenant  string
+}
+
+// HasScope reports whether the principal was granted scope, directly or through ScopeAdmin
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx that carries the principal
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of ctx, or nil when the request is anonymous
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// Subject returns the subject of the principal of ctx, or "anonymous"
+func Subject(ctx context.Context) string {
+	if principal := FromContext(ctx); principal != nil {
+		return principal.Subject
+	}
+	return "anonymous"
+}
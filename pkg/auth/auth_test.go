@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_HasScope(t *testing.T) {
+	reader := &Principal{Subject: "api_key:1", Scopes: []string{ScopePacksRead}}
+	admin := &Principal{Subject: "api_key:2", Scopes: []string{ScopeAdmin}}
+
+	assert.True(t, reader.HasScope(ScopePacksRead))
+	assert.False(t, reader.HasScope(ScopePacksWrite))
+	assert.True(t, admin.HasScope(ScopePacksWrite))
+	assert.True(t, admin.HasScope(ScopeCalculate))
+}
+
+func TestValidScope(t *testing.T) {
+	assert.True(t, ValidScope(ScopeCalculate))
+	assert.False(t, ValidScope("packs"))
+}
+
+func TestScopesOf(t *testing.T) {
+	assert.Equal(t, []string{ScopeCalculate, ScopePacksRead}, ScopesOf([]string{RoleViewer}))
+	assert.Equal(t, []string{ScopeCalculate, ScopePacksRead, ScopePacksWrite}, ScopesOf([]string{RoleViewer, RoleOperator}))
+	assert.Equal(t, []string{ScopeAdmin}, ScopesOf([]string{RoleAdmin, "auditor"}))
+	assert.Nil(t, ScopesOf(nil))
+	assert.True(t, ValidRole(RoleOperator))
+	assert.False(t, ValidRole("auditor"))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	assert.Equal(t, "anonymous", Subject(ctx))
+
+	principal := &Principal{Subject: "api_key:1", Scopes: []string{ScopeCalculate}}
+	ctx = NewContext(ctx, principal)
+	assert.Same(t, principal, FromContext(ctx))
+	assert.Equal(t, "api_key:1", Subject(ctx))
+}
@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyRefreshInterval limits how often a key set served from a URL is fetched again to
+// find a key ID it does not know, or after a fetch failed, so tokens with made up key IDs
+// cannot flood the issuer
+const keyRefreshInterval = time.Minute
+
+// KeySet holds the public keys of a JSON Web Key Set (RFC 7517) read from a file or an
+// http(s) URL. Keys from a URL are fetched again when a token names an unknown key ID,
+// which picks up the keys of an issuer that rotates them. The keys are read without
+// holding the lock, and requests that need them while they are read wait for that read.
+type KeySet struct {
+	source string
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	err         error
+	attemptedAt time.Time
+	pending     *keyFetch
+}
+
+// keyFetch is a read of the keys in progress, shared by the requests waiting for it
+type keyFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewKeySet returns a key set read from source, a file path or an http(s) URL. The keys
+// are read on first use, or by Load.
+func NewKeySet(source string, client *http.Client) *KeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeySet{source: source, client: client}
+}
+
+// Load reads the keys from the source, replacing the keys read before
+func (s *KeySet) Load(ctx context.Context) error {
+	return s.fetch(ctx)
+}
+
+// Key returns the key with the key ID. An empty key ID is accepted when the set holds
+// a single key.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	key, ok := s.lookup(kid)
+	pending, due := s.pending, s.due()
+	loaded, err := s.keys != nil, s.err
+	s.mu.Unlock()
+
+	if !ok && (pending != nil || due) {
+		if pending != nil {
+			err = pending.wait(ctx)
+		} else {
+			err = s.fetch(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		key, ok = s.lookup(kid)
+		s.mu.Unlock()
+	} else if !loaded && err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key ID %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+// due reports whether the keys should be read again to look for a key ID they lack.
+// A file is only read again until it was read once, and a URL at most once per
+// keyRefreshInterval.
+func (s *KeySet) due() bool {
+	if s.remote() {
+		return time.Since(s.attemptedAt) >= keyRefreshInterval
+	}
+	return s.keys == nil
+}
+
+func (s *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *KeySet) remote() bool {
+	return strings.HasPrefix(s.source, "http://") || strings.HasPrefix(s.source, "https://")
+}
+
+// fetch reads the keys from the source, or waits for the read in progress
+func (s *KeySet) fetch(ctx context.Context) error {
+	s.mu.Lock()
+	if pending := s.pending; pending != nil {
+		s.mu.Unlock()
+		return pending.wait(ctx)
+	}
+	pending := &keyFetch{done: make(chan struct{})}
+	s.pending = pending
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.load(ctx)
+
+	s.mu.Lock()
+	if err == nil {
+		s.keys = keys
+	}
+	s.err = err
+	s.pending = nil
+	s.mu.Unlock()
+
+	pending.err = err
+	close(pending.done)
+	return err
+}
+
+// wait returns the outcome of the read once it is done
+func (f *keyFetch) wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *KeySet) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the JWKS from %s: %w", s.source, err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the JWKS from %s: %w", s.source, err)
+	}
+	return keys, nil
+}
+
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !s.remote() {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// jsonWebKey is a key of a JWKS. Only RSA and EC signing keys are used.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS returns the RSA and EC signing keys of a JWKS by key ID. Encryption keys and
+// key types other than RSA and EC are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = rsaKey(jwk)
+		case "EC":
+			key, err = ecKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA or EC signing keys")
+	}
+	return keys, nil
+}
+
+func rsaKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeInt(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := decodeInt(jwk.E)
+	if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+	if n.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA keys must have at least 2048 bits")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func ecKey(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+	x, errX := decodeInt(jwk.X)
+	y, errY := decodeInt(jwk.Y)
+	if errX != nil || errY != nil || !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("invalid point")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decodeInt decodes a base64url encoded big-endian integer
+func decodeInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
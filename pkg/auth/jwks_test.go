@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJWKS(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa-1")
+	ecSigner := newECSigner(t, "ec-1")
+
+	t.Run("signing keys", func(t *testing.T) {
+		encryption := rsaSigner.jwk()
+		encryption["kid"] = "enc-1"
+		encryption["use"] = "enc"
+		data, _ := json.Marshal(map[string]any{"keys": []any{rsaSigner.jwk(), ecSigner.jwk(), encryption, map[string]string{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}}})
+
+		keys, err := ParseJWKS(data)
+
+		assert.NoError(t, err)
+		assert.Len(t, keys, 2)
+		assert.Equal(t, &rsaSigner.rsa.PublicKey, keys["rsa-1"])
+		assert.True(t, ecSigner.ec.PublicKey.Equal(keys["ec-1"]))
+	})
+
+	t.Run("point not on the curve", func(t *testing.T) {
+		jwk := ecSigner.jwk()
+		jwk["y"] = jwk["x"]
+		data, _ := json.Marshal(map[string]any{"keys": []any{jwk}})
+
+		_, err := ParseJWKS(data)
+
+		assert.ErrorContains(t, err, "invalid point")
+	})
+
+	t.Run("no signing keys", func(t *testing.T) {
+		_, err := ParseJWKS([]byte(`{"keys":[]}`))
+
+		assert.ErrorContains(t, err, "no RSA or EC signing keys")
+	})
+}
+
+func TestKeySet_URL(t *testing.T) {
+	first := newRSASigner(t, "rsa-1")
+	rotated := newRSASigner(t, "rsa-2")
+
+	var fetches atomic.Int32
+	served := []*testSigner{first}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		keys := make([]map[string]string, 0, len(served))
+		for _, signer := range served {
+			keys = append(keys, signer.jwk())
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	defer server.Close()
+
+	keySet := NewKeySet(server.URL, server.Client())
+	ctx := context.Background()
+
+	key, err := keySet.Key(ctx, "rsa-1")
+	assert.NoError(t, err)
+	assert.Equal(t, &first.rsa.PublicKey, key)
+
+	// An unknown key ID is not fetched again within the refresh interval
+	served = []*testSigner{first, rotated}
+	_, err = keySet.Key(ctx, "rsa-2")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Once the interval passed, the rotated key is fetched
+	keySet.attemptedAt = keySet.attemptedAt.Add(-keyRefreshInterval)
+	key, err = keySet.Key(ctx, "rsa-2")
+	assert.NoError(t, err)
+	assert.Equal(t, &rotated.rsa.PublicKey, key)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestKeySet_FailedFetch(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	keySet := NewKeySet(server.URL, server.Client())
+	ctx := context.Background()
+
+	// A failed fetch is not retried within the refresh interval
+	for range 3 {
+		_, err := keySet.Key(ctx, "rsa-1")
+		assert.ErrorContains(t, err, "503 Service Unavailable")
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	keySet.attemptedAt = keySet.attemptedAt.Add(-keyRefreshInterval)
+	_, err := keySet.Key(ctx, "rsa-1")
+	assert.ErrorContains(t, err, "503 Service Unavailable")
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestKeySet_ConcurrentFetch(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(map[string]any{"keys": []any{signer.jwk()}})
+	}))
+	defer server.Close()
+
+	keySet := NewKeySet(server.URL, server.Client())
+	ctx := context.Background()
+
+	t.Run("requests share the fetch in progress", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := keySet.Key(ctx, "rsa-1")
+				errs <- err
+			}()
+		}
+		assert.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), fetches.Load())
+	})
+
+	t.Run("known keys are served while a fetch is in progress", func(t *testing.T) {
+		release = make(chan struct{})
+		defer close(release)
+		keySet.attemptedAt = keySet.attemptedAt.Add(-keyRefreshInterval)
+
+		go keySet.Key(ctx, "rsa-2")
+		assert.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+		key, err := keySet.Key(ctx, "rsa-1")
+		assert.NoError(t, err)
+		assert.Equal(t, &signer.rsa.PublicKey, key)
+	})
+}
+
+func TestKeySet_Load(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewKeySet(server.URL, server.Client()).Load(context.Background())
+
+	assert.ErrorContains(t, err, "503 Service Unavailable")
+	assert.NotErrorIs(t, err, ErrInvalidToken)
+}
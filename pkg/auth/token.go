@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pack-calculator/pkg/tenant"
+)
+
+// ErrInvalidToken is returned for bearer tokens that are malformed, badly signed, expired
+// or issued for someone else. The wrapped message says which.
+var ErrInvalidToken = errors.New("invalid token")
+
+// clockSkew is the leeway given to the expiry and not-before times of a token
+const clockSkew = time.Minute
+
+// TokenConfig holds what a bearer token must satisfy and how its claims map to roles.
+// Tokens must be issued for the Audience, so tokens the issuer signs for other
+// applications are not accepted. RolesClaim is the claim holding the roles, a dotted path
+// such as "realm_access.roles" for nested claims. RoleMap maps claim values to roles; other
+// values grant nothing, even when they equal a role name. TenantClaim is the claim binding
+// the token to a tenant.
+type TokenConfig struct {
+	Issuer      string
+	Audience    string
+	RolesClaim  string
+	RoleMap     map[string]string
+	TenantClaim string
+}
+
+// TokenVerifier validates JWT bearer tokens (RFC 7519) signed by an OpenID Connect
+// issuer with one of the keys of its JWKS, and maps them to a principal
+type TokenVerifier struct {
+	cfg  TokenConfig
+	keys *KeySet
+	now  func() time.Time
+}
+
+func NewTokenVerifier(cfg TokenConfig, keys *KeySet) *TokenVerifier {
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	if cfg.TenantClaim == "" {
+		cfg.TenantClaim = "tenant"
+	}
+	return &TokenVerifier{cfg: cfg, keys: keys, now: time.Now}
+}
+
+// signingMethods maps the supported JWS algorithms to their hash
+var signingMethods = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// Verify checks the signature, issuer, audience and validity period of a token and
+// returns its principal. The subject is "user:" followed by the sub claim, and a
+// tenant claim binds the principal to that tenant.
+func (v *TokenVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	hashFunc, ok := signingMethods[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if !verifySignature(header.Alg, hashFunc, key, parts[0]+"."+parts[1], signature) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	tenantID, _ := claims[v.cfg.TenantClaim].(string)
+	if _, ok := claims[v.cfg.TenantClaim]; ok && !tenant.Valid(tenantID) {
+		return nil, fmt.Errorf("%w: invalid tenant", ErrInvalidToken)
+	}
+
+	subject, _ := claims["sub"].(string)
+	roles := v.roles(claims)
+	return &Principal{
+		Subject: "user:" + subject,
+		Name:    displayName(claims),
+		Roles:   roles,
+		Scopes:  ScopesOf(roles),
+		Tenant:  tenantID,
+	}, nil
+}
+
+// validate checks the registered claims of a token
+func (v *TokenVerifier) validate(claims map[string]any) error {
+	now := v.now()
+	if issuer, _ := claims["iss"].(string); issuer != v.cfg.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, issuer)
+	}
+	if v.cfg.Audience == "" || !slices.Contains(stringsOf(claims["aud"]), v.cfg.Audience) {
+		return fmt.Errorf("%w: not issued for %q", ErrInvalidToken, v.cfg.Audience)
+	}
+	if subject, _ := claims["sub"].(string); subject == "" {
+		return fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	expiry, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: missing expiry", ErrInvalidToken)
+	}
+	if now.After(time.Unix(int64(expiry), 0).Add(clockSkew)) {
+		return fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if notBefore, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(notBefore), 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	return nil
+}
+
+// roles returns the roles the role map assigns to the values of the roles claim
+func (v *TokenVerifier) roles(claims map[string]any) []string {
+	var value any = claims
+	for _, name := range strings.Split(v.cfg.RolesClaim, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	var roles []string
+	for _, claimed := range stringsOf(value) {
+		role, ok := v.cfg.RoleMap[claimed]
+		if ok && ValidRole(role) && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// verifySignature checks the JWS signature of the signing input with the key
+func verifySignature(alg string, hashFunc crypto.Hash, key crypto.PublicKey, input string, signature []byte) bool {
+	h := hashFunc.New()
+	h.Write([]byte(input))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(key, hashFunc, digest, signature) == nil
+		case "PS":
+			return rsa.VerifyPSS(key, hashFunc, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+	case *ecdsa.PublicKey:
+		// ES signatures are r and s as fixed size big-endian integers, on the curve of the algorithm
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || len(signature) != 2*size || key.Curve.Params().BitSize != curveBits(alg) {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+// curveBits returns the curve size an ES algorithm signs with
+func curveBits(alg string) int {
+	switch alg {
+	case "ES256":
+		return 256
+	case "ES384":
+		return 384
+	default:
+		return 521
+	}
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stringsOf returns a claim that is a string or an array of strings as a slice
+func stringsOf(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// displayName returns the most readable name the claims carry
+func displayName(claims map[string]any) string {
+	for _, claim := range []string{"name", "preferred_username", "email", "sub"} {
+		if name, _ := claims[claim].(string); name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,260 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSigner signs tokens with a generated RSA or EC key
+type testSigner struct {
+	kid string
+	alg string
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newRSASigner(t *testing.T, kid string) *testSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return &testSigner{kid: kid, alg: "RS256", rsa: key}
+}
+
+func newECSigner(t *testing.T, kid string) *testSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testSigner{kid: kid, alg: "ES256", ec: key}
+}
+
+// jwk returns the public key as a JSON web key
+func (s *testSigner) jwk() map[string]string {
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	if s.rsa != nil {
+		return map[string]string{"kty": "RSA", "kid": s.kid, "use": "sig", "n": encode(s.rsa.N), "e": encode(big.NewInt(int64(s.rsa.E)))}
+	}
+	return map[string]string{"kty": "EC", "kid": s.kid, "crv": "P-256", "x": encode(s.ec.X), "y": encode(s.ec.Y)}
+}
+
+func (s *testSigner) sign(t *testing.T, claims map[string]any) string {
+	header, err := json.Marshal(map[string]string{"alg": s.alg, "kid": s.kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(input))
+	var signature []byte
+	if s.rsa != nil {
+		signature, err = rsa.SignPKCS1v15(rand.Reader, s.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	} else {
+		r, sig, err := ecdsa.Sign(rand.Reader, s.ec, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), sig.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// writeJWKS writes the public keys of the signers to a JWKS file
+func writeJWKS(t *testing.T, signers ...*testSigner) string {
+	keys := make([]map[string]string, 0, len(signers))
+	for _, signer := range signers {
+		keys = append(keys, signer.jwk())
+	}
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa-1")
+	ecSigner := newECSigner(t, "ec-1")
+	unknownSigner := newRSASigner(t, "rsa-2")
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	verifier := NewTokenVerifier(TokenConfig{
+		Issuer:     "https://id.example.com",
+		Audience:   "pack-calculator",
+		RolesClaim: "realm_access.roles",
+		RoleMap:    map[string]string{"pack-admins": RoleAdmin, "pack-operators": RoleOperator},
+	}, NewKeySet(writeJWKS(t, rsaSigner, ecSigner), nil))
+	verifier.now = func() time.Time { return now }
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":          "https://id.example.com",
+			"aud":          []string{"pack-calculator", "other"},
+			"sub":          "alice",
+			"name":         "Alice",
+			"exp":          now.Add(time.Hour).Unix(),
+			"realm_access": map[string]any{"roles": []string{"pack-operators", "offline_access"}},
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(c, name)
+			} else {
+				c[name] = value
+			}
+		}
+		return c
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		wantPrincipal *Principal
+		wantErr       string
+	}{
+		{
+			name:          "RS256 token",
+			token:         rsaSigner.sign(t, claims(nil)),
+			wantPrincipal: &Principal{Subject: "user:alice", Name: "Alice", Roles: []string{RoleOperator}, Scopes: []string{ScopeCalculate, ScopePacksRead, ScopePacksWrite}},
+		},
+		{
+			name:          "ES256 token with a mapped role and a single audience",
+			token:         ecSigner.sign(t, claims(map[string]any{"aud": "pack-calculator", "name": nil, "email": "bob@example.com", "realm_access": map[string]any{"roles": []string{"pack-admins"}}})),
+			wantPrincipal: &Principal{Subject: "user:alice", Name: "bob@example.com", Roles: []string{RoleAdmin}, Scopes: []string{ScopeAdmin}},
+		},
+		{
+			name:          "role names are not mapped to themselves",
+			token:         rsaSigner.sign(t, claims(map[string]any{"realm_access": map[string]any{"roles": []string{"admin", "operator"}}})),
+			wantPrincipal: &Principal{Subject: "user:alice", Name: "Alice"},
+		},
+		{
+			name:          "token without roles",
+			token:         rsaSigner.sign(t, claims(map[string]any{"realm_access": nil})),
+			wantPrincipal: &Principal{Subject: "user:alice", Name: "Alice"},
+		},
+		{
+			name:          "token bound to a tenant",
+			token:         rsaSigner.sign(t, claims(map[string]any{"tenant": "retail"})),
+			wantPrincipal: &Principal{Subject: "user:alice", Name: "Alice", Roles: []string{RoleOperator}, Scopes: []string{ScopeCalculate, ScopePacksRead, ScopePacksWrite}, Tenant: "retail"},
+		},
+		{
+			name:    "invalid tenant",
+			token:   rsaSigner.sign(t, claims(map[string]any{"tenant": "Retail EU"})),
+			wantErr: "invalid tenant",
+		},
+		{
+			name:    "expired",
+			token:   rsaSigner.sign(t, claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()})),
+			wantErr: "expired",
+		},
+		{
+			name:    "not valid yet",
+			token:   rsaSigner.sign(t, claims(map[string]any{"nbf": now.Add(2 * time.Minute).Unix()})),
+			wantErr: "not valid yet",
+		},
+		{
+			name:    "missing expiry",
+			token:   rsaSigner.sign(t, claims(map[string]any{"exp": nil})),
+			wantErr: "missing expiry",
+		},
+		{
+			name:    "other issuer",
+			token:   rsaSigner.sign(t, claims(map[string]any{"iss": "https://evil.example.com"})),
+			wantErr: "unexpected issuer",
+		},
+		{
+			name:    "other audience",
+			token:   rsaSigner.sign(t, claims(map[string]any{"aud": "billing"})),
+			wantErr: "not issued for",
+		},
+		{
+			name:    "missing audience",
+			token:   rsaSigner.sign(t, claims(map[string]any{"aud": nil})),
+			wantErr: "not issued for",
+		},
+		{
+			name:    "unknown key",
+			token:   unknownSigner.sign(t, claims(nil)),
+			wantErr: "unknown key ID",
+		},
+		{
+			name: "key of another signer",
+			token: func() string {
+				forged := newRSASigner(t, "rsa-1")
+				return forged.sign(t, claims(nil))
+			}(),
+			wantErr: "bad signature",
+		},
+		{
+			name: "tampered claims",
+			token: func() string {
+				parts := strings.Split(rsaSigner.sign(t, claims(nil)), ".")
+				payload, _ := json.Marshal(claims(map[string]any{"sub": "mallory"}))
+				parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+				return strings.Join(parts, ".")
+			}(),
+			wantErr: "bad signature",
+		},
+		{
+			name: "unsigned token",
+			token: func() string {
+				header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+				payload, _ := json.Marshal(claims(nil))
+				return header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+			}(),
+			wantErr: "unsupported algorithm",
+		},
+		{
+			name:    "not a JWT",
+			token:   "pk_0123abcd",
+			wantErr: "not a JWT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(context.Background(), tt.token)
+
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPrincipal, got)
+		})
+	}
+}
+
+func TestTokenVerifier_RequiresAudience(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	verifier := NewTokenVerifier(TokenConfig{Issuer: "https://id.example.com"}, NewKeySet(writeJWKS(t, signer), nil))
+	token := signer.sign(t, map[string]any{"iss": "https://id.example.com", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+
+	_, err := verifier.Verify(context.Background(), token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenVerifier_AlgorithmMustMatchKey(t *testing.T) {
+	ecSigner := newECSigner(t, "ec-1")
+	verifier := NewTokenVerifier(TokenConfig{Issuer: "https://id.example.com", Audience: "pack-calculator"}, NewKeySet(writeJWKS(t, ecSigner), nil))
+
+	ecSigner.alg = "RS256"
+	token := ecSigner.sign(t, map[string]any{"iss": "https://id.example.com", "aud": "pack-calculator", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+
+	_, err := verifier.Verify(context.Background(), token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,166 @@
+// Package client is a typed Go client for the pack calculator HTTP API. It encodes the
+// request types of the apitypes package, decodes error responses into *Error and retries
+// requests that were rate limited, and reads that failed on the server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for the zero value of Options
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Options configure a Client. The zero value uses the defaults.
+type Options struct {
+	// HTTPClient sends the requests, a client with DefaultTimeout when nil
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries after a 429 response, or a 5xx response to a
+	// GET or HEAD request. A negative value disables retries.
+	MaxRetries int
+	// Backoff is the wait before the first retry. It doubles with every retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// APIKey is sent in the X-API-Key header when the server requires authentication
+	APIKey string
+	// Tenant is sent in the X-Tenant-ID header to act for a tenant other than the default
+	Tenant string
+}
+
+// Client calls the pack calculator API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	apiKey     string
+	tenant     string
+}
+
+// New creates a client for the server at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts Options) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api",
+		httpClient: opts.HTTPClient,
+		maxRetries: opts.MaxRetries,
+		backoff:    opts.Backoff,
+		maxBackoff: opts.MaxBackoff,
+		apiKey:     opts.APIKey,
+		tenant:     opts.Tenant,
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = DefaultMaxRetries
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.backoff <= 0 {
+		c.backoff = DefaultBackoff
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = DefaultMaxBackoff
+	}
+	return c
+}
+
+// doJSON sends body encoded as JSON and decodes the response into out
+func (c *Client) doJSON(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	return c.do(ctx, method, path, "application/json", payload, out)
+}
+
+// do sends the request, retrying rate limited requests and server errors with an
+// exponential backoff. A Retry-After header in seconds overrides the backoff. A rate
+// limited request was not handled, but the server may have acted on a request that
+// failed, so server errors are only retried for methods that do not change anything.
+func (c *Client) do(ctx context.Context, method, path, contentType string, payload []byte, out any) error {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, contentType, payload)
+		if err != nil {
+			return err
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError && idempotent(method)
+		if !retryable || attempt == c.maxRetries {
+			return decodeResponse(resp, method, path, out)
+		}
+
+		wait := backoff
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+// idempotent reports whether repeating a request with the method has no further effect
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func (c *Client) send(ctx context.Context, method, path, contentType string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.tenant)
+	}
+	return c.httpClient.Do(req)
+}
+
+// decodeResponse decodes a successful response into out, or an error response into *Error
+func decodeResponse(resp *http.Response, method, path string, out any) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp, method, path)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
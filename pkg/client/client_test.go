@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pack-calculator/pkg/apitypes"
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         Options
+		post         bool
+		failures     int
+		status       int
+		retryAfter   string
+		wantAttempts int32
+		wantStatus   int
+	}{
+		{
+			name:         "retries server errors until success",
+			failures:     2,
+			status:       http.StatusInternalServerError,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries rate limited requests",
+			failures:     1,
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "0",
+			wantAttempts: 2,
+		},
+		{
+			name:         "gives up after the maximum retries",
+			opts:         Options{MaxRetries: 2},
+			failures:     5,
+			status:       http.StatusServiceUnavailable,
+			wantAttempts: 3,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:         "retries disabled",
+			opts:         Options{MaxRetries: -1},
+			failures:     1,
+			status:       http.StatusBadGateway,
+			wantAttempts: 1,
+			wantStatus:   http.StatusBadGateway,
+		},
+		{
+			name:         "server errors of a POST are not retried",
+			post:         true,
+			failures:     1,
+			status:       http.StatusInternalServerError,
+			wantAttempts: 1,
+			wantStatus:   http.StatusInternalServerError,
+		},
+		{
+			name:         "rate limited POST is retried",
+			post:         true,
+			failures:     1,
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "0",
+			wantAttempts: 2,
+		},
+		{
+			name:         "client errors are not retried",
+			failures:     1,
+			status:       http.StatusBadRequest,
+			wantAttempts: 1,
+			wantStatus:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) <= int32(tt.failures) {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"Type":"INTERNAL","Message":"try again","Err":{}}`))
+					return
+				}
+				w.Write([]byte(`{"packSizes":[250,500]}`))
+			}))
+			defer server.Close()
+			tt.opts.Backoff = time.Millisecond
+
+			api := New(server.URL, tt.opts)
+			var got *apitypes.PackConfiguration
+			var err error
+			if tt.post {
+				got, err = api.CreatePackConfiguration(context.Background(), apitypes.PackConfigurationRequest{PackSizes: []int{250, 500}})
+			} else {
+				got, err = api.GetActivePackConfiguration(context.Background())
+			}
+
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+			if tt.wantStatus != 0 {
+				var apiErr *Error
+				assert.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tt.wantStatus, apiErr.StatusCode)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []int{250, 500}, got.PackSizes)
+		})
+	}
+}
+
+func TestClient_RetryStopsWhenContextIsDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := New(server.URL, Options{Backoff: time.Hour}).GetActivePackConfiguration(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantType    apperrors.ErrorType
+		wantMessage string
+	}{
+		{
+			name:        "validation error",
+			status:      http.StatusBadRequest,
+			body:        `{"Type":"INVALID_REQUEST","Message":"Pack sizes must be positive","Err":{}}`,
+			wantType:    apperrors.ErrorTypeInvalidRequest,
+			wantMessage: "Pack sizes must be positive",
+		},
+		{
+			name:        "body that is not an error",
+			status:      http.StatusNotFound,
+			body:        "404 page not found",
+			wantType:    apperrors.ErrorTypeNotFound,
+			wantMessage: "Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := New(server.URL, Options{}).GetPackConfiguration(context.Background(), 3)
+
+			assert.True(t, apperrors.IsType(err, tt.wantType))
+			var apiErr *Error
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.wantMessage, apiErr.Err.Message)
+			assert.Equal(t, "/packs/configurations/3", apiErr.Path)
+		})
+	}
+}
+
+func TestClient_Tenant(t *testing.T) {
+	var gotTenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTenant = r.Header.Get("X-Tenant-ID")
+		w.Write([]byte(`{"id":4,"packSizes":[6,12],"active":true}`))
+	}))
+	defer server.Close()
+
+	config, err := New(server.URL, Options{Tenant: "retail"}).GetActivePackConfiguration(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "retail", gotTenant)
+	assert.Equal(t, uint(4), config.ID)
+}
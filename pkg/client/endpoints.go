@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pack-calculator/pkg/apitypes"
+)
+
+// GetActivePackConfiguration returns the active pack configuration
+func (c *Client) GetActivePackConfiguration(ctx context.Context) (*apitypes.PackConfiguration, error) {
+	var response apitypes.PackConfiguration
+	if err := c.doJSON(ctx, http.MethodGet, "/packs", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreatePackConfiguration creates a pack configuration and makes it the active one
+func (c *Client) CreatePackConfiguration(ctx context.Context, request apitypes.PackConfigurationRequest) (*apitypes.PackConfiguration, error) {
+	var response apitypes.PackConfiguration
+	if err := c.doJSON(ctx, http.MethodPost, "/packs", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListPackConfigurations returns every pack configuration in the order they were created
+func (c *Client) ListPackConfigurations(ctx context.Context) ([]apitypes.PackConfiguration, error) {
+	var response apitypes.PackConfigurationsResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/packs/configurations", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Configurations, nil
+}
+
+// GetPackConfiguration returns a pack configuration by ID
+func (c *Client) GetPackConfiguration(ctx context.Context, id uint) (*apitypes.PackConfiguration, error) {
+	var response apitypes.PackConfiguration
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/packs/configurations/%d", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ActivatePackConfiguration makes an existing pack configuration the active one
+func (c *Client) ActivatePackConfiguration(ctx context.Context, id uint) (*apitypes.PackConfiguration, error) {
+	var response apitypes.PackConfiguration
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/packs/configurations/%d/activate", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Calculate calculates the optimal packs for an order. When no packing fits the overfill
+// cap the error wraps a *apitypes.NoAcceptablePackingError with the closest options.
+func (c *Client) Calculate(ctx context.Context, request apitypes.CalculateRequest) (*apitypes.CalculateResponse, error) {
+	var response apitypes.CalculateResponse
+	if err := c.doJSON(ctx, http.MethodPost, "/calculate", request, &response); err != nil {
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+			apiErr.decodeDetails(&apitypes.NoAcceptablePackingError{})
+		}
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCalculations returns a page of order calculations, newest first. A limit of 0 uses the server default.
+func (c *Client) ListCalculations(ctx context.Context, offset, limit int) ([]apitypes.Calculation, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var response apitypes.CalculationsResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/calculations?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Calculations, nil
+}
+
+// GetCalculation returns an order calculation by ID
+func (c *Client) GetCalculation(ctx context.Context, id uint) (*apitypes.Calculation, error) {
+	var response apitypes.Calculation
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/calculations/%d", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AmendCalculation raises the quantity of a calculation, keeping its packs fixed
+func (c *Client) AmendCalculation(ctx context.Context, id uint, request apitypes.AmendRequest) (*apitypes.AmendResponse, error) {
+	var response apitypes.AmendResponse
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/calculations/%d/amend", id), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListRates returns the carrier rate tables
+func (c *Client) ListRates(ctx context.Context) ([]apitypes.Rate, error) {
+	var response apitypes.RatesResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/rates", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Rates, nil
+}
+
+// UploadRates replaces the rate tables of the carriers in a CSV rate table
+func (c *Client) UploadRates(ctx context.Context, csv io.Reader) ([]apitypes.Rate, error) {
+	payload, err := io.ReadAll(csv)
+	if err != nil {
+		return nil, err
+	}
+	var response apitypes.RatesResponse
+	if err := c.do(ctx, http.MethodPost, "/rates", "text/csv", payload, &response); err != nil {
+		return nil, err
+	}
+	return response.Rates, nil
+}
+
+// Quote quotes shipping for the item-optimal and the cost-optimal packing of an order
+func (c *Client) Quote(ctx context.Context, request apitypes.QuoteRequest) (*apitypes.QuoteResponse, error) {
+	var response apitypes.QuoteResponse
+	if err := c.doJSON(ctx, http.MethodPost, "/quote", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListWarehouses returns the warehouses and their inventory
+func (c *Client) ListWarehouses(ctx context.Context) ([]apitypes.Warehouse, error) {
+	var response apitypes.WarehousesResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/warehouses", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Warehouses, nil
+}
+
+// SaveWarehouse creates a warehouse or replaces its inventory
+func (c *Client) SaveWarehouse(ctx context.Context, request apitypes.WarehouseRequest) (*apitypes.Warehouse, error) {
+	var response apitypes.Warehouse
+	if err := c.doJSON(ctx, http.MethodPost, "/warehouses", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CommitReservation confirms a reservation and deducts its packs from warehouse stock
+func (c *Client) CommitReservation(ctx context.Context, id uint) (*apitypes.Reservation, error) {
+	var response apitypes.Reservation
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/reservations/%d/commit", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Stats returns the calculation statistics grouped by period and configuration
+func (c *Client) Stats(ctx context.Context, request apitypes.StatsRequest) (*apitypes.StatsResponse, error) {
+	query := url.Values{}
+	if request.Period != "" {
+		query.Set("period", request.Period)
+	}
+	if request.BucketSize != 0 {
+		query.Set("bucketSize", strconv.Itoa(request.BucketSize))
+	}
+	if request.ConfigurationID != 0 {
+		query.Set("configurationId", strconv.FormatUint(uint64(request.ConfigurationID), 10))
+	}
+	if !request.From.IsZero() {
+		query.Set("from", request.From.Format("2006-01-02"))
+	}
+	if !request.To.IsZero() {
+		query.Set("to", request.To.Format("2006-01-02"))
+	}
+
+	var response apitypes.StatsResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/stats?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// LatestForecast returns the latest replenishment forecast
+func (c *Client) LatestForecast(ctx context.Context) (*apitypes.Forecast, error) {
+	var response apitypes.Forecast
+	if err := c.doJSON(ctx, http.MethodGet, "/forecast", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StartExperiment starts trialling a candidate configuration on a share of the orders
+func (c *Client) StartExperiment(ctx context.Context, request apitypes.ExperimentRequest) (*apitypes.Experiment, error) {
+	var response apitypes.Experiment
+	if err := c.doJSON(ctx, http.MethodPost, "/experiments", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StopExperiment stops an experiment
+func (c *Client) StopExperiment(ctx context.Context, id uint) (*apitypes.Experiment, error) {
+	var response apitypes.Experiment
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/experiments/%d/stop", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ExperimentResults compares the arms of an experiment
+func (c *Client) ExperimentResults(ctx context.Context, id uint) (*apitypes.ExperimentResults, error) {
+	var response apitypes.ExperimentResults
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/experiments/%d/results", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListCustomerProfiles returns the customer profiles
+func (c *Client) ListCustomerProfiles(ctx context.Context) ([]apitypes.CustomerProfile, error) {
+	var response apitypes.CustomerProfilesResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/customers", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Profiles, nil
+}
+
+// SaveCustomerProfile creates a customer profile or replaces it
+func (c *Client) SaveCustomerProfile(ctx context.Context, request apitypes.CustomerProfileRequest) (*apitypes.CustomerProfile, error) {
+	var response apitypes.CustomerProfile
+	if err := c.doJSON(ctx, http.MethodPost, "/customers", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListAPIKeys returns every API key, without the keys themselves
+func (c *Client) ListAPIKeys(ctx context.Context) ([]apitypes.APIKey, error) {
+	var response apitypes.APIKeysResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/keys", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Keys, nil
+}
+
+// IssueAPIKey issues an API key. The key is only returned here and cannot be retrieved later.
+func (c *Client) IssueAPIKey(ctx context.Context, request apitypes.IssueAPIKeyRequest) (*apitypes.IssueAPIKeyResponse, error) {
+	var response apitypes.IssueAPIKeyResponse
+	if err := c.doJSON(ctx, http.MethodPost, "/keys", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RevokeAPIKey revokes an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, id uint) (*apitypes.APIKey, error) {
+	var response apitypes.APIKey
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/keys/%d/revoke", id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListAuditEvents returns a page of the audit trail matching the request, newest first.
+// A limit of 0 uses the server default.
+func (c *Client) ListAuditEvents(ctx context.Context, request apitypes.ListAuditEventsRequest) ([]apitypes.AuditEvent, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"actor":        request.Actor,
+		"action":       request.Action,
+		"resourceType": request.ResourceType,
+		"resourceId":   request.ResourceID,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if !request.From.IsZero() {
+		query.Set("from", request.From.Format(time.RFC3339))
+	}
+	if !request.To.IsZero() {
+		query.Set("to", request.To.Format(time.RFC3339))
+	}
+	query.Set("offset", strconv.Itoa(request.Offset))
+	if request.Limit > 0 {
+		query.Set("limit", strconv.Itoa(request.Limit))
+	}
+
+	var response apitypes.AuditEventsResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/audit?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Events, nil
+}
+
+// VerifyAuditTrail checks the hash chain of the audit trail
+func (c *Client) VerifyAuditTrail(ctx context.Context) (*apitypes.AuditVerification, error) {
+	var response apitypes.AuditVerification
+	if err := c.doJSON(ctx, http.MethodGet, "/audit/verify", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
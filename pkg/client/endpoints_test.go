@@ -0,0 +1,626 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/pack-calculator/api"
+	"github.com/pack-calculator/api/middleware"
+	"github.com/pack-calculator/config"
+	"github.com/pack-calculator/internal/api_keys"
+	"github.com/pack-calculator/internal/audit_events"
+	"github.com/pack-calculator/internal/customers"
+	"github.com/pack-calculator/internal/experiments"
+	"github.com/pack-calculator/internal/forecasts"
+	"github.com/pack-calculator/internal/order_calculations"
+	"github.com/pack-calculator/internal/pack_configurations"
+	"github.com/pack-calculator/internal/reservations"
+	"github.com/pack-calculator/internal/shipping_rates"
+	"github.com/pack-calculator/internal/stats"
+	"github.com/pack-calculator/internal/warehouses"
+	"github.com/pack-calculator/pkg/apitypes"
+	"github.com/pack-calculator/pkg/auth"
+	apperrors "github.com/pack-calculator/pkg/errors"
+	"github.com/pack-calculator/pkg/packsolver"
+)
+
+// fakePacks keeps pack configurations in memory
+type fakePacks struct {
+	mu      sync.Mutex
+	configs []pack_configurations.PackConfiguration
+	active  uint
+}
+
+func (f *fakePacks) Create(ctx context.Context, config *pack_configurations.PackConfiguration) (*pack_configurations.PackConfiguration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	config.ID = uint(len(f.configs) + 1)
+	f.configs = append(f.configs, *config)
+	f.active = config.ID
+	return config, nil
+}
+
+func (f *fakePacks) GetActive(ctx context.Context) (*pack_configurations.PackConfiguration, error) {
+	return f.Get(ctx, f.active)
+}
+
+func (f *fakePacks) List(ctx context.Context) ([]pack_configurations.PackConfiguration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	configs := append([]pack_configurations.PackConfiguration(nil), f.configs...)
+	for i := range configs {
+		configs[i].Active = configs[i].ID == f.active
+	}
+	return configs, nil
+}
+
+func (f *fakePacks) Get(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id == 0 || int(id) > len(f.configs) {
+		return nil, pack_configurations.ErrConfigurationNotFound
+	}
+	config := f.configs[id-1]
+	config.Active = id == f.active
+	return &config, nil
+}
+
+func (f *fakePacks) Activate(ctx context.Context, id uint) (*pack_configurations.PackConfiguration, error) {
+	if _, err := f.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.active = id
+	f.mu.Unlock()
+	return f.Get(ctx, id)
+}
+
+// fakeCalculations solves orders with the solver library over the active pack sizes
+type fakeCalculations struct {
+	mu    sync.Mutex
+	packs *fakePacks
+	calcs []order_calculations.OrderCalculation
+}
+
+func (f *fakeCalculations) OrderProcessing(ctx context.Context, order order_calculations.OrderRequest) (*order_calculations.OrderCalculation, error) {
+	packCfg, err := f.packs.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	solution, err := packsolver.Solve(packSizes(packCfg.PackSizes), order.OrderQuantity, packsolver.Options{})
+	if err != nil {
+		return nil, err
+	}
+	if order.Overfill != nil && order.Overfill.MaxItems > 0 && solution.TotalItems-order.OrderQuantity > order.Overfill.MaxItems {
+		return nil, &order_calculations.NoAcceptablePackingError{
+			OrderQuantity: order.OrderQuantity,
+			MaxOverfill:   order.Overfill.MaxItems,
+			Options:       []order_calculations.PackingOption{{TotalItems: solution.TotalItems, TotalPacks: solution.TotalPacks, Packs: packResults(solution.Packs)}},
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calc := order_calculations.OrderCalculation{
+		ID:              uint(len(f.calcs) + 1),
+		OrderQuantity:   order.OrderQuantity,
+		MinQuantity:     order.OrderQuantity,
+		Mode:            order_calculations.CalculationModeStandard,
+		Result:          packResults(solution.Packs),
+		TotalItems:      solution.TotalItems,
+		TotalPacks:      solution.TotalPacks,
+		ConfigurationID: packCfg.ID,
+		Timestamp:       time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+	f.calcs = append(f.calcs, calc)
+	return &calc, nil
+}
+
+func (f *fakeCalculations) CalculateOptimalPacks(ctx context.Context, orderQuantity int, sizes []int) (map[int]int, error) {
+	solution, err := packsolver.Solve(sizes, orderQuantity, packsolver.Options{})
+	return solution.Packs, err
+}
+
+func (f *fakeCalculations) AmendOrder(ctx context.Context, calculationID uint, orderQuantity int) (*order_calculations.Amendment, error) {
+	original, err := f.GetCalculation(ctx, calculationID)
+	if err != nil {
+		return nil, err
+	}
+	amended, err := f.OrderProcessing(ctx, order_calculations.OrderRequest{OrderQuantity: orderQuantity})
+	if err != nil {
+		return nil, err
+	}
+	return &order_calculations.Amendment{
+		Calculation: amended,
+		FixedPacks:  original.Result,
+		FromScratch: order_calculations.PackingOption{TotalItems: amended.TotalItems, TotalPacks: amended.TotalPacks, Packs: amended.Result},
+	}, nil
+}
+
+func (f *fakeCalculations) ListCalculations(ctx context.Context, offset, limit int) ([]order_calculations.OrderCalculation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var page []order_calculations.OrderCalculation
+	for i := len(f.calcs) - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		page = append(page, f.calcs[i])
+	}
+	return page, nil
+}
+
+func (f *fakeCalculations) GetCalculation(ctx context.Context, id uint) (*order_calculations.OrderCalculation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id == 0 || int(id) > len(f.calcs) {
+		return nil, order_calculations.ErrCalculationNotFound
+	}
+	calc := f.calcs[id-1]
+	return &calc, nil
+}
+
+type fakeRates struct{ rates []shipping_rates.Rate }
+
+func (f *fakeRates) Upload(ctx context.Context, rates []shipping_rates.Rate) error {
+	f.rates = rates
+	return nil
+}
+
+func (f *fakeRates) List(ctx context.Context) ([]shipping_rates.Rate, error) { return f.rates, nil }
+
+func (f *fakeRates) Quote(ctx context.Context, request shipping_rates.QuoteRequest) (*shipping_rates.Quote, error) {
+	if len(f.rates) == 0 {
+		return nil, apperrors.NewValidationError("No rates for zone " + request.Zone)
+	}
+	option := shipping_rates.QuoteOption{Carrier: f.rates[0].Carrier, TotalItems: request.OrderQuantity, TotalPacks: 1, ShippingCost: f.rates[0].Cost}
+	return &shipping_rates.Quote{OrderQuantity: request.OrderQuantity, Zone: request.Zone, ItemOptimal: option, CostOptimal: option}, nil
+}
+
+type fakeWarehouses struct{ warehouses []warehouses.Warehouse }
+
+func (f *fakeWarehouses) Save(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	warehouse.ID = uint(len(f.warehouses) + 1)
+	f.warehouses = append(f.warehouses, *warehouse)
+	return nil
+}
+
+func (f *fakeWarehouses) List(ctx context.Context) ([]warehouses.Warehouse, error) {
+	return f.warehouses, nil
+}
+
+type fakeReservations struct{}
+
+func (fakeReservations) Commit(ctx context.Context, id uint) (*reservations.Reservation, error) {
+	if id != 1 {
+		return nil, reservations.ErrReservationNotFound
+	}
+	return &reservations.Reservation{ID: 1, CalculationID: 1, Status: reservations.StatusCommitted}, nil
+}
+
+func (fakeReservations) ReleaseExpired(ctx context.Context) (int64, error) { return 0, nil }
+
+type fakeStats struct{ request stats.Request }
+
+func (f *fakeStats) Stats(ctx context.Context, request stats.Request) (*stats.Stats, error) {
+	f.request = request
+	return &stats.Stats{Period: request.Period, BucketSize: request.BucketSize, Groups: []stats.Group{}}, nil
+}
+
+func (f *fakeStats) Refresh(ctx context.Context) error { return nil }
+
+// fakeAudit records the filter of the last listing and reports an intact chain
+type fakeAudit struct{ filter audit_events.Filter }
+
+func (f *fakeAudit) List(ctx context.Context, filter audit_events.Filter) ([]audit_events.Event, error) {
+	f.filter = filter
+	return []audit_events.Event{{ID: 1, Action: audit_events.ActionAPIKeyIssue, ResourceType: audit_events.ResourceAPIKey, ResourceID: "1", Actor: "bootstrap"}}, nil
+}
+
+func (f *fakeAudit) Verify(ctx context.Context) (*audit_events.Verification, error) {
+	return &audit_events.Verification{Valid: true, Events: 1}, nil
+}
+
+// fakeForecasts fails the given number of requests before returning a forecast
+type fakeForecasts struct{ failures int }
+
+func (f *fakeForecasts) Generate(ctx context.Context) (*forecasts.Forecast, error) { return nil, nil }
+
+func (f *fakeForecasts) Tenants(ctx context.Context) ([]string, error) { return nil, nil }
+
+func (f *fakeForecasts) Latest(ctx context.Context) (*forecasts.Forecast, error) {
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("database is restarting")
+	}
+	return &forecasts.Forecast{ID: 4, Method: "exponential", HistoryDays: 28, HorizonDays: 14, Items: []forecasts.ForecastItem{}}, nil
+}
+
+type fakeExperiments struct{ experiments []experiments.Experiment }
+
+func (f *fakeExperiments) Start(ctx context.Context, experiment *experiments.Experiment) error {
+	experiment.ID = uint(len(f.experiments) + 1)
+	experiment.Status = experiments.StatusRunning
+	f.experiments = append(f.experiments, *experiment)
+	return nil
+}
+
+func (f *fakeExperiments) Stop(ctx context.Context, id uint) (*experiments.Experiment, error) {
+	if id == 0 || int(id) > len(f.experiments) {
+		return nil, experiments.ErrExperimentNotFound
+	}
+	f.experiments[id-1].Status = experiments.StatusStopped
+	return &f.experiments[id-1], nil
+}
+
+func (f *fakeExperiments) Results(ctx context.Context, id uint) (*experiments.Results, error) {
+	if id == 0 || int(id) > len(f.experiments) {
+		return nil, experiments.ErrExperimentNotFound
+	}
+	return &experiments.Results{Experiment: f.experiments[id-1], Arms: []experiments.ArmComparison{}}, nil
+}
+
+type fakeCustomers struct{ profiles []customers.Profile }
+
+func (f *fakeCustomers) Save(ctx context.Context, profile *customers.Profile) error {
+	f.profiles = append(f.profiles, *profile)
+	return nil
+}
+
+func (f *fakeCustomers) List(ctx context.Context) ([]customers.Profile, error) {
+	return f.profiles, nil
+}
+
+// fakeKeys keeps API keys in memory, by key
+type fakeKeys struct {
+	mu   sync.Mutex
+	keys map[string]*api_keys.APIKey
+}
+
+func (f *fakeKeys) Issue(ctx context.Context, request *api_keys.IssueAPIRequest) (*api_keys.IssueAPIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.keys == nil {
+		f.keys = make(map[string]*api_keys.APIKey)
+	}
+	key := fmt.Sprintf("pk_test%d", len(f.keys)+1)
+	f.keys[key] = &api_keys.APIKey{ID: uint(len(f.keys) + 1), Name: request.Name, Scopes: request.Scopes, CreatedBy: auth.Subject(ctx)}
+	return &api_keys.IssueAPIResponse{APIKey: *f.keys[key], Key: key}, nil
+}
+
+func (f *fakeKeys) List(ctx context.Context) ([]api_keys.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := []api_keys.APIKey{}
+	for _, key := range f.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (f *fakeKeys) Revoke(ctx context.Context, id uint) (*api_keys.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range f.keys {
+		if key.ID == id {
+			now := time.Now()
+			key.RevokedAt = &now
+			return key, nil
+		}
+	}
+	return nil, api_keys.ErrKeyNotFound
+}
+
+func (f *fakeKeys) Authenticate(ctx context.Context, key string) (*auth.Principal, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	apiKey, ok := f.keys[key]
+	if !ok || apiKey.RevokedAt != nil {
+		return nil, api_keys.ErrInvalidKey
+	}
+	return &auth.Principal{Subject: apiKey.Subject(), Name: apiKey.Name, Scopes: apiKey.Scopes}, nil
+}
+
+func packSizes(sizes pq.Int64Array) []int {
+	ints := make([]int, len(sizes))
+	for i, size := range sizes {
+		ints[i] = int(size)
+	}
+	return ints
+}
+
+func packResults(packs map[int]int) []order_calculations.PackResult {
+	results := make([]order_calculations.PackResult, 0, len(packs))
+	for size, quantity := range packs {
+		results = append(results, order_calculations.PackResult{Size: size, Quantity: quantity})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Size > results[j].Size })
+	return results
+}
+
+type testServer struct {
+	client    *Client
+	url       string
+	stats     *fakeStats
+	forecasts *fakeForecasts
+	audit     *fakeAudit
+}
+
+// newTestServer serves the API router with in-memory services
+func newTestServer(t *testing.T, cfg *config.AppConfig, opts Options) *testServer {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+	packs := &fakePacks{}
+	keys := &fakeKeys{}
+	ts := &testServer{stats: &fakeStats{}, forecasts: &fakeForecasts{}, audit: &fakeAudit{}}
+
+	router := api.SetupRouter(logger, cfg, middleware.NewAuthenticator(cfg.Auth, keys, nil), middleware.NewRateLimiter(cfg.RateLimiter),
+		pack_configurations.NewHandler(logger, packs),
+		order_calculations.NewHandler(logger, &fakeCalculations{packs: packs}),
+		shipping_rates.NewHandler(logger, &fakeRates{}),
+		warehouses.NewHandler(logger, &fakeWarehouses{}),
+		reservations.NewHandler(logger, fakeReservations{}),
+		stats.NewHandler(logger, ts.stats),
+		forecasts.NewHandler(logger, ts.forecasts),
+		experiments.NewHandler(logger, &fakeExperiments{}),
+		customers.NewHandler(logger, &fakeCustomers{}),
+		api_keys.NewHandler(logger, keys),
+		audit_events.NewHandler(logger, ts.audit),
+	)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	if opts.Backoff == 0 {
+		opts.Backoff = time.Millisecond
+	}
+	ts.url = server.URL
+	ts.client = New(server.URL, opts)
+	return ts
+}
+
+func TestClient_PackConfigurations(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, &config.AppConfig{}, Options{}).client
+
+	created, err := c.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: []int{250, 500, 1000}, Rules: []string{"at most 2 x 250"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 500, 1000}, created.PackSizes)
+	_, err = c.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: []int{23, 31, 53}})
+	assert.NoError(t, err)
+
+	active, err := c.GetActivePackConfiguration(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &apitypes.PackConfiguration{ID: 2, Active: true, PackSizes: []int{23, 31, 53}}, active)
+
+	configs, err := c.ListPackConfigurations(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []apitypes.PackConfiguration{
+		{ID: 1, PackSizes: []int{250, 500, 1000}, Rules: []string{"at most 2 x 250"}},
+		{ID: 2, Active: true, PackSizes: []int{23, 31, 53}},
+	}, configs)
+
+	activated, err := c.ActivatePackConfiguration(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, activated.Active)
+
+	got, err := c.GetPackConfiguration(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, activated, got)
+
+	_, err = c.GetPackConfiguration(ctx, 9)
+	var apiErr *Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Pack configuration not found", apiErr.Err.Message)
+
+	_, err = c.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: []int{-250}})
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+}
+
+func TestClient_Calculations(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, &config.AppConfig{}, Options{}).client
+	_, err := c.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: []int{250, 500, 1000, 2000, 5000}})
+	assert.NoError(t, err)
+
+	calculated, err := c.Calculate(ctx, apitypes.CalculateRequest{OrderQuantity: 12001})
+	assert.NoError(t, err)
+	assert.True(t, calculated.Success)
+	assert.Equal(t, 12250, calculated.TotalItems)
+	assert.Equal(t, []apitypes.PackResult{{Size: 5000, Quantity: 2}, {Size: 2000, Quantity: 1}, {Size: 250, Quantity: 1}}, calculated.Packs)
+
+	_, err = c.Calculate(ctx, apitypes.CalculateRequest{OrderQuantity: 1})
+	assert.NoError(t, err)
+
+	calcs, err := c.ListCalculations(ctx, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, calcs, 2)
+	assert.Equal(t, uint(2), calcs[0].ID)
+
+	calc, err := c.GetCalculation(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 12001, calc.OrderQuantity)
+
+	amended, err := c.AmendCalculation(ctx, 2, apitypes.AmendRequest{OrderQuantity: 501})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), amended.AmendsID)
+	assert.Equal(t, 750, amended.TotalItems)
+
+	_, err = c.ListCalculations(ctx, -1, 10)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+
+	_, err = c.AmendCalculation(ctx, 9, apitypes.AmendRequest{OrderQuantity: 501})
+	var apiErr *Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeNotFound))
+}
+
+func TestClient_CalculateNoAcceptablePacking(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, &config.AppConfig{}, Options{}).client
+	_, err := c.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: []int{1000}})
+	assert.NoError(t, err)
+
+	_, err = c.Calculate(ctx, apitypes.CalculateRequest{OrderQuantity: 1001, Overfill: &apitypes.OverfillPolicy{MaxItems: 100}})
+
+	var noAcceptable *apitypes.NoAcceptablePackingError
+	assert.ErrorAs(t, err, &noAcceptable)
+	assert.Equal(t, 1001, noAcceptable.OrderQuantity)
+	assert.Equal(t, 2000, noAcceptable.Options[0].TotalItems)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeUnprocessable))
+}
+
+func TestClient_ShippingAndInventory(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, &config.AppConfig{}, Options{}).client
+
+	_, err := c.Quote(ctx, apitypes.QuoteRequest{OrderQuantity: 500, Zone: "eu"})
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeInvalidRequest))
+
+	uploaded, err := c.UploadRates(ctx, strings.NewReader("carrier,zone,max_weight,cost\nups,eu,10,5.5\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []apitypes.Rate{{Carrier: "ups", Zone: "eu", MaxWeight: 10, Cost: 5.5}}, uploaded)
+
+	rates, err := c.ListRates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uploaded, rates)
+
+	quote, err := c.Quote(ctx, apitypes.QuoteRequest{OrderQuantity: 500, Zone: "eu"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ups", quote.CostOptimal.Carrier)
+
+	warehouse, err := c.SaveWarehouse(ctx, apitypes.WarehouseRequest{Name: "north", Inventory: map[int]int{250: 4}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), warehouse.ID)
+
+	list, err := c.ListWarehouses(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []apitypes.Warehouse{*warehouse}, list)
+
+	reservation, err := c.CommitReservation(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, reservations.StatusCommitted, reservation.Status)
+
+	_, err = c.CommitReservation(ctx, 2)
+	var apiErr *Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeNotFound))
+}
+
+func TestClient_InsightsAndExperiments(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServer(t, &config.AppConfig{}, Options{})
+	c := ts.client
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	got, err := c.Stats(ctx, apitypes.StatsRequest{Period: "week", BucketSize: 50, ConfigurationID: 3, From: from})
+	assert.NoError(t, err)
+	assert.Equal(t, "week", got.Period)
+	assert.Equal(t, uint(3), ts.stats.request.ConfigurationID)
+	assert.True(t, from.Equal(ts.stats.request.From))
+
+	ts.forecasts.failures = 2
+	forecast, err := c.LatestForecast(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), forecast.ID)
+
+	experiment, err := c.StartExperiment(ctx, apitypes.ExperimentRequest{Name: "bigger packs", ConfigurationID: 2, TrafficShare: 0.2})
+	assert.NoError(t, err)
+	assert.Equal(t, experiments.StatusRunning, experiment.Status)
+
+	results, err := c.ExperimentResults(ctx, experiment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "bigger packs", results.Experiment.Name)
+
+	stopped, err := c.StopExperiment(ctx, experiment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, experiments.StatusStopped, stopped.Status)
+
+	profile, err := c.SaveCustomerProfile(ctx, apitypes.CustomerProfileRequest{CustomerID: "acme", Name: "Acme", ExcludedSizes: []int{250}})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", profile.CustomerID)
+
+	profiles, err := c.ListCustomerProfiles(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []apitypes.CustomerProfile{*profile}, profiles)
+}
+
+func TestClient_APIKeys(t *testing.T) {
+	ctx := context.Background()
+	bootstrapKey := "bootstrap-key-of-at-least-32-characters"
+	ts := newTestServer(t, &config.AppConfig{Auth: config.AuthConfig{Enabled: true, BootstrapKey: bootstrapKey}}, Options{APIKey: bootstrapKey})
+
+	_, err := New(ts.url, Options{}).ListPackConfigurations(ctx)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeUnauthorized))
+
+	issued, err := ts.client.IssueAPIKey(ctx, apitypes.IssueAPIKeyRequest{Name: "reader", Scopes: []string{auth.ScopePacksRead}})
+	assert.NoError(t, err)
+	assert.Equal(t, "bootstrap", issued.CreatedBy)
+	reader := New(ts.url, Options{APIKey: issued.Key})
+
+	_, err = reader.ListPackConfigurations(ctx)
+	assert.NoError(t, err)
+	_, err = reader.CreatePackConfiguration(ctx, apitypes.PackConfigurationRequest{PackSizes: []int{250}})
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeForbidden))
+
+	keys, err := ts.client.ListAPIKeys(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	revoked, err := ts.client.RevokeAPIKey(ctx, issued.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	_, err = reader.ListPackConfigurations(ctx)
+	assert.True(t, apperrors.IsType(err, apperrors.ErrorTypeUnauthorized))
+}
+
+func TestClient_AuditTrail(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServer(t, &config.AppConfig{}, Options{})
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	events, err := ts.client.ListAuditEvents(ctx, apitypes.ListAuditEventsRequest{Actor: "bootstrap", ResourceType: audit_events.ResourceAPIKey, From: from})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "bootstrap", ts.audit.filter.Actor)
+	assert.Equal(t, audit_events.ResourceAPIKey, ts.audit.filter.ResourceType)
+	assert.True(t, from.Equal(ts.audit.filter.From))
+	assert.Equal(t, audit_events.DefaultEventsLimit, ts.audit.filter.Limit)
+
+	verification, err := ts.client.VerifyAuditTrail(ctx)
+	assert.NoError(t, err)
+	assert.True(t, verification.Valid)
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.AppConfig{RateLimiter: config.RateLimiterConfig{Enabled: true, MaxRequests: 1}}
+
+	limited := newTestServer(t, cfg, Options{MaxRetries: -1}).client
+	_, err := limited.ListWarehouses(ctx)
+	assert.NoError(t, err)
+	_, err = limited.ListWarehouses(ctx)
+	var apiErr *Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+
+	// The limiter allows one request per second, which the backoff waits out
+	retrying := newTestServer(t, cfg, Options{MaxRetries: 5, Backoff: 200 * time.Millisecond, MaxBackoff: 500 * time.Millisecond}).client
+	_, err = retrying.ListWarehouses(ctx)
+	assert.NoError(t, err)
+	_, err = retrying.ListWarehouses(ctx)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+// Error is an error response of the API. It wraps the decoded *errors.Error of the
+// server, so errors.IsType works on it, and the error details where the client knows
+// their type, such as *apitypes.NoAcceptablePackingError.
+type Error struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Err        *apperrors.Error
+	// details is the raw Err field of the response
+	details json.RawMessage
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Err.Message)
+}
+
+// Unwrap returns the server error, which in turn wraps its details
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError decodes an error response. Bodies that are not a JSON error keep the
+// status text as the message and the error type of the status.
+func newError(resp *http.Response, method, path string) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+		Err:        apperrors.NewError(apperrors.TypeForStatus(resp.StatusCode), http.StatusText(resp.StatusCode), nil),
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return apiErr
+	}
+	var decoded struct {
+		Type    apperrors.ErrorType
+		Message string
+		Err     json.RawMessage
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Message == "" {
+		return apiErr
+	}
+	apiErr.Err.Type = decoded.Type
+	apiErr.Err.Message = decoded.Message
+	apiErr.details = decoded.Err
+	return apiErr
+}
+
+// decodeDetails decodes the details of the error into target and wraps them
+func (e *Error) decodeDetails(target error) {
+	if len(e.details) == 0 || json.Unmarshal(e.details, target) != nil {
+		return
+	}
+	e.Err.Err = target
+}
@@ -3,15 +3,60 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 type ErrorType string
 
 const (
 	ErrorTypeInvalidRequest ErrorType = "INVALID_REQUEST"
+	ErrorTypeNotFound       ErrorType = "NOT_FOUND"
+	ErrorTypeConflict       ErrorType = "CONFLICT"
+	ErrorTypeUnprocessable  ErrorType = "UNPROCESSABLE"
+	ErrorTypeUnauthorized   ErrorType = "UNAUTHORIZED"
+	ErrorTypeForbidden      ErrorType = "FORBIDDEN"
+	ErrorTypeRateLimited    ErrorType = "RATE_LIMITED"
+	ErrorTypeUnavailable    ErrorType = "UNAVAILABLE"
+	ErrorTypeTimeout        ErrorType = "TIMEOUT"
 	ErrorTypeInternal       ErrorType = "INTERNAL"
 )
 
+// httpStatuses maps each error type to the status of its HTTP response
+var httpStatuses = map[ErrorType]int{
+	ErrorTypeInvalidRequest: http.StatusBadRequest,
+	ErrorTypeNotFound:       http.StatusNotFound,
+	ErrorTypeConflict:       http.StatusConflict,
+	ErrorTypeUnprocessable:  http.StatusUnprocessableEntity,
+	ErrorTypeUnauthorized:   http.StatusUnauthorized,
+	ErrorTypeForbidden:      http.StatusForbidden,
+	ErrorTypeRateLimited:    http.StatusTooManyRequests,
+	ErrorTypeUnavailable:    http.StatusServiceUnavailable,
+	ErrorTypeTimeout:        http.StatusGatewayTimeout,
+	ErrorTypeInternal:       http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP status for an error type. Unknown types are internal errors.
+func HTTPStatus(errType ErrorType) int {
+	if status, ok := httpStatuses[errType]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// TypeForStatus returns the error type answered with an HTTP status. Other client errors
+// are invalid requests and other statuses internal errors.
+func TypeForStatus(status int) ErrorType {
+	for errType, s := range httpStatuses {
+		if s == status {
+			return errType
+		}
+	}
+	if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+		return ErrorTypeInvalidRequest
+	}
+	return ErrorTypeInternal
+}
+
 type Error struct {
 	Type    ErrorType
 	Message string
@@ -25,6 +70,11 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("%s: %s", e.Type, e.Message)
 }
 
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 func NewError(errType ErrorType, message string, err error) *Error {
 	return &Error{
 		Type:    errType,
@@ -48,3 +98,51 @@ func NewValidationError(message string) *Error {
 func NewInternalError(message string) *Error {
 	return NewError(ErrorTypeInternal, message, errors.New(message))
 }
+
+func NewNotFoundErrorWrap(message string, err error) *Error {
+	return NewError(ErrorTypeNotFound, message, err)
+}
+
+func NewConflictErrorWrap(message string, err error) *Error {
+	return NewError(ErrorTypeConflict, message, err)
+}
+
+func NewUnprocessableErrorWrap(message string, err error) *Error {
+	return NewError(ErrorTypeUnprocessable, message, err)
+}
+
+func NewUnauthorizedError(message string) *Error {
+	return NewError(ErrorTypeUnauthorized, message, errors.New(message))
+}
+
+func NewForbiddenError(message string) *Error {
+	return NewError(ErrorTypeForbidden, message, errors.New(message))
+}
+
+func NewRateLimitedError(message string) *Error {
+	return NewError(ErrorTypeRateLimited, message, errors.New(message))
+}
+
+func NewUnavailableErrorWrap(message string, err error) *Error {
+	return NewError(ErrorTypeUnavailable, message, err)
+}
+
+func NewTimeoutErrorWrap(message string, err error) *Error {
+	return NewError(ErrorTypeTimeout, message, err)
+}
+
+// Wrap returns the first *Error in the chain of err. Errors without one become an
+// internal error with the message.
+func Wrap(message string, err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return NewInternalErrorWrap(message, err)
+}
+
+// IsType reports whether err wraps an *Error of the given type
+func IsType(err error, errType ErrorType) bool {
+	var appErr *Error
+	return errors.As(err, &appErr) && appErr.Type == errType
+}
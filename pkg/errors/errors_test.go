@@ -2,6 +2,8 @@ package errors
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 )
 
@@ -111,3 +113,94 @@ func TestNewInternalError(t *testing.T) {
 		t.Errorf("Err.Error() = %v, want %v", err.Err.Error(), "server error")
 	}
 }
+
+func TestIsType(t *testing.T) {
+	t.Run("matching type", func(t *testing.T) {
+		err := NewValidationError("invalid input")
+		if !IsType(err, ErrorTypeInvalidRequest) {
+			t.Error("IsType() = false, want true")
+		}
+	})
+
+	t.Run("wrapped error", func(t *testing.T) {
+		err := fmt.Errorf("processing: %w", NewValidationError("invalid input"))
+		if !IsType(err, ErrorTypeInvalidRequest) {
+			t.Error("IsType() = false, want true")
+		}
+	})
+
+	t.Run("different type", func(t *testing.T) {
+		err := NewInternalError("server error")
+		if IsType(err, ErrorTypeInvalidRequest) {
+			t.Error("IsType() = true, want false")
+		}
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		if IsType(errors.New("plain"), ErrorTypeInternal) {
+			t.Error("IsType() = true, want false")
+		}
+	})
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := map[ErrorType]int{
+		ErrorTypeInvalidRequest: http.StatusBadRequest,
+		ErrorTypeNotFound:       http.StatusNotFound,
+		ErrorTypeConflict:       http.StatusConflict,
+		ErrorTypeUnprocessable:  http.StatusUnprocessableEntity,
+		ErrorTypeUnauthorized:   http.StatusUnauthorized,
+		ErrorTypeForbidden:      http.StatusForbidden,
+		ErrorTypeRateLimited:    http.StatusTooManyRequests,
+		ErrorTypeUnavailable:    http.StatusServiceUnavailable,
+		ErrorTypeTimeout:        http.StatusGatewayTimeout,
+		ErrorTypeInternal:       http.StatusInternalServerError,
+		ErrorType("UNKNOWN"):    http.StatusInternalServerError,
+	}
+	for errType, want := range tests {
+		if got := HTTPStatus(errType); got != want {
+			t.Errorf("HTTPStatus(%v) = %v, want %v", errType, got, want)
+		}
+	}
+}
+
+func TestTypeForStatus(t *testing.T) {
+	tests := map[int]ErrorType{
+		http.StatusBadRequest:          ErrorTypeInvalidRequest,
+		http.StatusNotFound:            ErrorTypeNotFound,
+		http.StatusTooManyRequests:     ErrorTypeRateLimited,
+		http.StatusServiceUnavailable:  ErrorTypeUnavailable,
+		http.StatusMethodNotAllowed:    ErrorTypeInvalidRequest,
+		http.StatusBadGateway:          ErrorTypeInternal,
+		http.StatusInternalServerError: ErrorTypeInternal,
+	}
+	for status, want := range tests {
+		if got := TypeForStatus(status); got != want {
+			t.Errorf("TypeForStatus(%v) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("error with a type", func(t *testing.T) {
+		notFound := NewNotFoundErrorWrap("calculation not found", errors.New("record not found"))
+		err := Wrap("failed to load", fmt.Errorf("loading: %w", notFound))
+		if err != notFound {
+			t.Errorf("Wrap() = %v, want %v", err, notFound)
+		}
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		innerErr := errors.New("connection reset")
+		err := Wrap("failed to load", innerErr)
+		if err.Type != ErrorTypeInternal {
+			t.Errorf("Type = %v, want %v", err.Type, ErrorTypeInternal)
+		}
+		if err.Message != "failed to load" {
+			t.Errorf("Message = %v, want %v", err.Message, "failed to load")
+		}
+		if !errors.Is(err, innerErr) {
+			t.Errorf("errors.Is(%v, %v) = false, want true", err, innerErr)
+		}
+	})
+}
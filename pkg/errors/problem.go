@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of problem details responses
+const ProblemContentType = "application/problem+json"
+
+// Problem codes identify the kind of problem. They are the lower case error types and are
+// part of the API contract, so they do not change.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeNotFound       = "not_found"
+	CodeConflict       = "conflict"
+	CodeUnprocessable  = "unprocessable"
+	CodeUnauthorized   = "unauthorized"
+	CodeForbidden      = "forbidden"
+	CodeRateLimited    = "rate_limited"
+	CodeUnavailable    = "unavailable"
+	CodeTimeout        = "timeout"
+	CodeInternal       = "internal"
+)
+
+// Problem is an RFC 7807 problem details object with a stable code
+type Problem struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Code     string          `json:"code"`
+	Details  json.RawMessage `json:"details,omitempty"`
+}
+
+// NewProblem returns the problem details for an error of the given type
+func NewProblem(errType ErrorType, detail string) *Problem {
+	status := HTTPStatus(errType)
+	code := CodeInternal
+	if _, ok := httpStatuses[errType]; ok {
+		code = strings.ToLower(string(errType))
+	}
+	return &Problem{
+		Type:   "urn:pack-calculator:problem:" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
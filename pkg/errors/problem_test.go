@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewProblem(t *testing.T) {
+	problem := NewProblem(ErrorTypeNotFound, "Calculation not found")
+
+	if problem.Type != "urn:pack-calculator:problem:not_found" {
+		t.Errorf("Type = %v, want %v", problem.Type, "urn:pack-calculator:problem:not_found")
+	}
+	if problem.Title != "Not Found" {
+		t.Errorf("Title = %v, want %v", problem.Title, "Not Found")
+	}
+	if problem.Status != http.StatusNotFound {
+		t.Errorf("Status = %v, want %v", problem.Status, http.StatusNotFound)
+	}
+	if problem.Detail != "Calculation not found" {
+		t.Errorf("Detail = %v, want %v", problem.Detail, "Calculation not found")
+	}
+	if problem.Code != CodeNotFound {
+		t.Errorf("Code = %v, want %v", problem.Code, CodeNotFound)
+	}
+}
+
+func TestNewProblem_Codes(t *testing.T) {
+	tests := map[ErrorType]string{
+		ErrorTypeInvalidRequest: CodeInvalidRequest,
+		ErrorTypeNotFound:       CodeNotFound,
+		ErrorTypeConflict:       CodeConflict,
+		ErrorTypeUnprocessable:  CodeUnprocessable,
+		ErrorTypeUnauthorized:   CodeUnauthorized,
+		ErrorTypeForbidden:      CodeForbidden,
+		ErrorTypeRateLimited:    CodeRateLimited,
+		ErrorTypeUnavailable:    CodeUnavailable,
+		ErrorTypeTimeout:        CodeTimeout,
+		ErrorTypeInternal:       CodeInternal,
+		ErrorType("UNKNOWN"):    CodeInternal,
+	}
+	for errType, want := range tests {
+		if got := NewProblem(errType, "").Code; got != want {
+			t.Errorf("NewProblem(%v).Code = %v, want %v", errType, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package packsolver
+
+// findBoundedPacks finds the largest total not exceeding the limit that can be created
+// from the available number of packs per size, using as few packs as possible for it.
+func findBoundedPacks(limit int, packSizes []int, available map[int]int) map[int]int {
+	if limit <= 0 {
+		return make(map[int]int)
+	}
+
+	dp, reconstruct := boundedPackTable(limit, packSizes, available)
+	best := limit
+	for best > 0 && dp[best] < 0 {
+		best--
+	}
+	return reconstruct(best)
+}
+
+// findBoundedCover finds the smallest total of at least the order quantity that can be
+// created from the available number of packs per size, using as few packs as possible
+// for it. It returns nil when the available packs cannot cover the order quantity.
+// Pack sizes must be sorted in ascending order.
+func findBoundedCover(orderQuantity int, packSizes []int, available map[int]int) map[int]int {
+	if orderQuantity <= 0 {
+		return make(map[int]int)
+	}
+	if len(packSizes) == 0 {
+		return nil
+	}
+
+	// A covering total at or above the order quantity plus the largest pack always has a
+	// pack that can be dropped while still covering the order
+	limit := orderQuantity + packSizes[len(packSizes)-1] - 1
+	dp, reconstruct := boundedPackTable(limit, packSizes, available)
+	for total := orderQuantity; total <= limit; total++ {
+		if dp[total] >= 0 {
+			return reconstruct(total)
+		}
+	}
+	return nil
+}
+
+// boundedPackTable computes the fewest packs needed for every total up to the limit from
+// the available number of packs per size, with -1 marking unreachable totals, along with
+// a function that reconstructs the pack counts of a reachable total. Pack counts are split
+// into powers of two so each size is handled as a 0/1 knapsack.
+func boundedPackTable(limit int, packSizes []int, available map[int]int) ([]int, func(total int) map[int]int) {
+	type bundle struct {
+		size  int
+		count int
+	}
+	var bundles []bundle
+	for _, packSize := range packSizes {
+		remaining := available[packSize]
+		for count := 1; remaining > 0; count *= 2 {
+			count = min(count, remaining)
+			bundles = append(bundles, bundle{size: packSize, count: count})
+			remaining -= count
+		}
+	}
+
+	// dp[i] = minimum number of packs needed to make exactly i items
+	dp := make([]int, limit+1)
+	for i := range dp {
+		dp[i] = -1
+	}
+	dp[0] = 0
+
+	// taken[b][i] = whether bundle b improved the packs needed for total i
+	taken := make([][]bool, len(bundles))
+	for b, bd := range bundles {
+		taken[b] = make([]bool, limit+1)
+		weight := bd.size * bd.count
+		for i := limit; i >= weight; i-- {
+			if dp[i-weight] >= 0 && (dp[i] < 0 || dp[i-weight]+bd.count < dp[i]) {
+				dp[i] = dp[i-weight] + bd.count
+				taken[b][i] = true
+			}
+		}
+	}
+
+	// Reconstruct a solution by walking the bundles backwards
+	reconstruct := func(total int) map[int]int {
+		packCounts := make(map[int]int)
+		for b := len(bundles) - 1; b >= 0 && total > 0; b-- {
+			if taken[b][total] {
+				packCounts[bundles[b].size] += bundles[b].count
+				total -= bundles[b].size * bundles[b].count
+			}
+		}
+		return packCounts
+	}
+
+	return dp, reconstruct
+}
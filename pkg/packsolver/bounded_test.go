@@ -0,0 +1,97 @@
+package packsolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindBoundedPacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		packSizes []int
+		available map[int]int
+		want      map[int]int
+	}{
+		{
+			name:      "fewest packs for the largest total",
+			limit:     1000,
+			packSizes: []int{250, 500},
+			available: map[int]int{250: 4, 500: 2},
+			want:      map[int]int{500: 2},
+		},
+		{
+			name:      "limited by stock",
+			limit:     1000,
+			packSizes: []int{250, 500},
+			available: map[int]int{250: 1, 500: 1},
+			want:      map[int]int{250: 1, 500: 1},
+		},
+		{
+			name:      "many packs of one size",
+			limit:     2600,
+			packSizes: []int{250},
+			available: map[int]int{250: 100},
+			want:      map[int]int{250: 10},
+		},
+		{
+			name:      "nothing fits",
+			limit:     200,
+			packSizes: []int{250},
+			available: map[int]int{250: 3},
+			want:      map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findBoundedPacks(tt.limit, tt.packSizes, tt.available))
+		})
+	}
+}
+
+func TestFindBoundedCover(t *testing.T) {
+	tests := []struct {
+		name          string
+		orderQuantity int
+		packSizes     []int
+		available     map[int]int
+		want          map[int]int
+	}{
+		{
+			name:          "exact cover",
+			orderQuantity: 750,
+			packSizes:     []int{250, 500},
+			available:     map[int]int{250: 1, 500: 1},
+			want:          map[int]int{250: 1, 500: 1},
+		},
+		{
+			name:          "smallest pack out of stock",
+			orderQuantity: 251,
+			packSizes:     []int{250, 500, 1000},
+			available:     map[int]int{250: 0, 500: 0, 1000: 2},
+			want:          map[int]int{1000: 1},
+		},
+		{
+			name:          "fewest packs for the total",
+			orderQuantity: 1000,
+			packSizes:     []int{250, 500},
+			available:     map[int]int{250: 4, 500: 2},
+			want:          map[int]int{500: 2},
+		},
+		{
+			name:          "stock cannot cover",
+			orderQuantity: 1001,
+			packSizes:     []int{250, 500},
+			available:     map[int]int{250: 2, 500: 1},
+			want:          nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findBoundedCover(tt.orderQuantity, tt.packSizes, tt.available))
+		})
+	}
+}
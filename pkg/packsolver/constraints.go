@@ -0,0 +1,189 @@
+package packsolver
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxNeverMix bounds the never mix pairs of a packing. The solver branches on which size of
+// each pair is left out, so the work can double with every pair.
+const MaxNeverMix = 10
+
+// Constraints limit the packs of each size in a packing
+type Constraints struct {
+	// AtLeast is the minimum number of packs per size
+	AtLeast map[int]int
+	// AtMost is the maximum number of packs per size
+	AtMost map[int]int
+	// NeverMix lists pairs of sizes that must not both be used
+	NeverMix [][2]int
+}
+
+// empty reports whether the constraints do not limit any size
+func (c Constraints) empty() bool {
+	return len(c.AtLeast) == 0 && len(c.AtMost) == 0 && len(c.NeverMix) == 0
+}
+
+// validate checks that counts are not negative and that there are at most MaxNeverMix never
+// mix pairs, each naming two sizes
+func (c Constraints) validate() error {
+	for size, count := range c.AtLeast {
+		if count < 0 {
+			return fmt.Errorf("%w: at least %d packs of %d", ErrInvalidConstraints, count, size)
+		}
+	}
+	for size, count := range c.AtMost {
+		if count < 0 {
+			return fmt.Errorf("%w: at most %d packs of %d", ErrInvalidConstraints, count, size)
+		}
+	}
+	if len(c.NeverMix) > MaxNeverMix {
+		return fmt.Errorf("%w: %d never mix pairs, at most %d are allowed", ErrInvalidConstraints, len(c.NeverMix), MaxNeverMix)
+	}
+	for _, pair := range c.NeverMix {
+		if pair[0] == pair[1] {
+			return fmt.Errorf("%w: never mix %d and %d", ErrInvalidConstraints, pair[0], pair[1])
+		}
+	}
+	return nil
+}
+
+// solveWithConstraints finds the packing that satisfies the constraints. With cover set it
+// looks for the fewest items at or above the target, otherwise for the most items at or
+// below it, and for the fewest packs making that total. A non-nil inventory limits the packs
+// available per size. Packs in fixed are already part of the order and count towards the
+// constraints, but are not returned. It returns nil when no packing satisfies the constraints.
+func solveWithConstraints(target int, cover bool, packSizes []int, constraints Constraints, inventory, fixed map[int]int) map[int]int {
+	// Branch on which size of each never mix pair is left out and keep the best branch. A pair
+	// with a size left out by an earlier branch already holds and does not branch again.
+	var best map[int]int
+	bestTotal, bestPacks := 0, 0
+	excluded := make(map[int]bool, len(constraints.NeverMix))
+	var branch func(i int)
+	branch = func(i int) {
+		if i < len(constraints.NeverMix) {
+			pair := constraints.NeverMix[i]
+			if excluded[pair[0]] || excluded[pair[1]] {
+				branch(i + 1)
+				return
+			}
+			for _, size := range pair {
+				excluded[size] = true
+				branch(i + 1)
+				delete(excluded, size)
+			}
+			return
+		}
+
+		packCounts := solveConstraintBranch(target, cover, packSizes, excluded, constraints, inventory, fixed)
+		if packCounts == nil {
+			return
+		}
+		solution := newSolution(packCounts)
+		total, packs := solution.TotalItems, solution.TotalPacks
+		if best == nil || (cover && total < bestTotal) || (!cover && total > bestTotal) || (total == bestTotal && packs < bestPacks) {
+			best, bestTotal, bestPacks = packCounts, total, packs
+		}
+	}
+	branch(0)
+	return best
+}
+
+// solveConstraintBranch solves solveWithConstraints without the excluded sizes. The packs
+// the at least constraints require are taken first and the rest is solved as a bounded
+// knapsack with the at most constraints and the inventory as the packs available per size.
+func solveConstraintBranch(target int, cover bool, packSizes []int, excluded map[int]bool, constraints Constraints, inventory, fixed map[int]int) map[int]int {
+	for size := range excluded {
+		if fixed[size] > 0 {
+			return nil
+		}
+	}
+
+	configured := make(map[int]bool, len(packSizes))
+	for _, size := range packSizes {
+		configured[size] = true
+	}
+
+	required := make(map[int]int)
+	requiredTotal := 0
+	for size, count := range constraints.AtLeast {
+		missing := count - fixed[size]
+		if missing <= 0 {
+			continue
+		}
+		if excluded[size] || !configured[size] {
+			return nil
+		}
+		if inventory != nil && inventory[size] < missing {
+			return nil
+		}
+		// Required packs beyond the target, or beyond any int for a covering total, cannot fit
+		room := target - requiredTotal
+		if cover {
+			room = math.MaxInt - requiredTotal
+		}
+		if missing > room/size {
+			return nil
+		}
+		required[size] = missing
+		requiredTotal += missing * size
+	}
+	for size, limit := range constraints.AtMost {
+		if fixed[size]+required[size] > limit {
+			return nil
+		}
+	}
+
+	// A covering total never needs to exceed the remainder by a whole pack of the largest size
+	remaining, limit := target-requiredTotal, target-requiredTotal
+	if cover {
+		remaining = max(remaining, 0)
+		limit = remaining
+		if remaining > 0 && len(packSizes) > 0 {
+			limit = remaining + packSizes[len(packSizes)-1] - 1
+		}
+	} else if limit < 0 {
+		return nil
+	}
+
+	available := make(map[int]int, len(packSizes))
+	for _, size := range packSizes {
+		if excluded[size] {
+			continue
+		}
+		available[size] = limit / size
+		if maxCount, ok := constraints.AtMost[size]; ok {
+			available[size] = min(available[size], maxCount-fixed[size]-required[size])
+		}
+		if inventory != nil {
+			available[size] = min(available[size], inventory[size]-required[size])
+		}
+	}
+	dp, reconstruct := boundedPackTable(limit, packSizes, available)
+
+	total := -1
+	if cover {
+		for i := remaining; i <= limit; i++ {
+			if dp[i] >= 0 {
+				total = i
+				break
+			}
+		}
+	} else {
+		for i := limit; i >= 0; i-- {
+			if dp[i] >= 0 {
+				total = i
+				break
+			}
+		}
+	}
+	if total < 0 {
+		return nil
+	}
+
+	packCounts := reconstruct(total)
+	for size, count := range required {
+		packCounts[size] += count
+	}
+	return packCounts
+}
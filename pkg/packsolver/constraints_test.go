@@ -0,0 +1,144 @@
+package packsolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolveWithConstraints(t *testing.T) {
+	allSizes := []int{250, 500, 1000, 2000, 5000}
+
+	tests := []struct {
+		name        string
+		target      int
+		cover       bool
+		packSizes   []int
+		constraints Constraints
+		inventory   map[int]int
+		fixed       map[int]int
+		want        map[int]int
+	}{
+		{
+			name:      "no constraints",
+			target:    12001,
+			cover:     true,
+			packSizes: allSizes,
+			want:      map[int]int{250: 1, 2000: 1, 5000: 2},
+		},
+		{
+			name:        "at most caps a size",
+			target:      750,
+			cover:       true,
+			packSizes:   []int{250, 1000},
+			constraints: Constraints{AtMost: map[int]int{250: 2}},
+			want:        map[int]int{1000: 1},
+		},
+		{
+			name:        "at least forces a size",
+			target:      1000,
+			cover:       true,
+			packSizes:   allSizes,
+			constraints: Constraints{AtLeast: map[int]int{5000: 1}},
+			want:        map[int]int{5000: 1},
+		},
+		{
+			name:        "never mix keeps the better branch",
+			target:      5250,
+			cover:       true,
+			packSizes:   allSizes,
+			constraints: Constraints{NeverMix: [][2]int{{250, 5000}}},
+			want:        map[int]int{250: 1, 1000: 1, 2000: 2},
+		},
+		{
+			name:        "never mix pairs sharing a size",
+			target:      1250,
+			cover:       true,
+			packSizes:   allSizes,
+			constraints: Constraints{NeverMix: [][2]int{{250, 500}, {250, 1000}, {250, 2000}, {250, 5000}}},
+			want:        map[int]int{250: 5},
+		},
+		{
+			name:        "at least and at most contradict",
+			target:      1000,
+			cover:       true,
+			packSizes:   allSizes,
+			constraints: Constraints{AtLeast: map[int]int{5000: 1}, AtMost: map[int]int{5000: 0}},
+			want:        nil,
+		},
+		{
+			name:        "required size is not available",
+			target:      1000,
+			cover:       true,
+			packSizes:   []int{250, 500},
+			constraints: Constraints{AtLeast: map[int]int{5000: 1}},
+			want:        nil,
+		},
+		{
+			name:        "most items at or below the target",
+			target:      750,
+			cover:       false,
+			packSizes:   []int{250, 1000},
+			constraints: Constraints{AtMost: map[int]int{250: 2}},
+			want:        map[int]int{250: 2},
+		},
+		{
+			name:        "fixed packs count towards at most",
+			target:      250,
+			cover:       true,
+			packSizes:   []int{250, 1000},
+			constraints: Constraints{AtMost: map[int]int{250: 2}},
+			fixed:       map[int]int{250: 2},
+			want:        map[int]int{1000: 1},
+		},
+		{
+			name:        "fixed packs decide the never mix branch",
+			target:      5000,
+			cover:       true,
+			packSizes:   allSizes,
+			constraints: Constraints{NeverMix: [][2]int{{250, 5000}}},
+			fixed:       map[int]int{250: 1},
+			want:        map[int]int{1000: 1, 2000: 2},
+		},
+		{
+			name:        "inventory limits the other sizes",
+			target:      1000,
+			cover:       true,
+			packSizes:   []int{250, 500, 1000},
+			constraints: Constraints{AtLeast: map[int]int{250: 1}},
+			inventory:   map[int]int{250: 1, 1000: 5},
+			want:        map[int]int{250: 1, 1000: 1},
+		},
+		{
+			name:        "required packs are out of stock",
+			target:      1000,
+			cover:       true,
+			packSizes:   []int{250, 500, 1000},
+			constraints: Constraints{AtLeast: map[int]int{250: 2}},
+			inventory:   map[int]int{250: 1, 1000: 5},
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := solveWithConstraints(tt.target, tt.cover, tt.packSizes, tt.constraints, tt.inventory, tt.fixed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConstraints_Validate(t *testing.T) {
+	assert.NoError(t, Constraints{}.validate())
+	assert.NoError(t, Constraints{AtLeast: map[int]int{250: 1}, AtMost: map[int]int{250: 0}, NeverMix: [][2]int{{250, 500}}}.validate())
+	assert.ErrorIs(t, Constraints{AtLeast: map[int]int{250: -1}}.validate(), ErrInvalidConstraints)
+	assert.ErrorIs(t, Constraints{AtMost: map[int]int{250: -1}}.validate(), ErrInvalidConstraints)
+	assert.ErrorIs(t, Constraints{NeverMix: [][2]int{{250, 250}}}.validate(), ErrInvalidConstraints)
+
+	pairs := make([][2]int, MaxNeverMix+1)
+	for i := range pairs {
+		pairs[i] = [2]int{1, i + 2}
+	}
+	assert.NoError(t, Constraints{NeverMix: pairs[:MaxNeverMix]}.validate())
+	assert.ErrorIs(t, Constraints{NeverMix: pairs}.validate(), ErrInvalidConstraints)
+}
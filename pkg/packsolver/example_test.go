@@ -0,0 +1,60 @@
+package packsolver_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pack-calculator/pkg/packsolver"
+)
+
+func ExampleSolve() {
+	solution, err := packsolver.Solve([]int{250, 500, 1000, 2000, 5000}, 12001, packsolver.Options{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(solution.Packs, solution.TotalItems, solution.TotalPacks)
+	// Output: map[250:1 2000:1 5000:2] 12250 4
+}
+
+func ExampleSolve_tieBreak() {
+	sizes := []int{250, 500, 750, 1000, 1250}
+
+	larger, _ := packsolver.Solve(sizes, 2000, packsolver.Options{})
+	fewer, _ := packsolver.Solve(sizes, 2000, packsolver.Options{TieBreak: packsolver.TieBreak{Policy: packsolver.FewerSizes}})
+	fmt.Println(larger.Packs)
+	fmt.Println(fewer.Packs)
+	// Output:
+	// map[750:1 1250:1]
+	// map[1000:2]
+}
+
+func ExampleSolve_constraints() {
+	constraints := packsolver.Constraints{
+		AtMost:   map[int]int{250: 2},
+		NeverMix: [][2]int{{250, 5000}},
+	}
+	solution, err := packsolver.Solve([]int{250, 500, 1000, 2000, 5000}, 5250, packsolver.Options{Constraints: constraints})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(solution.Packs)
+	// Output: map[250:1 1000:1 2000:2]
+}
+
+func ExampleSolve_inventory() {
+	_, err := packsolver.Solve([]int{250, 500}, 1001, packsolver.Options{Inventory: map[int]int{250: 2, 500: 1}})
+	fmt.Println(errors.Is(err, packsolver.ErrInfeasible))
+	// Output: true
+}
+
+func ExampleSolveAtMost() {
+	solution, err := packsolver.SolveAtMost([]int{250, 500, 1000}, 2600, packsolver.Options{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(solution.Packs, solution.TotalItems)
+	// Output: map[500:1 1000:2] 2500
+}
@@ -0,0 +1,217 @@
+// Package packsolver finds the packs to ship for an order when only whole packs can be sent.
+// A solution ships the fewest items that cover the quantity and, for that total, uses the
+// fewest packs. The package has no dependencies outside the standard library.
+package packsolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrNoPackSizes is returned when no pack sizes are given
+	ErrNoPackSizes = errors.New("packsolver: no pack sizes")
+	// ErrInvalidPackSize is returned when a pack size is not positive
+	ErrInvalidPackSize = errors.New("packsolver: pack sizes must be positive")
+	// ErrInvalidQuantity is returned when the quantity is negative
+	ErrInvalidQuantity = errors.New("packsolver: quantity must not be negative")
+	// ErrInvalidTieBreak is returned for an unknown tie-break policy or invalid weights
+	ErrInvalidTieBreak = errors.New("packsolver: invalid tie-break policy")
+	// ErrInvalidConstraints is returned when constraints have negative counts or pair a size with itself
+	ErrInvalidConstraints = errors.New("packsolver: invalid constraints")
+	// ErrInfeasible is returned when no packing satisfies the constraints or the inventory
+	ErrInfeasible = errors.New("packsolver: no packing satisfies the constraints")
+)
+
+// Options adjust how a packing is chosen. The zero value solves without limits and
+// breaks ties by preferring larger packs.
+type Options struct {
+	// TieBreak chooses among packings with the same number of items and packs
+	TieBreak TieBreak
+	// Constraints limit the packs of each size
+	Constraints Constraints
+	// Inventory, when not nil, is the number of packs available per size. Sizes it does
+	// not list have no packs available.
+	Inventory map[int]int
+	// Fixed packs are already part of the order. They count towards the constraints
+	// but are not returned.
+	Fixed map[int]int
+}
+
+// Solution is a packing for an order
+type Solution struct {
+	// Packs is the number of packs per size, listing only the sizes used
+	Packs      map[int]int
+	TotalItems int
+	TotalPacks int
+}
+
+// Solve finds the packing with the fewest items that is at least the quantity, and the
+// fewest packs for that total. The tie-break policy only applies without constraints and
+// inventory; bounded solves pick among equal packings in a fixed order.
+func Solve(packSizes []int, quantity int, opts Options) (Solution, error) {
+	sizes, err := prepare(packSizes, quantity, opts)
+	if err != nil {
+		return Solution{}, err
+	}
+
+	var packCounts map[int]int
+	switch {
+	case !opts.Constraints.empty():
+		packCounts = solveWithConstraints(quantity, true, sizes, opts.Constraints, opts.Inventory, opts.Fixed)
+	case opts.Inventory != nil:
+		packCounts = findBoundedCover(quantity, sizes, opts.Inventory)
+	default:
+		packCounts = findOptimalPacks(quantity, sizes, opts.TieBreak)
+	}
+	if packCounts == nil {
+		return Solution{}, fmt.Errorf("%w: cannot cover %d items", ErrInfeasible, quantity)
+	}
+	return newSolution(packCounts), nil
+}
+
+// SolveAtMost finds the packing with the most items that does not exceed the limit, and
+// the fewest packs for that total. When no pack fits the solution is empty. It only fails
+// with ErrInfeasible when the constraints require packs that do not fit.
+func SolveAtMost(packSizes []int, limit int, opts Options) (Solution, error) {
+	sizes, err := prepare(packSizes, limit, opts)
+	if err != nil {
+		return Solution{}, err
+	}
+
+	var packCounts map[int]int
+	switch {
+	case !opts.Constraints.empty():
+		packCounts = solveWithConstraints(limit, false, sizes, opts.Constraints, opts.Inventory, opts.Fixed)
+	case opts.Inventory != nil:
+		packCounts = findBoundedPacks(limit, sizes, opts.Inventory)
+	default:
+		packCounts = findMinPacks(findMaxTotalItems(limit, sizes), sizes, opts.TieBreak)
+	}
+	if packCounts == nil {
+		return Solution{}, fmt.Errorf("%w: no packing of at most %d items", ErrInfeasible, limit)
+	}
+	return newSolution(packCounts), nil
+}
+
+// prepare validates the input and returns the pack sizes sorted in ascending order without duplicates
+func prepare(packSizes []int, quantity int, opts Options) ([]int, error) {
+	if len(packSizes) == 0 {
+		return nil, ErrNoPackSizes
+	}
+	if quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if err := opts.TieBreak.Validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.Constraints.validate(); err != nil {
+		return nil, err
+	}
+
+	sizes := append([]int(nil), packSizes...)
+	sort.Ints(sizes)
+	if sizes[0] <= 0 {
+		return nil, ErrInvalidPackSize
+	}
+	unique := sizes[:1]
+	for _, size := range sizes[1:] {
+		if size != unique[len(unique)-1] {
+			unique = append(unique, size)
+		}
+	}
+	return unique, nil
+}
+
+// newSolution summarises pack counts, dropping sizes without packs
+func newSolution(packCounts map[int]int) Solution {
+	solution := Solution{Packs: make(map[int]int, len(packCounts))}
+	for size, count := range packCounts {
+		if count == 0 {
+			continue
+		}
+		solution.Packs[size] = count
+		solution.TotalItems += size * count
+		solution.TotalPacks += count
+	}
+	return solution
+}
+
+// findOptimalPacks finds the optimal combination of packs for an order quantity,
+// choosing among equally optimal combinations by the tie-break policy.
+// Pack sizes must be sorted in ascending order.
+func findOptimalPacks(orderQuantity int, packSizes []int, tieBreak TieBreak) map[int]int {
+	// Fast-path for orders no larger than the minimum pack size
+	if orderQuantity > 0 && orderQuantity <= packSizes[0] {
+		return map[int]int{packSizes[0]: 1}
+	}
+
+	// Step 1: Find the minimum total items needed to fulfill the order
+	minTotal := findMinTotalItems(orderQuantity, packSizes)
+
+	// Step 2: Find the minimal pack combination for this total
+	return findMinPacks(minTotal, packSizes, tieBreak)
+}
+
+// findMinTotalItems finds the smallest possible total that can be created using
+// the pack sizes and is at least the order quantity. Pack sizes must be sorted in
+// ascending order.
+func findMinTotalItems(orderQuantity int, packSizes []int) int {
+	// If no pack sizes available, return -1 (error)
+	if len(packSizes) == 0 {
+		return -1
+	}
+
+	smallestPack := packSizes[0]
+	maxPossibleTotal := orderQuantity + smallestPack - 1
+
+	// dp[i] = true if we can make exactly i items using the pack sizes
+	dp := make([]bool, maxPossibleTotal+1)
+	dp[0] = true
+
+	for _, packSize := range packSizes {
+		for i := packSize; i <= maxPossibleTotal; i++ {
+			if dp[i-packSize] {
+				dp[i] = true
+			}
+		}
+	}
+
+	// Find the smallest valid total that's at least the order quantity
+	for i := orderQuantity; i <= maxPossibleTotal; i++ {
+		if dp[i] {
+			return i
+		}
+	}
+
+	return -1 // Should never happen if at least one pack size exists
+}
+
+// findMaxTotalItems finds the largest possible total that can be created using
+// the pack sizes without exceeding the limit, or 0 if none fits
+func findMaxTotalItems(limit int, packSizes []int) int {
+	if limit <= 0 || len(packSizes) == 0 {
+		return 0
+	}
+
+	// dp[i] = true if we can make exactly i items using the pack sizes
+	dp := make([]bool, limit+1)
+	dp[0] = true
+
+	for _, packSize := range packSizes {
+		for i := packSize; i <= limit; i++ {
+			if dp[i-packSize] {
+				dp[i] = true
+			}
+		}
+	}
+
+	for i := limit; i > 0; i-- {
+		if dp[i] {
+			return i
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,188 @@
+package packsolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve(t *testing.T) {
+	allSizes := []int{250, 500, 1000, 2000, 5000}
+
+	tests := []struct {
+		name      string
+		packSizes []int
+		quantity  int
+		opts      Options
+		want      Solution
+		wantErr   error
+	}{
+		{
+			name:      "smaller than the smallest pack",
+			packSizes: allSizes,
+			quantity:  1,
+			want:      Solution{Packs: map[int]int{250: 1}, TotalItems: 250, TotalPacks: 1},
+		},
+		{
+			name:      "fewest items before fewest packs",
+			packSizes: allSizes,
+			quantity:  501,
+			want:      Solution{Packs: map[int]int{250: 1, 500: 1}, TotalItems: 750, TotalPacks: 2},
+		},
+		{
+			name:      "large order",
+			packSizes: allSizes,
+			quantity:  12001,
+			want:      Solution{Packs: map[int]int{250: 1, 2000: 1, 5000: 2}, TotalItems: 12250, TotalPacks: 4},
+		},
+		{
+			name:      "unsorted sizes with duplicates",
+			packSizes: []int{5, 3, 5},
+			quantity:  8,
+			want:      Solution{Packs: map[int]int{3: 1, 5: 1}, TotalItems: 8, TotalPacks: 2},
+		},
+		{
+			name:      "zero quantity",
+			packSizes: allSizes,
+			quantity:  0,
+			want:      Solution{Packs: map[int]int{}},
+		},
+		{
+			name:      "constraints",
+			packSizes: allSizes,
+			quantity:  1000,
+			opts:      Options{Constraints: Constraints{AtLeast: map[int]int{5000: 1}}},
+			want:      Solution{Packs: map[int]int{5000: 1}, TotalItems: 5000, TotalPacks: 1},
+		},
+		{
+			name:      "inventory",
+			packSizes: []int{250, 500, 1000},
+			quantity:  251,
+			opts:      Options{Inventory: map[int]int{1000: 2}},
+			want:      Solution{Packs: map[int]int{1000: 1}, TotalItems: 1000, TotalPacks: 1},
+		},
+		{
+			name:      "inventory cannot cover",
+			packSizes: []int{250, 500},
+			quantity:  1001,
+			opts:      Options{Inventory: map[int]int{250: 2, 500: 1}},
+			wantErr:   ErrInfeasible,
+		},
+		{
+			name:      "required packs overflow the total",
+			packSizes: []int{250, 500},
+			quantity:  1000,
+			opts:      Options{Constraints: Constraints{AtLeast: map[int]int{250: 1 << 62}}},
+			wantErr:   ErrInfeasible,
+		},
+		{
+			name:      "contradicting constraints",
+			packSizes: allSizes,
+			quantity:  1000,
+			opts:      Options{Constraints: Constraints{AtLeast: map[int]int{5000: 1}, AtMost: map[int]int{5000: 0}}},
+			wantErr:   ErrInfeasible,
+		},
+		{
+			name:     "no pack sizes",
+			quantity: 1,
+			wantErr:  ErrNoPackSizes,
+		},
+		{
+			name:      "invalid pack size",
+			packSizes: []int{0, 250},
+			quantity:  1,
+			wantErr:   ErrInvalidPackSize,
+		},
+		{
+			name:      "negative quantity",
+			packSizes: allSizes,
+			quantity:  -1,
+			wantErr:   ErrInvalidQuantity,
+		},
+		{
+			name:      "invalid tie-break policy",
+			packSizes: allSizes,
+			quantity:  1,
+			opts:      Options{TieBreak: TieBreak{Policy: "smaller_packs"}},
+			wantErr:   ErrInvalidTieBreak,
+		},
+		{
+			name:      "invalid constraints",
+			packSizes: allSizes,
+			quantity:  1,
+			opts:      Options{Constraints: Constraints{NeverMix: [][2]int{{250, 250}}}},
+			wantErr:   ErrInvalidConstraints,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Solve(tt.packSizes, tt.quantity, tt.opts)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSolveAtMost(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []int
+		limit     int
+		opts      Options
+		want      Solution
+		wantErr   error
+	}{
+		{
+			name:      "most items below the limit",
+			packSizes: []int{250, 500, 1000},
+			limit:     2600,
+			want:      Solution{Packs: map[int]int{500: 1, 1000: 2}, TotalItems: 2500, TotalPacks: 3},
+		},
+		{
+			name:      "nothing fits",
+			packSizes: []int{250},
+			limit:     200,
+			want:      Solution{Packs: map[int]int{}},
+		},
+		{
+			name:      "inventory",
+			packSizes: []int{250, 500},
+			limit:     1000,
+			opts:      Options{Inventory: map[int]int{250: 1, 500: 1}},
+			want:      Solution{Packs: map[int]int{250: 1, 500: 1}, TotalItems: 750, TotalPacks: 2},
+		},
+		{
+			name:      "constraints",
+			packSizes: []int{250, 1000},
+			limit:     750,
+			opts:      Options{Constraints: Constraints{AtMost: map[int]int{250: 2}}},
+			want:      Solution{Packs: map[int]int{250: 2}, TotalItems: 500, TotalPacks: 2},
+		},
+		{
+			name:      "required packs do not fit",
+			packSizes: []int{250, 5000},
+			limit:     1000,
+			opts:      Options{Constraints: Constraints{AtLeast: map[int]int{5000: 1}}},
+			wantErr:   ErrInfeasible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SolveAtMost(tt.packSizes, tt.limit, tt.opts)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
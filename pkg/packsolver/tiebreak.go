@@ -0,0 +1,192 @@
+package packsolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tie-break policies choosing among combinations with the same number of items and packs
+const (
+	LargerPacks   = "larger_packs"
+	FewerSizes    = "fewer_sizes"
+	Lexicographic = "lexicographic"
+	Weighted      = "weighted"
+)
+
+// MaxWeight bounds the weight of a pack size, keeping weighted penalties within the solver's range
+const MaxWeight = 1000
+
+// TieBreak chooses among the combinations with the same number of items and packs.
+// LargerPacks prefers the most packs of the largest size, then of the next size and so on.
+// Lexicographic prefers the most packs of the smallest size, which gives the lexicographically
+// smallest list of pack sizes. FewerSizes prefers the fewest distinct sizes and Weighted the
+// highest total of Weights per pack, sizes without a weight counting as 0. Both fall back to
+// LargerPacks when still tied. An empty Policy means LargerPacks.
+type TieBreak struct {
+	Policy  string      `json:"policy"`
+	Weights map[int]int `json:"weights,omitempty"`
+}
+
+// String describes the policy, listing the weights in ascending size order
+func (p TieBreak) String() string {
+	if p.Policy != Weighted || len(p.Weights) == 0 {
+		return p.Policy
+	}
+	sizes := make([]int, 0, len(p.Weights))
+	for size := range p.Weights {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+	weights := make([]string, len(sizes))
+	for i, size := range sizes {
+		weights[i] = fmt.Sprintf("%d:%d", size, p.Weights[size])
+	}
+	return p.Policy + " " + strings.Join(weights, ",")
+}
+
+// TieBreakError reports why a tie-break policy is invalid. It matches ErrInvalidTieBreak.
+type TieBreakError struct {
+	Reason string
+}
+
+func (e *TieBreakError) Error() string {
+	return ErrInvalidTieBreak.Error() + ": " + e.Reason
+}
+
+// Is reports whether target is ErrInvalidTieBreak
+func (e *TieBreakError) Is(target error) bool {
+	return target == ErrInvalidTieBreak
+}
+
+// Validate checks that the policy is known and that weights are given exactly to the
+// weighted policy, each for a positive size and between -MaxWeight and MaxWeight. It
+// returns a *TieBreakError.
+func (p TieBreak) Validate() error {
+	switch p.Policy {
+	case "", LargerPacks, FewerSizes, Lexicographic:
+		if len(p.Weights) > 0 {
+			return &TieBreakError{Reason: "weights are only used by the weighted policy"}
+		}
+	case Weighted:
+		if len(p.Weights) == 0 {
+			return &TieBreakError{Reason: "the weighted policy requires weights"}
+		}
+		for size, weight := range p.Weights {
+			if size <= 0 || weight < -MaxWeight || weight > MaxWeight {
+				return &TieBreakError{Reason: fmt.Sprintf("weights must map positive pack sizes to weights between -%d and %d", MaxWeight, MaxWeight)}
+			}
+		}
+	default:
+		return &TieBreakError{Reason: fmt.Sprintf("policy must be %s, %s, %s or %s, got %q", LargerPacks, FewerSizes, Lexicographic, Weighted, p.Policy)}
+	}
+	return nil
+}
+
+// findMinPacks finds the minimum number of packs needed to make exactly the target total.
+// Among the combinations with that number of packs the tie-break policy picks one.
+//
+// Sizes are added one layer at a time, and each layer records the best cost of every total
+// using the sizes so far. The cost is the number of packs followed by the policy's penalty:
+// minus the weight of each pack for weighted, one per distinct size for fewer_sizes and
+// nothing otherwise. The combination is rebuilt from the last layer, taking as many packs
+// of each layer's size as still allows the best cost, so the size of the last layer is
+// preferred most. Layers run from the smallest size to the largest, except for
+// lexicographic, which prefers the smallest size most.
+func findMinPacks(targetTotal int, packSizes []int, tieBreak TieBreak) map[int]int {
+	packCounts := make(map[int]int)
+	if targetTotal <= 0 || len(packSizes) == 0 {
+		return packCounts
+	}
+
+	sizes := append([]int(nil), packSizes...)
+	sort.Ints(sizes)
+	if tieBreak.Policy == Lexicographic {
+		sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	}
+
+	// penalty returns the penalty of taking count packs of a size
+	penalty := func(size, count int) int32 {
+		switch {
+		case count == 0:
+			return 0
+		case tieBreak.Policy == Weighted:
+			return -int32(tieBreak.Weights[size] * count)
+		case tieBreak.Policy == FewerSizes:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	// packs[j][i] and penalties[j][i] hold the best cost of total i using the sizes of layers 0 to j,
+	// with -1 packs marking unreachable totals
+	packs := make([][]int32, len(sizes))
+	penalties := make([][]int32, len(sizes))
+	prevPacks := make([]int32, targetTotal+1)
+	prevPenalties := make([]int32, targetTotal+1)
+	for i := 1; i <= targetTotal; i++ {
+		prevPacks[i] = -1
+	}
+	base := [2][]int32{prevPacks, prevPenalties}
+
+	// usingPacks[i] and usingPenalties[i] hold the best cost of total i using the current size at least once
+	usingPacks := make([]int32, targetTotal+1)
+	usingPenalties := make([]int32, targetTotal+1)
+
+	better := func(p, q, bestP, bestQ int32) bool {
+		return bestP < 0 || p < bestP || (p == bestP && q < bestQ)
+	}
+
+	for j, size := range sizes {
+		curPacks := make([]int32, targetTotal+1)
+		curPenalties := make([]int32, targetTotal+1)
+		first, each := penalty(size, 1), penalty(size, 2)-penalty(size, 1)
+		for i := 0; i <= targetTotal; i++ {
+			usingPacks[i] = -1
+			if i >= size {
+				if prevPacks[i-size] >= 0 {
+					usingPacks[i], usingPenalties[i] = prevPacks[i-size]+1, prevPenalties[i-size]+first
+				}
+				if usingPacks[i-size] >= 0 && better(usingPacks[i-size]+1, usingPenalties[i-size]+each, usingPacks[i], usingPenalties[i]) {
+					usingPacks[i], usingPenalties[i] = usingPacks[i-size]+1, usingPenalties[i-size]+each
+				}
+			}
+
+			curPacks[i], curPenalties[i] = prevPacks[i], prevPenalties[i]
+			if usingPacks[i] >= 0 && better(usingPacks[i], usingPenalties[i], curPacks[i], curPenalties[i]) {
+				curPacks[i], curPenalties[i] = usingPacks[i], usingPenalties[i]
+			}
+		}
+		packs[j], penalties[j] = curPacks, curPenalties
+		prevPacks, prevPenalties = curPacks, curPenalties
+	}
+
+	last := len(sizes) - 1
+	if packs[last][targetTotal] < 0 {
+		return packCounts
+	}
+
+	// Rebuild the combination, taking as many packs of each layer's size as possible
+	remaining := targetTotal
+	for j := last; j >= 0; j-- {
+		size := sizes[j]
+		wantPacks, wantPenalty := packs[j][remaining], penalties[j][remaining]
+		lowerPacks, lowerPenalties := base[0], base[1]
+		if j > 0 {
+			lowerPacks, lowerPenalties = packs[j-1], penalties[j-1]
+		}
+		for count := remaining / size; count >= 0; count-- {
+			rest := remaining - count*size
+			if lowerPacks[rest] >= 0 && lowerPacks[rest]+int32(count) == wantPacks && lowerPenalties[rest]+penalty(size, count) == wantPenalty {
+				if count > 0 {
+					packCounts[size] = count
+				}
+				remaining = rest
+				break
+			}
+		}
+	}
+
+	return packCounts
+}
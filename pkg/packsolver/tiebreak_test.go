@@ -0,0 +1,101 @@
+package packsolver
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// tieBreakGoldenCase is one entry of testdata/tie_break.golden.json
+type tieBreakGoldenCase struct {
+	Name          string      `json:"name"`
+	OrderQuantity int         `json:"orderQuantity"`
+	PackSizes     []int       `json:"packSizes"`
+	TieBreak      TieBreak    `json:"tieBreak"`
+	Packs         map[int]int `json:"packs"`
+}
+
+// TestSolve_TieBreakGolden pins the packs each tie-break policy picks, as documented
+// in the API contract of the service. Run with -update to rewrite the golden file after an
+// intended change.
+func TestSolve_TieBreakGolden(t *testing.T) {
+	larger := TieBreak{Policy: LargerPacks}
+	fewer := TieBreak{Policy: FewerSizes}
+	lexicographic := TieBreak{Policy: Lexicographic}
+
+	cases := []tieBreakGoldenCase{
+		{Name: "single combination", OrderQuantity: 12001, PackSizes: []int{250, 500, 1000, 2000, 5000}, TieBreak: fewer},
+		{Name: "fast path", OrderQuantity: 1, PackSizes: []int{250, 500}, TieBreak: lexicographic},
+		{Name: "overfilled total - larger packs", OrderQuantity: 1900, PackSizes: []int{250, 500, 750, 1000, 1250}, TieBreak: larger},
+		{Name: "overfilled total - fewer sizes", OrderQuantity: 1900, PackSizes: []int{250, 500, 750, 1000, 1250}, TieBreak: fewer},
+		{Name: "overfilled total - weighted", OrderQuantity: 1900, PackSizes: []int{250, 500, 750, 1000, 1250}, TieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{1000: 1}}},
+		{Name: "four combinations - larger packs", OrderQuantity: 36, PackSizes: []int{3, 4, 6, 7}, TieBreak: larger},
+		{Name: "four combinations - lexicographic", OrderQuantity: 36, PackSizes: []int{3, 4, 6, 7}, TieBreak: lexicographic},
+		{Name: "four combinations - fewer sizes", OrderQuantity: 36, PackSizes: []int{3, 4, 6, 7}, TieBreak: fewer},
+		{Name: "four combinations - weighted", OrderQuantity: 36, PackSizes: []int{3, 4, 6, 7}, TieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{3: 10, 6: -1}}},
+		{Name: "four combinations - weighted tie falls back to larger packs", OrderQuantity: 36, PackSizes: []int{3, 4, 6, 7}, TieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{3: 1, 4: 1, 6: 1, 7: 1}}},
+		{Name: "three combinations - larger packs", OrderQuantity: 35, PackSizes: []int{3, 4, 7, 9}, TieBreak: larger},
+		{Name: "three combinations - lexicographic", OrderQuantity: 35, PackSizes: []int{3, 4, 7, 9}, TieBreak: lexicographic},
+		{Name: "three combinations - fewer sizes", OrderQuantity: 35, PackSizes: []int{3, 4, 7, 9}, TieBreak: fewer},
+		{Name: "three combinations - weighted", OrderQuantity: 35, PackSizes: []int{3, 4, 7, 9}, TieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{7: 2}}},
+		{Name: "large order", OrderQuantity: 500000, PackSizes: []int{23, 31, 53}, TieBreak: lexicographic},
+	}
+	for i := range cases {
+		solution, err := Solve(cases[i].PackSizes, cases[i].OrderQuantity, Options{TieBreak: cases[i].TieBreak})
+		assert.NoError(t, err)
+		cases[i].Packs = solution.Packs
+	}
+
+	path := filepath.Join("testdata", "tie_break.golden.json")
+	if *updateGolden {
+		data, err := json.MarshalIndent(cases, "", "  ")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, append(data, '\n'), 0o644))
+	}
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var want []tieBreakGoldenCase
+	assert.NoError(t, json.Unmarshal(data, &want))
+	assert.Equal(t, want, cases)
+}
+
+func TestTieBreak_String(t *testing.T) {
+	assert.Equal(t, "larger_packs", TieBreak{Policy: LargerPacks}.String())
+	assert.Equal(t, "weighted", TieBreak{Policy: Weighted}.String())
+	assert.Equal(t, "weighted 250:1,5000:-3", TieBreak{Policy: Weighted, Weights: map[int]int{5000: -3, 250: 1}}.String())
+}
+
+func TestTieBreak_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		tieBreak TieBreak
+		wantErr  bool
+	}{
+		{name: "zero value", tieBreak: TieBreak{}},
+		{name: "fewer sizes", tieBreak: TieBreak{Policy: FewerSizes}},
+		{name: "weighted", tieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{250: -MaxWeight, 500: MaxWeight}}},
+		{name: "unknown policy", tieBreak: TieBreak{Policy: "smaller_packs"}, wantErr: true},
+		{name: "weighted without weights", tieBreak: TieBreak{Policy: Weighted}, wantErr: true},
+		{name: "weights without weighted policy", tieBreak: TieBreak{Policy: Lexicographic, Weights: map[int]int{250: 1}}, wantErr: true},
+		{name: "weight out of range", tieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{250: MaxWeight + 1}}, wantErr: true},
+		{name: "weight for an invalid size", tieBreak: TieBreak{Policy: Weighted, Weights: map[int]int{0: 1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tieBreak.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTieBreak)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
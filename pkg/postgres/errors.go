@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+// ClassifyError gives database errors the error type that describes them to clients.
+// It returns nil for nil and errors that already have a type unchanged. Errors it cannot
+// classify become internal errors with a generic message, logged with their cause when
+// the response is written. The driver error is wrapped so that its fields, such as table
+// and constraint names, are not serialised into responses.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	wrapped := fmt.Errorf("%w", err)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperrors.NewNotFoundErrorWrap("Record not found", wrapped)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperrors.NewConflictErrorWrap("Record already exists", wrapped)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperrors.NewConflictErrorWrap("Record is referenced by or references another record", wrapped)
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperrors.NewTimeoutErrorWrap("Database query timed out", wrapped)
+	case errors.Is(err, driver.ErrBadConn):
+		return apperrors.NewUnavailableErrorWrap("Database unavailable", wrapped)
+	}
+
+	if code := sqlState(err); code != "" {
+		return classifySQLState(code, wrapped)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return apperrors.NewTimeoutErrorWrap("Database query timed out", wrapped)
+		}
+		return apperrors.NewUnavailableErrorWrap("Database unavailable", wrapped)
+	}
+	return apperrors.NewInternalErrorWrap("Database error", wrapped)
+}
+
+// sqlState returns the SQLSTATE code of a lib/pq or pgx error, or "" for other errors
+func sqlState(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// classifySQLState classifies an error by its SQLSTATE code
+func classifySQLState(code string, wrapped error) error {
+	switch {
+	case code == "23505":
+		return apperrors.NewConflictErrorWrap("Record already exists", wrapped)
+	case code == "23503":
+		return apperrors.NewConflictErrorWrap("Record is referenced by or references another record", wrapped)
+	case code == "40001" || code == "40P01":
+		return apperrors.NewConflictErrorWrap("Concurrent update, retry the request", wrapped)
+	case code == "23502" || code == "23514" || strings.HasPrefix(code, "22"):
+		return apperrors.NewValidationErrorWrap("Invalid data", wrapped)
+	case code == "57014":
+		return apperrors.NewTimeoutErrorWrap("Database query timed out", wrapped)
+	case strings.HasPrefix(code, "08") || strings.HasPrefix(code, "53") || strings.HasPrefix(code, "57P"):
+		return apperrors.NewUnavailableErrorWrap("Database unavailable", wrapped)
+	}
+	return apperrors.NewInternalErrorWrap("Database error", wrapped)
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	apperrors "github.com/pack-calculator/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantType apperrors.ErrorType
+	}{
+		{name: "nil", err: nil},
+		{name: "record not found", err: gorm.ErrRecordNotFound, wantType: apperrors.ErrorTypeNotFound},
+		{name: "duplicated key", err: gorm.ErrDuplicatedKey, wantType: apperrors.ErrorTypeConflict},
+		{name: "pq unique violation", err: &pq.Error{Code: "23505"}, wantType: apperrors.ErrorTypeConflict},
+		{name: "pgx unique violation", err: fmt.Errorf("saving: %w", &pgconn.PgError{Code: "23505"}), wantType: apperrors.ErrorTypeConflict},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: "23503"}, wantType: apperrors.ErrorTypeConflict},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, wantType: apperrors.ErrorTypeConflict},
+		{name: "check violation", err: &pq.Error{Code: "23514"}, wantType: apperrors.ErrorTypeInvalidRequest},
+		{name: "numeric out of range", err: &pgconn.PgError{Code: "22003"}, wantType: apperrors.ErrorTypeInvalidRequest},
+		{name: "query canceled", err: &pgconn.PgError{Code: "57014"}, wantType: apperrors.ErrorTypeTimeout},
+		{name: "context deadline", err: context.DeadlineExceeded, wantType: apperrors.ErrorTypeTimeout},
+		{name: "connection failure", err: &pq.Error{Code: "08006"}, wantType: apperrors.ErrorTypeUnavailable},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300"}, wantType: apperrors.ErrorTypeUnavailable},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, wantType: apperrors.ErrorTypeUnavailable},
+		{name: "bad connection", err: driver.ErrBadConn, wantType: apperrors.ErrorTypeUnavailable},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, wantType: apperrors.ErrorTypeUnavailable},
+		{name: "unknown sql state", err: &pq.Error{Code: "42P01", Message: `relation "secret_table" does not exist`, Table: "secret_table"}, wantType: apperrors.ErrorTypeInternal},
+		{name: "plain error", err: errors.New("unexpected"), wantType: apperrors.ErrorTypeInternal},
+		{name: "typed error", err: apperrors.NewValidationError("invalid input")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+
+			// Nil and errors that already have a type are returned unchanged
+			if tt.wantType == "" {
+				if got != tt.err {
+					t.Errorf("ClassifyError() = %v, want %v", got, tt.err)
+				}
+				return
+			}
+			if !apperrors.IsType(got, tt.wantType) {
+				t.Errorf("ClassifyError() = %v, want type %v", got, tt.wantType)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", got, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Package tenant holds the business unit a request acts for. The HTTP and gRPC layers
+// put the tenant in the request context and repositories scope their queries to it, so
+// tenants cannot see each other's data.
+package tenant
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Default is the tenant of requests that do not select one, and of the data that
+// existed before tenants were introduced
+const Default = "default"
+
+// maxLength bounds the length of a tenant ID
+const maxLength = 64
+
+// Valid reports whether id is a tenant ID: 1 to 64 lowercase letters, digits, dashes
+// and underscores
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+type tenantKey struct{}
+
+// NewContext returns a copy of ctx that carries the tenant
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of ctx, or Default when ctx carries none
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Scope returns a GORM scope that restricts a query to the rows of the tenant of ctx,
+// held in their tenant_id column
+func Scope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	id := FromContext(ctx)
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant_id = ?", id)
+	}
+}
@@ -0,0 +1,43 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid(Default))
+	assert.True(t, Valid("retail-eu_2"))
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("Retail"))
+	assert.False(t, Valid("retail eu"))
+	assert.False(t, Valid("retail' OR '1'='1"))
+	assert.False(t, Valid(string(make([]byte, 65))))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, Default, FromContext(ctx))
+	assert.Equal(t, "retail", FromContext(NewContext(ctx, "retail")))
+	assert.Equal(t, Default, FromContext(NewContext(ctx, "")))
+}
+
+func TestScope(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB, PreferSimpleProtocol: true}), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	type row struct{ ID uint }
+	stmt := db.Table("rows").Scopes(Scope(NewContext(context.Background(), "retail"))).Where("id = ?", 3).Find(&[]row{}).Statement
+
+	assert.Equal(t, `SELECT * FROM "rows" WHERE id = $1 AND tenant_id = $2`, stmt.SQL.String())
+	assert.Equal(t, []any{3, "retail"}, stmt.Vars)
+}
@@ -18,6 +18,11 @@ func CalculateArrayHash(arr []int) string {
 	}
 	str := strings.Join(elements, ",")
 
+	return CalculateStringHash(str)
+}
+
+// CalculateStringHash returns the SHA256 hash of a string as a hexadecimal string.
+func CalculateStringHash(str string) string {
 	// Calculate SHA256 hash
 	hasher := sha256.New()
 	hasher.Write([]byte(str))
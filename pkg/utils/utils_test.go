@@ -58,3 +58,31 @@ func TestCalculateArrayHash_Deterministic(t *testing.T) {
 		t.Errorf("Hash function is not deterministic: got %v, %v, %v for same input", hash1, hash2, hash3)
 	}
 }
+
+func TestCalculateStringHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Empty string",
+			input:    "",
+			expected: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name:     "Matches array hash of the same representation",
+			input:    "1,2,3",
+			expected: CalculateArrayHash([]int{1, 2, 3}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalculateStringHash(tt.input)
+			if result != tt.expected {
+				t.Errorf("CalculateStringHash(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -163,6 +163,7 @@ document.addEventListener('DOMContentLoaded', function () {
             <p>Order quantity: <strong>${escapeHtml(orderQuantity)}</strong></p>
             <p>Total items to be shipped: <strong>${escapeHtml(totalItems)}</strong></p>
             <p>Total packs: <strong>${escapeHtml(totalPacks)}</strong></p>
+            ${data.reason ? `<p>Reason: <em>${escapeHtml(data.reason)}</em></p>` : ''}
             
             <h3>Pack Breakdown:</h3>
             <table>